@@ -18,12 +18,21 @@ package app
 import (
 	"fmt"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/keikoproj/governor/pkg/reaper/pdbreaper"
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/yaml"
 )
 
-var pdbReaperArgs pdbreaper.Args
+var (
+	pdbReaperArgs       pdbreaper.Args
+	pdbReaperInterval   time.Duration
+	pdbReaperConfigFile string
+)
 
 // pdbReaperCmd represents the reap command
 var pdbReaperCmd = &cobra.Command{
@@ -31,26 +40,222 @@ var pdbReaperCmd = &cobra.Command{
 	Short: "pdb invokes the pdb reaper",
 	Long:  `reap finds and force deletes pod disruption budgets which are stuck or misconfigured`,
 	Run: func(cmd *cobra.Command, args []string) {
-		if err := pdbreaper.Run(&pdbReaperArgs); err != nil {
+		reaperArgs := &pdbReaperArgs
+		if pdbReaperConfigFile != "" {
+			var err error
+			if reaperArgs, err = loadPdbReaperArgs(cmd.Flags(), pdbReaperConfigFile); err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+		}
+
+		var err error
+		if pdbReaperInterval > 0 {
+			var reload pdbreaper.ArgsLoader
+			if pdbReaperConfigFile != "" {
+				reload = func() (*pdbreaper.Args, error) {
+					return loadPdbReaperArgs(cmd.Flags(), pdbReaperConfigFile)
+				}
+			}
+			err = pdbreaper.RunDaemon(reaperArgs, pdbReaperInterval, reload)
+		} else {
+			err = pdbreaper.Run(reaperArgs)
+		}
+		if err != nil {
 			fmt.Println(err)
-			os.Exit(1)
+			os.Exit(pdbreaper.ExitCode(err))
 		}
 	},
 }
 
 func init() {
 	reapCmd.AddCommand(pdbReaperCmd)
-	pdbReaperCmd.Flags().StringVar(&pdbReaperArgs.K8sConfigPath, "kubeconfig", "", "Absolute path to the kubeconfig file")
-	pdbReaperCmd.Flags().BoolVar(&pdbReaperArgs.LocalMode, "local-mode", false, "Use cluster external auth")
-	pdbReaperCmd.Flags().BoolVar(&pdbReaperArgs.DryRun, "dry-run", false, "Will not actually delete PDBs")
-	pdbReaperCmd.Flags().BoolVar(&pdbReaperArgs.ReapMisconfigured, "reap-misconfigured", true, "Delete PDBs which are configured to not allow disruptions")
-	pdbReaperCmd.Flags().BoolVar(&pdbReaperArgs.ReapMultiple, "reap-multiple", true, "Delete multiple PDBs which are targeting a single deployment")
-	pdbReaperCmd.Flags().BoolVar(&pdbReaperArgs.ReapCrashLoop, "reap-crashloop", false, "Delete PDBs which are targeting a deployment whose pods are in a crashloop")
-	pdbReaperCmd.Flags().BoolVar(&pdbReaperArgs.AllCrashLoop, "all-crashloop", true, "Only deletes PDBs for crashlooping pods when all pods are in crashloop")
-	pdbReaperCmd.Flags().IntVar(&pdbReaperArgs.CrashLoopRestartCount, "crashloop-restart-count", 5, "Minimum restart count to when considering pods in crashloop")
-	pdbReaperCmd.Flags().StringSliceVar(&pdbReaperArgs.ExcludedNamespaces, "excluded-namespaces", []string{}, "Namespaces excluded from scanning")
-	pdbReaperCmd.Flags().BoolVar(&pdbReaperArgs.ReapNotReady, "reap-not-ready", true, "Deletes PDBs which have pods in not-ready state")
-	pdbReaperCmd.Flags().IntVar(&pdbReaperArgs.ReapNotReadyThreshold, "not-ready-threshold-seconds", 1800, "Minimum seconds to wait when considering pods in not-ready state")
-	pdbReaperCmd.Flags().BoolVar(&pdbReaperArgs.AllNotReady, "all-not-ready", false, "Only deletes PDBs for not-ready pods when all pods are in not-ready state")
-	pdbReaperCmd.Flags().StringVar(&pdbReaperArgs.PromPushgateway, "prometheus-pushgateway", "", "Prometheus pushgateway URL")
+	pdbReaperCmd.Flags().DurationVar(&pdbReaperInterval, "interval", 0, "Run continuously with this interval between runs, SIGHUP reloads --reaper-config (0 runs once)")
+	pdbReaperCmd.Flags().StringVar(&pdbReaperConfigFile, "reaper-config", "", "Path to a YAML file of flag names and values, which override the command line flags")
+	addPdbReaperFlags(pdbReaperCmd.Flags(), &pdbReaperArgs)
+}
+
+// addPdbReaperFlags defines the pdb-reaper flags on a flag set, bound to args
+func addPdbReaperFlags(flags *pflag.FlagSet, args *pdbreaper.Args) {
+	flags.StringVar(&args.K8sConfigPath, "kubeconfig", "", "Absolute path to the kubeconfig file")
+	flags.StringSliceVar(&args.Clusters, "cluster", []string{}, "Cluster to scan in the form name=kubeconfig[:context], can be repeated to scan multiple clusters in one run")
+	flags.BoolVar(&args.LocalMode, "local-mode", false, "Use cluster external auth")
+	flags.BoolVar(&args.DryRun, "dry-run", false, "Will not actually delete PDBs")
+	flags.BoolVar(&args.EmitEvents, "emit-events", true, "Publish events on PDBs, also when --dry-run is set")
+	flags.BoolVar(&args.EmitMetrics, "emit-metrics", true, "Push metrics to the configured metrics backend, also when --dry-run is set")
+	flags.BoolVar(&args.ResetStaleMetrics, "reset-stale-metrics", true, "Reset the result metric of PDBs which were reapable in the previous run and no longer are to 0, requires --state-configmap outside of daemon mode")
+	flags.BoolVar(&args.DryRunAnnotate, "dry-run-annotate", false, "Annotate PDBs which would be deleted with the reason when --dry-run is set")
+	flags.BoolVar(&args.DumpEvents, "dump-events", false, "Capture the events which would be published in the logged run result instead of creating them when --dry-run is set")
+	flags.StringVar(&args.FixManifestsDir, "fix-manifests-dir", "", "Write a fixed manifest for each misconfigured PDB to this directory instead of deleting PDBs or writing to the cluster")
+	flags.BoolVar(&args.Validate, "validate", false, "Report misconfigured, blocking and overlapping PDBs as findings to stdout and exit without reaping, exits with code 2 on error findings")
+	flags.BoolVar(&args.DrainAssist, "drain-assist", false, "Report the PDBs blocking the drain of --node, with their pods on the node, to stdout and exit without reaping")
+	flags.StringVar(&args.Node, "node", "", "Name of the node to report blocking PDBs for, used with --drain-assist")
+	flags.StringVar(&args.Inspect, "inspect", "", "PDB in the form namespace/name whose decision trace is reported to stdout, evaluating every reap mode without side effects, and exit without reaping")
+	flags.BoolVar(&args.CleanupAnnotations, "cleanup-annotations", false, "Remove annotations managed by pdb-reaper from all PDBs and exit without reaping")
+	flags.BoolVar(&args.ReapMisconfigured, "reap-misconfigured", true, "Delete PDBs which are configured to not allow disruptions")
+	flags.BoolVar(&args.ExcludeDaemonSetPods, "exclude-daemonset-pods", false, "Leave pods owned by a DaemonSet out of crashloop and not-ready detection")
+	flags.BoolVar(&args.ReapOnlyIfPodsMatch, "reap-only-if-pods-match", false, "Only consider misconfigured PDBs reapable when their selector matches at least one pod")
+	flags.BoolVar(&args.ReapMultiple, "reap-multiple", true, "Delete multiple PDBs which are targeting a single deployment")
+	flags.BoolVar(&args.NodeDrainIntegration, "node-drain-integration", false, "During planned maintenance only delete PDBs blocking the drain of cordoned or maintenance nodes, maintenance is indicated by --maintenance-node-label or --maintenance-configmap")
+	flags.StringVar(&args.MaintenanceNodeLabel, "maintenance-node-label", "", "Node label in the form key or key=value marking nodes under planned maintenance, used with --node-drain-integration")
+	flags.StringVar(&args.MaintenanceConfigMap, "maintenance-configmap", "", "ConfigMap in the form namespace/name whose 'maintenance' key set to true indicates planned maintenance, used with --node-drain-integration")
+	flags.BoolVar(&args.RequireAllPodsForMultiple, "require-all-pods-for-multiple", false, "Only delete multiple PDBs when they match the same pods, same as --multiple-overlap-ratio=1")
+	flags.Float64Var(&args.MultipleOverlapRatio, "multiple-overlap-ratio", 0, "Only delete multiple PDBs when the pods they share are at least this ratio of the pods of the larger PDB (0 deletes on a single shared pod)")
+	flags.BoolVar(&args.FixOverlap, "fix-overlap", false, "Patch redundant multiple PDBs to a permissive maxUnavailable instead of deleting them, keeping the PDB selecting the most pods untouched")
+	flags.BoolVar(&args.ReapDuplicateSelector, "reap-duplicate-selector", false, "Delete PDBs in the same namespace which share an identical selector")
+	flags.BoolVar(&args.ReapMixedControllers, "reap-mixed-controllers", false, "Delete blocking PDBs whose selector matches pods of multiple controllers, e.g. two Deployments")
+	flags.BoolVar(&args.ReapSingleNode, "reap-single-node", false, "Delete blocking PDBs whose pods are all scheduled on a single node")
+	flags.BoolVar(&args.ReapStaleSelector, "reap-stale-selector", false, "Delete PDBs whose selector matches no pods and was likely left stale by a label change of a deployment in the namespace")
+	flags.BoolVar(&args.ReapHealthScore, "reap-health-score", false, "Delete blocking PDBs whose weighted health score exceeds --health-score-threshold")
+	flags.Float64Var(&args.HealthScoreThreshold, "health-score-threshold", pdbreaper.DefaultHealthScoreThreshold, "Health score between 0 and 1 above which a blocking PDB is reapable with --reap-health-score")
+	flags.StringSliceVar(&args.HealthScoreWeights, "health-score-weights", []string{}, "Weights of the health score components in the form component=weight, one of misconfigured,crashloop,not-ready,blocking-duration,overlap (default 1 each)")
+	flags.DurationVar(&args.HealthScoreBlockingDuration, "health-score-blocking-duration", pdbreaper.DefaultHealthScoreBlockingDuration, "Blocking duration at which the blocking-duration health score component is 1")
+	flags.BoolVar(&args.ReapZeroMaxUnavailable, "reap-zero-max-unavailable", false, "Delete PDBs whose maxUnavailable resolves to 0 regardless of whether they are blocking or the state of their pods")
+	flags.BoolVar(&args.ReapCrashLoop, "reap-crashloop", false, "Delete PDBs which are targeting a deployment whose pods are in a crashloop")
+	flags.BoolVar(&args.AllCrashLoop, "all-crashloop", true, "Only deletes PDBs for crashlooping pods when all pods are in crashloop")
+	flags.Float64Var(&args.CrashLoopPodFraction, "crashloop-pod-fraction", 0, "Only deletes PDBs for crashlooping pods when at least this fraction of pods are in crashloop, overrides --all-crashloop when set")
+	flags.BoolVar(&args.ReapDrainBlocking, "reap-drain-blocking", false, "Delete blocking PDBs which have pods on cordoned/draining nodes")
+	flags.BoolVar(&args.DrainBlockingOnly, "drain-blocking-only", false, "Only delete PDBs which have pods on cordoned/draining nodes, PDBs on healthy nodes are spared")
+	flags.IntVar(&args.CrashLoopRestartCount, "crashloop-restart-count", 5, "Minimum restart count to when considering pods in crashloop")
+	flags.StringSliceVar(&args.CrashLoopReasons, "crashloop-reasons", pdbreaper.DefaultCrashLoopReasons, "Waiting reasons of containers counted as crashlooping")
+	flags.BoolVar(&args.CrashLoopIncludeTerminated, "crashloop-include-terminated", false, "Also count terminated containers past the restart count whose exit code is at least --crashloop-min-exit-code as crashlooping")
+	flags.IntVar(&args.CrashLoopMinExitCode, "crashloop-min-exit-code", pdbreaper.DefaultCrashLoopMinExitCode, "Minimum exit code of terminated containers counted as crashlooping with --crashloop-include-terminated")
+	flags.BoolVar(&args.ProbePodLogs, "probe-pod-logs", false, "Include the last log lines of a crashlooping container in the crashloop detection event, requires get pods/log")
+	flags.IntVar(&args.PodLogsLines, "probe-pod-logs-lines", pdbreaper.DefaultPodLogsLines, "Number of log lines to include with --probe-pod-logs")
+	flags.IntVar(&args.PodLogsMaxBytes, "probe-pod-logs-max-bytes", pdbreaper.DefaultPodLogsMaxBytes, "Maximum bytes of logs to include with --probe-pod-logs, longer logs are truncated from the start")
+	flags.StringSliceVar(&args.ReapModes, "reap-modes", []string{}, "Reap modes to enable, one of misconfigured,crashloop,not-ready,multiple,drain-blocking,duplicate-selector,zero-max-unavailable,health-score,mixed-controllers,single-node,stale-selector, overrides the individual --reap-* flags when set")
+	flags.StringSliceVar(&args.ExcludedNamespaces, "excluded-namespaces", []string{}, "Namespaces excluded from scanning")
+	flags.StringSliceVar(&args.QuietNamespaces, "quiet-namespaces", []string{}, "Namespaces in which no events are published, reapable PDBs are still deleted and metrics are still exposed")
+	flags.StringVar(&args.ExcludedNamespacesConfigMap, "excluded-namespaces-configmap", "", "ConfigMap in the form namespace/name listing namespaces excluded from scanning, read on every run and merged with --excluded-namespaces")
+	flags.StringVar(&args.ExcludedNamespacesConfigMapKey, "excluded-namespaces-configmap-key", pdbreaper.DefaultExcludedNamespacesConfigMapKey, "Key of the --excluded-namespaces-configmap holding the namespaces, separated by commas or newlines")
+	flags.StringVar(&args.PlatformExclusionsConfigMap, "platform-exclusions-configmap", "", "Platform-owned ConfigMap in the form namespace/name listing mandatory namespace exclusions under key excluded-namespaces, read on every run and always added to the local exclusions")
+	flags.StringSliceVar(&args.ExcludedPDBNames, "exclude-pdb-names", []string{}, "PDBs excluded from scanning, in the form namespace/name or a bare name matching any namespace")
+	flags.StringSliceVar(&args.ProtectedPriorityClasses, "protected-priority-classes", pdbreaper.DefaultProtectedPriorityClasses, "PDBs selecting pods with one of these priority classes are never reaped, set to empty to disable")
+	flags.StringVar(&args.EnforceNamespaceLabel, "enforce-namespace-label", "", "Namespace label in the form key or key=value, e.g. pdb-reaper=enforce, reapable PDBs in namespaces without it are only reported")
+	flags.BoolVar(&args.HonorPausedNamespaces, "honor-paused-namespaces", false, "Skip reaping in namespaces annotated pdb-reaper/paused=true, PDBs are still detected and reported")
+	flags.BoolVar(&args.PausedSkipDetection, "paused-skip-detection", false, "Skip detection as well in namespaces paused by --honor-paused-namespaces")
+	flags.StringVar(&args.PDBLabelRequired, "pdb-label-required", "", "Label in the form key or key=value, e.g. pdb-reaper/managed=true, which PDBs must carry to be considered at all")
+	flags.BoolVar(&args.ReapNotReady, "reap-not-ready", true, "Deletes PDBs which have pods in not-ready state")
+	flags.IntVar(&args.ReapNotReadyThreshold, "not-ready-threshold-seconds", 1800, "Minimum seconds to wait when considering pods in not-ready state")
+	flags.BoolVar(&args.ReadinessProbeGrace, "readiness-probe-grace", false, "Add the longest readiness probe initialDelaySeconds of a pod's containers to --not-ready-threshold-seconds for that pod")
+	flags.BoolVar(&args.NotReadyIncludePending, "not-ready-include-pending", false, "Also count Pending pods whose containers are not ready as not-ready, by default only Running pods are counted")
+	flags.StringVar(&args.ExpectedPodsSource, "expected-pods-source", pdbreaper.ExpectedPodsSourceStatus, "Pod count budgets are resolved against when detecting misconfiguration, one of status,live")
+	flags.BoolVar(&args.EvaluateZeroExpectedPods, "evaluate-zero-expected-pods", false, "Evaluate PDBs expecting 0 pods using the live pod count when their selector matches pods, instead of skipping them")
+	flags.BoolVar(&args.AllNotReady, "all-not-ready", false, "Only deletes PDBs for not-ready pods when all pods are in not-ready state")
+	flags.Float64Var(&args.NotReadyPodFraction, "not-ready-pod-fraction", 0, "Only deletes PDBs for not-ready pods when at least this fraction of pods are in not-ready state, overrides --all-not-ready when set")
+	flags.StringSliceVar(&args.ReapReasonPriority, "reap-reason-priority", []string{}, "Order of reap modes used to pick the primary deletion reason when a PDB is reapable for multiple reasons (default drain-blocking,zero-max-unavailable,misconfigured,duplicate-selector,stale-selector,mixed-controllers,single-node,multiple,crashloop,not-ready,health-score)")
+	flags.BoolVar(&args.CrashLoopPrecedence, "crashloop-precedence", true, "Pods which are counted as crashlooping are not also counted as not-ready")
+	flags.StringSliceVar(&args.NotReadyGateTypes, "not-ready-gate-types", []string{}, "Readiness gate condition types which are also considered when detecting pods in not-ready state")
+	flags.StringVar(&args.OwnerLabel, "owner-label", "team", "PDB label whose distinct values among reaped PDBs are counted in the affected owners metric")
+	flags.BoolVar(&args.StrictRBAC, "strict-rbac", false, "Fail the run when the startup RBAC self-check finds insufficient permissions")
+	flags.BoolVar(&args.SelfTest, "self-test", false, "Create, list and delete a PDB matching no pods before acting on real PDBs, to verify permissions and API availability")
+	flags.StringVar(&args.SelfTestNamespace, "self-test-namespace", pdbreaper.DefaultSelfTestNamespace, "Namespace the --self-test PDB is created in")
+	flags.BoolVar(&args.StrictSelfTest, "strict-self-test", false, "Fail the run when the --self-test fails")
+	flags.BoolVar(&args.NDJSON, "ndjson", false, "Write each detection and deletion to stdout as a line of JSON")
+	flags.BoolVar(&args.StampProcessedGeneration, "stamp-processed-generation", false, "Annotate evaluated PDBs with the generation which was last evaluated")
+	flags.BoolVar(&args.AnnotateWorkloads, "annotate-workloads", false, "Annotate the Deployments and StatefulSets owning the pods of a reaped PDB with its name and the time it was reaped")
+	flags.StringVar(&args.MinKubernetesVersion, "min-kubernetes-version", "", "Fail runs against servers older than this version, e.g. 1.21, and disable options the server version does not support")
+	flags.BoolVar(&args.StrictVersionCheck, "strict-version-check", false, "Fail runs instead of disabling options the server version does not support, requires --min-kubernetes-version")
+	flags.BoolVar(&args.AnnotateDeleteReason, "annotate-delete-reason", false, "Annotate PDBs with the reason they are deleted for right before deleting them, so the reason is recorded in the API audit log")
+	flags.Int64Var(&args.DeleteGraceSeconds, "delete-grace-seconds", -1, "Grace period in seconds passed when deleting PDBs, a negative value uses the API default")
+	flags.StringVar(&args.BackupSink, "backup-sink", "", "Back up PDBs before deleting them, one of secret,object-store")
+	flags.StringVar(&args.BackupSecretNamespace, "backup-secret-namespace", "", "Namespace of the backup Secrets with --backup-sink=secret, defaults to the namespace of each PDB")
+	flags.StringVar(&args.BackupEndpoint, "backup-endpoint", "", "Endpoint of an S3 compatible object store with --backup-sink=object-store, e.g. https://storage.googleapis.com, defaults to S3")
+	flags.StringVar(&args.BackupRegion, "backup-region", pdbreaper.DefaultBackupRegion, "Region of the --backup-bucket")
+	flags.StringVar(&args.BackupBucket, "backup-bucket", "", "Bucket backups are uploaded to with --backup-sink=object-store")
+	flags.BoolVar(&args.ConfirmWithEviction, "confirm-with-eviction-after-delete", false, "After deleting a PDB, issue a dry-run eviction against one of its pods to confirm it can be disrupted")
+	flags.StringVar(&args.CSVOutput, "csv-output", "", "Path of a CSV file to write a row to for each reason a PDB was found reapable for, replaced on every run")
+	flags.StringVar(&args.PlanOut, "plan-out", "", "Path of a plan file to write the deletions of the run to instead of deleting, execute it later with --apply")
+	flags.StringVar(&args.Apply, "apply", "", "Path of a plan file written by --plan-out whose deletions are executed, PDBs which changed since are skipped")
+	flags.StringVar(&args.PromPushgateway, "prometheus-pushgateway", "", "Prometheus pushgateway URL")
+	flags.StringVar(&args.StatsdAddress, "statsd-address", "", "Statsd address in the form host:port to send metrics to as gauges, with dogstatsd tags")
+	flags.BoolVar(&args.BatchMetrics, "batch-metrics", false, "Buffer metric values during a run and send them at once when the run ends, instead of one request per value")
+	flags.StringVar(&args.StatsdPrefix, "statsd-prefix", "", "Prefix added to metric names sent to statsd")
+	flags.StringVar(&args.SummaryEventObject, "summary-event-object", "", "Object in the form kind/namespace/name to publish a run summary event on, whose annotation carries the JSON run result")
+	flags.BoolVar(&args.NamespaceFindingsEvents, "annotate-findings-to-namespace-events", false, "Publish one event per namespace on the Namespace object, summarizing the reapable PDBs of the run in that namespace by reason")
+	flags.BoolVar(&args.NamespaceFindingsOnly, "namespace-findings-only", false, "Only publish the namespace findings events, without per-PDB detection events")
+	flags.StringVar(&args.ErrorWebhook, "report-webhook-on-error", "", "Webhook URL to POST a JSON error summary to when a run fails")
+	flags.StringVar(&args.HTTPProxy, "http-proxy", "", "Proxy URL used by outbound webhook and pushgateway requests, instead of the proxy environment variables")
+	flags.StringVar(&args.HTTPCABundle, "http-ca-bundle", "", "Path to a PEM bundle of CA certificates trusted by outbound webhook and pushgateway requests, in addition to the system roots")
+	flags.Float64Var(&args.MaxReapableRatio, "max-reapable-ratio", 0, "Skip reaping unless confirmed by the next run when the ratio of reapable to scanned PDBs exceeds this value, requires --state-configmap outside of daemon mode (0 disables)")
+	flags.BoolVar(&args.SkipFirstRunReap, "skip-first-run-reap", false, "Only report reapable PDBs in the first run, e.g. of the daemon, requires --state-configmap outside of daemon mode")
+	flags.IntVar(&args.MaxNamespaces, "max-namespaces", 0, "Abort the run when PDBs in more than this many namespaces are in scope, as a safeguard against a wrong exclusion list (0 disables)")
+	flags.IntVar(&args.MaxReapsPerRun, "max-reaps-per-run", 0, "Maximum number of PDBs to delete in a single run, the rest are deferred to the next run (0 disables)")
+	flags.IntVar(&args.MaxWritesPerRun, "max-writes-per-run", 0, "Maximum number of events and annotation updates in a single run, further writes are skipped (0 disables)")
+	flags.BoolVar(&args.DeferReapsOnWriteCap, "defer-reaps-on-write-cap", false, "Defer deletions to the next run once --max-writes-per-run is reached")
+	flags.StringVar(&args.DeletionOrder, "deletion-order", pdbreaper.DeletionOrderDiscovery, "Order reapable PDBs are deleted in, one of discovery,oldest-first,most-pods-first")
+	flags.BoolVar(&args.NamespaceFairness, "namespace-concurrency-fairness", false, "Delete reapable PDBs round-robin across namespaces, so that --max-reaps-per-run is spread across namespaces")
+	flags.BoolVar(&args.CheckDisruptionController, "check-disruption-controller", false, "Skip reaping when the PDB status looks stale, which indicates the disruption controller is unhealthy")
+	flags.Float64Var(&args.StaleStatusRatio, "stale-status-ratio", pdbreaper.DefaultStaleStatusRatio, "Ratio of scanned PDBs whose status has not observed their latest generation at which the status is considered stale")
+	flags.DurationVar(&args.ReapCooldown, "reap-cooldown", 0, "Skip reaping a recreated PDB if a PDB with the same namespace/name was reaped within this duration, requires --state-configmap outside of daemon mode (0 disables)")
+	flags.DurationVar(&args.RecreateWindow, "recreate-window", 0, "Publish a warning event and count PDBs recreated within this duration of being reaped, requires --state-configmap outside of daemon mode (0 disables)")
+	flags.DurationVar(&args.UpdateDebounce, "update-debounce", 0, "Evaluate a PDB updated since its last evaluation at most once per this duration, so that a PDB which keeps updating is not acted on while it changes, requires --state-configmap outside of daemon mode (0 disables)")
+	flags.IntVar(&args.BlockingRuns, "blocking-runs", 1, "Consecutive runs a PDB must allow 0 disruptions before it is considered blocking, requires --state-configmap outside of daemon mode when greater than 1")
+	flags.StringSliceVar(&args.ReportOnlyThreshold, "report-only-threshold", []string{}, "Consecutive runs a PDB is only reported before it is reaped, in the form mode=runs or runs for all reap modes, requires --state-configmap outside of daemon mode")
+	flags.DurationVar(&args.MaxAgeToConsider, "max-age-to-consider", 0, "Ignore PDBs created longer than this duration ago, which are assumed to be intentional (0 disables)")
+	flags.DurationVar(&args.PDBTimeout, "pdb-timeout", 0, "Maximum time to spend evaluating a PDB, including its pod lists, re-lists and detections, a PDB whose evaluation times out is skipped for the run (0 disables)")
+	flags.IntVar(&args.PodCountRetries, "pod-count-retries", 0, "Re-list the pods of a PDB up to this many times when fewer pods than its expected pods are listed, before making a reap decision (0 disables)")
+	flags.DurationVar(&args.PodCountRetryDelay, "pod-count-retry-delay", time.Second, "Delay before re-listing the pods of a PDB when fewer pods than expected are listed")
+	flags.IntVar(&args.ThrottleRetries, "throttle-retries", pdbreaper.DefaultThrottleRetries, "Retry API server requests throttled with 429 Too Many Requests without Retry-After up to this many times, requests with Retry-After are retried by the client (0 disables)")
+	flags.DurationVar(&args.ThrottleBackoff, "throttle-backoff", pdbreaper.DefaultThrottleBackoff, "Initial backoff between retries of throttled API server requests without Retry-After, doubled on each retry")
+	flags.DurationVar(&args.ThrottleMaxWait, "throttle-max-wait", pdbreaper.DefaultThrottleMaxWait, "Maximum backoff before retrying an API server request throttled without Retry-After")
+	flags.IntVar(&args.ProgressEveryNamespaces, "progress-every-namespaces", 0, "Log progress every N namespaces evaluated (0 disables)")
+	flags.DurationVar(&args.ProgressInterval, "progress-interval", 30*time.Second, "Log progress when this much time has passed since the last progress log (0 disables)")
+	flags.StringVar(&args.ReapWindow, "reap-window", "", "Only delete PDBs within this daily time window in the form HH:MM-HH:MM, outside of it PDBs are detected but deletion is deferred")
+	flags.StringVar(&args.ReapWindowTimezone, "reap-window-timezone", "UTC", "IANA timezone of --reap-window")
+	flags.StringVar(&args.StateConfigMap, "state-configmap", "", "ConfigMap in the form namespace/name used to persist state between runs")
+}
+
+// loadPdbReaperArgs returns the arguments from the flags set on the command line, overridden by the values in the config
+// file, which is a YAML map of flag names to values
+func loadPdbReaperArgs(cmdFlags *pflag.FlagSet, configFile string) (*pdbreaper.Args, error) {
+	args := &pdbreaper.Args{}
+	flags := pflag.NewFlagSet("pdb", pflag.ContinueOnError)
+	addPdbReaperFlags(flags, args)
+
+	var err error
+	cmdFlags.Visit(func(f *pflag.Flag) {
+		target := flags.Lookup(f.Name)
+		if err != nil || target == nil {
+			return
+		}
+		if slice, ok := f.Value.(pflag.SliceValue); ok {
+			err = target.Value.(pflag.SliceValue).Replace(slice.GetSlice())
+			return
+		}
+		err = flags.Set(f.Name, f.Value.String())
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to apply command line flags")
+	}
+
+	data, err := os.ReadFile(configFile)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read config file %v", configFile)
+	}
+	values := make(map[string]interface{})
+	if err = yaml.Unmarshal(data, &values); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse config file %v", configFile)
+	}
+
+	for name, value := range values {
+		if flags.Lookup(name) == nil {
+			return nil, errors.Errorf("unknown flag '%v' in config file %v", name, configFile)
+		}
+		var s string
+		switch v := value.(type) {
+		case []interface{}:
+			items := make([]string, 0, len(v))
+			for _, item := range v {
+				items = append(items, fmt.Sprint(item))
+			}
+			s = strings.Join(items, ",")
+		default:
+			s = fmt.Sprint(v)
+		}
+		if err = flags.Set(name, s); err != nil {
+			return nil, errors.Wrapf(err, "invalid value for '%v' in config file %v", name, configFile)
+		}
+	}
+	return args, nil
 }
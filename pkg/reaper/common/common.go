@@ -54,14 +54,24 @@ func PathExists(path string) bool {
 	return false
 }
 
+// ConfigOption modifies a client config before the client is created
+type ConfigOption func(*rest.Config)
+
+func applyConfigOptions(config *rest.Config, opts []ConfigOption) {
+	for _, opt := range opts {
+		opt(config)
+	}
+}
+
 // InClusterAuth returns an in-cluster kubernetes client
-func InClusterAuth() (*kubernetes.Clientset, error) {
+func InClusterAuth(opts ...ConfigOption) (*kubernetes.Clientset, error) {
 	Log.Infoln("starting in-cluster auth")
 
 	config, err := rest.InClusterConfig()
 	if err != nil {
 		return &kubernetes.Clientset{}, err
 	}
+	applyConfigOptions(config, opts)
 
 	clientset, err := kubernetes.NewForConfig(config)
 	if err != nil {
@@ -72,7 +82,7 @@ func InClusterAuth() (*kubernetes.Clientset, error) {
 }
 
 // OutOfClusterAuth returns an external kubernetes client
-func OutOfClusterAuth(providedConfigPath string) (*kubernetes.Clientset, error) {
+func OutOfClusterAuth(providedConfigPath string, opts ...ConfigOption) (*kubernetes.Clientset, error) {
 	Log.Infoln("starting cluster external auth")
 
 	var configPath string
@@ -95,6 +105,35 @@ func OutOfClusterAuth(providedConfigPath string) (*kubernetes.Clientset, error)
 	}
 
 	Log.Infof("target: %v\n", config.Host)
+	applyConfigOptions(config, opts)
+
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return &kubernetes.Clientset{}, err
+	}
+
+	return clientset, nil
+}
+
+// OutOfClusterContextAuth returns an external kubernetes client for a context in the given kubeconfig, when the context
+// is empty the current context is used
+func OutOfClusterContextAuth(configPath, kubeContext string, opts ...ConfigOption) (*kubernetes.Clientset, error) {
+	if kubeContext == "" {
+		return OutOfClusterAuth(configPath, opts...)
+	}
+	Log.Infoln("starting cluster external auth")
+	Log.Infof("kubeconfig: %v, context: %v\n", configPath, kubeContext)
+
+	config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		&clientcmd.ClientConfigLoadingRules{ExplicitPath: configPath},
+		&clientcmd.ConfigOverrides{CurrentContext: kubeContext},
+	).ClientConfig()
+	if err != nil {
+		return &kubernetes.Clientset{}, err
+	}
+
+	Log.Infof("target: %v\n", config.Host)
+	applyConfigOptions(config, opts)
 
 	clientset, err := kubernetes.NewForConfig(config)
 	if err != nil {
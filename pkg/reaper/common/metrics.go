@@ -1,6 +1,133 @@
 package common
 
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+)
+
 type MetricsAPI interface {
 	// Set Metric value on metric
 	SetMetricValue(metricName string, tags map[string]string, value float64) error
 }
+
+// ContextMetricsAPI is implemented by a MetricsAPI which can abandon setting a metric value when the context is done
+type ContextMetricsAPI interface {
+	MetricsAPI
+	SetMetricValueContext(ctx context.Context, metricName string, tags map[string]string, value float64) error
+}
+
+// HistogramMetricsAPI is implemented by a MetricsAPI which can record observations of a histogram, rather than setting
+// the value of a gauge
+type HistogramMetricsAPI interface {
+	MetricsAPI
+	ObserveMetricValue(metricName string, tags map[string]string, value float64) error
+}
+
+// MetricValue is a single value of a metric
+type MetricValue struct {
+	Name  string
+	Tags  map[string]string
+	Value float64
+}
+
+// BatchMetricsAPI is implemented by a MetricsAPI which can send many metric values at once
+type BatchMetricsAPI interface {
+	MetricsAPI
+	SetMetricValues(values []MetricValue) error
+}
+
+// BufferedMetricsAPI buffers metric values until they are flushed to the wrapped MetricsAPI, only the last value of
+// a metric with the same tags is kept
+type BufferedMetricsAPI struct {
+	API MetricsAPI
+
+	mu     sync.Mutex
+	values []MetricValue
+	index  map[string]int
+}
+
+func NewBufferedMetricsAPI(api MetricsAPI) *BufferedMetricsAPI {
+	return &BufferedMetricsAPI{API: api}
+}
+
+// SetMetricValue buffers a metric value until the next Flush
+func (a *BufferedMetricsAPI) SetMetricValue(metricName string, tags map[string]string, value float64) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	copied := make(map[string]string, len(tags))
+	for k, v := range tags {
+		copied[k] = v
+	}
+	metric := MetricValue{Name: metricName, Tags: copied, Value: value}
+
+	if a.index == nil {
+		a.index = make(map[string]int)
+	}
+	key := metricKey(metricName, tags)
+	if i, ok := a.index[key]; ok {
+		a.values[i] = metric
+		return nil
+	}
+	a.index[key] = len(a.values)
+	a.values = append(a.values, metric)
+	return nil
+}
+
+// ObserveMetricValue records a histogram observation on the wrapped MetricsAPI immediately, since observations of the
+// same metric must not replace each other, a wrapped MetricsAPI without histograms gets a buffered gauge instead
+func (a *BufferedMetricsAPI) ObserveMetricValue(metricName string, tags map[string]string, value float64) error {
+	if api, ok := a.API.(HistogramMetricsAPI); ok {
+		return api.ObserveMetricValue(metricName, tags, value)
+	}
+	return a.SetMetricValue(metricName, tags, value)
+}
+
+// Buffered returns the number of metric values waiting to be flushed
+func (a *BufferedMetricsAPI) Buffered() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return len(a.values)
+}
+
+// Flush sends the buffered metric values to the wrapped MetricsAPI, in a single call when it is a BatchMetricsAPI,
+// the buffer is emptied even when sending fails
+func (a *BufferedMetricsAPI) Flush() error {
+	a.mu.Lock()
+	values := a.values
+	a.values, a.index = nil, nil
+	a.mu.Unlock()
+
+	if len(values) == 0 {
+		return nil
+	}
+	if api, ok := a.API.(BatchMetricsAPI); ok {
+		return api.SetMetricValues(values)
+	}
+
+	var firstErr error
+	for _, metric := range values {
+		if err := a.API.SetMetricValue(metric.Name, metric.Tags, metric.Value); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// metricKey identifies a metric by its name and tags sorted by key
+func metricKey(metricName string, tags map[string]string) string {
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	b.WriteString(metricName)
+	for _, key := range keys {
+		b.WriteString("," + key + "=" + tags[key])
+	}
+	return b.String()
+}
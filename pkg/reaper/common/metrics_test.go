@@ -0,0 +1,74 @@
+package common
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeBatchMetricsAPI struct {
+	single  int
+	batches [][]MetricValue
+}
+
+func (a *fakeBatchMetricsAPI) SetMetricValue(metricName string, tags map[string]string, value float64) error {
+	a.single++
+	return nil
+}
+
+func (a *fakeBatchMetricsAPI) SetMetricValues(values []MetricValue) error {
+	a.batches = append(a.batches, values)
+	return nil
+}
+
+func TestBufferedMetricsAPI(t *testing.T) {
+	backend := &fakeBatchMetricsAPI{}
+	api := NewBufferedMetricsAPI(backend)
+
+	tags := map[string]string{"namespace": "namespace-1", "pdb": "pdb-1"}
+	assert.NoError(t, api.SetMetricValue("pdb_reaper_result", tags, 0))
+	assert.NoError(t, api.SetMetricValue("pdb_reaper_matched_pods", tags, 3))
+	assert.NoError(t, api.SetMetricValue("pdb_reaper_result", map[string]string{"pdb": "pdb-1", "namespace": "namespace-1"}, 1))
+	assert.NoError(t, api.SetMetricValue("pdb_reaper_reaped", nil, 1))
+	assert.Equal(t, 3, api.Buffered())
+	assert.Empty(t, backend.batches)
+
+	assert.NoError(t, api.Flush())
+	assert.Equal(t, 0, backend.single)
+	assert.Len(t, backend.batches, 1)
+	assert.Equal(t, []MetricValue{
+		{Name: "pdb_reaper_result", Tags: tags, Value: 1},
+		{Name: "pdb_reaper_matched_pods", Tags: tags, Value: 3},
+		{Name: "pdb_reaper_reaped", Tags: map[string]string{}, Value: 1},
+	}, backend.batches[0])
+
+	// the buffer is emptied by a flush
+	assert.Equal(t, 0, api.Buffered())
+	assert.NoError(t, api.Flush())
+	assert.Len(t, backend.batches, 1)
+}
+
+func TestBufferedMetricsAPI_Statsd(t *testing.T) {
+	listener, err := net.ListenPacket("udp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer listener.Close()
+
+	api := NewBufferedMetricsAPI(NewStatsdAPI(listener.LocalAddr().String(), ""))
+	assert.NoError(t, api.SetMetricValue("pdb_reaper_result", map[string]string{"pdb": "pdb-1"}, 1))
+	assert.NoError(t, api.SetMetricValue("pdb_reaper_result", map[string]string{"pdb": "pdb-2"}, 0))
+	assert.NoError(t, api.SetMetricValue("pdb_reaper_reaped", nil, 1))
+	assert.NoError(t, api.Flush())
+
+	buf := make([]byte, StatsdMaxPacketSize)
+	assert.NoError(t, listener.SetReadDeadline(time.Now().Add(5*time.Second)))
+	n, _, err := listener.ReadFrom(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, strings.Join([]string{
+		"pdb_reaper_result:1|g|#pdb:pdb-1",
+		"pdb_reaper_result:0|g|#pdb:pdb-2",
+		"pdb_reaper_reaped:1|g",
+	}, "\n"), string(buf[:n]))
+}
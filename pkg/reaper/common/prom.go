@@ -1,6 +1,10 @@
 package common
 
 import (
+	"context"
+	"net/http"
+	"strings"
+
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/push"
 	log "github.com/sirupsen/logrus"
@@ -10,6 +14,8 @@ import (
 type PrometheusAPI struct {
 	//Base URL
 	Pushgateway string
+	// Client is used to push metrics, http.DefaultClient is used when nil
+	Client *http.Client
 }
 
 func NewPrometheusAPI(pushgateway string) *PrometheusAPI {
@@ -17,19 +23,80 @@ func NewPrometheusAPI(pushgateway string) *PrometheusAPI {
 }
 
 func (a *PrometheusAPI) SetMetricValue(metricName string, tags map[string]string, value float64) error {
-	newMetric := prometheus.NewGauge(prometheus.GaugeOpts{
+	return a.SetMetricValueContext(context.Background(), metricName, tags, value)
+}
+
+// SetMetricValueContext pushes a metric value, the push is abandoned when the context is done
+func (a *PrometheusAPI) SetMetricValueContext(ctx context.Context, metricName string, tags map[string]string, value float64) error {
+	return a.push(ctx, tags, []MetricValue{{Name: metricName, Tags: tags, Value: value}})
+}
+
+// ObserveMetricValue pushes a histogram with a single observation, the pushgateway keeps the last push of each metric
+// name in a grouping key, so the tags should identify what is observed
+func (a *PrometheusAPI) ObserveMetricValue(metricName string, tags map[string]string, value float64) error {
+	histogram := prometheus.NewHistogram(prometheus.HistogramOpts{
 		Name: metricName,
 		Help: "new metric generated by governor",
 	})
-	newMetric.Set(value)
-	var pusher = push.New(a.Pushgateway, "governor").Collector(newMetric)
+	histogram.Observe(value)
+	return a.pushCollectors(context.Background(), tags, []string{metricName}, histogram)
+}
+
+// SetMetricValues pushes the metric values with one push per distinct set of tags, since tags are the grouping key
+func (a *PrometheusAPI) SetMetricValues(values []MetricValue) error {
+	groups := make(map[string][]MetricValue)
+	keys := make([]string, 0)
+	for _, metric := range values {
+		key := metricKey("", metric.Tags)
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], metric)
+	}
+
+	var firstErr error
+	for _, key := range keys {
+		group := groups[key]
+		if err := a.push(context.Background(), group[0].Tags, group); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// push pushes metric values sharing the same tags in a single request
+func (a *PrometheusAPI) push(ctx context.Context, tags map[string]string, values []MetricValue) error {
+	names := make([]string, 0, len(values))
+	collectors := make([]prometheus.Collector, 0, len(values))
+	for _, metric := range values {
+		newMetric := prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: metric.Name,
+			Help: "new metric generated by governor",
+		})
+		newMetric.Set(metric.Value)
+		collectors = append(collectors, newMetric)
+		names = append(names, metric.Name)
+	}
+	return a.pushCollectors(ctx, tags, names, collectors...)
+}
+
+// pushCollectors pushes collectors sharing the same tags in a single request, only the pushed metrics are replaced in
+// the grouping key, so metrics pushed separately with the same tags don't replace each other
+func (a *PrometheusAPI) pushCollectors(ctx context.Context, tags map[string]string, names []string, collectors ...prometheus.Collector) error {
+	var pusher = push.New(a.Pushgateway, "governor")
+	for _, collector := range collectors {
+		pusher.Collector(collector)
+	}
+	if a.Client != nil {
+		pusher.Client(a.Client)
+	}
 	// Copy from the original map to the target map
 	for key, value := range tags {
 		pusher.Grouping(key, value)
 	}
 
-	if err := pusher.Push(); err != nil {
-		log.Warnf("failed to push metric to pushgateway: %s, %v", metricName, tags)
+	if err := pusher.AddContext(ctx); err != nil {
+		log.Warnf("failed to push metric to pushgateway: %s, %v", strings.Join(names, ","), tags)
 		return err
 	}
 	return nil
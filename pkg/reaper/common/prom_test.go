@@ -1,11 +1,21 @@
 package common
 
 import (
+	"context"
+	"encoding/base64"
+	"errors"
 	"github.com/stretchr/testify/assert"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
 	"testing"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
 )
 
 func TestAPIs(t *testing.T) {
@@ -39,4 +49,127 @@ func TestAPIs(t *testing.T) {
 
 	err := api.SetMetricValue("abc", tags, 50)
 	assert.Nil(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = api.SetMetricValueContext(ctx, "abc", tags, 50)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+// fakePushgateway keeps the pushed metrics like a pushgateway, a PUT replaces every metric of the grouping key, a POST
+// only replaces the metrics with the same name
+type fakePushgateway struct {
+	*httptest.Server
+
+	mu      sync.Mutex
+	metrics map[string]map[string]*dto.MetricFamily
+}
+
+func newFakePushgateway(t *testing.T) *fakePushgateway {
+	pgw := &fakePushgateway{metrics: make(map[string]map[string]*dto.MetricFamily)}
+	pgw.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pgw.mu.Lock()
+		defer pgw.mu.Unlock()
+		key, err := groupingKey(r.URL.Path)
+		if err != nil {
+			t.Errorf("invalid grouping key %v: %v", r.URL.Path, err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		group := pgw.metrics[key]
+		if group == nil || r.Method == http.MethodPut {
+			group = make(map[string]*dto.MetricFamily)
+			pgw.metrics[key] = group
+		}
+		decoder := expfmt.NewDecoder(r.Body, expfmt.ResponseFormat(r.Header))
+		for {
+			family := &dto.MetricFamily{}
+			if err := decoder.Decode(family); err != nil {
+				if err != io.EOF {
+					t.Errorf("failed to decode pushed metrics: %v", err)
+				}
+				break
+			}
+			group[family.GetName()] = family
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	return pgw
+}
+
+// groupingKey returns the labels of the grouping key in a push URL path, the pushgateway doesn't depend on their order
+func groupingKey(path string) (string, error) {
+	components := strings.Split(strings.TrimPrefix(path, "/metrics/"), "/")
+	if len(components)%2 != 0 {
+		return "", errors.New("expected label name and value pairs")
+	}
+	labels := make(map[string]string)
+	for i := 0; i < len(components); i += 2 {
+		name, value := components[i], components[i+1]
+		if strings.HasSuffix(name, "@base64") {
+			decoded, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(value, "="))
+			if err != nil {
+				return "", err
+			}
+			name, value = strings.TrimSuffix(name, "@base64"), string(decoded)
+		}
+		labels[name] = value
+	}
+	return labelsKey(labels), nil
+}
+
+// labelsKey returns the sorted label pairs of a grouping key
+func labelsKey(labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for name, value := range labels {
+		pairs = append(pairs, name+"="+value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+// pushedGroupingKey returns the grouping key of metrics pushed with the tags
+func pushedGroupingKey(tags map[string]string) string {
+	labels := map[string]string{"job": "governor"}
+	for key, value := range tags {
+		labels[key] = value
+	}
+	return labelsKey(labels)
+}
+
+// names returns the names of the metrics kept in the grouping key of the tags
+func (p *fakePushgateway) names(tags map[string]string) []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	names := make([]string, 0)
+	for name := range p.metrics[pushedGroupingKey(tags)] {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestPushKeepsMetricsWithSameTags(t *testing.T) {
+	pgw := newFakePushgateway(t)
+	defer pgw.Close()
+	api := PrometheusAPI{Pushgateway: pgw.URL}
+
+	tags := map[string]string{"cluster": "cluster-1"}
+	assert.Nil(t, api.SetMetricValue("governor_pdb_reaper_reapable_detected", tags, 3))
+	assert.Nil(t, api.SetMetricValue("governor_pdb_reaper_reaped", tags, 1))
+	assert.Nil(t, api.ObserveMetricValue("governor_pdb_reaper_evaluation_seconds", tags, 0.5))
+	assert.Nil(t, api.SetMetricValues([]MetricValue{{Name: "governor_pdb_reaper_suppressed_writes", Tags: tags, Value: 2}}))
+	assert.Equal(t, []string{
+		"governor_pdb_reaper_evaluation_seconds",
+		"governor_pdb_reaper_reapable_detected",
+		"governor_pdb_reaper_reaped",
+		"governor_pdb_reaper_suppressed_writes",
+	}, pgw.names(tags))
+
+	// pushing a metric again replaces its value
+	assert.Nil(t, api.SetMetricValue("governor_pdb_reaper_reaped", tags, 2))
+	pgw.mu.Lock()
+	value := pgw.metrics[pushedGroupingKey(tags)]["governor_pdb_reaper_reaped"].GetMetric()[0].GetGauge().GetValue()
+	pgw.mu.Unlock()
+	assert.Equal(t, float64(2), value)
 }
@@ -0,0 +1,124 @@
+package common
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// StatsdDialTimeout is the maximum time to wait when connecting to statsd
+const StatsdDialTimeout = 5 * time.Second
+
+// StatsdMaxPacketSize is the maximum size of a packet of batched metric values, which fits the usual network MTU
+const StatsdMaxPacketSize = 1432
+
+// API for sending metrics to statsd as gauges, tags are sent in the dogstatsd format
+type StatsdAPI struct {
+	// Address of the statsd agent in the form host:port
+	Address string
+	// Prefix added to metric names, separated by a dot
+	Prefix string
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+func NewStatsdAPI(address, prefix string) *StatsdAPI {
+	return &StatsdAPI{Address: address, Prefix: prefix}
+}
+
+func (a *StatsdAPI) SetMetricValue(metricName string, tags map[string]string, value float64) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return a.send(metricName, a.gaugeLine(metricName, tags, value))
+}
+
+// ObserveMetricValue sends a histogram observation
+func (a *StatsdAPI) ObserveMetricValue(metricName string, tags map[string]string, value float64) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return a.send(metricName, a.metricLine(metricName, "h", tags, value))
+}
+
+// SetMetricValues sends the metric values as newline separated gauges, in as few packets as StatsdMaxPacketSize allows
+func (a *StatsdAPI) SetMetricValues(values []MetricValue) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	var packet strings.Builder
+	for _, metric := range values {
+		line := a.gaugeLine(metric.Name, metric.Tags, metric.Value)
+		if packet.Len() > 0 && packet.Len()+1+len(line) > StatsdMaxPacketSize {
+			if err := a.send(fmt.Sprintf("%v metrics", len(values)), packet.String()); err != nil {
+				return err
+			}
+			packet.Reset()
+		}
+		if packet.Len() > 0 {
+			packet.WriteString("\n")
+		}
+		packet.WriteString(line)
+	}
+	if packet.Len() == 0 {
+		return nil
+	}
+	return a.send(fmt.Sprintf("%v metrics", len(values)), packet.String())
+}
+
+// send writes a packet to statsd, connecting first if needed
+func (a *StatsdAPI) send(metricName, packet string) error {
+	if a.conn == nil {
+		conn, err := net.DialTimeout("udp", a.Address, StatsdDialTimeout)
+		if err != nil {
+			log.Warnf("failed to connect to statsd: %s, %v", a.Address, err)
+			return errors.Wrapf(err, "failed to connect to statsd at %v", a.Address)
+		}
+		a.conn = conn
+	}
+
+	if _, err := a.conn.Write([]byte(packet)); err != nil {
+		log.Warnf("failed to send metric to statsd: %s", metricName)
+		// the connection is re-established on the next metric
+		a.conn.Close()
+		a.conn = nil
+		return errors.Wrapf(err, "failed to send metric %v to statsd", metricName)
+	}
+	return nil
+}
+
+// gaugeLine returns a gauge in the form prefix.name:value|g|#key:value,key:value with tags sorted by key
+func (a *StatsdAPI) gaugeLine(metricName string, tags map[string]string, value float64) string {
+	return a.metricLine(metricName, "g", tags, value)
+}
+
+// metricLine returns a metric of the given statsd type in the form prefix.name:value|type|#key:value,key:value
+func (a *StatsdAPI) metricLine(metricName, metricType string, tags map[string]string, value float64) string {
+	name := metricName
+	if a.Prefix != "" {
+		name = fmt.Sprintf("%v.%v", a.Prefix, metricName)
+	}
+	line := fmt.Sprintf("%v:%v|%v", name, strconv.FormatFloat(value, 'f', -1, 64), metricType)
+
+	if len(tags) == 0 {
+		return line
+	}
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		pairs = append(pairs, fmt.Sprintf("%v:%v", key, tags[key]))
+	}
+	return fmt.Sprintf("%v|#%v", line, strings.Join(pairs, ","))
+}
@@ -0,0 +1,58 @@
+package common
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatsdAPI(t *testing.T) {
+	listener, err := net.ListenPacket("udp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer listener.Close()
+
+	api := NewStatsdAPI(listener.LocalAddr().String(), "governor")
+	tags := map[string]string{
+		"pdb":       "pdb-1",
+		"namespace": "namespace-1",
+	}
+	err = api.SetMetricValue("pdb_reaper_result", tags, 1)
+	assert.NoError(t, err)
+
+	buf := make([]byte, 1024)
+	assert.NoError(t, listener.SetReadDeadline(time.Now().Add(5*time.Second)))
+	n, _, err := listener.ReadFrom(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, "governor.pdb_reaper_result:1|g|#namespace:namespace-1,pdb:pdb-1", string(buf[:n]))
+
+	api.Prefix = ""
+	err = api.SetMetricValue("pdb_reaper_ratio", nil, 0.25)
+	assert.NoError(t, err)
+	n, _, err = listener.ReadFrom(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, "pdb_reaper_ratio:0.25|g", string(buf[:n]))
+}
+
+func TestStatsdAPI_ObserveMetricValue(t *testing.T) {
+	listener, err := net.ListenPacket("udp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer listener.Close()
+
+	api := NewStatsdAPI(listener.LocalAddr().String(), "governor")
+	err = api.ObserveMetricValue("pdb_reaper_evaluation_seconds", map[string]string{"reason": "None"}, 0.5)
+	assert.NoError(t, err)
+
+	buf := make([]byte, 1024)
+	assert.NoError(t, listener.SetReadDeadline(time.Now().Add(5*time.Second)))
+	n, _, err := listener.ReadFrom(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, "governor.pdb_reaper_evaluation_seconds:0.5|h|#reason:None", string(buf[:n]))
+}
+
+func TestStatsdAPI_ConnectionFailure(t *testing.T) {
+	api := NewStatsdAPI("invalid-address", "")
+	err := api.SetMetricValue("pdb_reaper_result", nil, 1)
+	assert.Error(t, err)
+}
@@ -0,0 +1,41 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pdbreaper
+
+import (
+	authorizationv1 "k8s.io/api/authorization/v1"
+	policyv1 "k8s.io/api/policy/v1"
+)
+
+// DeleteReasonAnnotationKey is set on a PDB right before it is deleted, so the object in the audit record of the
+// delete request carries the reason
+const DeleteReasonAnnotationKey = "pdb-reaper/delete-reason"
+
+// DeleteReasonPermissions are the additional permissions needed when --annotate-delete-reason is set
+var DeleteReasonPermissions = []authorizationv1.ResourceAttributes{
+	{Verb: "patch", Group: "policy", Resource: "poddisruptionbudgets"},
+}
+
+// annotateDeleteReason sets the reason a PDB is about to be deleted for on it, failures are logged and do not prevent
+// the deletion
+func (ctx *ReaperContext) annotateDeleteReason(pdb policyv1.PodDisruptionBudget, reason Reason) {
+	if !ctx.AnnotateDeleteReason {
+		return
+	}
+	if err := ctx.patchAnnotations(pdb, map[string]interface{}{DeleteReasonAnnotationKey: reason.String()}); err != nil {
+		log.Warnf(err.Error())
+	}
+}
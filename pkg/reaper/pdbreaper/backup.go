@@ -0,0 +1,223 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pdbreaper
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/pkg/errors"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	BackupSinkSecret      = "secret"
+	BackupSinkObjectStore = "object-store"
+
+	DefaultBackupRegion = "us-east-1"
+
+	BackupLabelKey           = "pdb-reaper/backup"
+	BackupOfAnnotationKey    = "pdb-reaper/backup-of"
+	BackedUpAtAnnotationKey  = "pdb-reaper/backed-up-at"
+	BackupSecretNamePrefix   = "pdb-reaper-backup-"
+	BackupSecretDataKey      = "pdb.yaml"
+	BackupObjectContentType  = "application/yaml"
+	BackupObjectKeyExtension = ".yaml"
+)
+
+// BackupSinks are the supported --backup-sink values
+var BackupSinks = [...]string{BackupSinkSecret, BackupSinkObjectStore}
+
+// BackupPermissions are the additional permissions needed when --backup-sink=secret is set
+var BackupPermissions = []authorizationv1.ResourceAttributes{
+	{Verb: "create", Group: "", Resource: "secrets"},
+	{Verb: "update", Group: "", Resource: "secrets"},
+}
+
+// BackupSink stores the manifest of a PDB before it is deleted
+type BackupSink interface {
+	Store(pdb policyv1.PodDisruptionBudget, manifest []byte, backedUpAt time.Time) error
+	String() string
+}
+
+// SecretBackupSink stores each backup in a Secret named after the PDB, replacing the previous backup
+type SecretBackupSink struct {
+	Client    kubernetes.Interface
+	Context   context.Context
+	Namespace string
+}
+
+// Store creates or updates the backup Secret of a PDB, in the namespace of the PDB unless a namespace is set
+func (s *SecretBackupSink) Store(pdb policyv1.PodDisruptionBudget, manifest []byte, backedUpAt time.Time) error {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      backupSecretName(pdb),
+			Namespace: s.secretNamespace(pdb),
+			Labels:    map[string]string{BackupLabelKey: "true"},
+			Annotations: map[string]string{
+				BackupOfAnnotationKey:   pdbNamespacedName(pdb),
+				BackedUpAtAnnotationKey: backedUpAt.UTC().Format(time.RFC3339),
+			},
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{BackupSecretDataKey: manifest},
+	}
+
+	secrets := s.Client.CoreV1().Secrets(secret.Namespace)
+	_, err := secrets.Create(s.Context, secret, metav1.CreateOptions{})
+	if kerrors.IsAlreadyExists(err) {
+		_, err = secrets.Update(s.Context, secret, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to store backup secret %v/%v", secret.Namespace, secret.Name)
+	}
+	return nil
+}
+
+func (s *SecretBackupSink) secretNamespace(pdb policyv1.PodDisruptionBudget) string {
+	if s.Namespace != "" {
+		return s.Namespace
+	}
+	return pdb.GetNamespace()
+}
+
+func (s *SecretBackupSink) String() string {
+	if s.Namespace != "" {
+		return fmt.Sprintf("secret in namespace %v", s.Namespace)
+	}
+	return "secret"
+}
+
+// backupSecretName returns the name of the backup Secret of a PDB, the namespace is included so backups of PDBs from
+// different namespaces do not collide in a shared --backup-secret-namespace
+func backupSecretName(pdb policyv1.PodDisruptionBudget) string {
+	return fmt.Sprintf("%v%v.%v", BackupSecretNamePrefix, pdb.GetNamespace(), pdb.GetName())
+}
+
+// ObjectStoreBackupSink stores each backup as an object in an S3 compatible bucket, e.g. S3 or GCS through its
+// interoperability endpoint, keeping every backup
+type ObjectStoreBackupSink struct {
+	S3      s3iface.S3API
+	Context context.Context
+	Bucket  string
+	Cluster string
+}
+
+// NewObjectStoreBackupSink returns an object store sink for the bucket, using the default AWS credential chain, the
+// endpoint is only needed for object stores other than S3
+func NewObjectStoreBackupSink(endpoint, region, bucket string) *ObjectStoreBackupSink {
+	config := aws.Config{
+		Region: aws.String(region),
+	}
+	if endpoint != "" {
+		config.Endpoint = aws.String(endpoint)
+		config.S3ForcePathStyle = aws.Bool(true)
+	}
+	sess := session.Must(session.NewSessionWithOptions(session.Options{
+		SharedConfigState: session.SharedConfigEnable,
+		Config:            config,
+	}))
+	return &ObjectStoreBackupSink{
+		S3:     s3.New(sess),
+		Bucket: bucket,
+	}
+}
+
+// Store uploads the backup of a PDB to <cluster>/<namespace>/<name>/<timestamp>.yaml
+func (s *ObjectStoreBackupSink) Store(pdb policyv1.PodDisruptionBudget, manifest []byte, backedUpAt time.Time) error {
+	key := path.Join(s.Cluster, pdb.GetNamespace(), pdb.GetName(), backedUpAt.UTC().Format("20060102T150405Z")+BackupObjectKeyExtension)
+	ctx := s.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	_, err := s.S3.PutObjectWithContext(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(s.Bucket),
+		Key:         aws.String(key),
+		Body:        bytes.NewReader(manifest),
+		ContentType: aws.String(BackupObjectContentType),
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to upload backup to s3://%v/%v", s.Bucket, key)
+	}
+	return nil
+}
+
+func (s *ObjectStoreBackupSink) String() string {
+	return fmt.Sprintf("bucket %v", s.Bucket)
+}
+
+// backupSink returns the sink of --backup-sink bound to the current cluster, nil when backups are disabled
+func (ctx *ReaperContext) backupSink() BackupSink {
+	switch sink := ctx.BackupSink.(type) {
+	case *SecretBackupSink:
+		sink.Client = ctx.KubernetesClient
+		sink.Context = ctx.runContext()
+	case *ObjectStoreBackupSink:
+		sink.Context = ctx.runContext()
+		sink.Cluster = ctx.ClusterName
+	}
+	return ctx.BackupSink
+}
+
+// backupPodDisruptionBudget stores the manifest of a PDB in the backup sink before it is deleted
+func (ctx *ReaperContext) backupPodDisruptionBudget(pdb policyv1.PodDisruptionBudget) error {
+	sink := ctx.backupSink()
+	if sink == nil {
+		return nil
+	}
+
+	manifest, err := yaml.Marshal(newBackupManifest(pdb))
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal backup of PDB %v", pdbNamespacedName(pdb))
+	}
+	if err := sink.Store(pdb, manifest, ctx.now()); err != nil {
+		return err
+	}
+	log.Infof("backed up PDB %v to %v", pdbNamespacedName(pdb), sink)
+	return nil
+}
+
+// newBackupManifest returns a PDB without status and server populated metadata, so it can be applied to restore it
+func newBackupManifest(pdb policyv1.PodDisruptionBudget) *policyv1.PodDisruptionBudget {
+	backup := &policyv1.PodDisruptionBudget{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: policyv1.SchemeGroupVersion.String(),
+			Kind:       "PodDisruptionBudget",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            pdb.GetName(),
+			Namespace:       pdb.GetNamespace(),
+			Labels:          pdb.GetLabels(),
+			Annotations:     pdb.GetAnnotations(),
+			OwnerReferences: pdb.GetOwnerReferences(),
+		},
+		Spec: *pdb.Spec.DeepCopy(),
+	}
+	return backup
+}
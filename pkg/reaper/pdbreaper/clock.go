@@ -0,0 +1,37 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pdbreaper
+
+import "time"
+
+// Clock provides the current time, tests may inject a fake clock to drive time-based behavior deterministically
+type Clock interface {
+	Now() time.Time
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+// now returns the current time of the context clock, the real clock is used when none is set
+func (ctx *ReaperContext) now() time.Time {
+	if ctx.Clock == nil {
+		return realClock{}.Now()
+	}
+	return ctx.Clock.Now()
+}
@@ -0,0 +1,127 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pdbreaper
+
+import (
+	"strings"
+
+	"github.com/keikoproj/governor/pkg/reaper/common"
+	"github.com/pkg/errors"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ClusterTarget is a cluster scanned by pdb-reaper when running against multiple clusters
+type ClusterTarget struct {
+	Name             string
+	KubernetesClient kubernetes.Interface
+	State            ReaperState
+}
+
+// ClusterResult holds the results of a run against a single cluster
+type ClusterResult struct {
+	ScannedPodDisruptionBudgetsCount  int
+	ReapablePodDisruptionBudgetsCount int
+	ReapedPodDisruptionBudgetCount    int
+	Err                               error
+}
+
+// parseClusterTarget parses a --cluster value in the form name=kubeconfig[:context]
+func parseClusterTarget(value string) (name, configPath, kubeContext string, err error) {
+	parts := strings.SplitN(value, "=", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", "", errors.Errorf("--cluster value '%v' must be in the form name=kubeconfig[:context]", value)
+	}
+	name = parts[0]
+
+	pathParts := strings.SplitN(parts[1], ":", 2)
+	configPath = pathParts[0]
+	if len(pathParts) == 2 {
+		kubeContext = pathParts[1]
+	}
+	if configPath == "" {
+		return "", "", "", errors.Errorf("--cluster value '%v' must be in the form name=kubeconfig[:context]", value)
+	}
+	return name, configPath, kubeContext, nil
+}
+
+// loadClusterTargets creates a client for each --cluster value
+func (ctx *ReaperContext) loadClusterTargets(values []string) error {
+	targets := make([]ClusterTarget, 0)
+	for _, value := range values {
+		name, configPath, kubeContext, err := parseClusterTarget(value)
+		if err != nil {
+			return err
+		}
+		for _, target := range targets {
+			if target.Name == name {
+				return errors.Errorf("--cluster name '%v' is used more than once", name)
+			}
+		}
+		if ok := common.PathExists(configPath); !ok {
+			return errors.Errorf("--cluster kubeconfig path '%v' was not found", configPath)
+		}
+
+		client, err := common.OutOfClusterContextAuth(configPath, kubeContext, ctx.throttleConfig()...)
+		if err != nil {
+			return errors.Wrapf(err, "cluster external auth failed for cluster %v", name)
+		}
+		targets = append(targets, ClusterTarget{Name: name, KubernetesClient: client})
+	}
+	ctx.Clusters = targets
+	return nil
+}
+
+// executeClusters runs pdb-reaper against each cluster target in turn, a failure on one cluster does not prevent the
+// others from being processed. The returned error carries the code of the first failing cluster.
+func (ctx *ReaperContext) executeClusters() error {
+	ctx.ClusterResults = make(map[string]ClusterResult)
+	failed := make([]string, 0)
+	var code ErrorCode
+
+	for i := range ctx.Clusters {
+		target := &ctx.Clusters[i]
+		log.Infof("pdb-reaper processing cluster %v", target.Name)
+
+		ctx.ClusterName = target.Name
+		ctx.KubernetesClient = target.KubernetesClient
+		ctx.State = target.State
+
+		err := ctx.executeCluster()
+		target.State = ctx.State
+		ctx.ClusterResults[target.Name] = ClusterResult{
+			ScannedPodDisruptionBudgetsCount:  ctx.ScannedPodDisruptionBudgetsCount,
+			ReapablePodDisruptionBudgetsCount: ctx.ReapablePodDisruptionBudgetsCount,
+			ReapedPodDisruptionBudgetCount:    ctx.ReapedPodDisruptionBudgetCount,
+			Err:                               err,
+		}
+		if err != nil {
+			log.Errorf("execution failed on cluster %v: %v", target.Name, err)
+			if len(failed) == 0 {
+				code = errorCode(err)
+			}
+			failed = append(failed, target.Name)
+		}
+	}
+
+	if len(failed) > 0 {
+		err := errors.Errorf("execution failed on clusters %v", strings.Join(failed, ","))
+		if code == "" {
+			return err
+		}
+		return &RunError{Code: code, Err: err}
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pdbreaper
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// podController returns the top-level controller of a pod, a ReplicaSet created by a Deployment is resolved to the
+// Deployment from its name and the pod-template-hash label, so pods of a Deployment being rolled out are not counted
+// as separate controllers
+func podController(pod corev1.Pod) (workloadRef, bool) {
+	owner := metav1.GetControllerOf(&pod)
+	if owner == nil {
+		return workloadRef{}, false
+	}
+
+	if owner.Kind == "ReplicaSet" {
+		if hash, ok := pod.GetLabels()[appsv1.DefaultDeploymentUniqueLabelKey]; ok && strings.HasSuffix(owner.Name, "-"+hash) {
+			return workloadRef{Kind: "Deployment", Name: strings.TrimSuffix(owner.Name, "-"+hash)}, true
+		}
+	}
+	return workloadRef{Kind: owner.Kind, Name: owner.Name}, true
+}
+
+// podControllers returns the distinct controllers of the pods sorted by kind and name, pods without a controller are
+// ignored
+func podControllers(pods []corev1.Pod) []workloadRef {
+	seen := make(map[workloadRef]bool)
+	controllers := make([]workloadRef, 0)
+	for _, pod := range pods {
+		controller, ok := podController(pod)
+		if !ok || seen[controller] {
+			continue
+		}
+		seen[controller] = true
+		controllers = append(controllers, controller)
+	}
+	sort.Slice(controllers, func(i, j int) bool {
+		if controllers[i].Kind != controllers[j].Kind {
+			return controllers[i].Kind < controllers[j].Kind
+		}
+		return controllers[i].Name < controllers[j].Name
+	})
+	return controllers
+}
+
+func workloadRefStrings(workloads []workloadRef) []string {
+	names := make([]string, 0, len(workloads))
+	for _, workload := range workloads {
+		names = append(names, fmt.Sprintf("%v/%v", workload.Kind, workload.Name))
+	}
+	return names
+}
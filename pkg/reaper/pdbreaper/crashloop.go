@@ -0,0 +1,53 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pdbreaper
+
+import (
+	"github.com/keikoproj/governor/pkg/reaper/common"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// DefaultCrashLoopReasons are the waiting reasons of containers counted as crashlooping by default
+var DefaultCrashLoopReasons = []string{ReasonCrashLoopBackOff}
+
+// DefaultCrashLoopMinExitCode is the minimum exit code of terminated containers counted as crashlooping with
+// --crashloop-include-terminated
+const DefaultCrashLoopMinExitCode = 1
+
+// containerStatusPredicate decides whether a container status counts as crashlooping
+type containerStatusPredicate func(status corev1.ContainerStatus) bool
+
+// crashLoopPredicate returns the predicate of crashlooping containers past the restart threshold, containers count
+// when waiting with one of --crashloop-reasons, or with --crashloop-include-terminated when terminated with an exit
+// code of at least --crashloop-min-exit-code
+func (ctx *ReaperContext) crashLoopPredicate(threshold int) containerStatusPredicate {
+	reasons := ctx.CrashLoopReasons
+	if len(reasons) == 0 {
+		reasons = DefaultCrashLoopReasons
+	}
+	return func(status corev1.ContainerStatus) bool {
+		if status.RestartCount < int32(threshold) {
+			return false
+		}
+		if waiting := status.State.Waiting; waiting != nil && common.StringSliceContains(reasons, waiting.Reason) {
+			return true
+		}
+		if terminated := status.State.Terminated; ctx.CrashLoopIncludeTerminated && terminated != nil {
+			return terminated.ExitCode >= int32(ctx.CrashLoopMinExitCode)
+		}
+		return false
+	}
+}
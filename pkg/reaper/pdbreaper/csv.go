@@ -0,0 +1,118 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pdbreaper
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/keikoproj/governor/pkg/reaper/common"
+	"github.com/pkg/errors"
+	policyv1 "k8s.io/api/policy/v1"
+)
+
+const (
+	CSVActionDeleted     = "deleted"
+	CSVActionWouldDelete = "would-delete"
+	CSVActionDeferred    = "deferred"
+)
+
+// CSVHeader is the header row of --csv-output
+var CSVHeader = []string{"namespace", "name", "reason", "matched_pods", "resolved_budget", "age", "action"}
+
+// writeFindingsCSV writes one row for each reason a PDB was found reapable for to --csv-output, ordered by
+// namespace/name, the file is replaced on every run
+func (ctx *ReaperContext) writeFindingsCSV() error {
+	if ctx.CSVOutput == "" {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	if err := writer.Write(CSVHeader); err != nil {
+		return errors.Wrap(err, "failed to write CSV header")
+	}
+	pdbs := append([]policyv1.PodDisruptionBudget{}, ctx.ReapablePodDisruptionBudgets...)
+	sort.SliceStable(pdbs, func(i, j int) bool {
+		return pdbNamespacedName(pdbs[i]) < pdbNamespacedName(pdbs[j])
+	})
+	for _, pdb := range pdbs {
+		action := ctx.findingAction(pdb)
+		for _, reason := range ctx.ReapableReasons[pdbNamespacedName(pdb)] {
+			row := []string{
+				pdb.GetNamespace(),
+				pdb.GetName(),
+				reason.String(),
+				strconv.Itoa(ctx.matchedPodsCount(pdb)),
+				resolvedBudget(pdb),
+				ctx.now().Sub(pdb.CreationTimestamp.Time).Round(time.Second).String(),
+				action,
+			}
+			if err := writer.Write(row); err != nil {
+				return errors.Wrap(err, "failed to write CSV row")
+			}
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return errors.Wrap(err, "failed to write CSV")
+	}
+
+	if err := os.WriteFile(ctx.CSVOutput, buf.Bytes(), 0644); err != nil {
+		return errors.Wrapf(err, "failed to write CSV output %v", ctx.CSVOutput)
+	}
+	log.Infof("wrote %v reapable PDBs to %v", len(ctx.ReapablePodDisruptionBudgets), ctx.CSVOutput)
+	return nil
+}
+
+// findingAction returns the action taken on a reapable PDB in this run
+func (ctx *ReaperContext) findingAction(pdb policyv1.PodDisruptionBudget) string {
+	switch {
+	case common.StringSliceContains(ctx.reapedNames, pdbNamespacedName(pdb)):
+		return CSVActionDeleted
+	case ctx.DryRun:
+		return CSVActionWouldDelete
+	default:
+		return CSVActionDeferred
+	}
+}
+
+// matchedPodsCount returns the number of pods matched by a PDB when they were listed, or its expected pods otherwise
+func (ctx *ReaperContext) matchedPodsCount(pdb policyv1.PodDisruptionBudget) int {
+	if count, ok := ctx.matchedPods[pdbNamespacedName(pdb)]; ok {
+		return count
+	}
+	return int(pdb.Status.ExpectedPods)
+}
+
+// resolvedBudget formats maxUnavailable and minAvailable resolved against the expected pods, e.g. maxUnavailable=0
+func resolvedBudget(pdb policyv1.PodDisruptionBudget) string {
+	simulation := simulateReap(pdb)
+	budgets := make([]string, 0)
+	if simulation.MaxUnavailable != nil {
+		budgets = append(budgets, fmt.Sprintf("%v=%v", BudgetTypeMaxUnavailable, *simulation.MaxUnavailable))
+	}
+	if simulation.MinAvailable != nil {
+		budgets = append(budgets, fmt.Sprintf("%v=%v", BudgetTypeMinAvailable, *simulation.MinAvailable))
+	}
+	return strings.Join(budgets, ";")
+}
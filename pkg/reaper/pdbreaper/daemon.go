@@ -0,0 +1,144 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pdbreaper
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// ArgsLoader loads the arguments which are applied when the daemon is reloaded
+type ArgsLoader func() (*Args, error)
+
+// Daemon runs pdb-reaper repeatedly, and reloads its arguments on SIGHUP
+type Daemon struct {
+	Interval time.Duration
+	Reload   ArgsLoader
+
+	// OnRun is called after each run
+	OnRun func(ctx *ReaperContext, err error)
+
+	newContext func(args *Args) (*ReaperContext, error)
+}
+
+// RunDaemon runs pdb-reaper every interval until the process is stopped
+func RunDaemon(args *Args, interval time.Duration, reload ArgsLoader) error {
+	log.SetFormatter(&logrus.TextFormatter{
+		FullTimestamp: true,
+	})
+
+	d := &Daemon{
+		Interval: interval,
+		Reload:   reload,
+	}
+	return d.Run(args, nil)
+}
+
+// Run runs pdb-reaper immediately and then every interval until stop is closed. On SIGHUP the arguments are reloaded and
+// validated, and applied to subsequent runs, a run in progress finishes with the previous arguments. When the reloaded
+// arguments are invalid the previous arguments are kept.
+func (d *Daemon) Run(args *Args, stop <-chan struct{}) error {
+	if d.Interval <= 0 {
+		return &RunError{Code: ErrorCodeInvalidConfig, Err: errors.Errorf("--interval value must be greater than 0 in daemon mode")}
+	}
+	if d.newContext == nil {
+		d.newContext = newReaperContext
+	}
+
+	ctx, err := d.newContext(args)
+	if err != nil {
+		return errors.Wrap(err, "failed to validate arguments")
+	}
+
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	ticker := time.NewTicker(d.Interval)
+	defer ticker.Stop()
+
+	// a run in progress when stop is closed does not publish further events or metrics
+	runCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-stop:
+			cancel()
+		case <-runCtx.Done():
+		}
+	}()
+
+	d.execute(runCtx, ctx)
+	for {
+		select {
+		case <-stop:
+			return nil
+		case <-hup:
+			ctx = d.reload(ctx)
+		case <-ticker.C:
+			d.execute(runCtx, ctx)
+		}
+	}
+}
+
+func (d *Daemon) execute(runCtx context.Context, ctx *ReaperContext) {
+	err := ctx.executeContext(runCtx)
+	if err != nil {
+		log.Errorf("execution failed: %v", err)
+	}
+	if d.OnRun != nil {
+		d.OnRun(ctx, err)
+	}
+}
+
+// reload returns a context with the reloaded arguments, or the current context if they cannot be loaded or are invalid
+func (d *Daemon) reload(current *ReaperContext) *ReaperContext {
+	log.Info("received SIGHUP, reloading configuration")
+	if d.Reload == nil {
+		log.Warn("configuration reload is not supported without a config file, keeping the current configuration")
+		return current
+	}
+
+	args, err := d.Reload()
+	if err != nil {
+		log.Errorf("failed to reload configuration, keeping the current configuration: %v", err)
+		return current
+	}
+
+	ctx, err := d.newContext(args)
+	if err != nil {
+		log.Errorf("reloaded configuration is invalid, keeping the current configuration: %v", err)
+		return current
+	}
+
+	// state which is kept in memory carries over to the new configuration
+	ctx.State = current.State
+	for i := range ctx.Clusters {
+		for _, target := range current.Clusters {
+			if target.Name == ctx.Clusters[i].Name {
+				ctx.Clusters[i].State = target.State
+			}
+		}
+	}
+	log.Info("configuration reloaded")
+	return ctx
+}
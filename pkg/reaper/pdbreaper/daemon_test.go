@@ -0,0 +1,160 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pdbreaper
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+const testKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: test
+  cluster:
+    server: https://127.0.0.1:6443
+contexts:
+- name: test
+  context:
+    cluster: test
+    user: test
+current-context: test
+users:
+- name: test
+  user:
+    token: test
+`
+
+func TestDaemonReloadOnSIGHUP(t *testing.T) {
+	reaper := _fakeReaperContext()
+	client := reaper.KubernetesClient.(*fake.Clientset)
+	_fakeAPI(&ReaperUnitTest{FakeReaper: reaper, Mocks: _reapWindowMocks()})
+
+	dir := t.TempDir()
+	kubeconfig := filepath.Join(dir, "kubeconfig")
+	if err := os.WriteFile(kubeconfig, []byte(testKubeconfig), 0600); err != nil {
+		t.Fatalf("failed to write kubeconfig: %v", err)
+	}
+
+	args := &Args{
+		K8sConfigPath:         kubeconfig,
+		LocalMode:             true,
+		DryRun:                true,
+		ReapMisconfigured:     true,
+		CrashLoopRestartCount: 5,
+		ReapNotReadyThreshold: 1800,
+	}
+
+	// the config file holds the arguments which are applied on reload
+	configFile := filepath.Join(dir, "config.json")
+	writeConfig := func(a Args) {
+		data, _ := json.Marshal(a)
+		if err := os.WriteFile(configFile, data, 0600); err != nil {
+			t.Fatalf("failed to write config file: %v", err)
+		}
+	}
+	writeConfig(*args)
+
+	runs := make(chan *ReaperContext, 10)
+	stop := make(chan struct{})
+	d := &Daemon{
+		Interval: 20 * time.Millisecond,
+		Reload: func() (*Args, error) {
+			data, err := os.ReadFile(configFile)
+			if err != nil {
+				return nil, err
+			}
+			reloaded := &Args{}
+			return reloaded, json.Unmarshal(data, reloaded)
+		},
+		OnRun: func(ctx *ReaperContext, err error) {
+			if err != nil {
+				t.Errorf("execution failed: %v", err)
+			}
+			runs <- ctx
+		},
+		newContext: func(a *Args) (*ReaperContext, error) {
+			ctx, err := newReaperContext(a)
+			if err != nil {
+				return nil, err
+			}
+			ctx.KubernetesClient = client
+			return ctx, nil
+		},
+	}
+
+	done := make(chan error)
+	go func() {
+		done <- d.Run(args, stop)
+	}()
+
+	waitRun := func() *ReaperContext {
+		select {
+		case ctx := <-runs:
+			return ctx
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for a run")
+		}
+		return nil
+	}
+
+	if ctx := waitRun(); !ctx.DryRun || ctx.ReapedPodDisruptionBudgetCount != 0 {
+		t.Fatalf("assertion failed, expected first run in dry-run without reaping")
+	}
+
+	// an invalid config is rejected and the previous config is kept
+	invalid := *args
+	invalid.CrashLoopRestartCount = 0
+	writeConfig(invalid)
+	if err := syscall.Kill(os.Getpid(), syscall.SIGHUP); err != nil {
+		t.Fatalf("failed to send SIGHUP: %v", err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	for len(runs) > 0 {
+		<-runs
+	}
+	if ctx := waitRun(); !ctx.DryRun || ctx.CrashLoopRestartCount != 5 {
+		t.Fatalf("assertion failed, expected invalid config to be rejected")
+	}
+
+	updated := *args
+	updated.DryRun = false
+	writeConfig(updated)
+	if err := syscall.Kill(os.Getpid(), syscall.SIGHUP); err != nil {
+		t.Fatalf("failed to send SIGHUP: %v", err)
+	}
+
+	deadline := time.After(5 * time.Second)
+	for reaped := false; !reaped; {
+		select {
+		case ctx := <-runs:
+			reaped = !ctx.DryRun && ctx.ReapedPodDisruptionBudgetCount == 1
+		case <-deadline:
+			t.Fatalf("timed out waiting for a run with the reloaded config")
+		}
+	}
+
+	close(stop)
+	if err := <-done; err != nil {
+		t.Fatalf("daemon failed: %v", err)
+	}
+}
@@ -0,0 +1,60 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pdbreaper
+
+import (
+	"time"
+
+	policyv1 "k8s.io/api/policy/v1"
+)
+
+// Evaluation is the resource version of a PDB when it was last evaluated
+type Evaluation struct {
+	ResourceVersion string    `json:"resourceVersion"`
+	At              time.Time `json:"at"`
+}
+
+// isUpdateDebounced returns how long ago a PDB was last evaluated when it was updated since, and its next evaluation is
+// held back until --update-debounce has passed, so that a PDB which keeps updating is evaluated at most once per window
+func (ctx *ReaperContext) isUpdateDebounced(pdb policyv1.PodDisruptionBudget) (time.Duration, bool) {
+	if ctx.UpdateDebounce == 0 {
+		return 0, false
+	}
+	last, ok := ctx.State.Evaluated[pdbNamespacedName(pdb)]
+	if !ok || last.ResourceVersion == pdb.GetResourceVersion() {
+		return 0, false
+	}
+	since := ctx.now().Sub(last.At)
+	return since, since < ctx.UpdateDebounce
+}
+
+// updateEvaluations records the resource version of each scanned PDB, debounced PDBs keep their last evaluation and
+// PDBs which no longer exist are dropped
+func (ctx *ReaperContext) updateEvaluations(debounced []policyv1.PodDisruptionBudget) {
+	if ctx.UpdateDebounce == 0 {
+		return
+	}
+
+	evaluated := make(map[string]Evaluation)
+	for _, pdb := range debounced {
+		namespacedName := pdbNamespacedName(pdb)
+		evaluated[namespacedName] = ctx.State.Evaluated[namespacedName]
+	}
+	for _, pdb := range ctx.ScannedPodDisruptionBudgets {
+		evaluated[pdbNamespacedName(pdb)] = Evaluation{ResourceVersion: pdb.GetResourceVersion(), At: ctx.now().UTC()}
+	}
+	ctx.State.Evaluated = evaluated
+}
@@ -0,0 +1,49 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pdbreaper
+
+import (
+	policyv1 "k8s.io/api/policy/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// isPodListForbidden returns true when listing pods failed because the reaper is not allowed to list pods in the
+// namespace, in which case detection degrades to the PDB status, the reduced capability is logged once per namespace
+func (ctx *ReaperContext) isPodListForbidden(namespace string, err error) bool {
+	if !kerrors.IsForbidden(err) {
+		return false
+	}
+	if !ctx.degradedNamespaces[namespace] {
+		log.Warnf("listing pods in namespace %v is forbidden, falling back to status-based detection with reduced capability, crashloop, not-ready and overlap detection are skipped: %v", namespace, err)
+		ctx.degradedNamespaces[namespace] = true
+	}
+	return true
+}
+
+// handleStatusOnlyBlocking evaluates a blocking PDB whose pods can't be listed, only misconfiguration is detected,
+// resolved against the expected pods reported in the PDB status
+func (ctx *ReaperContext) handleStatusOnlyBlocking(pdb policyv1.PodDisruptionBudget) error {
+	if !ctx.ReapMisconfigured {
+		return nil
+	}
+
+	misconfigured, err := ctx.evaluateStatusOnlyMisconfigured(pdb)
+	if err != nil {
+		return err
+	}
+	ctx.markMisconfigured(pdb, misconfigured.matched)
+	return nil
+}
@@ -0,0 +1,71 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pdbreaper
+
+import (
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// Diagnostics are the reasons a PDB was found reapable for in a run, and the values which drove each decision
+type Diagnostics struct {
+	Reasons            []Reason       `json:"reasons,omitempty"`
+	ExpectedPods       int32          `json:"expectedPods"`
+	DisruptionsAllowed int32          `json:"disruptionsAllowed"`
+	ResolvedBudget     map[string]int `json:"resolvedBudget,omitempty"`
+	MatchedPods        int            `json:"matchedPods"`
+	DrainingPods       int            `json:"drainingPods"`
+	CrashLoopPods      int            `json:"crashLoopPods"`
+	NotReadyPods       int            `json:"notReadyPods"`
+	HealthScore        float64        `json:"healthScore,omitempty"`
+}
+
+// diagnostics returns the diagnostics of a PDB in the current run, creating them on first use
+func (ctx *ReaperContext) diagnostics(pdb policyv1.PodDisruptionBudget) *Diagnostics {
+	namespacedName := pdbNamespacedName(pdb)
+	if diagnostics, ok := ctx.Diagnostics[namespacedName]; ok {
+		return diagnostics
+	}
+	diagnostics := &Diagnostics{
+		ExpectedPods:       pdb.Status.ExpectedPods,
+		DisruptionsAllowed: pdb.Status.DisruptionsAllowed,
+		ResolvedBudget:     resolvedBudgets(pdb),
+	}
+	ctx.Diagnostics[namespacedName] = diagnostics
+	return diagnostics
+}
+
+// resolvedBudgets returns the integer values of maxUnavailable and minAvailable resolved against the expected pods,
+// percentages are rounded up
+func resolvedBudgets(pdb policyv1.PodDisruptionBudget) map[string]int {
+	budgets := map[string]*intstr.IntOrString{
+		BudgetTypeMaxUnavailable: pdb.Spec.MaxUnavailable,
+		BudgetTypeMinAvailable:   pdb.Spec.MinAvailable,
+	}
+	resolved := make(map[string]int)
+	for budgetType, budget := range budgets {
+		if budget == nil {
+			continue
+		}
+		value, err := intstr.GetScaledValueFromIntOrPercent(budget, int(pdb.Status.ExpectedPods), true)
+		if err != nil {
+			log.Warnf("failed to resolve %v of pdb %v: %v", budgetType, pdbNamespacedName(pdb), err)
+			continue
+		}
+		resolved[budgetType] = value
+	}
+	return resolved
+}
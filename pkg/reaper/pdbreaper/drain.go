@@ -0,0 +1,109 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pdbreaper
+
+import (
+	"context"
+
+	"github.com/keikoproj/governor/pkg/reaper/common"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// isDrainAware returns true when node drain state must be evaluated
+func (ctx *ReaperContext) isDrainAware() bool {
+	return ctx.ReapDrainBlocking || ctx.DrainBlockingOnly || ctx.inMaintenance
+}
+
+// loadDrainingNodes lists the nodes once per run and records which of them are being drained
+func (ctx *ReaperContext) loadDrainingNodes() error {
+	if ctx.drainingNodes != nil {
+		return nil
+	}
+
+	nodes, err := ctx.KubernetesClient.CoreV1().Nodes().List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		return errors.Wrap(err, "failed to list nodes")
+	}
+
+	ctx.drainingNodes = make(map[string]bool)
+	for _, node := range nodes.Items {
+		if isNodeDraining(node) || ctx.maintenanceNodes[node.GetName()] {
+			ctx.drainingNodes[node.GetName()] = true
+		}
+	}
+	log.Infof("found %v cordoned/draining nodes", len(ctx.drainingNodes))
+	return nil
+}
+
+// podsOnDrainingNodes returns the pods which are scheduled on a cordoned/draining node
+func (ctx *ReaperContext) podsOnDrainingNodes(pods []corev1.Pod) ([]corev1.Pod, error) {
+	if err := ctx.loadDrainingNodes(); err != nil {
+		return nil, err
+	}
+
+	draining := make([]corev1.Pod, 0)
+	for _, pod := range pods {
+		if pod.Spec.NodeName != "" && ctx.drainingNodes[pod.Spec.NodeName] {
+			draining = append(draining, pod)
+		}
+	}
+	return draining, nil
+}
+
+// filterDrainBlocking returns the PDBs which have pods on a cordoned/draining node
+func (ctx *ReaperContext) filterDrainBlocking(pdbs []policyv1.PodDisruptionBudget) ([]policyv1.PodDisruptionBudget, error) {
+	drainBlocking := make([]policyv1.PodDisruptionBudget, 0)
+	for _, pdb := range pdbs {
+		labelSelector, err := common.GetSelectorString(pdb.Spec.Selector)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get label selector from structured selector %+v", pdb.Spec.Selector)
+		}
+
+		pods, err := ctx.listPodsWithSelector(ctx.evaluationContext(pdb), pdb.GetNamespace(), labelSelector)
+		if err != nil {
+			if errors.Is(err, context.DeadlineExceeded) {
+				log.Warnf("evaluation of PDB %v timed out after %v, skipping it: %v", pdbNamespacedName(pdb), ctx.PodDisruptionBudgetTimeout, err)
+				continue
+			}
+			return nil, errors.Wrap(err, "failed to list PDB pods")
+		}
+
+		drainingPods, err := ctx.podsOnDrainingNodes(pods)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to determine pods on draining nodes")
+		}
+		if len(drainingPods) > 0 {
+			drainBlocking = append(drainBlocking, pdb)
+		}
+	}
+	return drainBlocking, nil
+}
+
+// isNodeDraining returns true when a node is cordoned, either by spec or by the unschedulable taint
+func isNodeDraining(node corev1.Node) bool {
+	if node.Spec.Unschedulable {
+		return true
+	}
+	for _, taint := range node.Spec.Taints {
+		if taint.Key == corev1.TaintNodeUnschedulable {
+			return true
+		}
+	}
+	return false
+}
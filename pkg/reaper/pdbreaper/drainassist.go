@@ -0,0 +1,105 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pdbreaper
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/keikoproj/governor/pkg/reaper/common"
+	"github.com/pkg/errors"
+)
+
+// BlockingPDB is a PDB which blocks the drain of a node, with its pods running on that node
+type BlockingPDB struct {
+	PDB                string   `json:"pdb"`
+	DisruptionsAllowed int32    `json:"disruptionsAllowed"`
+	ExpectedPods       int32    `json:"expectedPods"`
+	Pods               []string `json:"pods"`
+}
+
+// DrainAssistReport is written to the output by --drain-assist
+type DrainAssistReport struct {
+	Cluster  string        `json:"cluster,omitempty"`
+	Node     string        `json:"node"`
+	Blocking []BlockingPDB `json:"blocking"`
+}
+
+// drainAssist reports the PDBs which would block draining --node, a PDB blocks the drain when it allows fewer
+// disruptions than it selects pods on the node. Nothing is written to the cluster.
+func (ctx *ReaperContext) drainAssist() error {
+	pdbList, err := ctx.listPodDisruptionBudgets()
+	if err != nil {
+		return errors.Wrap(err, "failed to list PDBs")
+	}
+
+	report := DrainAssistReport{
+		Cluster:  ctx.ClusterName,
+		Node:     ctx.DrainAssistNode,
+		Blocking: make([]BlockingPDB, 0),
+	}
+	for _, pdb := range pdbList.Items {
+		if pdb.GetDeletionTimestamp() != nil {
+			continue
+		}
+
+		labelSelector, err := common.GetSelectorString(pdb.Spec.Selector)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get label selector from structured selector %+v", pdb.Spec.Selector)
+		}
+		pods, err := ctx.listPodsWithSelector(ctx.evaluationContext(pdb), pdb.GetNamespace(), labelSelector)
+		if err != nil {
+			return errors.Wrap(err, "failed to list PDB pods")
+		}
+
+		nodePods := make([]string, 0)
+		for _, pod := range pods {
+			if pod.Spec.NodeName == ctx.DrainAssistNode && !isPodTerminated(pod) {
+				nodePods = append(nodePods, pod.GetName())
+			}
+		}
+		if len(nodePods) == 0 || int(pdb.Status.DisruptionsAllowed) >= len(nodePods) {
+			continue
+		}
+
+		sort.Strings(nodePods)
+		report.Blocking = append(report.Blocking, BlockingPDB{
+			PDB:                pdbNamespacedName(pdb),
+			DisruptionsAllowed: pdb.Status.DisruptionsAllowed,
+			ExpectedPods:       pdb.Status.ExpectedPods,
+			Pods:               nodePods,
+		})
+	}
+
+	sort.SliceStable(report.Blocking, func(i, j int) bool {
+		return report.Blocking[i].PDB < report.Blocking[j].PDB
+	})
+	for _, blocking := range report.Blocking {
+		log.Infof("PDB %v allows %v disruptions and blocks the drain of node %v with pods %v", blocking.PDB, blocking.DisruptionsAllowed, report.Node, blocking.Pods)
+	}
+	log.Infof("found %v PDBs blocking the drain of node %v", len(report.Blocking), report.Node)
+
+	if ctx.Output != nil {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal drain assist report")
+		}
+		if _, err = ctx.Output.Write(append(data, '\n')); err != nil {
+			return errors.Wrap(err, "failed to write drain assist report")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pdbreaper
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	ModeEnforce = "enforce"
+	ModeReport  = "report"
+
+	ModeLabelKey = "pdb-reaper/mode"
+)
+
+// EnforcePermissions are the additional permissions needed when --enforce-namespace-label is set
+var EnforcePermissions = []authorizationv1.ResourceAttributes{
+	{Verb: "list", Group: "", Resource: "namespaces"},
+}
+
+// loadEnforcedNamespaces lists the namespaces carrying --enforce-namespace-label once per run, reapable PDBs in other
+// namespaces are only reported
+func (ctx *ReaperContext) loadEnforcedNamespaces() error {
+	ctx.enforcedNamespaces = nil
+	if ctx.EnforceNamespaceLabel == "" {
+		return nil
+	}
+
+	namespaces, err := ctx.KubernetesClient.CoreV1().Namespaces().List(context.Background(), metav1.ListOptions{LabelSelector: ctx.EnforceNamespaceLabel})
+	if err != nil {
+		return errors.Wrap(err, "failed to list enforced namespaces")
+	}
+	ctx.enforcedNamespaces = make(map[string]bool)
+	for _, namespace := range namespaces.Items {
+		ctx.enforcedNamespaces[namespace.GetName()] = true
+	}
+	log.Infof("reaping is enforced in %v namespaces labeled '%v', reapable PDBs in other namespaces are only reported", len(ctx.enforcedNamespaces), ctx.EnforceNamespaceLabel)
+	return nil
+}
+
+// namespaceMode returns whether reaping is enforced or only reported in a namespace, or an empty string when
+// --enforce-namespace-label is not set
+func (ctx *ReaperContext) namespaceMode(namespace string) string {
+	if ctx.EnforceNamespaceLabel == "" {
+		return ""
+	}
+	if ctx.enforcedNamespaces[namespace] {
+		return ModeEnforce
+	}
+	return ModeReport
+}
@@ -0,0 +1,72 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pdbreaper
+
+import (
+	"github.com/pkg/errors"
+)
+
+// ErrorCode is the category of an error returned by a run
+type ErrorCode string
+
+const (
+	ErrorCodeScanFailed    ErrorCode = "ScanFailed"
+	ErrorCodeReapFailed    ErrorCode = "ReapFailed"
+	ErrorCodeInvalidConfig ErrorCode = "InvalidConfig"
+)
+
+var (
+	// ErrScanFailed matches errors of runs which failed to scan the cluster
+	ErrScanFailed = &RunError{Code: ErrorCodeScanFailed}
+	// ErrReapFailed matches errors of runs which failed to detect or delete reapable PDBs
+	ErrReapFailed = &RunError{Code: ErrorCodeReapFailed}
+	// ErrInvalidConfig matches errors of runs whose arguments failed validation
+	ErrInvalidConfig = &RunError{Code: ErrorCodeInvalidConfig}
+)
+
+// RunError is an error returned by a run along with its category, use errors.Is with ErrScanFailed, ErrReapFailed or
+// ErrInvalidConfig to match the category, or errors.As to get the code
+type RunError struct {
+	Code ErrorCode
+	Err  error
+}
+
+func (e *RunError) Error() string {
+	if e.Err == nil {
+		return string(e.Code)
+	}
+	return e.Err.Error()
+}
+
+func (e *RunError) Unwrap() error {
+	return e.Err
+}
+
+// Is returns true when target is a RunError with the same code, which makes the sentinel errors match any error of
+// their category
+func (e *RunError) Is(target error) bool {
+	t, ok := target.(*RunError)
+	return ok && t.Code == e.Code
+}
+
+// errorCode returns the code of the outermost RunError in an error chain
+func errorCode(err error) ErrorCode {
+	var runErr *RunError
+	if errors.As(err, &runErr) {
+		return runErr.Code
+	}
+	return ""
+}
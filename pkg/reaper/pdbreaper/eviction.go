@@ -0,0 +1,89 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pdbreaper
+
+import (
+	"context"
+
+	"github.com/keikoproj/governor/pkg/reaper/common"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	PdbReaperEvictionConfirmedMetricName = "governor_pdb_reaper_eviction_confirmed"
+)
+
+// EvictionPermissions are the additional permissions needed when --confirm-with-eviction-after-delete is set
+var EvictionPermissions = []authorizationv1.ResourceAttributes{
+	{Verb: "create", Group: "", Resource: "pods", Subresource: "eviction"},
+}
+
+// confirmEviction issues a dry-run eviction against a pod matched by a reaped PDB to confirm that it can be disrupted
+// now, the result is exposed as a metric with 1 for success and 0 for failure. Pods are not evicted.
+func (ctx *ReaperContext) confirmEviction(pdb policyv1.PodDisruptionBudget) {
+	if !ctx.ConfirmWithEviction || !ctx.isFeatureSupported(FeatureConfirmWithEviction) {
+		return
+	}
+
+	labelSelector, err := common.GetSelectorString(pdb.Spec.Selector)
+	if err != nil {
+		log.Warnf("failed to get label selector of reaped pdb %v: %v", pdbNamespacedName(pdb), err)
+		return
+	}
+	pods, err := ctx.listPodsWithSelector(ctx.runContext(), pdb.GetNamespace(), labelSelector)
+	if err != nil {
+		log.Warnf("failed to list pods of reaped pdb %v: %v", pdbNamespacedName(pdb), err)
+		return
+	}
+
+	pod, ok := evictionCandidate(pods)
+	if !ok {
+		log.Infof("no running pods matched by reaped pdb %v, skipping eviction confirmation", pdbNamespacedName(pdb))
+		return
+	}
+
+	eviction := &policyv1.Eviction{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      pod.GetName(),
+			Namespace: pod.GetNamespace(),
+		},
+		DeleteOptions: &metav1.DeleteOptions{
+			DryRun: []string{metav1.DryRunAll},
+		},
+	}
+	err = ctx.KubernetesClient.CoreV1().Pods(pod.GetNamespace()).EvictV1(context.Background(), eviction)
+	if err != nil {
+		log.Warnf("dry-run eviction of pod %v/%v failed after reaping pdb %v: %v", pod.GetNamespace(), pod.GetName(), pdbNamespacedName(pdb), err)
+		ctx.exposePodDisruptionBudgetMetric(pdb, PdbReaperEvictionConfirmedMetricName, 0)
+		return
+	}
+	log.Infof("dry-run eviction of pod %v/%v succeeded after reaping pdb %v", pod.GetNamespace(), pod.GetName(), pdbNamespacedName(pdb))
+	ctx.exposePodDisruptionBudgetMetric(pdb, PdbReaperEvictionConfirmedMetricName, 1)
+}
+
+// evictionCandidate returns the first running pod which is not being deleted
+func evictionCandidate(pods []corev1.Pod) (corev1.Pod, bool) {
+	for _, pod := range pods {
+		if pod.GetDeletionTimestamp() != nil || pod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+		return pod, true
+	}
+	return corev1.Pod{}, false
+}
@@ -0,0 +1,98 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pdbreaper
+
+import (
+	"context"
+	"strings"
+
+	"github.com/keikoproj/governor/pkg/reaper/common"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DefaultExcludedNamespacesConfigMapKey is the default key of the --excluded-namespaces-configmap
+const DefaultExcludedNamespacesConfigMapKey = "excluded-namespaces"
+
+// loadExcludedNamespaces merges the namespaces listed in the --excluded-namespaces-configmap and the
+// --platform-exclusions-configmap, separated by commas or newlines, with the --excluded-namespaces once per run. When
+// the local ConfigMap does not exist only the flag entries apply. Platform exclusions are always added on top of the
+// local exclusions, so local configuration can't remove them, and the run fails when they can't be read.
+func (ctx *ReaperContext) loadExcludedNamespaces() error {
+	if ctx.ExcludedNamespacesConfigMapName == "" && ctx.PlatformExclusionsConfigMapName == "" {
+		return nil
+	}
+
+	excluded := append([]string{}, ctx.flagExcludedNamespaces...)
+	defer func() {
+		ctx.ExcludedNamespaces = excluded
+	}()
+
+	if ctx.ExcludedNamespacesConfigMapName != "" {
+		namespaces, err := ctx.readNamespaceList(ctx.ExcludedNamespacesConfigMapNamespace, ctx.ExcludedNamespacesConfigMapName, ctx.ExcludedNamespacesConfigMapKey)
+		if err != nil {
+			if !kerrors.IsNotFound(err) {
+				return errors.Wrapf(err, "failed to get excluded namespaces configmap %v/%v", ctx.ExcludedNamespacesConfigMapNamespace, ctx.ExcludedNamespacesConfigMapName)
+			}
+			log.Warnf("excluded namespaces configmap %v/%v not found, only excluding namespaces %+v", ctx.ExcludedNamespacesConfigMapNamespace, ctx.ExcludedNamespacesConfigMapName, excluded)
+		}
+		excluded = mergeNamespaces(excluded, namespaces)
+	}
+
+	ctx.PlatformExcludedNamespaces = nil
+	if ctx.PlatformExclusionsConfigMapName != "" {
+		namespaces, err := ctx.readNamespaceList(ctx.PlatformExclusionsConfigMapNamespace, ctx.PlatformExclusionsConfigMapName, DefaultExcludedNamespacesConfigMapKey)
+		if err != nil {
+			// mandatory exclusions which can't be read, or are missing, must not let the reaper loose on protected namespaces
+			return errors.Wrapf(err, "failed to get platform exclusions configmap %v/%v", ctx.PlatformExclusionsConfigMapNamespace, ctx.PlatformExclusionsConfigMapName)
+		}
+		ctx.PlatformExcludedNamespaces = namespaces
+		excluded = mergeNamespaces(excluded, namespaces)
+		log.Infof("Platform excluded namespaces = %+v", namespaces)
+	}
+	log.Infof("Excluded namespaces = %+v", excluded)
+	return nil
+}
+
+// readNamespaceList reads the namespaces listed under a key of a ConfigMap, separated by commas or newlines
+func (ctx *ReaperContext) readNamespaceList(namespace, name, key string) ([]string, error) {
+	cm, err := ctx.KubernetesClient.CoreV1().ConfigMaps(namespace).Get(context.Background(), name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	namespaces := make([]string, 0)
+	fields := strings.FieldsFunc(cm.Data[key], func(r rune) bool {
+		return r == ',' || r == '\n'
+	})
+	for _, field := range fields {
+		if namespace := strings.TrimSpace(field); namespace != "" {
+			namespaces = append(namespaces, namespace)
+		}
+	}
+	return namespaces, nil
+}
+
+// mergeNamespaces appends the namespaces which are not already excluded
+func mergeNamespaces(excluded, namespaces []string) []string {
+	for _, namespace := range namespaces {
+		if !common.StringSliceContains(excluded, namespace) {
+			excluded = append(excluded, namespace)
+		}
+	}
+	return excluded
+}
@@ -0,0 +1,49 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pdbreaper
+
+const (
+	PdbReaperStaleStatusMetricName = "governor_pdb_reaper_stale_status"
+
+	DefaultStaleStatusRatio = 0.5
+)
+
+// isDisruptionStatusStale returns true when the PDB status looks stale, i.e. the disruption controller of the
+// kube-controller-manager has not observed the latest spec of at least --stale-status-ratio of the scanned PDBs, in
+// which case DisruptionsAllowed and ExpectedPods cannot be trusted
+func (ctx *ReaperContext) isDisruptionStatusStale() bool {
+	if !ctx.CheckDisruptionController || ctx.ScannedPodDisruptionBudgetsCount == 0 {
+		return false
+	}
+
+	var stale int
+	for _, pdb := range ctx.ScannedPodDisruptionBudgets {
+		if pdb.Status.ObservedGeneration < pdb.GetGeneration() {
+			stale++
+		}
+	}
+
+	ratio := float64(stale) / float64(len(ctx.ScannedPodDisruptionBudgets))
+	if ratio < ctx.StaleStatusRatio {
+		ctx.exposeClusterMetric(PdbReaperStaleStatusMetricName, 0)
+		return false
+	}
+
+	log.Warnf("disruption controller looks unhealthy, the status of %v/%v scanned PDBs is stale (ratio %.2f reaches %.2f), reaping is skipped",
+		stale, len(ctx.ScannedPodDisruptionBudgets), ratio, ctx.StaleStatusRatio)
+	ctx.exposeClusterMetric(PdbReaperStaleStatusMetricName, 1)
+	return true
+}
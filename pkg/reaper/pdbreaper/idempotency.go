@@ -0,0 +1,56 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pdbreaper
+
+import (
+	"strconv"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	policyv1 "k8s.io/api/policy/v1"
+)
+
+const (
+	ProcessedGenerationAnnotationKey = "pdb-reaper/processed-generation"
+)
+
+// ProcessedGenerationPermissions are the additional permissions needed when --stamp-processed-generation is set
+var ProcessedGenerationPermissions = []authorizationv1.ResourceAttributes{
+	{Verb: "patch", Group: "policy", Resource: "poddisruptionbudgets"},
+}
+
+// isProcessed returns true if the generation of a PDB was already processed in the current run, e.g. when a retried
+// list returns the same PDB twice
+func (ctx *ReaperContext) isProcessed(pdb policyv1.PodDisruptionBudget) bool {
+	generation, ok := ctx.processedGenerations[pdbNamespacedName(pdb)]
+	return ok && generation == pdb.GetGeneration()
+}
+
+// markProcessed records that the generation of a PDB was processed in the current run, with
+// --stamp-processed-generation the generation is also stamped on the PDB as an annotation
+func (ctx *ReaperContext) markProcessed(pdb policyv1.PodDisruptionBudget) {
+	ctx.processedGenerations[pdbNamespacedName(pdb)] = pdb.GetGeneration()
+
+	if !ctx.StampProcessedGeneration || ctx.DryRun || ctx.FixManifestsDir != "" {
+		return
+	}
+	generation := strconv.FormatInt(pdb.GetGeneration(), 10)
+	if pdb.GetAnnotations()[ProcessedGenerationAnnotationKey] == generation {
+		return
+	}
+	if err := ctx.patchAnnotations(pdb, map[string]interface{}{ProcessedGenerationAnnotationKey: generation}); err != nil {
+		log.Warnf(err.Error())
+	}
+}
@@ -0,0 +1,396 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pdbreaper
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/keikoproj/governor/pkg/reaper/common"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	InspectActionIgnore = "ignore"
+	InspectActionNone   = "none"
+	InspectActionSkip   = "skip"
+	InspectActionReport = "report"
+	InspectActionDelete = "delete"
+	InspectActionPatch  = "patch"
+)
+
+// RuleResult is the result of a single reap mode evaluated by --inspect, with the values behind it
+type RuleResult struct {
+	Rule    string `json:"rule"`
+	Enabled bool   `json:"enabled"`
+	Matched bool   `json:"matched"`
+	Details string `json:"details"`
+}
+
+// InspectReport is written to the output by --inspect
+type InspectReport struct {
+	Cluster            string       `json:"cluster,omitempty"`
+	PDB                string       `json:"pdb"`
+	Selector           string       `json:"selector"`
+	MinAvailable       string       `json:"minAvailable,omitempty"`
+	MaxUnavailable     string       `json:"maxUnavailable,omitempty"`
+	ExpectedPods       int32        `json:"expectedPods"`
+	CurrentHealthy     int32        `json:"currentHealthy"`
+	DesiredHealthy     int32        `json:"desiredHealthy"`
+	DisruptionsAllowed int32        `json:"disruptionsAllowed"`
+	MatchedPods        []string     `json:"matchedPods"`
+	Rules              []RuleResult `json:"rules"`
+	Reasons            []Reason     `json:"reasons"`
+	Action             string       `json:"action"`
+	ActionDetails      string       `json:"actionDetails"`
+}
+
+// isReapModeEnabled returns true when a reap mode is enabled
+func (ctx *ReaperContext) isReapModeEnabled(mode string) bool {
+	switch mode {
+	case ReapModeMisconfigured:
+		return ctx.ReapMisconfigured
+	case ReapModeCrashLoop:
+		return ctx.ReapCrashLoop
+	case ReapModeNotReady:
+		return ctx.ReapNotReady
+	case ReapModeMultiple:
+		return ctx.ReapMultiple
+	case ReapModeDrainBlocking:
+		return ctx.ReapDrainBlocking
+	case ReapModeDuplicateSelector:
+		return ctx.ReapDuplicateSelector
+	case ReapModeZeroMaxUnavailable:
+		return ctx.ReapZeroMaxUnavailable
+	case ReapModeHealthScore:
+		return ctx.ReapHealthScore
+	case ReapModeMixedControllers:
+		return ctx.ReapMixedControllers
+	case ReapModeSingleNode:
+		return ctx.ReapSingleNode
+	case ReapModeStaleSelector:
+		return ctx.ReapStaleSelector
+	}
+	return false
+}
+
+// inspect writes the decision trace of --inspect for a single PDB, every reap mode is evaluated with the detection
+// functions of a run, but nothing is written to the cluster and no events or metrics are published
+func (ctx *ReaperContext) inspect() error {
+	namespace, name, _ := strings.Cut(ctx.InspectPDB, "/")
+	defer ctx.releaseEvaluationContexts()
+	pdb, err := ctx.KubernetesClient.PolicyV1().PodDisruptionBudgets(namespace).Get(context.Background(), name, metav1.GetOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "failed to get PDB %v", ctx.InspectPDB)
+	}
+
+	labelSelector, err := common.GetSelectorString(pdb.Spec.Selector)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get label selector from structured selector %+v", pdb.Spec.Selector)
+	}
+	// like a run, a PDB whose pods can't be listed is only evaluated against its status
+	pods, err := ctx.listPodsWithSelector(ctx.evaluationContext(*pdb), namespace, labelSelector)
+	degraded := err != nil && ctx.isPodListForbidden(namespace, err)
+	if err != nil && !degraded {
+		return errors.Wrap(err, "failed to list PDB pods")
+	}
+
+	report := InspectReport{
+		Cluster:            ctx.ClusterName,
+		PDB:                pdbNamespacedName(*pdb),
+		Selector:           labelSelector,
+		ExpectedPods:       pdb.Status.ExpectedPods,
+		CurrentHealthy:     pdb.Status.CurrentHealthy,
+		DesiredHealthy:     pdb.Status.DesiredHealthy,
+		DisruptionsAllowed: pdb.Status.DisruptionsAllowed,
+		MatchedPods:        podSliceNamespacedNames(pods),
+		Rules:              make([]RuleResult, 0),
+		Reasons:            make([]Reason, 0),
+	}
+	if pdb.Spec.MinAvailable != nil {
+		report.MinAvailable = pdb.Spec.MinAvailable.String()
+	}
+	if pdb.Spec.MaxUnavailable != nil {
+		report.MaxUnavailable = pdb.Spec.MaxUnavailable.String()
+	}
+
+	if ignored := ctx.inspectIgnored(*pdb); ignored != "" {
+		report.Action, report.ActionDetails = InspectActionIgnore, ignored
+		return ctx.writeInspectReport(report)
+	}
+
+	others, err := ctx.inspectNamespacePodDisruptionBudgets(*pdb)
+	if err != nil {
+		return err
+	}
+	if len(others) > 0 {
+		ctx.NamespacesWithMultiplePodDisruptionBudgets[namespace] = append([]policyv1.PodDisruptionBudget{*pdb}, others...)
+	}
+
+	in := &inspection{pdb: *pdb, pods: pods, others: others, degraded: degraded, nonBlocking: ctx.nonBlockingReason(*pdb)}
+	if !degraded {
+		if in.drain, err = ctx.evaluateDrainBlocking(pods); err != nil {
+			return err
+		}
+	}
+	for _, mode := range ReapModes {
+		rule, err := ctx.inspectRule(mode, in)
+		if err != nil {
+			return errors.Wrapf(err, "failed to evaluate %v", mode)
+		}
+		result := RuleResult{Rule: mode, Enabled: ctx.isReapModeEnabled(mode), Matched: rule.matched, Details: rule.details}
+		if result.Enabled && result.Matched {
+			report.Reasons = append(report.Reasons, ReapModeReasons[mode])
+		}
+		report.Rules = append(report.Rules, result)
+	}
+
+	report.Action, report.ActionDetails, err = ctx.inspectAction(*pdb, report.Reasons)
+	if err != nil {
+		return err
+	}
+	return ctx.writeInspectReport(report)
+}
+
+// inspectIgnored returns why a PDB is ignored by the scan, or an empty string when it is scanned
+func (ctx *ReaperContext) inspectIgnored(pdb policyv1.PodDisruptionBudget) string {
+	switch {
+	case common.StringSliceContains(ctx.ExcludedNamespaces, pdb.GetNamespace()):
+		return fmt.Sprintf("namespace %v is excluded", pdb.GetNamespace())
+	case ctx.isExcludedPodDisruptionBudget(pdb):
+		return "PDB is excluded by --exclude-pdb-names"
+	case pdb.GetDeletionTimestamp() != nil:
+		return "PDB is terminating"
+	case ctx.isOlderThanMaxAge(pdb):
+		return fmt.Sprintf("PDB is older than --max-age-to-consider %v", ctx.MaxAgeToConsider)
+	}
+	return ""
+}
+
+// inspectNamespacePodDisruptionBudgets returns the other scanned PDBs in the namespace of a PDB
+func (ctx *ReaperContext) inspectNamespacePodDisruptionBudgets(pdb policyv1.PodDisruptionBudget) ([]policyv1.PodDisruptionBudget, error) {
+	pdbList, err := ctx.KubernetesClient.PolicyV1().PodDisruptionBudgets(pdb.GetNamespace()).List(context.Background(), metav1.ListOptions{LabelSelector: ctx.RequiredPDBLabel})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list PDBs")
+	}
+
+	others := make([]policyv1.PodDisruptionBudget, 0)
+	for _, other := range pdbList.Items {
+		if other.GetName() == pdb.GetName() || ctx.inspectIgnored(other) != "" {
+			continue
+		}
+		others = append(others, other)
+	}
+	return others, nil
+}
+
+// inspection is a PDB evaluated by --inspect, along with the state shared by its rules
+type inspection struct {
+	pdb    policyv1.PodDisruptionBudget
+	pods   []corev1.Pod
+	others []policyv1.PodDisruptionBudget
+	// degraded is set when the pods of the PDB can't be listed
+	degraded bool
+	// nonBlocking is why the PDB is not blocking, empty when it is blocking
+	nonBlocking string
+	drain       ruleResult
+}
+
+// inspectRule evaluates a single reap mode against a PDB with the rules of a run, the modes which only apply to blocking
+// PDBs are not matched when the PDB is not blocking
+func (ctx *ReaperContext) inspectRule(mode string, in *inspection) (ruleResult, error) {
+	switch mode {
+	case ReapModeZeroMaxUnavailable:
+		return ctx.evaluateZeroMaxUnavailable(in.pdb)
+	case ReapModeDuplicateSelector:
+		return ctx.inspectDuplicateSelector(in.pdb, in.others)
+	case ReapModeMultiple:
+		if in.degraded {
+			return ruleResult{details: "pods can't be listed, the PDB is excluded from multiple PDB detection"}, nil
+		}
+		return ctx.inspectMultiple(in.pdb, in.pods, in.others)
+	case ReapModeStaleSelector:
+		if in.degraded {
+			return ruleResult{details: "pods can't be listed"}, nil
+		}
+		return ctx.evaluateStaleSelector(in.pdb, in.pods)
+	}
+
+	switch {
+	case in.nonBlocking != "":
+		return ruleResult{details: "not blocking, " + in.nonBlocking}, nil
+	case in.degraded && mode == ReapModeMisconfigured:
+		return ctx.evaluateStatusOnlyMisconfigured(in.pdb)
+	case in.degraded:
+		return ruleResult{details: "pods can't be listed, only misconfiguration is evaluated"}, nil
+	case ctx.isDrainBlockingOnly() && !in.drain.matched:
+		return ruleResult{details: "no pods on cordoned/draining nodes, spared by --drain-blocking-only"}, nil
+	}
+
+	switch mode {
+	case ReapModeMisconfigured:
+		return ctx.evaluateMisconfigured(in.pdb, in.pods)
+	case ReapModeCrashLoop:
+		return ctx.evaluateCrashLoop(in.pdb, ctx.statePods(in.pdb, in.pods)), nil
+	case ReapModeNotReady:
+		return ctx.evaluateNotReady(in.pdb, ctx.statePods(in.pdb, in.pods)), nil
+	case ReapModeDrainBlocking:
+		return in.drain, nil
+	case ReapModeMixedControllers:
+		return evaluateMixedControllers(in.pods), nil
+	case ReapModeSingleNode:
+		return evaluateSingleNode(in.pods), nil
+	case ReapModeHealthScore:
+		result, _, err := ctx.evaluateHealthScore(in.pdb, in.pods)
+		return result, err
+	}
+	return ruleResult{}, errors.Errorf("unknown reap mode %v", mode)
+}
+
+// inspectDuplicateSelector matches a PDB which shares an identical selector with another PDB in its namespace
+func (ctx *ReaperContext) inspectDuplicateSelector(pdb policyv1.PodDisruptionBudget, others []policyv1.PodDisruptionBudget) (ruleResult, error) {
+	if pdb.Spec.Selector == nil {
+		return ruleResult{details: "PDB has no selector"}, nil
+	}
+	labelSelector, err := common.GetSelectorString(pdb.Spec.Selector)
+	if err != nil {
+		return ruleResult{}, err
+	}
+	_, selectorPDBs, err := groupBySelector(append([]policyv1.PodDisruptionBudget{pdb}, others...))
+	if err != nil {
+		return ruleResult{}, err
+	}
+
+	duplicates := selectorPDBs[labelSelector]
+	reapable, err := ctx.reapableDuplicateSelector(duplicates)
+	if err != nil {
+		return ruleResult{}, err
+	}
+	result := ruleResult{
+		matched: len(intersectPodDisruptionBudgets(reapable, []policyv1.PodDisruptionBudget{pdb})) > 0,
+		details: fmt.Sprintf("PDBs with selector '%v': %v", labelSelector, pdbSliceNamespacedNames(duplicates[1:])),
+	}
+	if len(duplicates) > 1 && !result.matched {
+		result.details += ", no pods on cordoned/draining nodes, spared by --drain-blocking-only"
+	}
+	return result, nil
+}
+
+// inspectMultiple matches a PDB whose pods overlap another PDB in its namespace
+func (ctx *ReaperContext) inspectMultiple(pdb policyv1.PodDisruptionBudget, pods []corev1.Pod, others []policyv1.PodDisruptionBudget) (ruleResult, error) {
+	evaluated := make([]policyv1.PodDisruptionBudget, 0)
+	drainBlocking := make([]policyv1.PodDisruptionBudget, 0)
+	overlap := newPodOverlapTracker()
+	add := func(pdb policyv1.PodDisruptionBudget, pods []corev1.Pod) error {
+		if ctx.isDrainBlockingOnly() {
+			drain, err := ctx.evaluateDrainBlocking(pods)
+			if err != nil {
+				return err
+			}
+			if drain.matched {
+				drainBlocking = append(drainBlocking, pdb)
+			}
+		}
+		evaluated = append(evaluated, pdb)
+		overlap.add(pdb, pods)
+		return nil
+	}
+
+	if err := add(pdb, pods); err != nil {
+		return ruleResult{}, err
+	}
+	overlapping := make([]string, 0)
+	for _, other := range others {
+		otherPods, ok, err := ctx.listOverlapPods(other)
+		if err != nil {
+			return ruleResult{}, err
+		}
+		if !ok {
+			continue
+		}
+		if err := add(other, otherPods); err != nil {
+			return ruleResult{}, err
+		}
+		if ratio := podOverlapRatio(overlap.podNames[pdbNamespacedName(pdb)], overlap.podNames[pdbNamespacedName(other)]); ratio > 0 {
+			overlapping = append(overlapping, fmt.Sprintf("%v (%.2f)", pdbNamespacedName(other), ratio))
+		}
+	}
+
+	reapable, kept := ctx.reapableMultiple(evaluated, overlap, drainBlocking)
+	result := ruleResult{
+		matched: len(intersectPodDisruptionBudgets(reapable, []policyv1.PodDisruptionBudget{pdb})) > 0,
+		details: fmt.Sprintf("overlapping PDBs %v, --multiple-overlap-ratio %v", overlapping, ctx.MultipleOverlapRatio),
+	}
+	switch {
+	case result.matched && ctx.FixOverlap:
+		ctx.overlapFixes[pdbNamespacedName(pdb)] = true
+		result.details += ", redundant with --fix-overlap"
+	case len(intersectPodDisruptionBudgets(kept, []policyv1.PodDisruptionBudget{pdb})) > 0:
+		result.details += ", kept untouched by --fix-overlap"
+	}
+	return result, nil
+}
+
+// inspectAction returns the action a run would take on a PDB reapable for the given reasons, run level limits such as
+// --max-reaps-per-run, --reap-window or the circuit breaker are not evaluated
+func (ctx *ReaperContext) inspectAction(pdb policyv1.PodDisruptionBudget, reasons []Reason) (string, string, error) {
+	if len(reasons) == 0 {
+		return InspectActionNone, "the PDB is not reapable", nil
+	}
+
+	// like a run, a PDB whose pods can't be checked for protected priority classes is skipped
+	priorityClass, err := ctx.protectedPriorityClass(pdb)
+	if err != nil {
+		return InspectActionSkip, fmt.Sprintf("failed to determine if the PDB protects critical pods: %v", err), nil
+	}
+	if priorityClass != "" {
+		return InspectActionSkip, fmt.Sprintf("the PDB selects pods with protected priority class %v", priorityClass), nil
+	}
+
+	ctx.ReapableReasons[pdbNamespacedName(pdb)] = reasons
+	if ctx.isOverlapFix(pdb) {
+		if ctx.DryRun {
+			return InspectActionReport, fmt.Sprintf("redundant overlapping PDB would be patched to maxUnavailable %v, --dry-run is on", FixOverlapMaxUnavailable), nil
+		}
+		return InspectActionPatch, fmt.Sprintf("redundant overlapping PDB is patched to maxUnavailable %v", FixOverlapMaxUnavailable), nil
+	}
+	primaryReason := ctx.primaryReason(pdb)
+	if ctx.DryRun {
+		return InspectActionReport, fmt.Sprintf("reapable due to %v, --dry-run is on", primaryReason), nil
+	}
+	return InspectActionDelete, fmt.Sprintf("reapable due to %v", primaryReason), nil
+}
+
+func (ctx *ReaperContext) writeInspectReport(report InspectReport) error {
+	log.Infof("PDB %v inspected, action: %v, %v", report.PDB, report.Action, report.ActionDetails)
+	if ctx.Output == nil {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal inspect report")
+	}
+	if _, err = ctx.Output.Write(append(data, '\n')); err != nil {
+		return errors.Wrap(err, "failed to write inspect report")
+	}
+	return nil
+}
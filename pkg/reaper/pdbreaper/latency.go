@@ -0,0 +1,102 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pdbreaper
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/keikoproj/governor/pkg/reaper/common"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	PdbReaperEvaluationSecondsMetricName = "governor_pdb_reaper_evaluation_seconds"
+
+	// EvaluationReasonNone is the reason label of PDBs which were evaluated and not found reapable
+	EvaluationReasonNone = "None"
+)
+
+// evaluationTimer measures the time spent evaluating each PDB in a detection loop, starting the next PDB stops the
+// measurement of the previous one so PDBs skipped with continue are measured as well
+type evaluationTimer struct {
+	ctx     *ReaperContext
+	current string
+	started time.Time
+}
+
+func (ctx *ReaperContext) newEvaluationTimer() *evaluationTimer {
+	return &evaluationTimer{ctx: ctx}
+}
+
+// start stops the measurement of the previous PDB and starts measuring a PDB
+func (t *evaluationTimer) start(pdb policyv1.PodDisruptionBudget) {
+	t.stop()
+	t.current, t.started = pdbNamespacedName(pdb), t.ctx.now()
+}
+
+// stop adds the time spent on the current PDB to its evaluation time, a PDB evaluated by several detections is summed
+func (t *evaluationTimer) stop() {
+	if t.current == "" {
+		return
+	}
+	t.ctx.evaluationTimes[t.current] += t.ctx.now().Sub(t.started)
+	t.current = ""
+}
+
+// exposeEvaluationMetrics records an observation of the evaluation time of each evaluated PDB, labeled by the primary
+// reason it is reapable for, or EvaluationReasonNone
+func (ctx *ReaperContext) exposeEvaluationMetrics() {
+	if !ctx.isMetricsEnabled() {
+		return
+	}
+
+	names := make([]string, 0, len(ctx.evaluationTimes))
+	for namespacedName := range ctx.evaluationTimes {
+		names = append(names, namespacedName)
+	}
+	sort.Strings(names)
+
+	for _, namespacedName := range names {
+		namespace, name, _ := strings.Cut(namespacedName, "/")
+		pdb := policyv1.PodDisruptionBudget{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name}}
+		reason := EvaluationReasonNone
+		if _, ok := ctx.ReapableReasons[namespacedName]; ok {
+			reason = ctx.primaryReason(pdb).String()
+		}
+
+		tags := ctx.metricTags()
+		tags["namespace"] = namespace
+		tags["pdb"] = name
+		tags["reason"] = reason
+		if err := ctx.observeMetricValue(PdbReaperEvaluationSecondsMetricName, tags, ctx.evaluationTimes[namespacedName].Seconds()); err != nil {
+			log.Warnf("Pushing metric error:%v", err)
+		}
+	}
+}
+
+// observeMetricValue records a histogram observation, a MetricsAPI without histograms gets the value as a gauge
+func (ctx *ReaperContext) observeMetricValue(metricName string, tags map[string]string, value float64) error {
+	if err := ctx.runContext().Err(); err != nil {
+		return err
+	}
+	if api, ok := ctx.MetricsAPI.(common.HistogramMetricsAPI); ok {
+		return api.ObserveMetricValue(metricName, tags, value)
+	}
+	return ctx.setMetricValue(metricName, tags, value)
+}
@@ -0,0 +1,167 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pdbreaper
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/keikoproj/governor/pkg/reaper/common"
+	"github.com/pkg/errors"
+	policyv1 "k8s.io/api/policy/v1"
+)
+
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+
+	ExitCodeFindings = 2
+)
+
+// ErrValidationFindings is returned by a --validate run which found PDBs with error severity findings
+var ErrValidationFindings = errors.New("validation found misconfigured PDBs")
+
+// Finding is a single problem found on a PDB by --validate
+type Finding struct {
+	PDB      string `json:"pdb"`
+	Check    string `json:"check"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+// ValidationReport is written to the output by --validate
+type ValidationReport struct {
+	Cluster  string    `json:"cluster,omitempty"`
+	Scanned  int       `json:"scanned"`
+	Errors   int       `json:"errors"`
+	Warnings int       `json:"warnings"`
+	Findings []Finding `json:"findings"`
+}
+
+// ExitCode returns the process exit code for the result of a run, a --validate run which found PDBs with error
+// severity findings exits with ExitCodeFindings
+func ExitCode(err error) int {
+	switch {
+	case err == nil:
+		return 0
+	case errors.Is(err, ErrValidationFindings):
+		return ExitCodeFindings
+	default:
+		return 1
+	}
+}
+
+// lint reports misconfigured, blocking and overlapping PDBs as findings, without any events, metrics or deletion
+func (ctx *ReaperContext) lint() error {
+	pdbList, err := ctx.listPodDisruptionBudgets()
+	if err != nil {
+		return errors.Wrap(err, "failed to list PDBs")
+	}
+
+	report := ValidationReport{
+		Cluster:  ctx.ClusterName,
+		Findings: make([]Finding, 0),
+	}
+	namespacedPDBs := make(map[string][]policyv1.PodDisruptionBudget)
+	overlap := newPodOverlapTracker()
+	for _, pdb := range pdbList.Items {
+		if common.StringSliceContains(ctx.ExcludedNamespaces, pdb.GetNamespace()) || ctx.isExcludedPodDisruptionBudget(pdb) {
+			continue
+		}
+		if pdb.GetDeletionTimestamp() != nil {
+			continue
+		}
+		report.Scanned++
+
+		labelSelector, err := common.GetSelectorString(pdb.Spec.Selector)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get label selector from structured selector %+v", pdb.Spec.Selector)
+		}
+		pods, err := ctx.listPodsWithSelector(ctx.evaluationContext(pdb), pdb.GetNamespace(), labelSelector)
+		if err != nil {
+			return errors.Wrap(err, "failed to list PDB pods")
+		}
+		namespacedPDBs[pdb.GetNamespace()] = append(namespacedPDBs[pdb.GetNamespace()], pdb)
+		overlap.add(pdb, pods)
+
+		misconfigured, err := ctx.isPodDisruptionBudgetMisconfigured(pdb, pods)
+		if err != nil {
+			return errors.Wrap(err, "failed to determine if PDB is misconfigured")
+		}
+		switch {
+		case misconfigured:
+			report.add(pdb, ReapModeMisconfigured, SeverityError, "PDB configuration never allows a disruption")
+		case pdb.Status.DisruptionsAllowed == 0 && pdb.Status.ExpectedPods > 0:
+			report.add(pdb, "blocking", SeverityWarning, fmt.Sprintf("PDB currently allows 0 disruptions of %v expected pods", pdb.Status.ExpectedPods))
+		}
+	}
+
+	for _, pdbs := range namespacedPDBs {
+		for i := range pdbs {
+			overlapping := make([]string, 0)
+			for j := range pdbs {
+				if i != j && podOverlapRatio(overlap.podNames[pdbNamespacedName(pdbs[i])], overlap.podNames[pdbNamespacedName(pdbs[j])]) > 0 {
+					overlapping = append(overlapping, pdbs[j].GetName())
+				}
+			}
+			if len(overlapping) > 0 {
+				report.add(pdbs[i], ReapModeMultiple, SeverityError, fmt.Sprintf("PDB selects pods which are also selected by %v", strings.Join(overlapping, ",")))
+			}
+		}
+	}
+
+	sort.SliceStable(report.Findings, func(i, j int) bool {
+		if report.Findings[i].PDB != report.Findings[j].PDB {
+			return report.Findings[i].PDB < report.Findings[j].PDB
+		}
+		return report.Findings[i].Check < report.Findings[j].Check
+	})
+	for _, finding := range report.Findings {
+		log.Infof("%v: PDB %v failed check %v: %v", finding.Severity, finding.PDB, finding.Check, finding.Message)
+	}
+	log.Infof("validated %v PDBs, found %v errors and %v warnings", report.Scanned, report.Errors, report.Warnings)
+
+	if ctx.Output != nil {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal validation report")
+		}
+		if _, err = ctx.Output.Write(append(data, '\n')); err != nil {
+			return errors.Wrap(err, "failed to write validation report")
+		}
+	}
+
+	if report.Errors > 0 {
+		return ErrValidationFindings
+	}
+	return nil
+}
+
+func (r *ValidationReport) add(pdb policyv1.PodDisruptionBudget, check, severity, message string) {
+	r.Findings = append(r.Findings, Finding{
+		PDB:      pdbNamespacedName(pdb),
+		Check:    check,
+		Severity: severity,
+		Message:  message,
+	})
+	if severity == SeverityError {
+		r.Errors++
+	} else {
+		r.Warnings++
+	}
+}
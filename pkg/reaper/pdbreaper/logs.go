@@ -0,0 +1,80 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pdbreaper
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	DefaultPodLogsLines    = 10
+	DefaultPodLogsMaxBytes = 512
+
+	// truncatedLogsPrefix marks logs which were truncated to --probe-pod-logs-max-bytes
+	truncatedLogsPrefix = "..."
+)
+
+// probeCrashLoopLogs returns the last log lines of the first crashlooping container among the pods, and the
+// pod/container they were read from. Probing is best-effort, failures are logged and return empty logs.
+func (ctx *ReaperContext) probeCrashLoopLogs(pods []corev1.Pod, isCrashLooping containerStatusPredicate) (string, string) {
+	pod, container, ok := crashLoopContainer(pods, isCrashLooping)
+	if !ok {
+		return "", ""
+	}
+	source := fmt.Sprintf("%v/%v/%v", pod.GetNamespace(), pod.GetName(), container)
+
+	tailLines := int64(ctx.PodLogsLines)
+	limitBytes := int64(ctx.PodLogsMaxBytes)
+	options := &corev1.PodLogOptions{
+		Container:  container,
+		Previous:   true,
+		TailLines:  &tailLines,
+		LimitBytes: &limitBytes,
+	}
+	data, err := ctx.KubernetesClient.CoreV1().Pods(pod.GetNamespace()).GetLogs(pod.GetName(), options).DoRaw(ctx.runContext())
+	if err != nil {
+		log.Warnf("failed to probe logs of crashlooping container %v: %v", source, err)
+		return source, ""
+	}
+	return source, truncateLogs(strings.TrimSpace(string(data)), ctx.PodLogsMaxBytes)
+}
+
+// crashLoopContainer returns the first pod and container name which is crashlooping
+func crashLoopContainer(pods []corev1.Pod, isCrashLooping containerStatusPredicate) (corev1.Pod, string, bool) {
+	for _, pod := range pods {
+		statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+		for _, containerStatus := range statuses {
+			if isCrashLooping(containerStatus) {
+				return pod, containerStatus.Name, true
+			}
+		}
+	}
+	return corev1.Pod{}, "", false
+}
+
+// truncateLogs keeps the end of the logs when they exceed maxBytes, since the last lines are the most relevant
+func truncateLogs(logs string, maxBytes int) string {
+	if maxBytes <= 0 || len(logs) <= maxBytes {
+		return logs
+	}
+	if maxBytes <= len(truncatedLogsPrefix) {
+		return logs[len(logs)-maxBytes:]
+	}
+	return truncatedLogsPrefix + logs[len(logs)-maxBytes+len(truncatedLogsPrefix):]
+}
@@ -0,0 +1,78 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pdbreaper
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MaintenanceConfigMapKey is the key of the --maintenance-configmap which indicates planned maintenance when "true"
+const MaintenanceConfigMapKey = "maintenance"
+
+// loadMaintenance evaluates the maintenance indicators once per run, during planned maintenance only PDBs blocking
+// the drain of cordoned or maintenance nodes are reaped
+func (ctx *ReaperContext) loadMaintenance() error {
+	ctx.inMaintenance = false
+	ctx.maintenanceNodes = nil
+	if !ctx.NodeDrainIntegration {
+		return nil
+	}
+
+	if ctx.MaintenanceConfigMapName != "" {
+		cm, err := ctx.KubernetesClient.CoreV1().ConfigMaps(ctx.MaintenanceConfigMapNamespace).Get(context.Background(), ctx.MaintenanceConfigMapName, metav1.GetOptions{})
+		if err != nil && !kerrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to get maintenance configmap %v/%v", ctx.MaintenanceConfigMapNamespace, ctx.MaintenanceConfigMapName)
+		}
+		if err == nil {
+			if value, ok := cm.Data[MaintenanceConfigMapKey]; ok {
+				enabled, parseErr := strconv.ParseBool(value)
+				if parseErr != nil {
+					log.Warnf("ignoring invalid value '%v' of key %v in maintenance configmap %v/%v", value, MaintenanceConfigMapKey, ctx.MaintenanceConfigMapNamespace, ctx.MaintenanceConfigMapName)
+				}
+				ctx.inMaintenance = enabled
+			}
+		}
+	}
+
+	if ctx.MaintenanceNodeLabel != "" {
+		nodes, err := ctx.KubernetesClient.CoreV1().Nodes().List(context.Background(), metav1.ListOptions{LabelSelector: ctx.MaintenanceNodeLabel})
+		if err != nil {
+			return errors.Wrap(err, "failed to list maintenance nodes")
+		}
+		ctx.maintenanceNodes = make(map[string]bool)
+		for _, node := range nodes.Items {
+			ctx.maintenanceNodes[node.GetName()] = true
+		}
+		if len(ctx.maintenanceNodes) > 0 {
+			ctx.inMaintenance = true
+		}
+	}
+
+	if ctx.inMaintenance {
+		log.Infof("planned maintenance is in progress on %v labeled nodes, only PDBs blocking the drain of cordoned/maintenance nodes will be reaped", len(ctx.maintenanceNodes))
+	}
+	return nil
+}
+
+// isDrainBlockingOnly returns true when PDBs without pods on cordoned/draining nodes must be spared
+func (ctx *ReaperContext) isDrainBlockingOnly() bool {
+	return ctx.DrainBlockingOnly || ctx.inMaintenance
+}
@@ -0,0 +1,88 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pdbreaper
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/yaml"
+)
+
+// FixedMaxUnavailable is the maxUnavailable set in fixed manifests, which always allows a single disruption
+var FixedMaxUnavailable = intstr.FromInt(1)
+
+type fixedManifestMetadata struct {
+	Name      string            `json:"name"`
+	Namespace string            `json:"namespace"`
+	Labels    map[string]string `json:"labels,omitempty"`
+}
+
+// fixedManifest is a PDB manifest without status and server populated metadata, so it can be applied as is
+type fixedManifest struct {
+	APIVersion string                           `json:"apiVersion"`
+	Kind       string                           `json:"kind"`
+	Metadata   fixedManifestMetadata            `json:"metadata"`
+	Spec       policyv1.PodDisruptionBudgetSpec `json:"spec"`
+}
+
+// writeFixedManifests writes a corrected manifest for each misconfigured PDB to --fix-manifests-dir, one file per PDB
+func (ctx *ReaperContext) writeFixedManifests() error {
+	if err := os.MkdirAll(ctx.FixManifestsDir, 0755); err != nil {
+		return errors.Wrapf(err, "failed to create directory %v", ctx.FixManifestsDir)
+	}
+
+	for _, pdb := range ctx.ReapablePodDisruptionBudgets {
+		if !containsReason(ctx.ReapableReasons[pdbNamespacedName(pdb)], ReasonBlocking) {
+			continue
+		}
+
+		data, err := yaml.Marshal(newFixedManifest(pdb))
+		if err != nil {
+			return errors.Wrapf(err, "failed to marshal fixed manifest of PDB %v", pdbNamespacedName(pdb))
+		}
+
+		path := filepath.Join(ctx.FixManifestsDir, fmt.Sprintf("%v.%v.yaml", pdb.GetNamespace(), pdb.GetName()))
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			return errors.Wrapf(err, "failed to write fixed manifest of PDB %v", pdbNamespacedName(pdb))
+		}
+		log.Infof("wrote fixed manifest of PDB %v to %v", pdbNamespacedName(pdb), path)
+	}
+	return nil
+}
+
+// newFixedManifest returns the manifest of a PDB with minAvailable removed and a safe maxUnavailable
+func newFixedManifest(pdb policyv1.PodDisruptionBudget) fixedManifest {
+	spec := *pdb.Spec.DeepCopy()
+	spec.MinAvailable = nil
+	maxUnavailable := FixedMaxUnavailable
+	spec.MaxUnavailable = &maxUnavailable
+
+	return fixedManifest{
+		APIVersion: policyv1.SchemeGroupVersion.String(),
+		Kind:       "PodDisruptionBudget",
+		Metadata: fixedManifestMetadata{
+			Name:      pdb.GetName(),
+			Namespace: pdb.GetNamespace(),
+			Labels:    pdb.GetLabels(),
+		},
+		Spec: spec,
+	}
+}
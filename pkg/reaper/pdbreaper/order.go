@@ -0,0 +1,76 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pdbreaper
+
+import (
+	"sort"
+
+	policyv1 "k8s.io/api/policy/v1"
+)
+
+const (
+	DeletionOrderDiscovery     = "discovery"
+	DeletionOrderOldestFirst   = "oldest-first"
+	DeletionOrderMostPodsFirst = "most-pods-first"
+)
+
+// DeletionOrders are all known deletion ordering strategies
+var DeletionOrders = [...]string{DeletionOrderDiscovery, DeletionOrderOldestFirst, DeletionOrderMostPodsFirst}
+
+// orderedReapableDisruptionBudgets returns the reapable PDBs in the order they are deleted in, so that when
+// --max-reaps-per-run applies the prioritized PDBs are deleted first
+func (ctx *ReaperContext) orderedReapableDisruptionBudgets() []policyv1.PodDisruptionBudget {
+	pdbs := append([]policyv1.PodDisruptionBudget{}, ctx.ReapablePodDisruptionBudgets...)
+	switch ctx.DeletionOrder {
+	case DeletionOrderOldestFirst:
+		sort.SliceStable(pdbs, func(i, j int) bool {
+			return pdbs[i].CreationTimestamp.Before(&pdbs[j].CreationTimestamp)
+		})
+	case DeletionOrderMostPodsFirst:
+		sort.SliceStable(pdbs, func(i, j int) bool {
+			return pdbs[i].Status.ExpectedPods > pdbs[j].Status.ExpectedPods
+		})
+	}
+	if ctx.NamespaceFairness {
+		pdbs = roundRobinByNamespace(pdbs)
+	}
+	return pdbs
+}
+
+// roundRobinByNamespace interleaves PDBs across namespaces, taking one PDB of each namespace in turn while keeping the
+// order within each namespace, so that --max-reaps-per-run is not exhausted by a single namespace
+func roundRobinByNamespace(pdbs []policyv1.PodDisruptionBudget) []policyv1.PodDisruptionBudget {
+	namespaces := make([]string, 0)
+	byNamespace := make(map[string][]policyv1.PodDisruptionBudget)
+	for _, pdb := range pdbs {
+		namespace := pdb.GetNamespace()
+		if _, ok := byNamespace[namespace]; !ok {
+			namespaces = append(namespaces, namespace)
+		}
+		byNamespace[namespace] = append(byNamespace[namespace], pdb)
+	}
+
+	result := make([]policyv1.PodDisruptionBudget, 0, len(pdbs))
+	for len(result) < len(pdbs) {
+		for _, namespace := range namespaces {
+			if queue := byNamespace[namespace]; len(queue) > 0 {
+				result = append(result, queue[0])
+				byNamespace[namespace] = queue[1:]
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,125 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pdbreaper
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	RecordTypeDetection  = "detection"
+	RecordTypeDeletion   = "deletion"
+	RecordTypeSimulation = "simulation"
+
+	PostReapDisruptionsAllowedUnbounded = "unbounded"
+	SimulationNoteFmt                   = "deleting PDB %v removes all disruption constraints from the %v pods it selects"
+)
+
+// ActionRecord is a single reaper action, written as a line of NDJSON when --ndjson is set
+type ActionRecord struct {
+	Type      string    `json:"type"`
+	Cluster   string    `json:"cluster,omitempty"`
+	PDB       string    `json:"pdb"`
+	Reason    Reason    `json:"reason"`
+	Code      int       `json:"reasonCode"`
+	Timestamp time.Time `json:"timestamp"`
+
+	Simulation *ReapSimulation `json:"simulation,omitempty"`
+}
+
+// ReapSimulation is the disruption allowance of a PDB before and after it is reaped, reported in dry-run
+type ReapSimulation struct {
+	ExpectedPods               int32  `json:"expectedPods"`
+	MinAvailable               *int   `json:"minAvailable,omitempty"`
+	MaxUnavailable             *int   `json:"maxUnavailable,omitempty"`
+	DisruptionsAllowed         int32  `json:"disruptionsAllowed"`
+	PostReapDisruptionsAllowed string `json:"postReapDisruptionsAllowed"`
+	Note                       string `json:"note"`
+}
+
+// simulateReap resolves the current disruption allowance of a PDB, once it is deleted the pods it selects are unbounded
+func simulateReap(pdb policyv1.PodDisruptionBudget) ReapSimulation {
+	simulation := ReapSimulation{
+		ExpectedPods:               pdb.Status.ExpectedPods,
+		DisruptionsAllowed:         pdb.Status.DisruptionsAllowed,
+		PostReapDisruptionsAllowed: PostReapDisruptionsAllowedUnbounded,
+		Note:                       fmt.Sprintf(SimulationNoteFmt, pdbNamespacedName(pdb), pdb.Status.ExpectedPods),
+	}
+	resolve := func(budget *intstr.IntOrString) *int {
+		if budget == nil {
+			return nil
+		}
+		value, err := intstr.GetScaledValueFromIntOrPercent(budget, int(pdb.Status.ExpectedPods), true)
+		if err != nil {
+			log.Warnf("failed to resolve budget of pdb %v: %v", pdbNamespacedName(pdb), err)
+			return nil
+		}
+		return &value
+	}
+	simulation.MinAvailable = resolve(pdb.Spec.MinAvailable)
+	simulation.MaxUnavailable = resolve(pdb.Spec.MaxUnavailable)
+	return simulation
+}
+
+// emitRecord writes an action record to the output as it happens
+func (ctx *ReaperContext) emitRecord(recordType string, pdb policyv1.PodDisruptionBudget, reason Reason) {
+	if !ctx.NDJSON || ctx.Output == nil {
+		return
+	}
+
+	ctx.writeRecord(ActionRecord{
+		Type:      recordType,
+		Cluster:   ctx.ClusterName,
+		PDB:       pdbNamespacedName(pdb),
+		Reason:    reason,
+		Code:      reason.Code(),
+		Timestamp: ctx.now().UTC(),
+	})
+}
+
+// emitSimulation writes the dry-run simulation of reaping a PDB to the output
+func (ctx *ReaperContext) emitSimulation(pdb policyv1.PodDisruptionBudget, simulation ReapSimulation) {
+	if !ctx.NDJSON || ctx.Output == nil {
+		return
+	}
+
+	reason := ctx.primaryReason(pdb)
+	ctx.writeRecord(ActionRecord{
+		Type:       RecordTypeSimulation,
+		Cluster:    ctx.ClusterName,
+		PDB:        pdbNamespacedName(pdb),
+		Reason:     reason,
+		Code:       reason.Code(),
+		Timestamp:  ctx.now().UTC(),
+		Simulation: &simulation,
+	})
+}
+
+func (ctx *ReaperContext) writeRecord(record ActionRecord) {
+	data, err := json.Marshal(record)
+	if err != nil {
+		log.Warnf("failed to marshal action record: %v", err)
+		return
+	}
+	if _, err = ctx.Output.Write(append(data, '\n')); err != nil {
+		log.Warnf("failed to write action record: %v", err)
+	}
+}
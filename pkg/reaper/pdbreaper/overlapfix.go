@@ -0,0 +1,103 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pdbreaper
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+
+	"github.com/pkg/errors"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// FixOverlapMaxUnavailable is the maxUnavailable redundant overlapping PDBs are patched to by --fix-overlap, which
+// always allows the shared pods to be disrupted as far as the redundant PDB is concerned
+const FixOverlapMaxUnavailable = "100%"
+
+// redundantOverlappingPodDisruptionBudgets splits overlapping PDBs into the PDBs to keep and the redundant PDBs, the PDB
+// selecting the most pods is kept and every PDB sharing a pod with a kept PDB is redundant
+func redundantOverlappingPodDisruptionBudgets(pdbs []policyv1.PodDisruptionBudget, overlap *podOverlapTracker) ([]policyv1.PodDisruptionBudget, []policyv1.PodDisruptionBudget) {
+	sorted := append([]policyv1.PodDisruptionBudget{}, pdbs...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := len(overlap.podNames[pdbNamespacedName(sorted[i])]), len(overlap.podNames[pdbNamespacedName(sorted[j])])
+		if a != b {
+			return a > b
+		}
+		return pdbNamespacedName(sorted[i]) < pdbNamespacedName(sorted[j])
+	})
+
+	kept := make([]policyv1.PodDisruptionBudget, 0)
+	redundant := make([]policyv1.PodDisruptionBudget, 0)
+	for _, pdb := range sorted {
+		isRedundant := false
+		for _, k := range kept {
+			if podOverlapRatio(overlap.podNames[pdbNamespacedName(k)], overlap.podNames[pdbNamespacedName(pdb)]) > 0 {
+				isRedundant = true
+				break
+			}
+		}
+		if isRedundant {
+			redundant = append(redundant, pdb)
+		} else {
+			kept = append(kept, pdb)
+		}
+	}
+	return kept, redundant
+}
+
+// isOverlapFix returns true when a reapable PDB is patched by --fix-overlap rather than deleted, which is only the case
+// when it is reapable for no reason other than overlapping another PDB
+func (ctx *ReaperContext) isOverlapFix(pdb policyv1.PodDisruptionBudget) bool {
+	if !ctx.FixOverlap || !ctx.overlapFixes[pdbNamespacedName(pdb)] {
+		return false
+	}
+	for _, reason := range ctx.ReapableReasons[pdbNamespacedName(pdb)] {
+		if reason != ReasonMultiple {
+			return false
+		}
+	}
+	return true
+}
+
+// patchOverlap patches a redundant overlapping PDB to FixOverlapMaxUnavailable, removing its minAvailable
+func (ctx *ReaperContext) patchOverlap(pdb policyv1.PodDisruptionBudget) error {
+	patch := map[string]interface{}{
+		"spec": map[string]interface{}{
+			"minAvailable":   nil,
+			"maxUnavailable": FixOverlapMaxUnavailable,
+		},
+	}
+	data, err := json.Marshal(patch)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal overlap patch")
+	}
+
+	_, err = ctx.KubernetesClient.PolicyV1().PodDisruptionBudgets(pdb.GetNamespace()).Patch(context.Background(), pdb.GetName(), types.MergePatchType, data, metav1.PatchOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "failed to patch overlapping PDB %v", pdbNamespacedName(pdb))
+	}
+
+	err = ctx.publishEvent(pdb, ReasonPodDisruptionBudgetPatched, EventMessagePatchedFmt, FixOverlapMaxUnavailable)
+	if err != nil {
+		log.Warnf(err.Error())
+	}
+	ctx.PatchedPodDisruptionBudgetCount++
+	ctx.exposeMetric(pdb, ReasonPodDisruptionBudgetPatched, 1)
+	return nil
+}
@@ -0,0 +1,70 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pdbreaper
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/pkg/errors"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PausedAnnotationKey is the namespace annotation which pauses reaping in a namespace when set to true
+const PausedAnnotationKey = "pdb-reaper/paused"
+
+// PausedPermissions are the additional permissions needed when --honor-paused-namespaces is set
+var PausedPermissions = []authorizationv1.ResourceAttributes{
+	{Verb: "list", Group: "", Resource: "namespaces"},
+}
+
+// loadPausedNamespaces lists the namespaces annotated with PausedAnnotationKey once per run
+func (ctx *ReaperContext) loadPausedNamespaces() error {
+	ctx.pausedNamespaces = nil
+	if !ctx.HonorPausedNamespaces {
+		return nil
+	}
+
+	namespaces, err := ctx.KubernetesClient.CoreV1().Namespaces().List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		return errors.Wrap(err, "failed to list namespaces")
+	}
+	ctx.pausedNamespaces = make(map[string]bool)
+	for _, namespace := range namespaces.Items {
+		value, ok := namespace.GetAnnotations()[PausedAnnotationKey]
+		if !ok {
+			continue
+		}
+		paused, err := strconv.ParseBool(value)
+		if err != nil {
+			log.Warnf("ignoring invalid %v annotation value '%v' on namespace %v", PausedAnnotationKey, value, namespace.GetName())
+			continue
+		}
+		if paused {
+			ctx.pausedNamespaces[namespace.GetName()] = true
+		}
+	}
+	if len(ctx.pausedNamespaces) > 0 {
+		log.Infof("reaping is paused in %v namespaces annotated '%v=true'", len(ctx.pausedNamespaces), PausedAnnotationKey)
+	}
+	return nil
+}
+
+// isNamespacePaused returns true if a namespace is annotated with PausedAnnotationKey and --honor-paused-namespaces is set
+func (ctx *ReaperContext) isNamespacePaused(namespace string) bool {
+	return ctx.pausedNamespaces[namespace]
+}
@@ -19,7 +19,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"reflect"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/keikoproj/governor/pkg/reaper/common"
@@ -28,7 +30,9 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	policyv1 "k8s.io/api/policy/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
@@ -42,28 +46,76 @@ const (
 	EventReasonMultipleDetected              = "MultiplePodDisruptionBudgets"
 	EventReasonBlockingCrashLoopDetected     = "BlockingPodDisruptionBudgetWithCrashLoop"
 	EventReasonBlockingNotReadyStateDetected = "BlockingPodDisruptionBudgetWithNotReadyState"
-
-	EventMessageDeletedFmt   = "The PodDisruptionBudget %v has been deleted by pdb-reaper due to violation"
-	EventMessageBlockingFmt  = "The PodDisruptionBudget %v has been marked for deletion due to misconfiguration/not allowing disruptions"
-	EventMessageMultipleFmt  = "The PodDisruptionBudget %v has been marked for deletion due to multiple budgets targeting same pods"
-	EventMessageCrashLoopFmt = "The PodDisruptionBudget %v has been marked for deletion due to pods in CrashLoopBackOff blocking disruptions"
-	EventMessageNotReadyFmt  = "The PodDisruptionBudget %v has been marked for deletion due to pods in not-ready blocking disruptions"
-
-	PdbReaperResultMetricName = "governor_pdb_reaper_result"
+	EventReasonBlockingNodeDrainDetected     = "BlockingPodDisruptionBudgetWithNodeDrain"
+	EventReasonDuplicateSelectorDetected     = "DuplicateSelectorPodDisruptionBudgets"
+	EventReasonRecreatedDetected             = "RecreatedPodDisruptionBudget"
+	EventReasonZeroMaxUnavailableDetected    = "ZeroMaxUnavailablePodDisruptionBudget"
+	EventReasonHealthScoreDetected           = "UnhealthyPodDisruptionBudget"
+	EventReasonMixedControllersDetected      = "MixedControllersPodDisruptionBudget"
+	EventReasonPodDisruptionBudgetPatched    = "PodDisruptionBudgetPatched"
+	EventReasonSingleNodeDetected            = "SingleNodePodDisruptionBudget"
+	EventReasonStaleSelectorDetected         = "StaleSelectorPodDisruptionBudget"
+
+	EventMessageDeletedFmt            = "The PodDisruptionBudget %v has been deleted by pdb-reaper due to violation"
+	EventMessageDeletedReasonFmt      = "The PodDisruptionBudget %v has been deleted by pdb-reaper due to violation: %v"
+	EventMessageBlockingFmt           = "The PodDisruptionBudget %v has been marked for deletion due to misconfiguration/not allowing disruptions"
+	EventMessageMalformedFmt          = "The PodDisruptionBudget %v has been marked for deletion due to both maxUnavailable and minAvailable being set"
+	EventMessageMultipleFmt           = "The PodDisruptionBudget %v has been marked for deletion due to multiple budgets targeting same pods"
+	EventMessageCrashLoopFmt          = "The PodDisruptionBudget %v has been marked for deletion due to pods in CrashLoopBackOff blocking disruptions"
+	EventMessageCrashLoopLogsFmt      = "The PodDisruptionBudget %v has been marked for deletion due to pods in CrashLoopBackOff blocking disruptions, last logs of %v: %v"
+	EventMessageNotReadyFmt           = "The PodDisruptionBudget %v has been marked for deletion due to pods in not-ready blocking disruptions"
+	EventMessageNodeDrainFmt          = "The PodDisruptionBudget %v has been marked for deletion due to blocking the drain of cordoned nodes"
+	EventMessageDuplicateSelectorFmt  = "The PodDisruptionBudget %v has been marked for deletion due to another budget in the namespace having an identical selector"
+	EventMessageRecreatedFmt          = "The PodDisruptionBudget %v was recreated %v after being deleted by pdb-reaper, fix its source %v to stop the delete/recreate loop"
+	EventMessageZeroMaxUnavailableFmt = "The PodDisruptionBudget %v has been marked for deletion due to maxUnavailable resolving to 0, which forbids all voluntary disruptions"
+	EventMessageHealthScoreFmt        = "The PodDisruptionBudget %v has been marked for deletion due to its health score %.2f exceeding %v"
+	EventMessageMixedControllersFmt   = "The PodDisruptionBudget %v has been marked for deletion due to its selector matching pods of multiple controllers: %v"
+	EventMessageSingleNodeFmt         = "The PodDisruptionBudget %v has been marked for deletion due to all of its pods being scheduled on node %v, which permanently blocks the drain of that node"
+	EventMessageStaleSelectorFmt      = "The PodDisruptionBudget %v has been marked for deletion due to its selector matching no pods, likely left stale by a pod template label change of deployment %v, which no longer carries %v"
+	EventMessagePatchedFmt            = "The PodDisruptionBudget %v has been patched by pdb-reaper to maxUnavailable %v due to multiple budgets targeting same pods"
+
+	ClusterLabelKey = "pdb-reaper/cluster"
+
+	// ManagedAnnotationPrefix is the prefix of all annotations written or read by pdb-reaper
+	ManagedAnnotationPrefix = "pdb-reaper/"
+
+	WouldReapReasonAnnotationKey    = "pdb-reaper/would-reap-reason"
+	WouldReapTimestampAnnotationKey = "pdb-reaper/would-reap-timestamp"
+
+	PdbReaperResultMetricName         = "governor_pdb_reaper_result"
+	PdbReaperCircuitBreakerMetricName = "governor_pdb_reaper_circuit_breaker_tripped"
+	PdbReaperMatchedPodsMetricName    = "governor_pdb_reaper_matched_pods"
+	PdbReaperDeletedMetricName        = "governor_pdb_reaper_deleted"
+	PdbReaperRBACSufficientMetricName = "governor_pdb_reaper_rbac_sufficient"
+	PdbReaperAffectedOwnersMetricName = "governor_pdb_reaper_affected_owners"
+	PdbReaperReapableCountMetricName  = "governor_pdb_reaper_reapable_detected"
+	PdbReaperReapedCountMetricName    = "governor_pdb_reaper_reaped"
+
+	PdbReaperZeroExpectedPodsMetricName   = "governor_pdb_reaper_zero_expected_pods_matched"
+	PdbReaperResolvedBudgetMetricName     = "governor_pdb_reaper_resolved_budget"
+	PdbReaperBlockingDurationMetricName   = "governor_pdb_reaper_blocking_duration_seconds"
+	PdbReaperMatchedPodsAverageMetricName = "governor_pdb_reaper_matched_pods_average"
+	PdbReaperMatchedPodsMaxMetricName     = "governor_pdb_reaper_matched_pods_max"
+
+	BudgetTypeMaxUnavailable = "maxUnavailable"
+	BudgetTypeMinAvailable   = "minAvailable"
 )
 
-var EventReasons = [...]string{EventReasonPodDisruptionBudgetDeleted, EventReasonBlockingDetected, EventReasonMultipleDetected,
-	EventReasonBlockingCrashLoopDetected, EventReasonBlockingNotReadyStateDetected}
-
 // Run is the main runner function for pdb-reaper, and will initialize and start the pdb-reaper
 func Run(args *Args) error {
 	log.SetFormatter(&logrus.TextFormatter{
 		FullTimestamp: true,
 	})
 
-	ctx := NewReaperContext(args)
+	ctx, err := newReaperContext(args)
+	if err != nil {
+		return errors.Wrap(err, "failed to validate arguments")
+	}
+	if err := validateRunOnce(args); err != nil {
+		return errors.Wrap(&RunError{Code: ErrorCodeInvalidConfig, Err: err}, "failed to validate arguments")
+	}
 
-	err := ctx.execute()
+	err = ctx.execute()
 	if err != nil {
 		return errors.Wrap(err, "execution failed")
 	}
@@ -71,22 +123,166 @@ func Run(args *Args) error {
 	return nil
 }
 
+// executeContext executes a run whose side effects, such as events and metric pushes, stop once runCtx is done
+func (ctx *ReaperContext) executeContext(runCtx context.Context) error {
+	ctx.runCtx = runCtx
+	defer func() {
+		ctx.runCtx = nil
+	}()
+	return ctx.execute()
+}
+
+// runContext returns the context of the current run
+func (ctx *ReaperContext) runContext() context.Context {
+	if ctx.runCtx == nil {
+		return context.Background()
+	}
+	return ctx.runCtx
+}
+
 func (ctx *ReaperContext) execute() error {
 	log.Info("pdb-reaper starting")
 
+	var err error
+	if len(ctx.Clusters) > 0 {
+		err = ctx.executeClusters()
+	} else {
+		err = ctx.executeCluster()
+	}
+
+	ctx.flushMetrics()
+
+	if err != nil && ctx.ErrorWebhookURL != "" && !errors.Is(err, ErrValidationFindings) {
+		if webhookErr := ctx.reportErrorWebhook(err); webhookErr != nil {
+			log.Warnf("failed to report error to webhook: %v", webhookErr)
+		}
+	}
+	return err
+}
+
+func (ctx *ReaperContext) executeCluster() error {
+	ctx.resetRunState()
+	defer ctx.releaseEvaluationContexts()
+
+	if ctx.CleanupAnnotations {
+		if err := ctx.cleanupAnnotations(); err != nil {
+			return errors.Wrap(err, "failed to clean up annotations")
+		}
+		return nil
+	}
+
+	if err := ctx.loadExcludedNamespaces(); err != nil {
+		return errors.Wrap(err, "failed to load excluded namespaces")
+	}
+
+	if ctx.Lint {
+		return ctx.lint()
+	}
+
+	if ctx.DrainAssistNode != "" {
+		return ctx.drainAssist()
+	}
+
+	if ctx.InspectPDB != "" {
+		return ctx.inspect()
+	}
+
+	if err := ctx.checkServerVersion(); err != nil {
+		return errors.Wrap(err, "server version check failed")
+	}
+
+	if err := ctx.selfCheckRBAC(); err != nil {
+		return errors.Wrap(err, "RBAC self-check failed")
+	}
+
+	if err := ctx.selfTest(); err != nil {
+		return errors.Wrap(err, "self-test failed")
+	}
+
+	if err := ctx.loadState(); err != nil {
+		return errors.Wrap(err, "failed to load state")
+	}
+
+	if err := ctx.loadMaintenance(); err != nil {
+		return errors.Wrap(err, "failed to load maintenance indicators")
+	}
+
+	if err := ctx.loadEnforcedNamespaces(); err != nil {
+		return errors.Wrap(err, "failed to load enforced namespaces")
+	}
+
+	if err := ctx.loadPausedNamespaces(); err != nil {
+		return errors.Wrap(err, "failed to load paused namespaces")
+	}
+
+	if ctx.ApplyPlan != "" {
+		if err := ctx.applyPlan(); err != nil {
+			return &RunError{Code: ErrorCodeReapFailed, Err: errors.Wrap(err, "failed to apply plan")}
+		}
+		return errors.Wrap(ctx.saveState(), "failed to save state")
+	}
+
 	if err := ctx.scan(); err != nil {
-		return errors.Wrap(err, "failed to scan cluster")
+		return &RunError{Code: ErrorCodeScanFailed, Err: errors.Wrap(err, "failed to scan cluster")}
 	}
 
+	ctx.detectRecreatedDisruptionBudgets()
+
 	if err := ctx.reap(); err != nil {
-		return errors.Wrap(err, "failed to reap PDBs")
+		return &RunError{Code: ErrorCodeReapFailed, Err: errors.Wrap(err, "failed to reap PDBs")}
+	}
+
+	if err := ctx.writePlan(); err != nil {
+		return err
+	}
+
+	// reapable PDBs which were not reaped were deferred, e.g. by --max-reaps-per-run, --reap-window or --dry-run
+	ctx.exposeClusterMetric(PdbReaperReapableCountMetricName, float64(ctx.ReapablePodDisruptionBudgetsCount))
+	ctx.exposeClusterMetric(PdbReaperReapedCountMetricName, float64(ctx.ReapedPodDisruptionBudgetCount))
+	if ctx.MaxWritesPerRun > 0 {
+		ctx.exposeClusterMetric(PdbReaperSuppressedWritesMetricName, float64(ctx.SuppressedWritesCount))
+	}
+
+	if err := ctx.writeFindingsCSV(); err != nil {
+		log.Warnf(err.Error())
+	}
+
+	if err := ctx.publishNamespaceFindings(); err != nil {
+		log.Warnf(err.Error())
+	}
+
+	if err := ctx.publishRunSummary(); err != nil {
+		log.Warnf(err.Error())
+	}
+
+	if err := ctx.saveState(); err != nil {
+		return errors.Wrap(err, "failed to save state")
 	}
 	return nil
 }
 
 func (ctx *ReaperContext) reap() error {
+	// detection relies on the PDB status, which is not reliable while the disruption controller is unhealthy
+	if ctx.isDisruptionStatusStale() {
+		return nil
+	}
+
+	err := ctx.handleZeroMaxUnavailableDisruptionBudgets()
+	if err != nil {
+		return errors.Wrap(err, "failed to handle zero maxUnavailable PDBs")
+	}
+
+	err = ctx.handleDuplicateSelectorDisruptionBudgets()
+	if err != nil {
+		return errors.Wrap(err, "failed to handle duplicate selector PDBs")
+	}
+
+	err = ctx.handleStaleSelectorDisruptionBudgets()
+	if err != nil {
+		return errors.Wrap(err, "failed to handle stale selector PDBs")
+	}
 
-	err := ctx.handleMultipleDisruptionBudgets()
+	err = ctx.handleMultipleDisruptionBudgets()
 	if err != nil {
 		return errors.Wrap(err, "failed to handle multiple PDBs")
 	}
@@ -95,6 +291,30 @@ func (ctx *ReaperContext) reap() error {
 	if err != nil {
 		return errors.Wrap(err, "failed to handle blocking PDBs")
 	}
+	ctx.exposeEvaluationMetrics()
+	ctx.exposeMatchedPodsSummaryMetrics()
+	ctx.updateViolationStreaks()
+	ctx.resetStaleMetrics()
+
+	if ctx.FixManifestsDir != "" {
+		if err := ctx.writeFixedManifests(); err != nil {
+			return errors.Wrap(err, "failed to write fixed manifests")
+		}
+		log.Info("fixed manifests mode is on, no PDBs will be deleted")
+		return nil
+	}
+
+	if ctx.DryRunAnnotate {
+		ctx.annotateDryRunDisruptionBudgets()
+	}
+
+	if ctx.isCircuitBreakerTripped() {
+		return nil
+	}
+
+	if ctx.isFirstRun() {
+		return nil
+	}
 
 	err = ctx.handleReapableDisruptionBudgets()
 	if err != nil {
@@ -104,12 +324,42 @@ func (ctx *ReaperContext) reap() error {
 	return nil
 }
 
+// isCircuitBreakerTripped returns true when the ratio of reapable to scanned PDBs is abnormally high, an abnormal ratio
+// must be seen in two consecutive runs before reaping is allowed to proceed
+func (ctx *ReaperContext) isCircuitBreakerTripped() bool {
+	if ctx.MaxReapableRatio == 0 || ctx.ScannedPodDisruptionBudgetsCount == 0 {
+		return false
+	}
+
+	reapableCount := len(uniqueStrings(pdbSliceNamespacedNames(ctx.ReapablePodDisruptionBudgets)))
+	ratio := float64(reapableCount) / float64(ctx.ScannedPodDisruptionBudgetsCount)
+	if ratio <= ctx.MaxReapableRatio {
+		ctx.State.CircuitBreakerTripped = false
+		ctx.exposeClusterMetric(PdbReaperCircuitBreakerMetricName, 0)
+		return false
+	}
+
+	if ctx.State.CircuitBreakerTripped {
+		log.Warnf("reapable ratio %.2f exceeds %.2f for a second consecutive run, proceeding with reaping", ratio, ctx.MaxReapableRatio)
+		ctx.State.CircuitBreakerTripped = false
+		ctx.exposeClusterMetric(PdbReaperCircuitBreakerMetricName, 0)
+		return false
+	}
+
+	log.Warnf("circuit breaker tripped, %v/%v scanned PDBs are reapable (ratio %.2f exceeds %.2f), reaping is skipped until confirmed by the next run",
+		reapableCount, ctx.ScannedPodDisruptionBudgetsCount, ratio, ctx.MaxReapableRatio)
+	ctx.State.CircuitBreakerTripped = true
+	ctx.exposeClusterMetric(PdbReaperCircuitBreakerMetricName, 1)
+	return true
+}
+
 func (ctx *ReaperContext) scan() error {
 
 	var (
 		namespacedPDBs = make(map[string][]policyv1.PodDisruptionBudget)
+		debounced      = make([]policyv1.PodDisruptionBudget, 0)
 	)
-	pdbs, err := ctx.KubernetesClient.PolicyV1().PodDisruptionBudgets("").List(context.Background(), metav1.ListOptions{})
+	pdbs, err := ctx.listPodDisruptionBudgets()
 	if err != nil {
 		return errors.Wrap(err, "failed to list PDBs")
 	}
@@ -121,49 +371,207 @@ func (ctx *ReaperContext) scan() error {
 			log.Warnf("ignoring namespace %v since it's excluded", namespace)
 			continue
 		}
+
+		if ctx.PausedSkipDetection && ctx.isNamespacePaused(namespace) {
+			log.Warnf("ignoring pdb %v since namespace %v is paused", pdbNamespacedName(pdb), namespace)
+			continue
+		}
+
+		if ctx.isExcludedPodDisruptionBudget(pdb) {
+			log.Warnf("ignoring pdb %v since it's excluded", pdbNamespacedName(pdb))
+			continue
+		}
+
+		// if pdb is already being deleted, e.g. stuck on a finalizer, it should not be acted on again
+		if pdb.GetDeletionTimestamp() != nil {
+			log.Infof("ignoring pdb %v since it is already terminating", pdbNamespacedName(pdb))
+			continue
+		}
+
+		// very old PDBs are assumed to be intentional and may be load-bearing
+		if ctx.isOlderThanMaxAge(pdb) {
+			log.Warnf("ignoring pdb %v since it is older than --max-age-to-consider %v", pdbNamespacedName(pdb), ctx.MaxAgeToConsider)
+			continue
+		}
+
+		// a pdb which keeps updating is evaluated at most once per --update-debounce
+		if since, ok := ctx.isUpdateDebounced(pdb); ok {
+			log.Infof("ignoring pdb %v since it was updated after its last evaluation %v ago, it is evaluated again after --update-debounce %v", pdbNamespacedName(pdb), since.Round(time.Second), ctx.UpdateDebounce)
+			debounced = append(debounced, pdb)
+			continue
+		}
+
+		// a pdb returned again, e.g. by a retried list, should not be evaluated twice
+		if ctx.isProcessed(pdb) {
+			log.Infof("ignoring pdb %v since generation %v was already processed in this run", pdbNamespacedName(pdb), pdb.GetGeneration())
+			continue
+		}
+		ctx.markProcessed(pdb)
+		// the evaluation of the pdb is bounded by --pdb-timeout from here on
+		ctx.evaluationContext(pdb)
+		ctx.ScannedPodDisruptionBudgetsCount++
+		ctx.ScannedPodDisruptionBudgets = append(ctx.ScannedPodDisruptionBudgets, pdb)
 		namespacedPDBs[namespace] = append(namespacedPDBs[namespace], pdb)
 	}
 
+	// a fat-fingered exclusion list should not let the reaper loose on far more namespaces than expected
+	if ctx.MaxNamespaces > 0 && len(namespacedPDBs) > ctx.MaxNamespaces {
+		return errors.Errorf("%v namespaces are in scope, exceeding --max-namespaces %v, check the exclusions", len(namespacedPDBs), ctx.MaxNamespaces)
+	}
+
 	for namespace, pdbs := range namespacedPDBs {
 		if len(pdbs) > 1 {
 			ctx.NamespacesWithMultiplePodDisruptionBudgets[namespace] = append(ctx.NamespacesWithMultiplePodDisruptionBudgets[namespace], pdbs...)
 		}
 	}
 
-	for _, pdb := range pdbs.Items {
+	ctx.updateBlockingRuns()
+	ctx.updateEvaluations(debounced)
+
+	for _, pdb := range ctx.ScannedPodDisruptionBudgets {
 		var (
 			namespace = pdb.GetNamespace()
 		)
 
-		if common.StringSliceContains(ctx.ExcludedNamespaces, namespace) {
-			log.Warnf("ignoring namespace %v since it's excluded", namespace)
+		// a pdb allowing disruptions, or whose selector matches no pods / expected, is non-blocking
+		if reason := ctx.nonBlockingReason(pdb); reason != "" {
+			log.Infof("ignoring pdb %v since %v", pdbNamespacedName(pdb), reason)
+			if pdb.Status.DisruptionsAllowed == 0 {
+				ctx.classifyZeroExpectedPods(pdb)
+			}
+			continue
+		}
+		// a pdb which occasionally allows disruptions is not blocking until it has been stuck for --blocking-runs runs
+		if runs := ctx.State.BlockingRuns[pdbNamespacedName(pdb)]; ctx.BlockingRuns > 1 && runs < ctx.BlockingRuns {
+			log.Infof("ignoring pdb %v since it has allowed 0 disruptions for %v/%v consecutive runs", pdbNamespacedName(pdb), runs, ctx.BlockingRuns)
 			continue
 		}
 
-		// if pdb is allowing disruptions, it is non-blocking
+		ctx.ClusterBlockingPodDisruptionBudgets[namespace] = append(ctx.ClusterBlockingPodDisruptionBudgets[namespace], pdb)
+		ctx.exposeMetric(pdb, ReasonPodDisruptionBudgetDeleted, 0)
+		ctx.exposeResolvedBudgetMetrics(pdb)
+		ctx.exposeBlockingDurationMetric(pdb)
+	}
+
+	return nil
+}
+
+// updateBlockingRuns counts the consecutive runs in which each scanned PDB allowed zero disruptions, PDBs which are
+// no longer scanned are dropped from the state
+func (ctx *ReaperContext) updateBlockingRuns() {
+	if ctx.BlockingRuns <= 1 {
+		return
+	}
+
+	blockingRuns := make(map[string]int)
+	for _, pdb := range ctx.ScannedPodDisruptionBudgets {
 		if pdb.Status.DisruptionsAllowed != 0 {
-			log.Infof("ignoring pdb %v since it is allowing %v disruptions", pdbNamespacedName(pdb), pdb.Status.DisruptionsAllowed)
 			continue
 		}
-		// if no pods match the selector / expected, it is non-blocking
-		if pdb.Status.ExpectedPods == 0 {
-			log.Infof("ignoring pdb %v since it is expecting 0 pods", pdbNamespacedName(pdb))
+		namespacedName := pdbNamespacedName(pdb)
+		blockingRuns[namespacedName] = ctx.State.BlockingRuns[namespacedName] + 1
+	}
+	ctx.State.BlockingRuns = blockingRuns
+}
+
+// listPodDisruptionBudgets lists the PDBs in all namespaces, restricted to PDBs carrying --pdb-label-required when set
+func (ctx *ReaperContext) listPodDisruptionBudgets() (*policyv1.PodDisruptionBudgetList, error) {
+	return ctx.KubernetesClient.PolicyV1().PodDisruptionBudgets("").List(context.Background(), metav1.ListOptions{LabelSelector: ctx.RequiredPDBLabel})
+}
+
+// isExcludedPodDisruptionBudget returns true if a PDB matches an --exclude-pdb-names entry, entries in the form
+// namespace/name match a single PDB, while bare names match PDBs with that name in any namespace
+func (ctx *ReaperContext) isExcludedPodDisruptionBudget(pdb policyv1.PodDisruptionBudget) bool {
+	for _, excluded := range ctx.ExcludedPodDisruptionBudgets {
+		if strings.Contains(excluded, "/") {
+			if excluded == pdbNamespacedName(pdb) {
+				return true
+			}
 			continue
 		}
+		if excluded == pdb.GetName() {
+			return true
+		}
+	}
+	return false
+}
 
-		ctx.ClusterBlockingPodDisruptionBudgets[namespace] = append(ctx.ClusterBlockingPodDisruptionBudgets[namespace], pdb)
-		ctx.exposeMetric(pdb, EventReasonPodDisruptionBudgetDeleted, 0)
+// isOlderThanMaxAge returns true if a PDB was created longer than --max-age-to-consider ago
+func (ctx *ReaperContext) isOlderThanMaxAge(pdb policyv1.PodDisruptionBudget) bool {
+	created := pdb.GetCreationTimestamp()
+	if ctx.MaxAgeToConsider == 0 || created.IsZero() {
+		return false
 	}
+	return ctx.now().Sub(created.Time) > ctx.MaxAgeToConsider
+}
 
-	return nil
+// isZeroExpectedPodsEvaluated returns true if a PDB expecting 0 pods should still be evaluated, which is the case when
+// --evaluate-zero-expected-pods is set and its selector matches live pods, e.g. due to a controller bug or bare pods
+func (ctx *ReaperContext) isZeroExpectedPodsEvaluated(pdb policyv1.PodDisruptionBudget) bool {
+	if !ctx.EvaluateZeroExpectedPods {
+		return false
+	}
+
+	labelSelector, err := common.GetSelectorString(pdb.Spec.Selector)
+	if err != nil {
+		log.Warnf("failed to get label selector of pdb %v: %v", pdbNamespacedName(pdb), err)
+		return false
+	}
+	pods, err := ctx.listPodsWithSelector(ctx.evaluationContext(pdb), pdb.GetNamespace(), labelSelector)
+	if err != nil {
+		log.Warnf("failed to list pods of pdb %v: %v", pdbNamespacedName(pdb), err)
+		return false
+	}
+	if len(pods) == 0 {
+		return false
+	}
+
+	log.Warnf("pdb %v is expecting 0 pods but its selector matches %v live pods, evaluating it using the live pod count", pdbNamespacedName(pdb), len(pods))
+	ctx.exposePodDisruptionBudgetMetric(pdb, PdbReaperZeroExpectedPodsMetricName, float64(len(pods)))
+	return true
 }
 
 func (ctx *ReaperContext) handleReapableDisruptionBudgets() error {
-	for _, pdb := range ctx.ReapablePodDisruptionBudgets {
-		var (
-			name      = pdb.GetName()
-			namespace = pdb.GetNamespace()
-		)
+	ctx.pruneReapedState()
+
+	if ctx.isOutsideReapWindow(len(ctx.ReapablePodDisruptionBudgets)) {
+		return nil
+	}
+
+	affectedOwners := make(map[string]bool)
+	defer func() {
+		log.Infof("reaped PDBs of %v distinct owners by label %v", len(affectedOwners), ctx.OwnerLabel)
+		ctx.exposeClusterMetric(PdbReaperAffectedOwnersMetricName, float64(len(affectedOwners)))
+	}()
+
+	var attempted int
+	pdbs := ctx.orderedReapableDisruptionBudgets()
+	for i, pdb := range pdbs {
+		if ctx.isReapSkipped(pdb) {
+			continue
+		}
+		if ctx.isReapDeferred(attempted, len(pdbs)-i) {
+			break
+		}
+		attempted++
+
+		if ctx.isOverlapFix(pdb) {
+			if ctx.DryRun {
+				log.Warnf("DryRun is on, overlapping PDB %v will not be patched to maxUnavailable %v", pdbNamespacedName(pdb), FixOverlapMaxUnavailable)
+				continue
+			}
+			log.Infof("patching redundant overlapping PDB %v to maxUnavailable %v", pdbNamespacedName(pdb), FixOverlapMaxUnavailable)
+			if err := ctx.patchOverlap(pdb); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if ctx.PlanOut != "" {
+			ctx.planReap(pdb)
+			continue
+		}
+
 		log.Infof("deleting offending PDB %v", pdbNamespacedName(pdb))
 
 		pdbDump, err := json.Marshal(pdb)
@@ -174,164 +582,784 @@ func (ctx *ReaperContext) handleReapableDisruptionBudgets() error {
 
 		if ctx.DryRun {
 			log.Warnf("DryRun is on, PDB %v will not be deleted", pdbNamespacedName(pdb))
+			simulation := simulateReap(pdb)
+			log.Infof("PDB %v currently allows %v disruptions of %v expected pods, after deletion disruptions are %v: %v", pdbNamespacedName(pdb), simulation.DisruptionsAllowed, simulation.ExpectedPods, simulation.PostReapDisruptionsAllowed, simulation.Note)
+			ctx.emitSimulation(pdb, simulation)
 			continue
 		}
 
-		err = ctx.KubernetesClient.PolicyV1().PodDisruptionBudgets(namespace).Delete(context.Background(), name, metav1.DeleteOptions{})
+		deleted, err := ctx.reapPodDisruptionBudget(pdb, ctx.primaryReason(pdb))
 		if err != nil {
-			if kerrors.IsNotFound(err) {
-				continue
-			}
-			return errors.Wrapf(err, "failed to delete offending PDB %v", pdbNamespacedName(pdb))
+			return err
 		}
-		err = ctx.publishEvent(pdb, EventReasonPodDisruptionBudgetDeleted, EventMessageDeletedFmt)
-		if err != nil {
-			log.Warnf(err.Error())
+		if owner := pdb.GetLabels()[ctx.OwnerLabel]; deleted && owner != "" {
+			affectedOwners[owner] = true
 		}
-		ctx.ReapedPodDisruptionBudgetCount++
-		ctx.exposeMetric(pdb, EventReasonPodDisruptionBudgetDeleted, 1)
 	}
 	return nil
 }
 
-func (ctx *ReaperContext) handleBlockingDisruptionBudgets() error {
-
-	for namespace, pdbs := range ctx.ClusterBlockingPodDisruptionBudgets {
+// isOutsideReapWindow returns true when the deletion of the given number of reapable PDBs is deferred since the run is
+// outside of --reap-window
+func (ctx *ReaperContext) isOutsideReapWindow(reapable int) bool {
+	if ctx.ReapWindow == nil || reapable == 0 || ctx.ReapWindow.Contains(ctx.now()) {
+		return false
+	}
+	log.Warnf("outside of --reap-window %v, deferring deletion of %v reapable PDBs", ctx.ReapWindow, reapable)
+	return true
+}
 
-		for _, pdb := range pdbs {
-			log.Infof("evaluating blocking PDB %v", pdbNamespacedName(pdb))
-			labelSelector, err := common.GetSelectorString(pdb.Spec.Selector)
-			if err != nil {
-				return errors.Wrapf(err, "failed to get label selector from structured selector %+v", pdb.Spec.Selector)
-			}
+// isReapSkipped returns true when a reapable PDB must not be deleted, the checks are shared by a run and --apply so
+// that a planned deletion is spared the same way
+func (ctx *ReaperContext) isReapSkipped(pdb policyv1.PodDisruptionBudget) bool {
+	namespace := pdb.GetNamespace()
 
-			pods, err := ctx.listPodsWithSelector(namespace, labelSelector)
-			if err != nil {
-				return errors.Wrap(err, "failed to list PDB pods")
-			}
+	if reapedAt, ok := ctx.isInReapCooldown(pdb); ok {
+		log.Warnf("PDB %v was reaped at %v and recreated within the --reap-cooldown window, skipping it, this may indicate a delete/recreate loop", pdbNamespacedName(pdb), reapedAt.Format(time.RFC3339))
+		return true
+	}
 
-			if ctx.ReapMisconfigured {
-				misconfigured, err := isMisconfigured(pdb, pods)
-				if err != nil {
-					return errors.Wrap(err, "failed to determine if PDB is misconfigured")
-				}
+	priorityClass, err := ctx.protectedPriorityClass(pdb)
+	if err != nil {
+		// a PDB which may protect critical pods is spared, without holding back the other reapable PDBs
+		log.Warnf("failed to determine if PDB %v protects critical pods, skipping it: %v", pdbNamespacedName(pdb), err)
+		return true
+	}
+	if priorityClass != "" {
+		log.Warnf("PDB %v selects pods with priority class %v which is protected by --protected-priority-classes, skipping it", pdbNamespacedName(pdb), priorityClass)
+		return true
+	}
 
-				if misconfigured {
-					log.Infof("PDB %v is marked reapable due to blocking configuration", pdbNamespacedName(pdb))
-					ctx.addReapablePodDisruptionBudget(pdb)
-					err = ctx.publishEvent(pdb, EventReasonBlockingDetected, EventMessageBlockingFmt)
-					if err != nil {
-						log.Warnf(err.Error())
-					}
-					ctx.exposeMetric(pdb, EventReasonBlockingDetected, 1)
-				} else {
-					ctx.exposeMetric(pdb, EventReasonBlockingDetected, 0)
-				}
-			}
+	if ctx.isNamespacePaused(namespace) {
+		log.Infof("PDB %v is reapable but namespace %v is annotated '%v=true', skipping it", pdbNamespacedName(pdb), namespace, PausedAnnotationKey)
+		return true
+	}
 
-			if ctx.ReapCrashLoop {
-				if crashLoop := isPodsInCrashloop(pods, ctx.CrashLoopRestartCount, ctx.AllCrashLoop); crashLoop {
-					log.Infof("PDB %v is marked reapable due to targeted pods in crashloop: %+v", pdbNamespacedName(pdb), podSliceNamespacedNames(pods))
-					ctx.addReapablePodDisruptionBudget(pdb)
-					err = ctx.publishEvent(pdb, EventReasonBlockingCrashLoopDetected, EventMessageCrashLoopFmt)
-					if err != nil {
-						log.Warnf(err.Error())
-					}
-					ctx.exposeMetric(pdb, EventReasonBlockingCrashLoopDetected, 1)
-				} else {
-					ctx.exposeMetric(pdb, EventReasonBlockingCrashLoopDetected, 0)
-				}
-			} else {
-				ctx.exposeMetric(pdb, EventReasonBlockingCrashLoopDetected, 0)
-			}
+	if ctx.namespaceMode(namespace) == ModeReport {
+		log.Infof("PDB %v is reapable but namespace %v is not labeled '%v', only reporting it", pdbNamespacedName(pdb), namespace, ctx.EnforceNamespaceLabel)
+		return true
+	}
 
-			if ctx.ReapNotReady {
-				if notReady := isPodsInNotReadyState(pods, ctx.ReapNotReadyThreshold, ctx.AllNotReady); notReady {
-					log.Infof("PDB %v is marked reapable due to targeted pods in not-ready state: %+v", pdbNamespacedName(pdb), podSliceNamespacedNames(pods))
-					ctx.addReapablePodDisruptionBudget(pdb)
-					err = ctx.publishEvent(pdb, EventReasonBlockingNotReadyStateDetected, EventMessageNotReadyFmt)
-					if err != nil {
-						log.Warnf(err.Error())
-					}
-					ctx.exposeMetric(pdb, EventReasonBlockingNotReadyStateDetected, 1)
-				} else {
-					ctx.exposeMetric(pdb, EventReasonBlockingNotReadyStateDetected, 0)
-				}
-			} else {
-				ctx.exposeMetric(pdb, EventReasonBlockingNotReadyStateDetected, 0)
-			}
-		}
+	if reportOnly, runs, threshold := ctx.isReportOnly(pdb); reportOnly {
+		log.Infof("PDB %v has been reapable for %v consecutive runs, only reporting it until --report-only-threshold %v is exceeded", pdbNamespacedName(pdb), runs, threshold)
+		return true
 	}
-	return nil
+	return false
 }
 
-func (ctx *ReaperContext) handleMultipleDisruptionBudgets() error {
-
-	if !ctx.ReapMultiple {
-		return nil
+// isReapDeferred returns true when the remaining deletions of a run are deferred to the next run by
+// --max-reaps-per-run or --defer-reaps-on-write-cap
+func (ctx *ReaperContext) isReapDeferred(attempted, remaining int) bool {
+	if ctx.MaxReapsPerRun > 0 && attempted >= ctx.MaxReapsPerRun {
+		log.Warnf("reached --max-reaps-per-run %v, deferring deletion of %v reapable PDBs to the next run", ctx.MaxReapsPerRun, remaining)
+		return true
 	}
+	if ctx.DeferReapsOnWriteCap && ctx.isWriteCapReached() {
+		log.Warnf("reached --max-writes-per-run %v, deferring deletion of %v reapable PDBs to the next run", ctx.MaxWritesPerRun, remaining)
+		return true
+	}
+	return false
+}
 
-	for namespace, pdbs := range ctx.NamespacesWithMultiplePodDisruptionBudgets {
-		namespacePodsWithBudget := make([]corev1.Pod, 0)
-
-		// check if multiple PDBs in a namespace contain reference to same pods
-		for _, pdb := range pdbs {
-			log.Infof("evaluating multi-namespace PDB %v", pdbNamespacedName(pdb))
-
-			labelSelector, err := common.GetSelectorString(pdb.Spec.Selector)
-			if err != nil {
-				return errors.Wrapf(err, "failed to get label selector from structured selector %+v", pdb.Spec.Selector)
-			}
-
-			pods, err := ctx.listPodsWithSelector(namespace, labelSelector)
-			if err != nil {
-				return errors.Wrap(err, "failed to list PDB pods")
-			}
+// reapPodDisruptionBudget backs up and deletes a reapable PDB, returning false when it was not deleted
+func (ctx *ReaperContext) reapPodDisruptionBudget(pdb policyv1.PodDisruptionBudget, primaryReason Reason) (bool, error) {
+	// a PDB whose backup failed is not deleted, so it can't be lost
+	if err := ctx.backupPodDisruptionBudget(pdb); err != nil {
+		log.Warnf("failed to back up PDB %v, skipping its deletion: %v", pdbNamespacedName(pdb), err)
+		return false, nil
+	}
 
-			namespacePodsWithBudget = append(namespacePodsWithBudget, pods...)
-		}
+	ctx.annotateDeleteReason(pdb, primaryReason)
 
-		if isContainDuplicatePods(namespacePodsWithBudget) {
-			log.Infof("PDBs %+v are marked reapable - pods %+v has multiple PDBs", pdbSliceNamespacedNames(pdbs), podSliceNamespacedNames(namespacePodsWithBudget))
-			ctx.addReapablePodDisruptionBudget(pdbs...)
-			for _, pdb := range pdbs {
-				err := ctx.publishEvent(pdb, EventReasonMultipleDetected, EventMessageMultipleFmt)
-				if err != nil {
-					log.Warnf(err.Error())
-				}
-				ctx.exposeMetric(pdb, EventReasonMultipleDetected, 1)
-			}
-		} else {
-			for _, pdb := range pdbs {
-				ctx.exposeMetric(pdb, EventReasonMultipleDetected, 0)
-			}
+	err := ctx.KubernetesClient.PolicyV1().PodDisruptionBudgets(pdb.GetNamespace()).Delete(context.Background(), pdb.GetName(), metav1.DeleteOptions{GracePeriodSeconds: ctx.DeleteGraceSeconds})
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return false, nil
 		}
+		return false, errors.Wrapf(err, "failed to delete offending PDB %v", pdbNamespacedName(pdb))
 	}
-	return nil
+	err = ctx.publishEvent(pdb, ReasonPodDisruptionBudgetDeleted, EventMessageDeletedReasonFmt, primaryReason)
+	if err != nil {
+		log.Warnf(err.Error())
+	}
+	ctx.ReapedPodDisruptionBudgetCount++
+	ctx.reapedNames = append(ctx.reapedNames, pdbNamespacedName(pdb))
+	ctx.emitRecord(RecordTypeDeletion, pdb, ReasonPodDisruptionBudgetDeleted)
+	ctx.exposeMetric(pdb, ReasonPodDisruptionBudgetDeleted, 1)
+	ctx.exposeReasonMetric(pdb, PdbReaperDeletedMetricName, primaryReason, 1)
+	if ctx.reapedStateWindow() > 0 {
+		ctx.State.ReapedAt[pdbNamespacedName(pdb)] = ctx.now().UTC()
+	}
+	ctx.annotateReapedWorkloads(pdb)
+	ctx.confirmEviction(pdb)
+	return true, nil
 }
 
-func (ctx *ReaperContext) listPodsWithSelector(namespace, selector string) ([]corev1.Pod, error) {
-	var pods []corev1.Pod
-	podList, err := ctx.KubernetesClient.CoreV1().Pods(namespace).List(context.Background(), metav1.ListOptions{LabelSelector: selector})
-	if err != nil {
-		return pods, errors.Wrapf(err, "failed to list pods with selector '%v'", selector)
+// isInReapCooldown returns the time a PDB with the same namespace/name was reaped at, if it was reaped within the cooldown window
+func (ctx *ReaperContext) isInReapCooldown(pdb policyv1.PodDisruptionBudget) (time.Time, bool) {
+	if ctx.ReapCooldown == 0 {
+		return time.Time{}, false
 	}
-	pods = append(pods, podList.Items...)
-	return pods, nil
+	reapedAt, ok := ctx.State.ReapedAt[pdbNamespacedName(pdb)]
+	if !ok {
+		return time.Time{}, false
+	}
+	return reapedAt, ctx.now().Sub(reapedAt) < ctx.ReapCooldown
 }
 
-func (ctx *ReaperContext) publishEvent(pdb policyv1.PodDisruptionBudget, reason, msg string) error {
-	var (
+// pruneReapedState removes reaped PDBs which are outside of the cooldown and recreate windows from the state
+func (ctx *ReaperContext) pruneReapedState() {
+	if ctx.State.ReapedAt == nil {
+		ctx.State.ReapedAt = make(map[string]time.Time)
+	}
+	for namespacedName, reapedAt := range ctx.State.ReapedAt {
+		if ctx.now().Sub(reapedAt) >= ctx.reapedStateWindow() {
+			delete(ctx.State.ReapedAt, namespacedName)
+		}
+	}
+	for namespacedName := range ctx.State.Recreated {
+		if _, ok := ctx.State.ReapedAt[namespacedName]; !ok {
+			delete(ctx.State.Recreated, namespacedName)
+		}
+	}
+}
+
+// reapedStateWindow returns how long reaped PDBs are tracked in the state
+func (ctx *ReaperContext) reapedStateWindow() time.Duration {
+	if ctx.RecreateWindow > ctx.ReapCooldown {
+		return ctx.RecreateWindow
+	}
+	return ctx.ReapCooldown
+}
+
+// annotateDryRunDisruptionBudgets marks reapable PDBs with the reason they would be reaped for, and clears the mark from
+// PDBs which are no longer reapable
+func (ctx *ReaperContext) annotateDryRunDisruptionBudgets() {
+	now := ctx.now().UTC().Format(time.RFC3339)
+	for _, pdb := range ctx.ScannedPodDisruptionBudgets {
+		reasons, reapable := ctx.ReapableReasons[pdbNamespacedName(pdb)]
+		_, annotated := pdb.GetAnnotations()[WouldReapReasonAnnotationKey]
+
+		var annotations map[string]interface{}
+		switch {
+		case reapable:
+			log.Infof("annotating PDB %v with would-reap reason %v", pdbNamespacedName(pdb), reasons)
+			annotations = map[string]interface{}{
+				WouldReapReasonAnnotationKey:    strings.Join(reasonStrings(reasons), ","),
+				WouldReapTimestampAnnotationKey: now,
+			}
+		case annotated:
+			log.Infof("clearing would-reap annotations from PDB %v since it is no longer reapable", pdbNamespacedName(pdb))
+			annotations = map[string]interface{}{
+				WouldReapReasonAnnotationKey:    nil,
+				WouldReapTimestampAnnotationKey: nil,
+			}
+		default:
+			continue
+		}
+
+		if err := ctx.patchAnnotations(pdb, annotations); err != nil {
+			log.Warnf(err.Error())
+		}
+	}
+}
+
+// cleanupAnnotations removes all annotations managed by pdb-reaper from all considered PDBs, regardless of excluded namespaces
+func (ctx *ReaperContext) cleanupAnnotations() error {
+	pdbs, err := ctx.listPodDisruptionBudgets()
+	if err != nil {
+		return errors.Wrap(err, "failed to list PDBs")
+	}
+
+	var cleaned int
+	for _, pdb := range pdbs.Items {
+		annotations := make(map[string]interface{})
+		for key := range pdb.GetAnnotations() {
+			if strings.HasPrefix(key, ManagedAnnotationPrefix) && !common.StringSliceContains(OwnerAnnotationKeys, key) {
+				annotations[key] = nil
+			}
+		}
+		if len(annotations) == 0 {
+			continue
+		}
+
+		if ctx.DryRun {
+			log.Warnf("DryRun is on, managed annotations will not be removed from PDB %v", pdbNamespacedName(pdb))
+			continue
+		}
+
+		log.Infof("removing %v managed annotations from PDB %v", len(annotations), pdbNamespacedName(pdb))
+		if err := ctx.patchAnnotations(pdb, annotations); err != nil {
+			return err
+		}
+		cleaned++
+	}
+	log.Infof("removed managed annotations from %v PDBs", cleaned)
+	return nil
+}
+
+func (ctx *ReaperContext) handleBlockingDisruptionBudgets() error {
+
+	timer := ctx.newEvaluationTimer()
+	defer timer.stop()
+
+	progress := ctx.newProgressReporter("blocking PDB detection", len(ctx.ClusterBlockingPodDisruptionBudgets))
+	for _, pdbs := range ctx.ClusterBlockingPodDisruptionBudgets {
+		progress.step()
+
+		for _, pdb := range pdbs {
+			timer.start(pdb)
+			log.Infof("evaluating blocking PDB %v", pdbNamespacedName(pdb))
+			labelSelector, err := common.GetSelectorString(pdb.Spec.Selector)
+			if err != nil {
+				return errors.Wrapf(err, "failed to get label selector from structured selector %+v", pdb.Spec.Selector)
+			}
+
+			pods, err := ctx.listPodsReconciled(pdb, labelSelector)
+			if err != nil {
+				if errors.Is(err, context.DeadlineExceeded) {
+					log.Warnf("evaluation of PDB %v timed out after %v, skipping it: %v", pdbNamespacedName(pdb), ctx.PodDisruptionBudgetTimeout, err)
+					continue
+				}
+				if ctx.isPodListForbidden(pdb.GetNamespace(), err) {
+					if err := ctx.handleStatusOnlyBlocking(pdb); err != nil {
+						return err
+					}
+					continue
+				}
+				return errors.Wrap(err, "failed to list PDB pods")
+			}
+			ctx.matchedPods[pdbNamespacedName(pdb)] = len(pods)
+			ctx.exposePodDisruptionBudgetMetric(pdb, PdbReaperMatchedPodsMetricName, float64(len(pods)))
+			diagnostics := ctx.diagnostics(pdb)
+			diagnostics.MatchedPods = len(pods)
+
+			if ctx.isDrainAware() {
+				drain, err := ctx.evaluateDrainBlocking(pods)
+				if err != nil {
+					return err
+				}
+				diagnostics.DrainingPods = drain.count
+
+				if !drain.matched && ctx.isDrainBlockingOnly() {
+					log.Infof("PDB %v has no pods on cordoned/draining nodes, sparing it due to --drain-blocking-only", pdbNamespacedName(pdb))
+					continue
+				}
+
+				if ctx.ReapDrainBlocking && drain.matched {
+					log.Infof("PDB %v is marked reapable due to blocking the drain of nodes, %v", pdbNamespacedName(pdb), drain.details)
+					ctx.markReapable(pdb, ReasonBlockingNodeDrain, EventMessageNodeDrainFmt, drain.eventArgs...)
+				} else {
+					ctx.exposeMetric(pdb, ReasonBlockingNodeDrain, 0)
+				}
+			}
+
+			if ctx.ReapMisconfigured {
+				misconfigured, err := ctx.evaluateMisconfigured(pdb, pods)
+				if err != nil {
+					return err
+				}
+				ctx.markMisconfigured(pdb, misconfigured.matched)
+			}
+
+			if ctx.ReapMixedControllers {
+				if mixed := evaluateMixedControllers(pods); mixed.matched {
+					log.Infof("PDB %v is marked reapable due to matching %v", pdbNamespacedName(pdb), mixed.details)
+					ctx.markReapable(pdb, ReasonMixedControllers, EventMessageMixedControllersFmt, mixed.eventArgs...)
+				} else {
+					ctx.exposeMetric(pdb, ReasonMixedControllers, 0)
+				}
+			}
+
+			if ctx.ReapSingleNode {
+				if singleNode := evaluateSingleNode(pods); singleNode.matched {
+					log.Infof("PDB %v is marked reapable due to all of its pods being scheduled on node %v", pdbNamespacedName(pdb), singleNode.eventArgs[0])
+					ctx.markReapable(pdb, ReasonSingleNode, EventMessageSingleNodeFmt, singleNode.eventArgs...)
+				} else {
+					ctx.exposeMetric(pdb, ReasonSingleNode, 0)
+				}
+			}
+
+			statePods := ctx.statePods(pdb, pods)
+			crashLoop := ctx.evaluateCrashLoop(pdb, statePods)
+			diagnostics.CrashLoopPods = crashLoop.count
+			if ctx.ReapCrashLoop && crashLoop.matched {
+				log.Infof("PDB %v is marked reapable due to targeted pods in crashloop: %+v", pdbNamespacedName(pdb), podSliceNamespacedNames(statePods))
+				message, args := EventMessageCrashLoopFmt, []interface{}{}
+				if ctx.ProbePodLogs {
+					if source, logs := ctx.probeCrashLoopLogs(statePods, ctx.crashLoopPredicate(ctx.crashLoopThreshold(pdb))); logs != "" {
+						log.Infof("last logs of crashlooping container %v: %v", source, logs)
+						message, args = EventMessageCrashLoopLogsFmt, []interface{}{source, logs}
+					}
+				}
+				ctx.markReapable(pdb, ReasonBlockingCrashLoop, message, args...)
+			} else {
+				ctx.exposeMetric(pdb, ReasonBlockingCrashLoop, 0)
+			}
+
+			if ctx.ReapNotReady {
+				notReady := ctx.evaluateNotReady(pdb, statePods)
+				diagnostics.NotReadyPods = notReady.count
+				if notReady.matched {
+					log.Infof("PDB %v is marked reapable due to targeted pods in not-ready state: %+v", pdbNamespacedName(pdb), podSliceNamespacedNames(statePods))
+					ctx.markReapable(pdb, ReasonBlockingNotReadyState, EventMessageNotReadyFmt)
+				} else {
+					ctx.exposeMetric(pdb, ReasonBlockingNotReadyState, 0)
+				}
+			} else {
+				ctx.exposeMetric(pdb, ReasonBlockingNotReadyState, 0)
+			}
+
+			if ctx.ReapHealthScore {
+				unhealthy, score, err := ctx.evaluateHealthScore(pdb, pods)
+				if err != nil {
+					return err
+				}
+				diagnostics.HealthScore = score
+				ctx.exposePodDisruptionBudgetMetric(pdb, PdbReaperHealthScoreMetricName, score)
+				log.Infof("PDB %v has a health score of %v", pdbNamespacedName(pdb), unhealthy.details)
+				if unhealthy.matched {
+					log.Infof("PDB %v is marked reapable due to its health score exceeding %v", pdbNamespacedName(pdb), ctx.HealthScoreThreshold)
+					ctx.markReapable(pdb, ReasonHealthScore, EventMessageHealthScoreFmt, unhealthy.eventArgs...)
+				} else {
+					ctx.exposeMetric(pdb, ReasonHealthScore, 0)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (ctx *ReaperContext) handleMultipleDisruptionBudgets() error {
+
+	if !ctx.ReapMultiple {
+		return nil
+	}
+
+	timer := ctx.newEvaluationTimer()
+	defer timer.stop()
+
+	progress := ctx.newProgressReporter("multiple PDB detection", len(ctx.NamespacesWithMultiplePodDisruptionBudgets))
+	for namespace, pdbs := range ctx.NamespacesWithMultiplePodDisruptionBudgets {
+		progress.step()
+		drainBlocking := make([]policyv1.PodDisruptionBudget, 0)
+		evaluated := make([]policyv1.PodDisruptionBudget, 0)
+		// only pod names are kept, the pods of each PDB are released once it is evaluated
+		overlap := newPodOverlapTracker()
+
+		// check if multiple PDBs in a namespace contain reference to same pods
+		for _, pdb := range pdbs {
+			timer.start(pdb)
+			log.Infof("evaluating multi-namespace PDB %v", pdbNamespacedName(pdb))
+
+			pods, ok, err := ctx.listOverlapPods(pdb)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				continue
+			}
+			ctx.matchedPods[pdbNamespacedName(pdb)] = len(pods)
+			ctx.exposePodDisruptionBudgetMetric(pdb, PdbReaperMatchedPodsMetricName, float64(len(pods)))
+			ctx.diagnostics(pdb).MatchedPods = len(pods)
+
+			if ctx.isDrainBlockingOnly() {
+				drain, err := ctx.evaluateDrainBlocking(pods)
+				if err != nil {
+					return err
+				}
+				if drain.matched {
+					drainBlocking = append(drainBlocking, pdb)
+				}
+			}
+
+			evaluated = append(evaluated, pdb)
+			overlap.add(pdb, pods)
+		}
+		timer.stop()
+
+		var kept []policyv1.PodDisruptionBudget
+		pdbs, kept = ctx.reapableMultiple(evaluated, overlap, drainBlocking)
+		if ctx.FixOverlap && len(pdbs) > 0 {
+			log.Infof("keeping overlapping PDBs %+v, redundant PDBs %+v will be patched", pdbSliceNamespacedNames(kept), pdbSliceNamespacedNames(pdbs))
+			for _, pdb := range pdbs {
+				ctx.overlapFixes[pdbNamespacedName(pdb)] = true
+			}
+		}
+
+		reapable := make(map[string]bool)
+		if len(pdbs) > 0 {
+			sharedPods := overlap.sharedPods()
+			log.Infof("PDBs %+v are marked reapable - pods %+v in namespace %v have multiple PDBs", pdbSliceNamespacedNames(pdbs), sharedPods, namespace)
+			ctx.addReapablePodDisruptionBudget(ReasonMultiple, pdbs...)
+			for _, pdb := range pdbs {
+				err := ctx.publishEvent(pdb, ReasonMultiple, EventMessageMultipleFmt)
+				if err != nil {
+					log.Warnf(err.Error())
+				}
+				ctx.exposeMetric(pdb, ReasonMultiple, 1)
+				reapable[pdbNamespacedName(pdb)] = true
+			}
+		}
+		for _, pdb := range evaluated {
+			if !reapable[pdbNamespacedName(pdb)] {
+				ctx.exposeMetric(pdb, ReasonMultiple, 0)
+			}
+		}
+	}
+	return nil
+}
+
+// podOverlapTracker accumulates the pods selected by each PDB in a namespace as the PDBs are evaluated, by name only,
+// so that memory is bounded by the pod names rather than by every pod listed for every PDB
+type podOverlapTracker struct {
+	// podNames are the names of the pods selected by each PDB
+	podNames map[string][]string
+	// selectedBy are the PDBs selecting each pod
+	selectedBy map[string][]string
+}
+
+func newPodOverlapTracker() *podOverlapTracker {
+	return &podOverlapTracker{
+		podNames:   make(map[string][]string),
+		selectedBy: make(map[string][]string),
+	}
+}
+
+// add records the pods selected by a PDB, the pods are not retained
+func (t *podOverlapTracker) add(pdb policyv1.PodDisruptionBudget, pods []corev1.Pod) {
+	namespacedName := pdbNamespacedName(pdb)
+	names := make([]string, 0, len(pods))
+	for _, pod := range pods {
+		names = append(names, pod.GetName())
+		t.selectedBy[pod.GetName()] = append(t.selectedBy[pod.GetName()], namespacedName)
+	}
+	t.podNames[namespacedName] = names
+}
+
+// sharedPods returns the sorted names of the pods selected by more than one PDB
+func (t *podOverlapTracker) sharedPods() []string {
+	shared := make([]string, 0)
+	for name, selecting := range t.selectedBy {
+		if len(selecting) > 1 {
+			shared = append(shared, name)
+		}
+	}
+	sort.Strings(shared)
+	return shared
+}
+
+// sharingPodDisruptionBudgets returns the PDBs which select at least one pod selected by another PDB, the eviction API
+// refuses to evict such pods regardless of the disruptions each PDB allows
+func sharingPodDisruptionBudgets(pdbs []policyv1.PodDisruptionBudget, overlap *podOverlapTracker) []policyv1.PodDisruptionBudget {
+	sharing := make(map[string]bool)
+	for _, name := range overlap.sharedPods() {
+		for _, namespacedName := range overlap.selectedBy[name] {
+			sharing[namespacedName] = true
+		}
+	}
+
+	result := make([]policyv1.PodDisruptionBudget, 0)
+	for _, pdb := range pdbs {
+		if sharing[pdbNamespacedName(pdb)] {
+			result = append(result, pdb)
+		}
+	}
+	return result
+}
+
+// overlappingPodDisruptionBudgets returns the PDBs which share at least the given ratio of the pods of the larger PDB
+// with another PDB
+func overlappingPodDisruptionBudgets(pdbs []policyv1.PodDisruptionBudget, overlap *podOverlapTracker, ratio float64) []policyv1.PodDisruptionBudget {
+	overlapping := make(map[string]bool)
+	for i := range pdbs {
+		for j := i + 1; j < len(pdbs); j++ {
+			a, b := pdbNamespacedName(pdbs[i]), pdbNamespacedName(pdbs[j])
+			if podOverlapRatio(overlap.podNames[a], overlap.podNames[b]) >= ratio {
+				log.Infof("PDBs %v and %v overlap by at least %v of their pods", a, b, ratio)
+				overlapping[a] = true
+				overlapping[b] = true
+			}
+		}
+	}
+
+	result := make([]policyv1.PodDisruptionBudget, 0)
+	for _, pdb := range pdbs {
+		if overlapping[pdbNamespacedName(pdb)] {
+			result = append(result, pdb)
+		}
+	}
+	return result
+}
+
+// podOverlapRatio returns the number of pods shared by two lists of pod names, relative to the larger list
+func podOverlapRatio(a, b []string) float64 {
+	larger := len(a)
+	if len(b) > larger {
+		larger = len(b)
+	}
+	if larger == 0 {
+		return 0
+	}
+
+	names := make(map[string]bool)
+	for _, name := range a {
+		names[name] = true
+	}
+	shared := 0
+	for _, name := range b {
+		if names[name] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(larger)
+}
+
+// intersectPodDisruptionBudgets returns the PDBs in a which are also in b
+func intersectPodDisruptionBudgets(a, b []policyv1.PodDisruptionBudget) []policyv1.PodDisruptionBudget {
+	names := make(map[string]bool)
+	for _, pdb := range b {
+		names[pdbNamespacedName(pdb)] = true
+	}
+	result := make([]policyv1.PodDisruptionBudget, 0)
+	for _, pdb := range a {
+		if names[pdbNamespacedName(pdb)] {
+			result = append(result, pdb)
+		}
+	}
+	return result
+}
+
+// handleDuplicateSelectorDisruptionBudgets marks PDBs in the same namespace which share an identical selector as reapable,
+// which unlike overlapping pods is certain to be a duplicate regardless of which pods currently exist
+func (ctx *ReaperContext) handleDuplicateSelectorDisruptionBudgets() error {
+
+	if !ctx.ReapDuplicateSelector {
+		return nil
+	}
+
+	for _, pdbs := range ctx.NamespacesWithMultiplePodDisruptionBudgets {
+		selectors, selectorPDBs, err := groupBySelector(pdbs)
+		if err != nil {
+			return err
+		}
+
+		for _, labelSelector := range selectors {
+			if len(selectorPDBs[labelSelector]) < 2 {
+				ctx.exposeMetric(selectorPDBs[labelSelector][0], ReasonDuplicateSelector, 0)
+				continue
+			}
+
+			duplicates, err := ctx.reapableDuplicateSelector(selectorPDBs[labelSelector])
+			if err != nil {
+				return err
+			}
+			if len(duplicates) == 0 {
+				continue
+			}
+
+			log.Infof("PDBs %+v are marked reapable - identical selector '%v'", pdbSliceNamespacedNames(duplicates), labelSelector)
+			ctx.addReapablePodDisruptionBudget(ReasonDuplicateSelector, duplicates...)
+			for _, pdb := range duplicates {
+				err := ctx.publishEvent(pdb, ReasonDuplicateSelector, EventMessageDuplicateSelectorFmt)
+				if err != nil {
+					log.Warnf(err.Error())
+				}
+				ctx.exposeMetric(pdb, ReasonDuplicateSelector, 1)
+			}
+		}
+	}
+	return nil
+}
+
+// handleZeroMaxUnavailableDisruptionBudgets marks scanned PDBs whose maxUnavailable resolves to 0 as reapable, regardless
+// of whether they are blocking or the state of their pods, since they forbid all voluntary disruptions
+func (ctx *ReaperContext) handleZeroMaxUnavailableDisruptionBudgets() error {
+
+	if !ctx.ReapZeroMaxUnavailable {
+		return nil
+	}
+
+	for _, pdb := range ctx.ScannedPodDisruptionBudgets {
+		zeroMaxUnavailable, err := ctx.evaluateZeroMaxUnavailable(pdb)
+		if err != nil {
+			return err
+		}
+		if !zeroMaxUnavailable.matched {
+			ctx.exposeMetric(pdb, ReasonZeroMaxUnavailable, 0)
+			continue
+		}
+
+		log.Infof("PDB %v is marked reapable due to maxUnavailable resolving to 0", pdbNamespacedName(pdb))
+		ctx.markReapable(pdb, ReasonZeroMaxUnavailable, EventMessageZeroMaxUnavailableFmt)
+	}
+	return nil
+}
+
+// isZeroMaxUnavailable returns true if the maxUnavailable of a PDB resolves to 0 against its expected pods, malformed
+// values are left to misconfiguration detection. Any percentage resolves to 0 while a workload is scaled to zero, so
+// only a literal 0 or 0% matches PDBs expecting 0 pods
+func isZeroMaxUnavailable(pdb policyv1.PodDisruptionBudget) bool {
+	if pdb.Spec.MaxUnavailable == nil {
+		return false
+	}
+	if pdb.Spec.MaxUnavailable.String() == "0" || pdb.Spec.MaxUnavailable.String() == "0%" {
+		return true
+	}
+	if pdb.Status.ExpectedPods == 0 {
+		return false
+	}
+	maxUnavailable, err := intstr.GetScaledValueFromIntOrPercent(pdb.Spec.MaxUnavailable, int(pdb.Status.ExpectedPods), true)
+	if err != nil {
+		return false
+	}
+	return maxUnavailable == 0
+}
+
+// evaluationContext returns the context bounding the evaluation of a PDB by --pdb-timeout, it is created when the PDB is
+// scanned and shared by all its pod lists, re-lists and detections, so that a single slow PDB does
+// not stall the run
+func (ctx *ReaperContext) evaluationContext(pdb policyv1.PodDisruptionBudget) context.Context {
+	if ctx.PodDisruptionBudgetTimeout == 0 {
+		return ctx.runContext()
+	}
+	if ctx.evaluationContexts == nil {
+		ctx.evaluationContexts = make(map[string]context.Context)
+	}
+
+	namespacedName := pdbNamespacedName(pdb)
+	if evalCtx, ok := ctx.evaluationContexts[namespacedName]; ok {
+		return evalCtx
+	}
+	evalCtx, cancel := context.WithTimeout(ctx.runContext(), ctx.PodDisruptionBudgetTimeout)
+	ctx.evaluationContexts[namespacedName] = evalCtx
+	ctx.evaluationCancels = append(ctx.evaluationCancels, cancel)
+	return evalCtx
+}
+
+// releaseEvaluationContexts releases the evaluation contexts of the PDBs evaluated in the run
+func (ctx *ReaperContext) releaseEvaluationContexts() {
+	for _, cancel := range ctx.evaluationCancels {
+		cancel()
+	}
+	ctx.evaluationContexts = nil
+	ctx.evaluationCancels = nil
+}
+
+// listPodsWithSelector lists the pods matching a PDB selector, the list is abandoned once listCtx is done
+func (ctx *ReaperContext) listPodsWithSelector(listCtx context.Context, namespace, selector string) ([]corev1.Pod, error) {
+	var pods []corev1.Pod
+
+	type listResult struct {
+		podList *corev1.PodList
+		err     error
+	}
+	done := make(chan listResult, 1)
+	go func() {
+		podList, err := ctx.KubernetesClient.CoreV1().Pods(namespace).List(listCtx, metav1.ListOptions{LabelSelector: selector})
+		done <- listResult{podList: podList, err: err}
+	}()
+
+	select {
+	case result := <-done:
+		if result.err != nil {
+			return pods, errors.Wrapf(result.err, "failed to list pods with selector '%v'", selector)
+		}
+		pods = append(pods, result.podList.Items...)
+		return pods, nil
+	case <-listCtx.Done():
+		return pods, errors.Wrapf(listCtx.Err(), "failed to list pods with selector '%v'", selector)
+	}
+}
+
+// listPodsReconciled lists the pods of a PDB, the list may lag behind the PDB status so while fewer pods than the
+// expected pods are listed, the pods are re-listed after --pod-count-retry-delay up to --pod-count-retries times
+func (ctx *ReaperContext) listPodsReconciled(pdb policyv1.PodDisruptionBudget, selector string) ([]corev1.Pod, error) {
+	evalCtx := ctx.evaluationContext(pdb)
+	pods, err := ctx.listPodsWithSelector(evalCtx, pdb.GetNamespace(), selector)
+	for retry := 1; err == nil && retry <= ctx.PodCountRetries && len(pods) < int(pdb.Status.ExpectedPods); retry++ {
+		log.Infof("listed %v pods of PDB %v but %v are expected, re-listing in %v (%v/%v)", len(pods), pdbNamespacedName(pdb), pdb.Status.ExpectedPods, ctx.PodCountRetryDelay, retry, ctx.PodCountRetries)
+		select {
+		case <-time.After(ctx.PodCountRetryDelay):
+		case <-evalCtx.Done():
+			return pods, errors.Wrap(evalCtx.Err(), "stopped re-listing pods")
+		}
+		pods, err = ctx.listPodsWithSelector(evalCtx, pdb.GetNamespace(), selector)
+	}
+	if err == nil && ctx.PodCountRetries > 0 && len(pods) < int(pdb.Status.ExpectedPods) {
+		log.Warnf("listed %v pods of PDB %v but %v are expected after %v retries, evaluating the listed pods", len(pods), pdbNamespacedName(pdb), pdb.Status.ExpectedPods, ctx.PodCountRetries)
+	}
+	return pods, err
+}
+
+func (ctx *ReaperContext) publishEvent(pdb policyv1.PodDisruptionBudget, reason Reason, msg string, args ...interface{}) error {
+	var (
 		pdbNamespace   = pdb.GetNamespace()
 		pdbName        = pdb.GetName()
 		namespacedName = pdbNamespacedName(pdb)
 	)
 
-	now := time.Now()
+	// fixed manifests mode does not write to the cluster
+	if ctx.FixManifestsDir != "" {
+		return nil
+	}
+
+	if !ctx.EmitEvents {
+		return nil
+	}
+
+	// quiet namespaces are still reaped, only their events are suppressed
+	if common.StringSliceContains(ctx.QuietNamespaces, pdbNamespace) {
+		log.Infof("not publishing event %v on PDB %v in quiet namespace", reason, namespacedName)
+		return nil
+	}
+
+	// with --namespace-findings-only detections are only reported by the namespace findings event
+	if _, ok := reasonReapMode(reason); ok && ctx.NamespaceFindingsOnly {
+		log.Infof("not publishing event %v on PDB %v, findings are published per namespace", reason, namespacedName)
+		return nil
+	}
+
+	if err := ctx.runContext().Err(); err != nil {
+		return errors.Wrap(err, "failed to publish event")
+	}
+
+	now := ctx.now()
+	eventType := "Normal"
+	if reason == ReasonRecreated {
+		eventType = "Warning"
+	}
+	message := fmt.Sprintf(msg, append([]interface{}{namespacedName}, args...)...)
+
+	// with --dump-events a dry-run captures the events in the run result rather than creating them
+	if ctx.DryRun && ctx.DumpEvents {
+		log.Infof("DryRun is on, capturing event %v on PDB %v instead of publishing it", reason, namespacedName)
+		ctx.dumpedEvents = append(ctx.dumpedEvents, DumpedEvent{
+			PDB:       namespacedName,
+			Reason:    reason.String(),
+			Type:      eventType,
+			Message:   message,
+			Timestamp: now.UTC(),
+		})
+		return nil
+	}
+
+	if !ctx.allowWrite(fmt.Sprintf("event %v on PDB %v", reason, namespacedName)) {
+		return nil
+	}
+
+	labels := ctx.clusterLabels()
+	if mode := ctx.namespaceMode(pdbNamespace); mode != "" {
+		if labels == nil {
+			labels = make(map[string]string)
+		}
+		labels[ModeLabelKey] = mode
+	}
 	event := &corev1.Event{
 		ObjectMeta: metav1.ObjectMeta{
 			GenerateName: fmt.Sprintf("pdb-reaper-%v", pdbName),
 			Namespace:    pdbNamespace,
+			Labels:       labels,
 		},
 		InvolvedObject: corev1.ObjectReference{
 			Kind:            "PodDisruptionBudget",
@@ -341,57 +1369,148 @@ func (ctx *ReaperContext) publishEvent(pdb policyv1.PodDisruptionBudget, reason,
 			UID:             pdb.UID,
 			ResourceVersion: pdb.ResourceVersion,
 		},
-		Reason:         reason,
-		Message:        fmt.Sprintf(msg, namespacedName),
-		Type:           "Normal",
+		Reason:         reason.String(),
+		Message:        message,
+		Type:           eventType,
 		FirstTimestamp: metav1.NewTime(now),
 		LastTimestamp:  metav1.NewTime(now),
 	}
-	_, err := ctx.KubernetesClient.CoreV1().Events(pdbNamespace).Create(context.Background(), event, metav1.CreateOptions{})
+	_, err := ctx.KubernetesClient.CoreV1().Events(pdbNamespace).Create(ctx.runContext(), event, metav1.CreateOptions{})
 	if err != nil {
 		return errors.Wrap(err, "failed to publish event")
 	}
 	return nil
 }
 
-func (ctx *ReaperContext) addReapablePodDisruptionBudget(pdb ...policyv1.PodDisruptionBudget) {
-	for _, p := range ctx.ReapablePodDisruptionBudgets {
-		if reflect.DeepEqual(p, pdb) {
-			return
+// patchAnnotations merges the given annotations into the PDB, annotations with a nil value are removed
+func (ctx *ReaperContext) patchAnnotations(pdb policyv1.PodDisruptionBudget, annotations map[string]interface{}) error {
+	if !ctx.allowWrite(fmt.Sprintf("annotation update of PDB %v", pdbNamespacedName(pdb))) {
+		return nil
+	}
+
+	patch := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": annotations,
+		},
+	}
+	data, err := json.Marshal(patch)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal annotations patch")
+	}
+
+	_, err = ctx.KubernetesClient.PolicyV1().PodDisruptionBudgets(pdb.GetNamespace()).Patch(context.Background(), pdb.GetName(), types.MergePatchType, data, metav1.PatchOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "failed to patch annotations on PDB %v", pdbNamespacedName(pdb))
+	}
+	return nil
+}
+
+func (ctx *ReaperContext) addReapablePodDisruptionBudget(reason Reason, pdb ...policyv1.PodDisruptionBudget) {
+	for _, p := range pdb {
+		namespacedName := pdbNamespacedName(p)
+		reasons, alreadyReapable := ctx.ReapableReasons[namespacedName]
+		if !containsReason(reasons, reason) {
+			ctx.ReapableReasons[namespacedName] = append(reasons, reason)
+		}
+		ctx.diagnostics(p).Reasons = ctx.ReapableReasons[namespacedName]
+		ctx.emitRecord(RecordTypeDetection, p, reason)
+
+		// a PDB matching multiple reasons is only reaped once
+		if alreadyReapable {
+			continue
+		}
+		ctx.ReapablePodDisruptionBudgetsCount++
+		ctx.ReapablePodDisruptionBudgets = append(ctx.ReapablePodDisruptionBudgets, p)
+	}
+}
+
+// primaryReason returns the reason a PDB is reaped for, when multiple reasons apply it is chosen by the configured priority
+func (ctx *ReaperContext) primaryReason(pdb policyv1.PodDisruptionBudget) Reason {
+	reasons := ctx.ReapableReasons[pdbNamespacedName(pdb)]
+	if len(reasons) == 0 {
+		return ReasonUnknown
+	}
+
+	priority := ctx.ReapReasonPriority
+	if len(priority) == 0 {
+		priority = DefaultReapReasonPriority
+	}
+	for _, mode := range priority {
+		if reason := ReapModeReasons[mode]; containsReason(reasons, reason) {
+			return reason
 		}
 	}
+	return reasons[0]
+}
+
+// markReapable marks a PDB as reapable for a reason, publishes the event of the reason and exposes the detection
+func (ctx *ReaperContext) markReapable(pdb policyv1.PodDisruptionBudget, reason Reason, msg string, args ...interface{}) {
+	ctx.addReapablePodDisruptionBudget(reason, pdb)
+	err := ctx.publishEvent(pdb, reason, msg, args...)
+	if err != nil {
+		log.Warnf(err.Error())
+	}
+	ctx.exposeMetric(pdb, reason, 1)
+}
 
-	ctx.ReapablePodDisruptionBudgetsCount += len(pdb)
-	ctx.ReapablePodDisruptionBudgets = append(ctx.ReapablePodDisruptionBudgets, pdb...)
+// markMisconfigured marks a PDB found to be misconfigured as reapable, and exposes the result of the detection
+func (ctx *ReaperContext) markMisconfigured(pdb policyv1.PodDisruptionBudget, misconfigured bool) {
+	if !misconfigured {
+		ctx.exposeMetric(pdb, ReasonBlocking, 0)
+		return
+	}
+
+	log.Infof("PDB %v is marked reapable due to blocking configuration", pdbNamespacedName(pdb))
+	ctx.addReapablePodDisruptionBudget(ReasonBlocking, pdb)
+	message := EventMessageBlockingFmt
+	if isMalformed(pdb) {
+		message = EventMessageMalformedFmt
+	}
+	err := ctx.publishEvent(pdb, ReasonBlocking, message)
+	if err != nil {
+		log.Warnf(err.Error())
+	}
+	ctx.exposeMetric(pdb, ReasonBlocking, 1)
 }
 
 func isMisconfigured(pdb policyv1.PodDisruptionBudget, pods []corev1.Pod) (bool, error) {
+	return isMisconfiguredWithPodCount(pdb, statusPodCount(pdb, len(pods)))
+}
+
+// isMisconfiguredWithPodCount resolves the budget of a PDB against the given number of pods
+func isMisconfiguredWithPodCount(pdb policyv1.PodDisruptionBudget, podCount int) (bool, error) {
 	var (
 		maxUnavailable = pdb.Spec.MaxUnavailable
 		minAvailable   = pdb.Spec.MinAvailable
-		podCount       = len(pods)
 	)
 
 	switch {
+	case maxUnavailable != nil && minAvailable != nil:
+		// the API rejects such PDBs, but they can still surface through conversions or older objects
+		log.Warnf("pdb %v is malformed because both maxUnavailable and minAvailable are set", pdbNamespacedName(pdb))
+		return true, nil
 	case maxUnavailable != nil:
 		allowedUnavailable, err := intstr.GetValueFromIntOrPercent(maxUnavailable, podCount, true)
 		if err != nil {
-			return false, errors.Wrapf(err, "failed to get IntStr value from PDB Spec.MaxUnavailable '%+v'", maxUnavailable)
+			// the disruption controller cannot resolve a malformed value either, so the pdb never allows a disruption
+			log.Warnf("pdb %v is misconfigured because maxUnavailable '%v' is malformed: %v", pdbNamespacedName(pdb), maxUnavailable.String(), err)
+			return true, nil
 		}
 
 		// if pdb is not allowing any disruptions, it is considered misconfigured
-		if allowedUnavailable == 0 {
-			log.Infof("pdb %v is misconfigured because allowed unavailable replicas is 0", pdbNamespacedName(pdb))
+		if allowedUnavailable <= 0 {
+			log.Infof("pdb %v is misconfigured because allowed unavailable replicas is %v", pdbNamespacedName(pdb), allowedUnavailable)
 			return true, nil
 		}
 	case minAvailable != nil:
 		requiredAvailable, err := intstr.GetValueFromIntOrPercent(minAvailable, podCount, true)
 		if err != nil {
-			return false, errors.Wrapf(err, "failed to get IntStr value from PDB Spec.MinAvailable '%+v'", minAvailable)
+			log.Warnf("pdb %v is misconfigured because minAvailable '%v' is malformed: %v", pdbNamespacedName(pdb), minAvailable.String(), err)
+			return true, nil
 		}
 
-		// if pdb is requiring expected pods, it is considered misconfigured
-		if requiredAvailable == int(pdb.Status.ExpectedPods) {
+		// if pdb is requiring all, or more than, the expected pods, it is considered misconfigured
+		if requiredAvailable >= podCount {
 			log.Infof("pdb %v is misconfigured because required available replicas matches expected pods", pdbNamespacedName(pdb))
 			return true, nil
 		}
@@ -402,94 +1521,322 @@ func isMisconfigured(pdb policyv1.PodDisruptionBudget, pods []corev1.Pod) (bool,
 	return false, nil
 }
 
-func isContainDuplicatePods(pods []corev1.Pod) bool {
-	m := make(map[string]bool)
+// isMalformed returns true if both maxUnavailable and minAvailable are set on a PDB
+func isMalformed(pdb policyv1.PodDisruptionBudget) bool {
+	return pdb.Spec.MaxUnavailable != nil && pdb.Spec.MinAvailable != nil
+}
+
+// isPodsInCrashloop returns true if at least the given fraction of pods are crashlooping, a fraction of 0 means any pod
+func isPodsInCrashloop(pods []corev1.Pod, isCrashLooping containerStatusPredicate, fraction float64) bool {
+	podCount := len(pods)
+	crashingCount := countCrashloopingPods(pods, isCrashLooping)
+	if fraction == 0 {
+		return crashingCount > 0
+	}
+	return float64(crashingCount) >= fraction*float64(podCount)
+}
+
+// countCrashloopingPods returns the number of crashlooping pods
+func countCrashloopingPods(pods []corev1.Pod, isCrashLooping containerStatusPredicate) int {
+	var crashingCount int
 	for _, pod := range pods {
-		name := pod.GetName()
-		if _, ok := m[name]; ok {
+		if isPodInCrashloop(pod, isCrashLooping) {
+			crashingCount++
+		}
+	}
+	return crashingCount
+}
+
+// isPodInCrashloop returns true if any of the pod's init or regular containers are crashlooping
+func isPodInCrashloop(pod corev1.Pod, isCrashLooping containerStatusPredicate) bool {
+	statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+	for _, containerStatus := range statuses {
+		if isCrashLooping(containerStatus) {
 			return true
-		} else {
-			m[name] = true
 		}
 	}
 	return false
 }
 
-func isPodsInCrashloop(pods []corev1.Pod, threshold int, allPods bool) bool {
-	podCount := len(pods)
-	var crashingCount int
+// statePods returns the pods of a PDB whose crashloop and not-ready state is evaluated, DaemonSet pods are left out
+// with --exclude-daemonset-pods
+func (ctx *ReaperContext) statePods(pdb policyv1.PodDisruptionBudget, pods []corev1.Pod) []corev1.Pod {
+	if !ctx.ExcludeDaemonSetPods {
+		return pods
+	}
+	filtered := excludeDaemonSetPods(pods)
+	if excluded := len(pods) - len(filtered); excluded > 0 {
+		log.Infof("excluding %v DaemonSet pods of PDB %v from crashloop and not-ready detection", excluded, pdbNamespacedName(pdb))
+	}
+	return filtered
+}
+
+// excludeDaemonSetPods returns the pods which are not owned by a DaemonSet
+func excludeDaemonSetPods(pods []corev1.Pod) []corev1.Pod {
+	filtered := make([]corev1.Pod, 0)
 	for _, pod := range pods {
-		for _, containerStatus := range pod.Status.InitContainerStatuses {
-			if containerStatus.State.Waiting != nil && containerStatus.RestartCount >= int32(threshold) {
-				if containerStatus.State.Waiting.Reason == ReasonCrashLoopBackOff {
-					crashingCount++
-					break
-				}
-			}
-		}
-		for _, containerStatus := range pod.Status.ContainerStatuses {
-			if containerStatus.State.Waiting != nil && containerStatus.RestartCount >= int32(threshold) {
-				if containerStatus.State.Waiting.Reason == ReasonCrashLoopBackOff {
-					crashingCount++
-					break
-				}
-			}
+		if owner := metav1.GetControllerOf(&pod); owner != nil && owner.Kind == "DaemonSet" {
+			continue
 		}
+		filtered = append(filtered, pod)
 	}
-	if !allPods {
-		if crashingCount > 0 {
-			return true
-		}
-	} else {
-		if crashingCount == podCount {
-			return true
+	return filtered
+}
+
+// excludeCrashloopingPods returns the pods which are not crashlooping
+func excludeCrashloopingPods(pods []corev1.Pod, isCrashLooping containerStatusPredicate) []corev1.Pod {
+	filtered := make([]corev1.Pod, 0)
+	for _, pod := range pods {
+		if isPodInCrashloop(pod, isCrashLooping) {
+			continue
 		}
+		filtered = append(filtered, pod)
 	}
-	return false
+	return filtered
 }
 
-func isPodsInNotReadyState(pods []corev1.Pod, thresholdSeconds int, allPods bool) bool {
-	podCount := len(pods)
-	var notReadyCount int
+// isPodsInNotReadyState returns true if at least the given fraction of non-terminated pods are in not-ready state, a
+// fraction of 0 means any pod
+func isPodsInNotReadyState(now time.Time, pods []corev1.Pod, thresholdSeconds int, fraction float64, gateTypes []string, probeGrace, includePending bool) bool {
+	podCount, notReadyCount := countNotReadyPods(now, pods, thresholdSeconds, gateTypes, probeGrace, includePending)
+	if fraction == 0 {
+		return notReadyCount > 0
+	}
+	return podCount > 0 && float64(notReadyCount) >= fraction*float64(podCount)
+}
+
+// countNotReadyPods returns the number of non-terminated pods, and how many of them are in not-ready state past the
+// threshold, only Running pods and with includePending also Pending pods are counted as not-ready
+func countNotReadyPods(now time.Time, pods []corev1.Pod, thresholdSeconds int, gateTypes []string, probeGrace, includePending bool) (podCount, notReadyCount int) {
 
 	for _, pod := range pods {
+		// pods which have terminated, e.g. completed Job pods, are never ready again but are not blocking disruptions
+		if isPodTerminated(pod) {
+			continue
+		}
+		podCount++
+
+		// a Pending pod is expected to not be ready yet, e.g. while pulling images
+		if !isNotReadyPhase(pod, includePending) {
+			continue
+		}
+
+		podThresholdSeconds := thresholdSeconds
+		if probeGrace {
+			podThresholdSeconds += readinessProbeInitialDelaySeconds(pod)
+		}
 
 		for _, condition := range pod.Status.Conditions {
-			if condition.Type == "ContainersReady" && condition.Status == "False" {
-				if isPodReadinessThresholdPast(condition.LastTransitionTime, thresholdSeconds) {
+			if isNotReadyConditionType(condition.Type, gateTypes) && condition.Status == "False" {
+				if isPodReadinessThresholdPast(now, condition.LastTransitionTime, podThresholdSeconds) {
 					notReadyCount++
 					break
 				}
 			}
 		}
 	}
-	if !allPods {
-		if notReadyCount > 0 {
-			return true
+	return podCount, notReadyCount
+}
+
+// isNotReadyPhase returns true if a pod is in a phase in which not being ready is not expected
+func isNotReadyPhase(pod corev1.Pod, includePending bool) bool {
+	return pod.Status.Phase == corev1.PodRunning || (includePending && pod.Status.Phase == corev1.PodPending)
+}
+
+// isPodTerminated returns true if a pod has reached the Succeeded or Failed phase
+func isPodTerminated(pod corev1.Pod) bool {
+	return pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed
+}
+
+// isNotReadyConditionType returns true if a pod condition type should be considered when detecting not-ready pods,
+// ContainersReady is always considered, readiness gate condition types are considered when configured
+func isNotReadyConditionType(conditionType corev1.PodConditionType, gateTypes []string) bool {
+	if conditionType == corev1.ContainersReady {
+		return true
+	}
+	return common.StringSliceContains(gateTypes, string(conditionType))
+}
+
+// readinessProbeInitialDelaySeconds returns the longest readiness probe initial delay of a pod's containers
+func readinessProbeInitialDelaySeconds(pod corev1.Pod) int {
+	var delaySeconds int32
+	for _, container := range pod.Spec.Containers {
+		if container.ReadinessProbe != nil && container.ReadinessProbe.InitialDelaySeconds > delaySeconds {
+			delaySeconds = container.ReadinessProbe.InitialDelaySeconds
 		}
-	} else {
-		if notReadyCount == podCount {
-			return true
+	}
+	return int(delaySeconds)
+}
+
+func isPodReadinessThresholdPast(now time.Time, startTime metav1.Time, thresholdSeconds int) bool {
+	return now.Sub(startTime.Time) >= time.Duration(thresholdSeconds)*time.Second
+}
+
+func (ctx *ReaperContext) exposeMetric(pdb policyv1.PodDisruptionBudget, reason Reason, value float64) error {
+	return ctx.exposeReasonMetric(pdb, PdbReaperResultMetricName, reason, value)
+}
+
+// resetStaleMetrics resets the result metric of PDBs which were reapable for a reason in the last run but no longer are,
+// push-based gauges otherwise keep their last value
+func (ctx *ReaperContext) resetStaleMetrics() {
+	if !ctx.ResetStaleMetrics {
+		return
+	}
+
+	for namespacedName, reasons := range ctx.State.ReapableReasons {
+		namespace, name, _ := strings.Cut(namespacedName, "/")
+		pdb := policyv1.PodDisruptionBudget{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name}}
+		for _, reason := range reasons {
+			if !containsReason(ctx.ReapableReasons[namespacedName], reason) {
+				log.Infof("PDB %v is no longer reapable due to %v, resetting its metric", namespacedName, reason)
+				ctx.exposeMetric(pdb, reason, 0)
+			}
 		}
 	}
-	return false
+
+	ctx.State.ReapableReasons = make(map[string][]Reason)
+	for namespacedName, reasons := range ctx.ReapableReasons {
+		ctx.State.ReapableReasons[namespacedName] = append([]Reason{}, reasons...)
+	}
 }
 
-func isPodReadinessThresholdPast(startTime metav1.Time, thresholdSeconds int) bool {
-	currentTimestamp := metav1.Time{Time: time.Now()}
-	return currentTimestamp.Time.Sub(startTime.Time) >= time.Duration(thresholdSeconds)*time.Second
+func (ctx *ReaperContext) exposeReasonMetric(pdb policyv1.PodDisruptionBudget, metricName string, reason Reason, value float64) error {
+	if ctx.isMetricsEnabled() {
+		var tags = ctx.metricTags()
+		tags["namespace"] = pdb.GetNamespace()
+		tags["pdb"] = pdb.GetName()
+		tags["reason"] = reason.String()
+		tags["reason_code"] = strconv.Itoa(reason.Code())
+		if mode := ctx.namespaceMode(pdb.GetNamespace()); mode != "" {
+			tags["mode"] = mode
+		}
+
+		var err error
+		if err = ctx.setMetricValue(metricName, tags, value); err == nil {
+			log.Infof("Pushed new metric value %f at %s for reason %s on pdb %s in namespace %s", value, metricName, reason, pdb.GetName(), pdb.GetNamespace())
+		} else {
+			log.Warnf("Pushing metric error:%v", err)
+		}
+		return err
+	}
+	return nil
 }
 
-func (ctx *ReaperContext) exposeMetric(pdb policyv1.PodDisruptionBudget, eventReason string, value float64) error {
-	if ctx.MetricsAPI != nil {
-		var tags = make(map[string]string)
+// exposePodDisruptionBudgetMetric exposes a metric labeled by namespace and pdb, extraTags are additional key/value pairs
+func (ctx *ReaperContext) exposePodDisruptionBudgetMetric(pdb policyv1.PodDisruptionBudget, metricName string, value float64, extraTags ...string) error {
+	if ctx.isMetricsEnabled() {
+		var tags = ctx.metricTags()
 		tags["namespace"] = pdb.GetNamespace()
 		tags["pdb"] = pdb.GetName()
-		tags["reason"] = eventReason
+		if mode := ctx.namespaceMode(pdb.GetNamespace()); mode != "" {
+			tags["mode"] = mode
+		}
+		for i := 0; i+1 < len(extraTags); i += 2 {
+			tags[extraTags[i]] = extraTags[i+1]
+		}
+
+		var err error
+		if err = ctx.setMetricValue(metricName, tags, value); err == nil {
+			log.Infof("Pushed new metric value %f at %s on pdb %s in namespace %s", value, metricName, pdb.GetName(), pdb.GetNamespace())
+		} else {
+			log.Warnf("Pushing metric error:%v", err)
+		}
+		return err
+	}
+	return nil
+}
+
+// exposeResolvedBudgetMetrics exposes the integer values of maxUnavailable and minAvailable resolved against the expected pods
+func (ctx *ReaperContext) exposeResolvedBudgetMetrics(pdb policyv1.PodDisruptionBudget) {
+	for budgetType, value := range resolvedBudgets(pdb) {
+		ctx.exposePodDisruptionBudgetMetric(pdb, PdbReaperResolvedBudgetMetricName, float64(value), "type", budgetType)
+	}
+}
+
+// exposeBlockingDurationMetric exposes how long a PDB has been blocking, based on the transition time of its
+// DisruptionAllowed=False condition, PDBs without the condition are skipped
+func (ctx *ReaperContext) exposeBlockingDurationMetric(pdb policyv1.PodDisruptionBudget) {
+	if duration, ok := ctx.blockingDuration(pdb); ok {
+		ctx.exposePodDisruptionBudgetMetric(pdb, PdbReaperBlockingDurationMetricName, duration.Seconds())
+	}
+}
+
+// matchedPodsSummary is the average and max number of pods matched per PDB in a namespace
+type matchedPodsSummary struct {
+	Average float64
+	Max     int
+}
+
+// matchedPodsSummaries summarizes the pods matched by the PDBs whose pods were listed in the current run by namespace
+func (ctx *ReaperContext) matchedPodsSummaries() map[string]matchedPodsSummary {
+	var (
+		totals    = make(map[string]int)
+		counts    = make(map[string]int)
+		summaries = make(map[string]matchedPodsSummary)
+	)
+	for namespacedName, matched := range ctx.matchedPods {
+		namespace, _, _ := strings.Cut(namespacedName, "/")
+		totals[namespace] += matched
+		counts[namespace]++
+		summary := summaries[namespace]
+		if matched > summary.Max {
+			summary.Max = matched
+		}
+		summaries[namespace] = summary
+	}
+	for namespace, summary := range summaries {
+		summary.Average = float64(totals[namespace]) / float64(counts[namespace])
+		summaries[namespace] = summary
+	}
+	return summaries
+}
+
+// exposeMatchedPodsSummaryMetrics exposes the average and max number of pods matched per PDB of each namespace, an
+// outlier max points at a selector which is too broad
+func (ctx *ReaperContext) exposeMatchedPodsSummaryMetrics() {
+	if !ctx.isMetricsEnabled() {
+		return
+	}
+
+	summaries := ctx.matchedPodsSummaries()
+	namespaces := make([]string, 0, len(summaries))
+	for namespace := range summaries {
+		namespaces = append(namespaces, namespace)
+	}
+	sort.Strings(namespaces)
+
+	for _, namespace := range namespaces {
+		tags := ctx.metricTags()
+		tags["namespace"] = namespace
+		if err := ctx.setMetricValue(PdbReaperMatchedPodsAverageMetricName, tags, summaries[namespace].Average); err != nil {
+			log.Warnf("Pushing metric error:%v", err)
+		}
+		if err := ctx.setMetricValue(PdbReaperMatchedPodsMaxMetricName, tags, float64(summaries[namespace].Max)); err != nil {
+			log.Warnf("Pushing metric error:%v", err)
+		}
+	}
+}
+
+// blockingDuration returns how long a PDB has not been allowing disruptions, based on its DisruptionAllowed condition
+func (ctx *ReaperContext) blockingDuration(pdb policyv1.PodDisruptionBudget) (time.Duration, bool) {
+	condition := meta.FindStatusCondition(pdb.Status.Conditions, policyv1.DisruptionAllowedCondition)
+	if condition == nil || condition.Status != metav1.ConditionFalse || condition.LastTransitionTime.IsZero() {
+		return 0, false
+	}
+	duration := ctx.now().Sub(condition.LastTransitionTime.Time)
+	if duration < 0 {
+		duration = 0
+	}
+	return duration, true
+}
+
+func (ctx *ReaperContext) exposeClusterMetric(metricName string, value float64) error {
+	if ctx.isMetricsEnabled() {
+		var tags = ctx.metricTags()
 
 		var err error
-		if err = ctx.MetricsAPI.SetMetricValue(PdbReaperResultMetricName, tags, value); err == nil {
-			log.Infof("Pushed new metric value %f at %s for reason %s on pdb %s in namespace %s", value, PdbReaperResultMetricName, eventReason, pdb.GetName(), pdb.GetNamespace())
+		if err = ctx.setMetricValue(metricName, tags, value); err == nil {
+			log.Infof("Pushed new metric value %f at %s", value, metricName)
 		} else {
 			log.Warnf("Pushing metric error:%v", err)
 		}
@@ -497,3 +1844,50 @@ func (ctx *ReaperContext) exposeMetric(pdb policyv1.PodDisruptionBudget, eventRe
 	}
 	return nil
 }
+
+// isMetricsEnabled returns true if a metrics backend is configured and --emit-metrics is on
+func (ctx *ReaperContext) isMetricsEnabled() bool {
+	return ctx.EmitMetrics && ctx.MetricsAPI != nil
+}
+
+// setMetricValue pushes a metric value, a push is not attempted, or is abandoned, once the run context is done
+func (ctx *ReaperContext) setMetricValue(metricName string, tags map[string]string, value float64) error {
+	runCtx := ctx.runContext()
+	if err := runCtx.Err(); err != nil {
+		return err
+	}
+	if api, ok := ctx.MetricsAPI.(common.ContextMetricsAPI); ok {
+		return api.SetMetricValueContext(runCtx, metricName, tags, value)
+	}
+	return ctx.MetricsAPI.SetMetricValue(metricName, tags, value)
+}
+
+// flushMetrics sends the metric values buffered during the run with --batch-metrics
+func (ctx *ReaperContext) flushMetrics() {
+	api, ok := ctx.MetricsAPI.(*common.BufferedMetricsAPI)
+	if !ok {
+		return
+	}
+	count := api.Buffered()
+	if err := api.Flush(); err != nil {
+		log.Warnf("failed to flush %v metric values: %v", count, err)
+		return
+	}
+	log.Infof("flushed %v metric values", count)
+}
+
+// metricTags returns the base tags for a metric, which include the cluster name when running against multiple clusters
+func (ctx *ReaperContext) metricTags() map[string]string {
+	var tags = make(map[string]string)
+	if ctx.ClusterName != "" {
+		tags["cluster"] = ctx.ClusterName
+	}
+	return tags
+}
+
+func (ctx *ReaperContext) clusterLabels() map[string]string {
+	if ctx.ClusterName == "" {
+		return nil
+	}
+	return map[string]string{ClusterLabelKey: ctx.ClusterName}
+}
@@ -16,18 +16,48 @@ limitations under the License.
 package pdbreaper
 
 import (
+	"bytes"
 	"context"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
 	"flag"
+	"fmt"
+	"io"
 	"io/ioutil"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/keikoproj/governor/pkg/reaper/common"
+	"github.com/pkg/errors"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	appsv1 "k8s.io/api/apps/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
 	corev1 "k8s.io/api/core/v1"
 	policyv1 "k8s.io/api/policy/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	apiversion "k8s.io/apimachinery/pkg/version"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/fake"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/rest"
+	k8stesting "k8s.io/client-go/testing"
+	"sigs.k8s.io/yaml"
 )
 
 var (
@@ -54,6 +84,8 @@ func _fakeReaperContext() *ReaperContext {
 		CrashLoopRestartCount:                      5,
 		AllCrashLoop:                               false,
 		ReapNotReady:                               true,
+		EmitEvents:                                 true,
+		EmitMetrics:                                true,
 		ReapablePodDisruptionBudgets:               make([]policyv1.PodDisruptionBudget, 0),
 		ClusterBlockingPodDisruptionBudgets:        make(map[string][]policyv1.PodDisruptionBudget),
 		NamespacesWithMultiplePodDisruptionBudgets: make(map[string][]policyv1.PodDisruptionBudget),
@@ -62,6 +94,163 @@ func _fakeReaperContext() *ReaperContext {
 	return ctx
 }
 
+type fakeClock struct {
+	now time.Time
+}
+
+func (c fakeClock) Now() time.Time {
+	return c.now
+}
+
+type fakeMetric struct {
+	Name  string
+	Tags  map[string]string
+	Value float64
+}
+
+type fakeMetricsAPI struct {
+	Metrics      []fakeMetric
+	Observations []fakeMetric
+}
+
+func (m *fakeMetricsAPI) SetMetricValue(metricName string, tags map[string]string, value float64) error {
+	m.Metrics = append(m.Metrics, fakeMetric{Name: metricName, Tags: tags, Value: value})
+	return nil
+}
+
+func (m *fakeMetricsAPI) ObserveMetricValue(metricName string, tags map[string]string, value float64) error {
+	m.Observations = append(m.Observations, fakeMetric{Name: metricName, Tags: tags, Value: value})
+	return nil
+}
+
+// lastValue returns the most recent value pushed for a metric whose tags contain all given tags
+func (m *fakeMetricsAPI) lastValue(metricName string, tags map[string]string) (float64, bool) {
+	for i := len(m.Metrics) - 1; i >= 0; i-- {
+		metric := m.Metrics[i]
+		if metric.Name != metricName {
+			continue
+		}
+		match := true
+		for k, v := range tags {
+			if metric.Tags[k] != v {
+				match = false
+				break
+			}
+		}
+		if match {
+			return metric.Value, true
+		}
+	}
+	return 0, false
+}
+
+// fakePushgateway keeps the pushed metrics like a pushgateway, a PUT replaces every metric of the grouping key, a POST
+// only replaces the metrics with the same name
+type fakePushgateway struct {
+	*httptest.Server
+
+	mu      sync.Mutex
+	metrics map[string]map[string]*dto.MetricFamily
+}
+
+func _fakePushgateway(t *testing.T) *fakePushgateway {
+	pgw := &fakePushgateway{metrics: make(map[string]map[string]*dto.MetricFamily)}
+	pgw.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pgw.mu.Lock()
+		defer pgw.mu.Unlock()
+		key, err := groupingKey(r.URL.Path)
+		if err != nil {
+			t.Errorf("invalid grouping key %v: %v", r.URL.Path, err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		group := pgw.metrics[key]
+		if group == nil || r.Method == http.MethodPut {
+			group = make(map[string]*dto.MetricFamily)
+			pgw.metrics[key] = group
+		}
+		decoder := expfmt.NewDecoder(r.Body, expfmt.ResponseFormat(r.Header))
+		for {
+			family := &dto.MetricFamily{}
+			if err := decoder.Decode(family); err != nil {
+				if err != io.EOF {
+					t.Errorf("failed to decode pushed metrics: %v", err)
+				}
+				break
+			}
+			group[family.GetName()] = family
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(pgw.Close)
+	return pgw
+}
+
+// groupingKey returns the labels of the grouping key in a push URL path, the pushgateway doesn't depend on their order
+func groupingKey(path string) (string, error) {
+	components := strings.Split(strings.TrimPrefix(path, "/metrics/"), "/")
+	if len(components)%2 != 0 {
+		return "", errors.New("expected label name and value pairs")
+	}
+	labels := make(map[string]string)
+	for i := 0; i < len(components); i += 2 {
+		name, value := components[i], components[i+1]
+		if strings.HasSuffix(name, "@base64") {
+			decoded, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(value, "="))
+			if err != nil {
+				return "", err
+			}
+			name, value = strings.TrimSuffix(name, "@base64"), string(decoded)
+		}
+		labels[name] = value
+	}
+	return labelsKey(labels), nil
+}
+
+// labelsKey returns the sorted label pairs of a grouping key
+func labelsKey(labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for name, value := range labels {
+		pairs = append(pairs, name+"="+value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+// pushedGroupingKey returns the grouping key of metrics pushed with the tags
+func pushedGroupingKey(tags map[string]string) string {
+	labels := map[string]string{"job": "governor"}
+	for key, value := range tags {
+		labels[key] = value
+	}
+	return labelsKey(labels)
+}
+
+// value returns the value of a gauge, or the sample count of a histogram, kept in the grouping key of the tags
+func (p *fakePushgateway) value(metricName string, tags map[string]string) (float64, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	family, ok := p.metrics[pushedGroupingKey(tags)][metricName]
+	if !ok || len(family.GetMetric()) == 0 {
+		return 0, false
+	}
+	metric := family.GetMetric()[0]
+	if metric.GetHistogram() != nil {
+		return float64(metric.GetHistogram().GetSampleCount()), true
+	}
+	return metric.GetGauge().GetValue(), true
+}
+
+// assertPushed fails the test unless each metric is kept with its expected value in the grouping key of the tags
+func (p *fakePushgateway) assertPushed(t *testing.T, tags map[string]string, expected map[string]float64) {
+	t.Helper()
+	for metricName, expectedValue := range expected {
+		if value, ok := p.value(metricName, tags); !ok || value != expectedValue {
+			t.Fatalf("assertion failed, expected %v %v on the pushgateway with tags %v, got: %v (found %v)", metricName, expectedValue, tags, value, ok)
+		}
+	}
+}
+
 func _selector(s string) *metav1.LabelSelector {
 	selector, _ := metav1.ParseToLabelSelector(s)
 	return selector
@@ -71,7 +260,9 @@ func _fakeAPI(u *ReaperUnitTest) {
 
 	for _, n := range u.Mocks.Namespaces {
 		namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
-			Name: n.Name,
+			Name:        n.Name,
+			Labels:      n.Labels,
+			Annotations: n.Annotations,
 		}}
 		_, err := u.FakeReaper.KubernetesClient.CoreV1().Namespaces().Create(context.Background(), namespace, metav1.CreateOptions{})
 		if err != nil {
@@ -79,16 +270,39 @@ func _fakeAPI(u *ReaperUnitTest) {
 		}
 	}
 
+	for _, n := range u.Mocks.Nodes {
+		node := &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   n.Name,
+				Labels: n.Labels,
+			},
+			Spec: corev1.NodeSpec{
+				Unschedulable: n.Unschedulable,
+				Taints:        n.Taints,
+			},
+		}
+		_, err := u.FakeReaper.KubernetesClient.CoreV1().Nodes().Create(context.Background(), node, metav1.CreateOptions{})
+		if err != nil {
+			panic(err)
+		}
+	}
+
 	for _, p := range u.Mocks.Pods {
 		pod := &corev1.Pod{
 			ObjectMeta: metav1.ObjectMeta{
-				Name:      p.Name,
-				Namespace: p.Namespace,
-				Labels:    p.Labels,
+				Name:            p.Name,
+				Namespace:       p.Namespace,
+				Labels:          p.Labels,
+				OwnerReferences: p.OwnerReferences,
+			},
+			Spec: corev1.PodSpec{
+				NodeName:          p.NodeName,
+				PriorityClassName: p.PriorityClassName,
 			},
 		}
 		if p.IsInCrashloop {
 			pod.Status.ContainerStatuses = append(pod.Status.ContainerStatuses, corev1.ContainerStatus{
+				Name: "app",
 				State: corev1.ContainerState{
 					Waiting: &corev1.ContainerStateWaiting{
 						Reason: ReasonCrashLoopBackOff,
@@ -98,13 +312,25 @@ func _fakeAPI(u *ReaperUnitTest) {
 			})
 		}
 		if p.IsNotReady {
-			pod.Status.Phase = corev1.PodPending
+			pod.Status.Phase = corev1.PodRunning
 			pod.Status.Conditions = append(pod.Status.Conditions, corev1.PodCondition{
 				Type:               corev1.ContainersReady,
 				Status:             corev1.ConditionFalse,
 				LastTransitionTime: metav1.Time{Time: time.Now().Add(time.Duration(-50) * time.Second)},
 			})
 		}
+		pod.Status.Conditions = append(pod.Status.Conditions, p.Conditions...)
+		if p.Phase != "" {
+			pod.Status.Phase = p.Phase
+		}
+		if p.ReadinessProbeInitialDelay > 0 {
+			pod.Spec.Containers = append(pod.Spec.Containers, corev1.Container{
+				Name: "app",
+				ReadinessProbe: &corev1.Probe{
+					InitialDelaySeconds: p.ReadinessProbeInitialDelay,
+				},
+			})
+		}
 
 		pod.Status.StartTime = &metav1.Time{Time: time.Now().Add(time.Duration(-100) * time.Second)}
 		_, err := u.FakeReaper.KubernetesClient.CoreV1().Pods(p.Namespace).Create(context.Background(), pod, metav1.CreateOptions{})
@@ -116,8 +342,14 @@ func _fakeAPI(u *ReaperUnitTest) {
 	for _, p := range u.Mocks.PDBs {
 		pdb := &policyv1.PodDisruptionBudget{
 			ObjectMeta: metav1.ObjectMeta{
-				Name:      p.Name,
-				Namespace: p.Namespace,
+				Name:              p.Name,
+				Namespace:         p.Namespace,
+				Annotations:       p.Annotations,
+				Labels:            p.Labels,
+				DeletionTimestamp: p.DeletionTimestamp,
+				CreationTimestamp: p.CreationTimestamp,
+				Finalizers:        p.Finalizers,
+				Generation:        p.Generation,
 			},
 			Spec: policyv1.PodDisruptionBudgetSpec{
 				MinAvailable:   p.MinAvailable,
@@ -127,6 +359,8 @@ func _fakeAPI(u *ReaperUnitTest) {
 			Status: policyv1.PodDisruptionBudgetStatus{
 				DisruptionsAllowed: p.PodDisruptionsAllowed,
 				ExpectedPods:       p.ExpectedPods,
+				ObservedGeneration: p.ObservedGeneration,
+				Conditions:         p.Conditions,
 			},
 		}
 		_, err := u.FakeReaper.KubernetesClient.PolicyV1().PodDisruptionBudgets(p.Namespace).Create(context.Background(), pdb, metav1.CreateOptions{})
@@ -166,12 +400,22 @@ type ReaperUnitTest struct {
 
 type KubernetesMockAPI struct {
 	Namespaces []MockNamespace
+	Nodes      []MockNode
 	PDBs       []MockPDB
 	Pods       []MockPod
 }
 
+type MockNode struct {
+	Name          string
+	Labels        map[string]string
+	Unschedulable bool
+	Taints        []corev1.Taint
+}
+
 type MockNamespace struct {
-	Name string
+	Name        string
+	Labels      map[string]string
+	Annotations map[string]string
 }
 
 func _mockNamespace(name string) MockNamespace {
@@ -186,6 +430,14 @@ type MockPDB struct {
 	Selector              *metav1.LabelSelector
 	ExpectedPods          int32
 	PodDisruptionsAllowed int32
+	Annotations           map[string]string
+	Labels                map[string]string
+	DeletionTimestamp     *metav1.Time
+	CreationTimestamp     metav1.Time
+	Finalizers            []string
+	Generation            int64
+	ObservedGeneration    int64
+	Conditions            []metav1.Condition
 }
 
 func _mockPDB(name, namespace string, minAvailable, maxUnavailable *intstr.IntOrString, selector *metav1.LabelSelector, expected, disruptions int32) MockPDB {
@@ -207,6 +459,14 @@ type MockPod struct {
 	IsInCrashloop bool
 	RestartCount  int32
 	IsNotReady    bool
+	Conditions    []corev1.PodCondition
+	NodeName      string
+	Phase         corev1.PodPhase
+	// PriorityClassName is set on the pod spec
+	PriorityClassName string
+	OwnerReferences   []metav1.OwnerReference
+	// ReadinessProbeInitialDelay adds a container with a readiness probe when set
+	ReadinessProbeInitialDelay int32
 }
 
 func _mockPod(name, namespace string, labels map[string]string, crashloop bool, restarts int32, notReadyState bool) MockPod {
@@ -651,3 +911,6433 @@ func TestWithPushgateway(t *testing.T) {
 	}
 	testCase.Run(t)
 }
+
+func _circuitBreakerMocks() KubernetesMockAPI {
+	return KubernetesMockAPI{
+		Namespaces: []MockNamespace{
+			_mockNamespace("namespace-1"),
+			_mockNamespace("namespace-2"),
+			_mockNamespace("namespace-3"),
+			_mockNamespace("namespace-4"),
+		},
+		PDBs: []MockPDB{
+			_mockPDB("pdb-1", "namespace-1", nil, &intStrZeroInt, _selector("app=app-1"), 1, 0),
+			_mockPDB("pdb-2", "namespace-2", nil, &intStrZeroInt, _selector("app=app-2"), 1, 0),
+			_mockPDB("pdb-3", "namespace-3", nil, &intStrZeroInt, _selector("app=app-3"), 1, 0),
+			_mockPDB("pdb-4", "namespace-4", nil, &intStrOneInt, _selector("app=app-4"), 1, 1),
+		},
+		Pods: []MockPod{
+			_mockPod("pod-1", "namespace-1", map[string]string{"app": "app-1"}, false, 0, false),
+			_mockPod("pod-2", "namespace-2", map[string]string{"app": "app-2"}, false, 0, false),
+			_mockPod("pod-3", "namespace-3", map[string]string{"app": "app-3"}, false, 0, false),
+			_mockPod("pod-4", "namespace-4", map[string]string{"app": "app-4"}, false, 0, false),
+		},
+	}
+}
+
+func TestCircuitBreakerTripped(t *testing.T) {
+	reaper := _fakeReaperContext()
+	reaper.MaxReapableRatio = 0.5
+	metrics := &fakeMetricsAPI{}
+	reaper.MetricsAPI = metrics
+	testCase := ReaperUnitTest{
+		TestDescription:         "Tests that an abnormal ratio of reapable PDBs trips the circuit breaker",
+		FakeReaper:              reaper,
+		Mocks:                   _circuitBreakerMocks(),
+		ExpectedReapableBudgets: 3,
+		ExpectedReapedBudgets:   0,
+	}
+	testCase.Run(t)
+
+	if !reaper.State.CircuitBreakerTripped {
+		t.Fatalf("assertion failed, expected circuit breaker to be tripped")
+	}
+	if v, ok := metrics.lastValue(PdbReaperCircuitBreakerMetricName, nil); !ok || v != 1 {
+		t.Fatalf("assertion failed, expected circuit breaker metric 1, got: %v", v)
+	}
+
+	// the next run confirms the abnormal ratio and proceeds with reaping
+	if err := reaper.execute(); err != nil {
+		t.Fatalf("execution failed: %v", err.Error())
+	}
+	if reaper.ReapedPodDisruptionBudgetCount != 3 {
+		t.Fatalf("assertion failed, expected reaped: 3, got: %v", reaper.ReapedPodDisruptionBudgetCount)
+	}
+	if reaper.State.CircuitBreakerTripped {
+		t.Fatalf("assertion failed, expected circuit breaker to be reset")
+	}
+}
+
+func TestCircuitBreakerTrippedPushgateway(t *testing.T) {
+	reaper := _fakeReaperContext()
+	reaper.MaxReapableRatio = 0.5
+	pgw := _fakePushgateway(t)
+	reaper.MetricsAPI = common.NewPrometheusAPI(pgw.URL)
+	testCase := ReaperUnitTest{
+		TestDescription:         "Tests that the circuit breaker metric is kept along the other cluster metrics on the pushgateway",
+		FakeReaper:              reaper,
+		Mocks:                   _circuitBreakerMocks(),
+		ExpectedReapableBudgets: 3,
+		ExpectedReapedBudgets:   0,
+	}
+	testCase.Run(t)
+
+	pgw.assertPushed(t, nil, map[string]float64{
+		PdbReaperCircuitBreakerMetricName: 1,
+		PdbReaperReapableCountMetricName:  3,
+		PdbReaperReapedCountMetricName:    0,
+	})
+}
+
+func TestCircuitBreakerNotTripped(t *testing.T) {
+	reaper := _fakeReaperContext()
+	reaper.MaxReapableRatio = 0.8
+	metrics := &fakeMetricsAPI{}
+	reaper.MetricsAPI = metrics
+	testCase := ReaperUnitTest{
+		TestDescription:         "Tests that a normal ratio of reapable PDBs does not trip the circuit breaker",
+		FakeReaper:              reaper,
+		Mocks:                   _circuitBreakerMocks(),
+		ExpectedReapableBudgets: 3,
+		ExpectedReapedBudgets:   3,
+	}
+	testCase.Run(t)
+
+	if reaper.State.CircuitBreakerTripped {
+		t.Fatalf("assertion failed, expected circuit breaker not to be tripped")
+	}
+	if v, ok := metrics.lastValue(PdbReaperCircuitBreakerMetricName, nil); !ok || v != 0 {
+		t.Fatalf("assertion failed, expected circuit breaker metric 0, got: %v", v)
+	}
+}
+
+func TestCircuitBreakerPersistedState(t *testing.T) {
+	reaper := _fakeReaperContext()
+	reaper.MaxReapableRatio = 0.5
+	reaper.StateConfigMapNamespace = "governor"
+	reaper.StateConfigMapName = "pdb-reaper-state"
+	testCase := ReaperUnitTest{
+		TestDescription:         "Tests that a tripped circuit breaker is persisted for the next run",
+		FakeReaper:              reaper,
+		Mocks:                   _circuitBreakerMocks(),
+		ExpectedReapableBudgets: 3,
+		ExpectedReapedBudgets:   0,
+	}
+	testCase.Run(t)
+
+	nextReaper := _fakeReaperContext()
+	nextReaper.KubernetesClient = reaper.KubernetesClient
+	nextReaper.MaxReapableRatio = 0.5
+	nextReaper.StateConfigMapNamespace = "governor"
+	nextReaper.StateConfigMapName = "pdb-reaper-state"
+	if err := nextReaper.execute(); err != nil {
+		t.Fatalf("execution failed: %v", err.Error())
+	}
+	if nextReaper.ReapedPodDisruptionBudgetCount != 3 {
+		t.Fatalf("assertion failed, expected reaped: 3, got: %v", nextReaper.ReapedPodDisruptionBudgetCount)
+	}
+}
+
+func TestSkipFirstRunReap(t *testing.T) {
+	reaper := _fakeReaperContext()
+	reaper.SkipFirstRunReap = true
+	testCase := ReaperUnitTest{
+		TestDescription:         "Tests that the first run only reports reapable PDBs",
+		FakeReaper:              reaper,
+		Mocks:                   _circuitBreakerMocks(),
+		ExpectedReapableBudgets: 3,
+		ExpectedReapedBudgets:   0,
+	}
+	testCase.Run(t)
+
+	if !reaper.State.Observed {
+		t.Fatalf("assertion failed, expected the first run to be recorded in the state")
+	}
+	pdbs, err := reaper.KubernetesClient.PolicyV1().PodDisruptionBudgets("").List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("failed to list PDBs: %v", err)
+	}
+	if len(pdbs.Items) != 4 {
+		t.Fatalf("assertion failed, expected no PDBs to be deleted in the first run, got %v PDBs", len(pdbs.Items))
+	}
+
+	// a daemon keeps executing the same context
+	if err := reaper.execute(); err != nil {
+		t.Fatalf("execution failed: %v", err.Error())
+	}
+	if reaper.ReapedPodDisruptionBudgetCount != 3 {
+		t.Fatalf("assertion failed, expected reaped: 3, got: %v", reaper.ReapedPodDisruptionBudgetCount)
+	}
+}
+
+func TestSkipFirstRunReapPersistedState(t *testing.T) {
+	newReaper := func() *ReaperContext {
+		reaper := _fakeReaperContext()
+		reaper.SkipFirstRunReap = true
+		reaper.StateConfigMapNamespace = "governor"
+		reaper.StateConfigMapName = "pdb-reaper-state"
+		return reaper
+	}
+	reaper := newReaper()
+	testCase := ReaperUnitTest{
+		TestDescription:         "Tests that the first run is persisted for the next invocation",
+		FakeReaper:              reaper,
+		Mocks:                   _circuitBreakerMocks(),
+		ExpectedReapableBudgets: 3,
+		ExpectedReapedBudgets:   0,
+	}
+	testCase.Run(t)
+
+	nextReaper := newReaper()
+	nextReaper.KubernetesClient = reaper.KubernetesClient
+	if err := nextReaper.execute(); err != nil {
+		t.Fatalf("execution failed: %v", err.Error())
+	}
+	if nextReaper.ReapedPodDisruptionBudgetCount != 3 {
+		t.Fatalf("assertion failed, expected reaped: 3, got: %v", nextReaper.ReapedPodDisruptionBudgetCount)
+	}
+}
+
+func _readinessGateMocks() KubernetesMockAPI {
+	gatedPod := _mockPod("pod-1", "namespace-1", map[string]string{"app": "app-1"}, false, 0, false)
+	gatedPod.Phase = corev1.PodRunning
+	gatedPod.Conditions = []corev1.PodCondition{
+		{
+			Type:   corev1.ContainersReady,
+			Status: corev1.ConditionTrue,
+		},
+		{
+			Type:               "example.com/load-balancer-ready",
+			Status:             corev1.ConditionFalse,
+			LastTransitionTime: metav1.Time{Time: time.Now().Add(time.Duration(-50) * time.Second)},
+		},
+	}
+	return KubernetesMockAPI{
+		Namespaces: []MockNamespace{
+			_mockNamespace("namespace-1"),
+		},
+		PDBs: []MockPDB{
+			_mockPDB("pdb-1", "namespace-1", nil, &intStrOneInt, _selector("app=app-1"), 1, 0),
+		},
+		Pods: []MockPod{
+			gatedPod,
+		},
+	}
+}
+
+func TestNotReadyReadinessGate(t *testing.T) {
+	reaper := _fakeReaperContext()
+	reaper.ReapNotReadyThreshold = 10
+	reaper.NotReadyGateTypes = []string{"example.com/load-balancer-ready"}
+	testCase := ReaperUnitTest{
+		TestDescription:         "Tests execution scenario of pdb reaper with blocking PDBs due to a failing readiness gate",
+		FakeReaper:              reaper,
+		Mocks:                   _readinessGateMocks(),
+		ExpectedReapableBudgets: 1,
+		ExpectedReapedBudgets:   1,
+	}
+	testCase.Run(t)
+}
+
+func TestNotReadyReadinessGateNotConfigured(t *testing.T) {
+	reaper := _fakeReaperContext()
+	reaper.ReapNotReadyThreshold = 10
+	testCase := ReaperUnitTest{
+		TestDescription:         "Tests execution scenario of pdb reaper with a failing readiness gate which is not considered",
+		FakeReaper:              reaper,
+		Mocks:                   _readinessGateMocks(),
+		ExpectedReapableBudgets: 0,
+		ExpectedReapedBudgets:   0,
+	}
+	testCase.Run(t)
+}
+
+func _getPDB(t *testing.T, reaper *ReaperContext, namespace, name string) *policyv1.PodDisruptionBudget {
+	pdb, err := reaper.KubernetesClient.PolicyV1().PodDisruptionBudgets(namespace).Get(context.Background(), name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get PDB %v/%v: %v", namespace, name, err)
+	}
+	return pdb
+}
+
+func TestDryRunAnnotate(t *testing.T) {
+	reaper := _fakeReaperContext()
+	reaper.DryRun = true
+	reaper.DryRunAnnotate = true
+
+	staleAnnotatedPDB := _mockPDB("pdb-2", "namespace-2", nil, &intStrOneInt, _selector("app=app-2"), 1, 1)
+	staleAnnotatedPDB.Annotations = map[string]string{
+		WouldReapReasonAnnotationKey:    EventReasonBlockingDetected,
+		WouldReapTimestampAnnotationKey: "2020-01-01T00:00:00Z",
+		"team":                          "platform",
+	}
+	testCase := ReaperUnitTest{
+		TestDescription: "Tests execution scenario of pdb reaper annotating reapable PDBs in DryRun",
+		FakeReaper:      reaper,
+		Mocks: KubernetesMockAPI{
+			Namespaces: []MockNamespace{
+				_mockNamespace("namespace-1"),
+				_mockNamespace("namespace-2"),
+			},
+			PDBs: []MockPDB{
+				_mockPDB("pdb-1", "namespace-1", nil, &intStrZeroInt, _selector("app=app-1"), 1, 0),
+				staleAnnotatedPDB,
+			},
+			Pods: []MockPod{
+				_mockPod("pod-1", "namespace-1", map[string]string{"app": "app-1"}, false, 0, false),
+				_mockPod("pod-2", "namespace-2", map[string]string{"app": "app-2"}, false, 0, false),
+			},
+		},
+		ExpectedReapableBudgets: 1,
+		ExpectedReapedBudgets:   0,
+	}
+	testCase.Run(t)
+
+	pdb := _getPDB(t, reaper, "namespace-1", "pdb-1")
+	if reason := pdb.Annotations[WouldReapReasonAnnotationKey]; reason != EventReasonBlockingDetected {
+		t.Fatalf("assertion failed, expected would-reap reason %v, got: %v", EventReasonBlockingDetected, reason)
+	}
+	if _, ok := pdb.Annotations[WouldReapTimestampAnnotationKey]; !ok {
+		t.Fatalf("assertion failed, expected would-reap timestamp annotation")
+	}
+
+	pdb = _getPDB(t, reaper, "namespace-2", "pdb-2")
+	if _, ok := pdb.Annotations[WouldReapReasonAnnotationKey]; ok {
+		t.Fatalf("assertion failed, expected would-reap reason annotation to be cleared")
+	}
+	if _, ok := pdb.Annotations[WouldReapTimestampAnnotationKey]; ok {
+		t.Fatalf("assertion failed, expected would-reap timestamp annotation to be cleared")
+	}
+	if pdb.Annotations["team"] != "platform" {
+		t.Fatalf("assertion failed, expected unrelated annotations to be preserved")
+	}
+
+	// once the PDB is fixed, the annotations are cleared on the next run
+	pdb = _getPDB(t, reaper, "namespace-1", "pdb-1")
+	pdb.Spec.MaxUnavailable = &intStrOneInt
+	pdb.Status.DisruptionsAllowed = 1
+	if _, err := reaper.KubernetesClient.PolicyV1().PodDisruptionBudgets("namespace-1").Update(context.Background(), pdb, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("failed to update PDB: %v", err)
+	}
+	if err := reaper.execute(); err != nil {
+		t.Fatalf("execution failed: %v", err.Error())
+	}
+	pdb = _getPDB(t, reaper, "namespace-1", "pdb-1")
+	if _, ok := pdb.Annotations[WouldReapReasonAnnotationKey]; ok {
+		t.Fatalf("assertion failed, expected would-reap reason annotation to be cleared after fix")
+	}
+}
+
+func TestMatchedPodsMetric(t *testing.T) {
+	reaper := _fakeReaperContext()
+	metrics := &fakeMetricsAPI{}
+	reaper.MetricsAPI = metrics
+	testCase := ReaperUnitTest{
+		TestDescription: "Tests that the number of pods matched by each PDB is exposed as a metric",
+		FakeReaper:      reaper,
+		Mocks: KubernetesMockAPI{
+			Namespaces: []MockNamespace{
+				_mockNamespace("namespace-1"),
+				_mockNamespace("namespace-2"),
+			},
+			PDBs: []MockPDB{
+				_mockPDB("pdb-1", "namespace-1", nil, &intStrOneInt, _selector("app=app-1"), 3, 0),
+				_mockPDB("pdb-2", "namespace-2", nil, &intStrOneInt, _selector("app=app-2"), 1, 0),
+			},
+			Pods: []MockPod{
+				_mockPod("pod-1a", "namespace-1", map[string]string{"app": "app-1"}, false, 0, false),
+				_mockPod("pod-1b", "namespace-1", map[string]string{"app": "app-1"}, false, 0, false),
+				_mockPod("pod-1c", "namespace-1", map[string]string{"app": "app-1"}, false, 0, false),
+				_mockPod("pod-2", "namespace-2", map[string]string{"app": "app-2"}, false, 0, false),
+			},
+		},
+		ExpectedReapableBudgets: 0,
+		ExpectedReapedBudgets:   0,
+	}
+	testCase.Run(t)
+
+	expected := map[string]float64{"pdb-1": 3, "pdb-2": 1}
+	for name, count := range expected {
+		v, ok := metrics.lastValue(PdbReaperMatchedPodsMetricName, map[string]string{"pdb": name})
+		if !ok || v != count {
+			t.Fatalf("assertion failed, expected %v matched pods for %v, got: %v", count, name, v)
+		}
+	}
+}
+
+func TestMatchedPodsPushgateway(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	reaper := _fakeReaperContext()
+	reaper.Clock = fakeClock{now}
+	pgw := _fakePushgateway(t)
+	reaper.MetricsAPI = common.NewPrometheusAPI(pgw.URL)
+
+	blocking := _mockPDB("pdb-1", "namespace-1", nil, &intStrOneInt, _selector("app=app-1"), 2, 0)
+	blocking.Conditions = []metav1.Condition{{
+		Type:               policyv1.DisruptionAllowedCondition,
+		Status:             metav1.ConditionFalse,
+		Reason:             policyv1.InsufficientPodsReason,
+		LastTransitionTime: metav1.Time{Time: now.Add(-90 * time.Minute)},
+	}}
+	testCase := ReaperUnitTest{
+		TestDescription: "Tests that the matched pods metric is kept along the other metrics of the PDB on the pushgateway",
+		FakeReaper:      reaper,
+		Mocks: KubernetesMockAPI{
+			Namespaces: []MockNamespace{
+				_mockNamespace("namespace-1"),
+			},
+			PDBs: []MockPDB{blocking},
+			Pods: []MockPod{
+				_mockPod("pod-1a", "namespace-1", map[string]string{"app": "app-1"}, true, 6, false),
+				_mockPod("pod-1b", "namespace-1", map[string]string{"app": "app-1"}, true, 6, false),
+			},
+		},
+		ExpectedReapableBudgets: 1,
+		ExpectedReapedBudgets:   1,
+	}
+	testCase.Run(t)
+
+	pgw.assertPushed(t, map[string]string{"namespace": "namespace-1", "pdb": "pdb-1"}, map[string]float64{
+		PdbReaperMatchedPodsMetricName:      2,
+		PdbReaperBlockingDurationMetricName: 5400,
+	})
+}
+
+func _reapCooldownMocks() KubernetesMockAPI {
+	return KubernetesMockAPI{
+		Namespaces: []MockNamespace{
+			_mockNamespace("namespace-1"),
+		},
+		PDBs: []MockPDB{
+			_mockPDB("pdb-1", "namespace-1", nil, &intStrZeroInt, _selector("app=app-1"), 1, 0),
+		},
+		Pods: []MockPod{
+			_mockPod("pod-1", "namespace-1", map[string]string{"app": "app-1"}, false, 0, false),
+		},
+	}
+}
+
+func TestReapCooldownWithinWindow(t *testing.T) {
+	reaper := _fakeReaperContext()
+	reaper.ReapCooldown = time.Hour
+	reaper.State.ReapedAt = map[string]time.Time{
+		"namespace-1/pdb-1": time.Now().Add(-10 * time.Minute),
+	}
+	testCase := ReaperUnitTest{
+		TestDescription:         "Tests that a PDB recreated within the cooldown window is not reaped again",
+		FakeReaper:              reaper,
+		Mocks:                   _reapCooldownMocks(),
+		ExpectedReapableBudgets: 1,
+		ExpectedReapedBudgets:   0,
+	}
+	testCase.Run(t)
+}
+
+func TestReapCooldownOutsideWindow(t *testing.T) {
+	reaper := _fakeReaperContext()
+	reaper.ReapCooldown = time.Hour
+	reaper.State.ReapedAt = map[string]time.Time{
+		"namespace-1/pdb-1": time.Now().Add(-2 * time.Hour),
+	}
+	testCase := ReaperUnitTest{
+		TestDescription:         "Tests that a PDB recreated outside the cooldown window is reaped",
+		FakeReaper:              reaper,
+		Mocks:                   _reapCooldownMocks(),
+		ExpectedReapableBudgets: 1,
+		ExpectedReapedBudgets:   1,
+	}
+	testCase.Run(t)
+
+	if _, ok := reaper.State.ReapedAt["namespace-1/pdb-1"]; !ok {
+		t.Fatalf("assertion failed, expected reaped PDB to be tracked for cooldown")
+	}
+
+	// recreating the PDB right after it was reaped should not cause it to be reaped again
+	_fakeAPI(&ReaperUnitTest{FakeReaper: reaper, Mocks: KubernetesMockAPI{PDBs: _reapCooldownMocks().PDBs}})
+	if err := reaper.execute(); err != nil {
+		t.Fatalf("execution failed: %v", err.Error())
+	}
+	if reaper.ReapedPodDisruptionBudgetCount != 0 {
+		t.Fatalf("assertion failed, expected reaped: 0, got: %v", reaper.ReapedPodDisruptionBudgetCount)
+	}
+}
+
+func TestMisconfiguredBothFieldsSet(t *testing.T) {
+	reaper := _fakeReaperContext()
+	testCase := ReaperUnitTest{
+		TestDescription: "Tests execution scenario of pdb reaper with a malformed PDB setting both maxUnavailable and minAvailable",
+		FakeReaper:      reaper,
+		Mocks: KubernetesMockAPI{
+			Namespaces: []MockNamespace{
+				_mockNamespace("namespace-1"),
+			},
+			PDBs: []MockPDB{
+				_mockPDB("pdb-1", "namespace-1", &intStrZeroInt, &intStrOneInt, _selector("app=app-1"), 1, 0),
+			},
+			Pods: []MockPod{
+				_mockPod("pod-1", "namespace-1", map[string]string{"app": "app-1"}, false, 0, false),
+			},
+		},
+		ExpectedReapableBudgets: 1,
+		ExpectedReapedBudgets:   1,
+	}
+	testCase.Run(t)
+
+	events, err := reaper.KubernetesClient.CoreV1().Events("namespace-1").List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("failed to list events: %v", err)
+	}
+	var found bool
+	for _, event := range events.Items {
+		if event.Reason == EventReasonBlockingDetected && event.Message == fmt.Sprintf(EventMessageMalformedFmt, "namespace-1/pdb-1") {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("assertion failed, expected malformed PDB event")
+	}
+}
+
+func TestTerminatingPDBSkipped(t *testing.T) {
+	reaper := _fakeReaperContext()
+	reaper.DryRun = true
+	reaper.DryRunAnnotate = true
+
+	terminatingPDB := _mockPDB("pdb-1", "namespace-1", nil, &intStrZeroInt, _selector("app=app-1"), 1, 0)
+	terminatingPDB.DeletionTimestamp = &metav1.Time{Time: time.Now().Add(-time.Minute)}
+	terminatingPDB.Finalizers = []string{"example.com/finalizer"}
+	testCase := ReaperUnitTest{
+		TestDescription: "Tests that PDBs which are already terminating are skipped from all paths",
+		FakeReaper:      reaper,
+		Mocks: KubernetesMockAPI{
+			Namespaces: []MockNamespace{
+				_mockNamespace("namespace-1"),
+			},
+			PDBs: []MockPDB{
+				terminatingPDB,
+				_mockPDB("pdb-2", "namespace-1", nil, &intStrOneInt, _selector("app=app-1"), 1, 1),
+			},
+			Pods: []MockPod{
+				_mockPod("pod-1", "namespace-1", map[string]string{"app": "app-1"}, false, 0, false),
+			},
+		},
+		ExpectedReapableBudgets: 0,
+		ExpectedReapedBudgets:   0,
+	}
+	testCase.Run(t)
+
+	if reaper.ScannedPodDisruptionBudgetsCount != 1 {
+		t.Fatalf("assertion failed, expected scanned: 1, got: %v", reaper.ScannedPodDisruptionBudgetsCount)
+	}
+	if len(reaper.NamespacesWithMultiplePodDisruptionBudgets) != 0 {
+		t.Fatalf("assertion failed, expected terminating PDB to be excluded from multiple PDB detection")
+	}
+	if len(reaper.ClusterBlockingPodDisruptionBudgets) != 0 {
+		t.Fatalf("assertion failed, expected terminating PDB to be excluded from blocking PDB detection")
+	}
+	events, err := reaper.KubernetesClient.CoreV1().Events("namespace-1").List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("failed to list events: %v", err)
+	}
+	if len(events.Items) != 0 {
+		t.Fatalf("assertion failed, expected no events, got: %v", len(events.Items))
+	}
+	pdb := _getPDB(t, reaper, "namespace-1", "pdb-1")
+	if _, ok := pdb.Annotations[WouldReapReasonAnnotationKey]; ok {
+		t.Fatalf("assertion failed, expected terminating PDB not to be annotated")
+	}
+}
+
+func TestNDJSONOutput(t *testing.T) {
+	reaper := _fakeReaperContext()
+	output := &bytes.Buffer{}
+	reaper.NDJSON = true
+	reaper.Output = output
+	testCase := ReaperUnitTest{
+		TestDescription: "Tests that each reaper action is written as a line of NDJSON",
+		FakeReaper:      reaper,
+		Mocks: KubernetesMockAPI{
+			Namespaces: []MockNamespace{
+				_mockNamespace("namespace-1"),
+			},
+			PDBs: []MockPDB{
+				_mockPDB("pdb-1", "namespace-1", nil, &intStrZeroInt, _selector("app=app-1"), 1, 0),
+				_mockPDB("pdb-2", "namespace-1", nil, &intStrZeroInt, _selector("app=app-2"), 1, 0),
+			},
+			Pods: []MockPod{
+				_mockPod("pod-1", "namespace-1", map[string]string{"app": "app-1"}, false, 0, false),
+				_mockPod("pod-2", "namespace-1", map[string]string{"app": "app-2"}, false, 0, false),
+			},
+		},
+		ExpectedReapableBudgets: 2,
+		ExpectedReapedBudgets:   2,
+	}
+	testCase.Run(t)
+
+	lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("assertion failed, expected 4 NDJSON lines, got: %v", len(lines))
+	}
+
+	detected := make(map[string]int)
+	for i, line := range lines {
+		var record ActionRecord
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("failed to parse NDJSON line %q: %v", line, err)
+		}
+		if record.Timestamp.IsZero() {
+			t.Fatalf("assertion failed, expected record timestamp to be set")
+		}
+		switch record.Type {
+		case RecordTypeDetection:
+			if record.Reason != ReasonBlocking || record.Code != ReasonBlocking.Code() {
+				t.Fatalf("assertion failed, expected detection reason %v, got: %v", ReasonBlocking, record.Reason)
+			}
+			detected[record.PDB] = i
+		case RecordTypeDeletion:
+			if record.Reason != ReasonPodDisruptionBudgetDeleted {
+				t.Fatalf("assertion failed, expected deletion reason %v, got: %v", ReasonPodDisruptionBudgetDeleted, record.Reason)
+			}
+			if _, ok := detected[record.PDB]; !ok {
+				t.Fatalf("assertion failed, expected detection of %v before its deletion", record.PDB)
+			}
+		default:
+			t.Fatalf("assertion failed, unexpected record type %v", record.Type)
+		}
+	}
+}
+
+func _crashLoopPrecedenceMocks() KubernetesMockAPI {
+	return KubernetesMockAPI{
+		Namespaces: []MockNamespace{
+			_mockNamespace("namespace-1"),
+		},
+		PDBs: []MockPDB{
+			_mockPDB("pdb-1", "namespace-1", nil, &intStrOneInt, _selector("app=app-1"), 1, 0),
+		},
+		Pods: []MockPod{
+			_mockPod("pod-1", "namespace-1", map[string]string{"app": "app-1"}, true, 6, true),
+		},
+	}
+}
+
+func TestCrashLoopPrecedence(t *testing.T) {
+	reaper := _fakeReaperContext()
+	reaper.ReapNotReadyThreshold = 10
+	reaper.CrashLoopPrecedence = true
+	testCase := ReaperUnitTest{
+		TestDescription:         "Tests that a pod which is crashlooping and not-ready is only counted as crashlooping",
+		FakeReaper:              reaper,
+		Mocks:                   _crashLoopPrecedenceMocks(),
+		ExpectedReapableBudgets: 1,
+		ExpectedReapedBudgets:   1,
+	}
+	testCase.Run(t)
+
+	reasons := reaper.ReapableReasons["namespace-1/pdb-1"]
+	if len(reasons) != 1 || reasons[0] != ReasonBlockingCrashLoop {
+		t.Fatalf("assertion failed, expected reasons [%v], got: %v", ReasonBlockingCrashLoop, reasons)
+	}
+}
+
+func TestCrashLoopNoPrecedence(t *testing.T) {
+	reaper := _fakeReaperContext()
+	reaper.ReapNotReadyThreshold = 10
+	reaper.CrashLoopPrecedence = false
+	testCase := ReaperUnitTest{
+		TestDescription:         "Tests that a pod which is crashlooping and not-ready is counted under both reasons without precedence",
+		FakeReaper:              reaper,
+		Mocks:                   _crashLoopPrecedenceMocks(),
+		ExpectedReapableBudgets: 1,
+		ExpectedReapedBudgets:   1,
+	}
+	testCase.Run(t)
+
+	reasons := reaper.ReapableReasons["namespace-1/pdb-1"]
+	if len(reasons) != 2 {
+		t.Fatalf("assertion failed, expected crashloop and not-ready reasons, got: %v", reasons)
+	}
+}
+
+func _reasonPriorityMocks() KubernetesMockAPI {
+	return KubernetesMockAPI{
+		Namespaces: []MockNamespace{
+			_mockNamespace("namespace-1"),
+		},
+		PDBs: []MockPDB{
+			_mockPDB("pdb-1", "namespace-1", nil, &intStrZeroInt, _selector("app=app-1"), 1, 0),
+			_mockPDB("pdb-2", "namespace-1", nil, &intStrOneInt, _selector("app=app-1"), 1, 1),
+		},
+		Pods: []MockPod{
+			_mockPod("pod-1", "namespace-1", map[string]string{"app": "app-1"}, false, 0, false),
+		},
+	}
+}
+
+func TestReapReasonPriorityDefault(t *testing.T) {
+	reaper := _fakeReaperContext()
+	metrics := &fakeMetricsAPI{}
+	reaper.MetricsAPI = metrics
+	testCase := ReaperUnitTest{
+		TestDescription:         "Tests that the primary deletion reason follows the default priority",
+		FakeReaper:              reaper,
+		Mocks:                   _reasonPriorityMocks(),
+		ExpectedReapableBudgets: 2,
+		ExpectedReapedBudgets:   2,
+	}
+	testCase.Run(t)
+
+	if _, ok := metrics.lastValue(PdbReaperDeletedMetricName, map[string]string{"pdb": "pdb-1", "reason": EventReasonBlockingDetected}); !ok {
+		t.Fatalf("assertion failed, expected deleted metric with primary reason %v", EventReasonBlockingDetected)
+	}
+}
+
+func TestReapReasonDeletedPushgateway(t *testing.T) {
+	reaper := _fakeReaperContext()
+	pgw := _fakePushgateway(t)
+	reaper.MetricsAPI = common.NewPrometheusAPI(pgw.URL)
+	testCase := ReaperUnitTest{
+		TestDescription:         "Tests that the deleted metric doesn't replace the result metric of its primary reason on the pushgateway",
+		FakeReaper:              reaper,
+		Mocks:                   _reasonPriorityMocks(),
+		ExpectedReapableBudgets: 2,
+		ExpectedReapedBudgets:   2,
+	}
+	testCase.Run(t)
+
+	pgw.assertPushed(t, map[string]string{
+		"namespace":   "namespace-1",
+		"pdb":         "pdb-1",
+		"reason":      ReasonBlocking.String(),
+		"reason_code": strconv.Itoa(ReasonBlocking.Code()),
+	}, map[string]float64{
+		PdbReaperResultMetricName:  1,
+		PdbReaperDeletedMetricName: 1,
+	})
+}
+
+func TestReapReasonPriorityConfigured(t *testing.T) {
+	reaper := _fakeReaperContext()
+	metrics := &fakeMetricsAPI{}
+	reaper.MetricsAPI = metrics
+	reaper.ReapReasonPriority = []string{ReapModeMultiple, ReapModeMisconfigured}
+	testCase := ReaperUnitTest{
+		TestDescription:         "Tests that the primary deletion reason follows the configured priority",
+		FakeReaper:              reaper,
+		Mocks:                   _reasonPriorityMocks(),
+		ExpectedReapableBudgets: 2,
+		ExpectedReapedBudgets:   2,
+	}
+	testCase.Run(t)
+
+	if _, ok := metrics.lastValue(PdbReaperDeletedMetricName, map[string]string{"pdb": "pdb-1", "reason": EventReasonMultipleDetected}); !ok {
+		t.Fatalf("assertion failed, expected deleted metric with primary reason %v", EventReasonMultipleDetected)
+	}
+
+	// every matching rule is still recorded
+	reasons := reaper.ReapableReasons["namespace-1/pdb-1"]
+	if !containsReason(reasons, ReasonBlocking) || !containsReason(reasons, ReasonMultiple) {
+		t.Fatalf("assertion failed, expected reasons for each rule, got: %v", reasons)
+	}
+}
+
+func TestMultipleClusters(t *testing.T) {
+	reaper := _fakeReaperContext()
+	metrics := &fakeMetricsAPI{}
+	reaper.MetricsAPI = metrics
+
+	clusterA := fake.NewSimpleClientset()
+	clusterB := fake.NewSimpleClientset()
+	clusterC := fake.NewSimpleClientset()
+	clusterC.PrependReactor("list", "poddisruptionbudgets", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, fmt.Errorf("connection refused")
+	})
+
+	reaper.KubernetesClient = clusterA
+	_fakeAPI(&ReaperUnitTest{FakeReaper: reaper, Mocks: KubernetesMockAPI{
+		Namespaces: []MockNamespace{
+			_mockNamespace("namespace-1"),
+		},
+		PDBs: []MockPDB{
+			_mockPDB("pdb-1", "namespace-1", nil, &intStrZeroInt, _selector("app=app-1"), 1, 0),
+		},
+		Pods: []MockPod{
+			_mockPod("pod-1", "namespace-1", map[string]string{"app": "app-1"}, false, 0, false),
+		},
+	}})
+
+	reaper.KubernetesClient = clusterB
+	_fakeAPI(&ReaperUnitTest{FakeReaper: reaper, Mocks: KubernetesMockAPI{
+		Namespaces: []MockNamespace{
+			_mockNamespace("namespace-1"),
+		},
+		PDBs: []MockPDB{
+			_mockPDB("pdb-1", "namespace-1", nil, &intStrOneInt, _selector("app=app-1"), 1, 1),
+		},
+		Pods: []MockPod{
+			_mockPod("pod-1", "namespace-1", map[string]string{"app": "app-1"}, false, 0, false),
+		},
+	}})
+
+	reaper.Clusters = []ClusterTarget{
+		{Name: "cluster-c", KubernetesClient: clusterC},
+		{Name: "cluster-a", KubernetesClient: clusterA},
+		{Name: "cluster-b", KubernetesClient: clusterB},
+	}
+
+	err := reaper.execute()
+	if err == nil || !strings.Contains(err.Error(), "cluster-c") {
+		t.Fatalf("assertion failed, expected execution to fail for cluster-c, got: %v", err)
+	}
+	if !errors.Is(err, ErrScanFailed) {
+		t.Fatalf("assertion failed, expected the error of cluster-c to match %v, got: %v", ErrScanFailed, err)
+	}
+
+	if result := reaper.ClusterResults["cluster-c"]; result.Err == nil {
+		t.Fatalf("assertion failed, expected an error for cluster-c")
+	}
+	if result := reaper.ClusterResults["cluster-a"]; result.Err != nil || result.ReapablePodDisruptionBudgetsCount != 1 || result.ReapedPodDisruptionBudgetCount != 1 {
+		t.Fatalf("assertion failed, expected cluster-a to reap 1 PDB, got: %+v", result)
+	}
+	if result := reaper.ClusterResults["cluster-b"]; result.Err != nil || result.ScannedPodDisruptionBudgetsCount != 1 || result.ReapablePodDisruptionBudgetsCount != 0 {
+		t.Fatalf("assertion failed, expected cluster-b to scan 1 PDB and reap none, got: %+v", result)
+	}
+
+	if _, err := clusterA.PolicyV1().PodDisruptionBudgets("namespace-1").Get(context.Background(), "pdb-1", metav1.GetOptions{}); err == nil {
+		t.Fatalf("assertion failed, expected pdb-1 to be deleted from cluster-a")
+	}
+	if _, err := clusterB.PolicyV1().PodDisruptionBudgets("namespace-1").Get(context.Background(), "pdb-1", metav1.GetOptions{}); err != nil {
+		t.Fatalf("assertion failed, expected pdb-1 to remain in cluster-b: %v", err)
+	}
+
+	if _, ok := metrics.lastValue(PdbReaperResultMetricName, map[string]string{"cluster": "cluster-a", "pdb": "pdb-1", "reason": EventReasonPodDisruptionBudgetDeleted}); !ok {
+		t.Fatalf("assertion failed, expected deletion metric tagged with cluster-a")
+	}
+	if _, ok := metrics.lastValue(PdbReaperResultMetricName, map[string]string{"cluster": "cluster-b"}); ok {
+		t.Fatalf("assertion failed, expected no result metric for cluster-b")
+	}
+
+	events, err := clusterA.CoreV1().Events("namespace-1").List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("failed to list events: %v", err)
+	}
+	if len(events.Items) == 0 || events.Items[0].Labels[ClusterLabelKey] != "cluster-a" {
+		t.Fatalf("assertion failed, expected events in cluster-a to be labeled with the cluster name")
+	}
+}
+
+// _fakeAccessReviews responds to SelfSubjectAccessReviews, denying the given permissions
+func _fakeAccessReviews(client *fake.Clientset, denied ...string) {
+	client.PrependReactor("create", "selfsubjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SelfSubjectAccessReview)
+		permission := permissionString(*review.Spec.ResourceAttributes)
+		review.Status.Allowed = !common.StringSliceContains(denied, permission)
+		return true, review, nil
+	})
+}
+
+func _rbacMocks() KubernetesMockAPI {
+	return KubernetesMockAPI{
+		Namespaces: []MockNamespace{
+			_mockNamespace("namespace-1"),
+		},
+		PDBs: []MockPDB{
+			_mockPDB("pdb-1", "namespace-1", nil, &intStrZeroInt, _selector("app=app-1"), 1, 0),
+		},
+		Pods: []MockPod{
+			_mockPod("pod-1", "namespace-1", map[string]string{"app": "app-1"}, false, 0, false),
+		},
+	}
+}
+
+func TestPermissionString(t *testing.T) {
+	tests := []struct {
+		attributes authorizationv1.ResourceAttributes
+		expected   string
+	}{
+		{authorizationv1.ResourceAttributes{Verb: "list", Resource: "pods"}, "list pods"},
+		{authorizationv1.ResourceAttributes{Verb: "get", Resource: "pods", Subresource: "log"}, "get pods/log"},
+		{authorizationv1.ResourceAttributes{Verb: "delete", Group: "policy", Resource: "poddisruptionbudgets"}, "delete poddisruptionbudgets.policy"},
+		{authorizationv1.ResourceAttributes{Verb: "update", Group: "apps", Resource: "deployments", Subresource: "scale"}, "update deployments/scale.apps"},
+	}
+	for _, tt := range tests {
+		if permission := permissionString(tt.attributes); permission != tt.expected {
+			t.Fatalf("assertion failed, expected %v, got: %v", tt.expected, permission)
+		}
+	}
+}
+
+func TestRBACSelfCheckAllowed(t *testing.T) {
+	reaper := _fakeReaperContext()
+	metrics := &fakeMetricsAPI{}
+	reaper.MetricsAPI = metrics
+	reaper.StrictRBAC = true
+	_fakeAccessReviews(reaper.KubernetesClient.(*fake.Clientset))
+	testCase := ReaperUnitTest{
+		TestDescription:         "Tests that the run proceeds when RBAC permissions are sufficient",
+		FakeReaper:              reaper,
+		Mocks:                   _rbacMocks(),
+		ExpectedReapableBudgets: 1,
+		ExpectedReapedBudgets:   1,
+	}
+	testCase.Run(t)
+
+	if value, ok := metrics.lastValue(PdbReaperRBACSufficientMetricName, nil); !ok || value != 1 {
+		t.Fatalf("assertion failed, expected RBAC metric value 1, got: %v", value)
+	}
+}
+
+func TestRBACSelfCheckPushgateway(t *testing.T) {
+	reaper := _fakeReaperContext()
+	pgw := _fakePushgateway(t)
+	reaper.MetricsAPI = common.NewPrometheusAPI(pgw.URL)
+	_fakeAccessReviews(reaper.KubernetesClient.(*fake.Clientset))
+	testCase := ReaperUnitTest{
+		TestDescription:         "Tests that the RBAC metric is kept along the other cluster metrics on the pushgateway",
+		FakeReaper:              reaper,
+		Mocks:                   _rbacMocks(),
+		ExpectedReapableBudgets: 1,
+		ExpectedReapedBudgets:   1,
+	}
+	testCase.Run(t)
+
+	pgw.assertPushed(t, nil, map[string]float64{
+		PdbReaperRBACSufficientMetricName: 1,
+		PdbReaperReapableCountMetricName:  1,
+		PdbReaperReapedCountMetricName:    1,
+	})
+}
+
+func TestRBACSelfCheckDenied(t *testing.T) {
+	reaper := _fakeReaperContext()
+	metrics := &fakeMetricsAPI{}
+	reaper.MetricsAPI = metrics
+	_fakeAccessReviews(reaper.KubernetesClient.(*fake.Clientset), "delete poddisruptionbudgets.policy")
+	testCase := ReaperUnitTest{
+		TestDescription:         "Tests that the run proceeds with a warning when RBAC permissions are insufficient",
+		FakeReaper:              reaper,
+		Mocks:                   _rbacMocks(),
+		ExpectedReapableBudgets: 1,
+		ExpectedReapedBudgets:   1,
+	}
+	testCase.Run(t)
+
+	if value, ok := metrics.lastValue(PdbReaperRBACSufficientMetricName, nil); !ok || value != 0 {
+		t.Fatalf("assertion failed, expected RBAC metric value 0, got: %v", value)
+	}
+}
+
+func TestRBACSelfCheckDeniedStrict(t *testing.T) {
+	reaper := _fakeReaperContext()
+	reaper.StrictRBAC = true
+	_fakeAccessReviews(reaper.KubernetesClient.(*fake.Clientset), "list pods")
+	_fakeAPI(&ReaperUnitTest{FakeReaper: reaper, Mocks: _rbacMocks()})
+
+	err := reaper.execute()
+	if err == nil || !strings.Contains(err.Error(), "list pods") {
+		t.Fatalf("assertion failed, expected run to fail on missing permission, got: %v", err)
+	}
+	if reaper.ReapedPodDisruptionBudgetCount != 0 {
+		t.Fatalf("assertion failed, expected no PDBs to be reaped, got: %v", reaper.ReapedPodDisruptionBudgetCount)
+	}
+	_getPDB(t, reaper, "namespace-1", "pdb-1")
+}
+
+func _readinessProbeGraceMocks() KubernetesMockAPI {
+	slowPod := _mockPod("pod-1", "namespace-1", map[string]string{"app": "app-1"}, false, 0, true)
+	slowPod.ReadinessProbeInitialDelay = 600
+	return KubernetesMockAPI{
+		Namespaces: []MockNamespace{
+			_mockNamespace("namespace-1"),
+		},
+		PDBs: []MockPDB{
+			_mockPDB("pdb-1", "namespace-1", nil, &intStrOneInt, _selector("app=app-1"), 1, 0),
+		},
+		Pods: []MockPod{
+			slowPod,
+		},
+	}
+}
+
+func TestNotReadyReadinessProbeGrace(t *testing.T) {
+	reaper := _fakeReaperContext()
+	reaper.ReapNotReadyThreshold = 30
+	reaper.ReadinessProbeGrace = true
+	testCase := ReaperUnitTest{
+		TestDescription:         "Tests that a pod with a long readiness probe initial delay is spared from the not-ready count",
+		FakeReaper:              reaper,
+		Mocks:                   _readinessProbeGraceMocks(),
+		ExpectedReapableBudgets: 0,
+		ExpectedReapedBudgets:   0,
+	}
+	testCase.Run(t)
+}
+
+func TestNotReadyReadinessProbeGraceDisabled(t *testing.T) {
+	reaper := _fakeReaperContext()
+	reaper.ReapNotReadyThreshold = 30
+	testCase := ReaperUnitTest{
+		TestDescription:         "Tests that the readiness probe initial delay is ignored unless enabled",
+		FakeReaper:              reaper,
+		Mocks:                   _readinessProbeGraceMocks(),
+		ExpectedReapableBudgets: 1,
+		ExpectedReapedBudgets:   1,
+	}
+	testCase.Run(t)
+}
+
+func _reapWindowMocks() KubernetesMockAPI {
+	return KubernetesMockAPI{
+		Namespaces: []MockNamespace{
+			_mockNamespace("namespace-1"),
+		},
+		PDBs: []MockPDB{
+			_mockPDB("pdb-1", "namespace-1", nil, &intStrZeroInt, _selector("app=app-1"), 1, 0),
+		},
+		Pods: []MockPod{
+			_mockPod("pod-1", "namespace-1", map[string]string{"app": "app-1"}, false, 0, false),
+		},
+	}
+}
+
+func TestReapWindowInside(t *testing.T) {
+	reaper := _fakeReaperContext()
+	reaper.ReapWindow, _ = parseReapWindow("09:00-17:00", "UTC")
+	reaper.Clock = fakeClock{time.Date(2024, 1, 1, 10, 30, 0, 0, time.UTC)}
+	testCase := ReaperUnitTest{
+		TestDescription:         "Tests that PDBs are deleted inside the reap window",
+		FakeReaper:              reaper,
+		Mocks:                   _reapWindowMocks(),
+		ExpectedReapableBudgets: 1,
+		ExpectedReapedBudgets:   1,
+	}
+	testCase.Run(t)
+}
+
+func TestReapWindowOutside(t *testing.T) {
+	reaper := _fakeReaperContext()
+	reaper.ReapWindow, _ = parseReapWindow("09:00-17:00", "UTC")
+	reaper.Clock = fakeClock{time.Date(2024, 1, 1, 20, 0, 0, 0, time.UTC)}
+	testCase := ReaperUnitTest{
+		TestDescription:         "Tests that PDBs are detected but not deleted outside the reap window",
+		FakeReaper:              reaper,
+		Mocks:                   _reapWindowMocks(),
+		ExpectedReapableBudgets: 1,
+		ExpectedReapedBudgets:   0,
+	}
+	testCase.Run(t)
+
+	_getPDB(t, reaper, "namespace-1", "pdb-1")
+}
+
+func TestAffectedOwnersMetric(t *testing.T) {
+	reaper := _fakeReaperContext()
+	metrics := &fakeMetricsAPI{}
+	reaper.MetricsAPI = metrics
+	reaper.OwnerLabel = "team"
+
+	pdbs := []MockPDB{
+		_mockPDB("pdb-1", "namespace-1", nil, &intStrZeroInt, _selector("app=app-1"), 1, 0),
+		_mockPDB("pdb-2", "namespace-1", nil, &intStrZeroInt, _selector("app=app-2"), 1, 0),
+		_mockPDB("pdb-3", "namespace-1", nil, &intStrZeroInt, _selector("app=app-3"), 1, 0),
+		_mockPDB("pdb-4", "namespace-1", nil, &intStrZeroInt, _selector("app=app-4"), 1, 0),
+		_mockPDB("pdb-5", "namespace-1", nil, &intStrOneInt, _selector("app=app-5"), 1, 1),
+	}
+	pdbs[0].Labels = map[string]string{"team": "payments"}
+	pdbs[1].Labels = map[string]string{"team": "payments"}
+	pdbs[2].Labels = map[string]string{"team": "search"}
+	pdbs[4].Labels = map[string]string{"team": "storage"}
+
+	testCase := ReaperUnitTest{
+		TestDescription: "Tests that the number of distinct owners among reaped PDBs is exposed",
+		FakeReaper:      reaper,
+		Mocks: KubernetesMockAPI{
+			Namespaces: []MockNamespace{
+				_mockNamespace("namespace-1"),
+			},
+			PDBs: pdbs,
+			Pods: []MockPod{
+				_mockPod("pod-1", "namespace-1", map[string]string{"app": "app-1"}, false, 0, false),
+				_mockPod("pod-2", "namespace-1", map[string]string{"app": "app-2"}, false, 0, false),
+				_mockPod("pod-3", "namespace-1", map[string]string{"app": "app-3"}, false, 0, false),
+				_mockPod("pod-4", "namespace-1", map[string]string{"app": "app-4"}, false, 0, false),
+				_mockPod("pod-5", "namespace-1", map[string]string{"app": "app-5"}, false, 0, false),
+			},
+		},
+		ExpectedReapableBudgets: 4,
+		ExpectedReapedBudgets:   4,
+	}
+	testCase.Run(t)
+
+	if value, ok := metrics.lastValue(PdbReaperAffectedOwnersMetricName, nil); !ok || value != 2 {
+		t.Fatalf("assertion failed, expected affected owners: 2, got: %v", value)
+	}
+}
+
+func TestAffectedOwnersPushgateway(t *testing.T) {
+	reaper := _fakeReaperContext()
+	pgw := _fakePushgateway(t)
+	reaper.MetricsAPI = common.NewPrometheusAPI(pgw.URL)
+	reaper.OwnerLabel = "team"
+
+	pdbs := []MockPDB{
+		_mockPDB("pdb-1", "namespace-1", nil, &intStrZeroInt, _selector("app=app-1"), 1, 0),
+		_mockPDB("pdb-2", "namespace-1", nil, &intStrZeroInt, _selector("app=app-2"), 1, 0),
+	}
+	pdbs[0].Labels = map[string]string{"team": "payments"}
+	pdbs[1].Labels = map[string]string{"team": "search"}
+
+	testCase := ReaperUnitTest{
+		TestDescription: "Tests that the affected owners metric is kept along the other cluster metrics on the pushgateway",
+		FakeReaper:      reaper,
+		Mocks: KubernetesMockAPI{
+			Namespaces: []MockNamespace{
+				_mockNamespace("namespace-1"),
+			},
+			PDBs: pdbs,
+			Pods: []MockPod{
+				_mockPod("pod-1", "namespace-1", map[string]string{"app": "app-1"}, false, 0, false),
+				_mockPod("pod-2", "namespace-1", map[string]string{"app": "app-2"}, false, 0, false),
+			},
+		},
+		ExpectedReapableBudgets: 2,
+		ExpectedReapedBudgets:   2,
+	}
+	testCase.Run(t)
+
+	pgw.assertPushed(t, nil, map[string]float64{
+		PdbReaperAffectedOwnersMetricName: 2,
+		PdbReaperReapableCountMetricName:  2,
+		PdbReaperReapedCountMetricName:    2,
+	})
+}
+
+func TestCleanupAnnotations(t *testing.T) {
+	reaper := _fakeReaperContext()
+	reaper.CleanupAnnotations = true
+
+	annotatedPDB := _mockPDB("pdb-1", "namespace-1", nil, &intStrZeroInt, _selector("app=app-1"), 1, 0)
+	annotatedPDB.Annotations = map[string]string{
+		WouldReapReasonAnnotationKey:    EventReasonBlockingDetected,
+		WouldReapTimestampAnnotationKey: "2024-01-01T00:00:00Z",
+		"pdb-reaper/first-violation":    "2024-01-01T00:00:00Z",
+		"example.com/owner":             "team-a",
+	}
+	unmanagedPDB := _mockPDB("pdb-2", "namespace-2", nil, &intStrOneInt, _selector("app=app-2"), 1, 1)
+	unmanagedPDB.Annotations = map[string]string{
+		"example.com/owner": "team-b",
+	}
+
+	testCase := ReaperUnitTest{
+		TestDescription: "Tests that managed annotations are stripped from all PDBs and no PDBs are reaped",
+		FakeReaper:      reaper,
+		Mocks: KubernetesMockAPI{
+			Namespaces: []MockNamespace{
+				_mockNamespace("namespace-1"),
+				_mockNamespace("namespace-2"),
+			},
+			PDBs: []MockPDB{
+				annotatedPDB,
+				unmanagedPDB,
+			},
+			Pods: []MockPod{
+				_mockPod("pod-1", "namespace-1", map[string]string{"app": "app-1"}, false, 0, false),
+			},
+		},
+		ExpectedReapableBudgets: 0,
+		ExpectedReapedBudgets:   0,
+	}
+	testCase.Run(t)
+
+	pdb := _getPDB(t, reaper, "namespace-1", "pdb-1")
+	for key := range pdb.Annotations {
+		if strings.HasPrefix(key, ManagedAnnotationPrefix) {
+			t.Fatalf("assertion failed, expected managed annotation %v to be removed", key)
+		}
+	}
+	if pdb.Annotations["example.com/owner"] != "team-a" {
+		t.Fatalf("assertion failed, expected unrelated annotations to be preserved, got: %v", pdb.Annotations)
+	}
+	pdb = _getPDB(t, reaper, "namespace-2", "pdb-2")
+	if pdb.Annotations["example.com/owner"] != "team-b" {
+		t.Fatalf("assertion failed, expected unrelated annotations to be preserved, got: %v", pdb.Annotations)
+	}
+}
+
+// slowPodsClientset delays pod lists in a namespace without holding the fake clientset lock, so other calls proceed
+type slowPodsClientset struct {
+	*fake.Clientset
+	slowNamespace string
+	delay         time.Duration
+}
+
+func (c *slowPodsClientset) CoreV1() typedcorev1.CoreV1Interface {
+	return &slowPodsCoreV1{CoreV1Interface: c.Clientset.CoreV1(), client: c}
+}
+
+type slowPodsCoreV1 struct {
+	typedcorev1.CoreV1Interface
+	client *slowPodsClientset
+}
+
+func (c *slowPodsCoreV1) Pods(namespace string) typedcorev1.PodInterface {
+	return &slowPods{PodInterface: c.CoreV1Interface.Pods(namespace), namespace: namespace, client: c.client}
+}
+
+type slowPods struct {
+	typedcorev1.PodInterface
+	namespace string
+	client    *slowPodsClientset
+}
+
+func (p *slowPods) List(ctx context.Context, opts metav1.ListOptions) (*corev1.PodList, error) {
+	if p.namespace == p.client.slowNamespace {
+		time.Sleep(p.client.delay)
+	}
+	return p.PodInterface.List(ctx, opts)
+}
+
+func TestPodDisruptionBudgetTimeout(t *testing.T) {
+	reaper := _fakeReaperContext()
+	reaper.PodDisruptionBudgetTimeout = 50 * time.Millisecond
+
+	reaper.KubernetesClient = &slowPodsClientset{
+		Clientset:     reaper.KubernetesClient.(*fake.Clientset),
+		slowNamespace: "namespace-slow",
+		delay:         time.Second,
+	}
+
+	testCase := ReaperUnitTest{
+		TestDescription: "Tests that a PDB whose pod list is slow is abandoned without blocking other PDBs",
+		FakeReaper:      reaper,
+		Mocks: KubernetesMockAPI{
+			Namespaces: []MockNamespace{
+				_mockNamespace("namespace-slow"),
+				_mockNamespace("namespace-1"),
+				_mockNamespace("namespace-2"),
+			},
+			PDBs: []MockPDB{
+				_mockPDB("pdb-slow", "namespace-slow", nil, &intStrZeroInt, _selector("app=app-1"), 1, 0),
+				_mockPDB("pdb-1", "namespace-1", nil, &intStrZeroInt, _selector("app=app-1"), 1, 0),
+				_mockPDB("pdb-2", "namespace-2", nil, &intStrZeroInt, _selector("app=app-2"), 1, 0),
+			},
+			Pods: []MockPod{
+				_mockPod("pod-slow", "namespace-slow", map[string]string{"app": "app-1"}, false, 0, false),
+				_mockPod("pod-1", "namespace-1", map[string]string{"app": "app-1"}, false, 0, false),
+				_mockPod("pod-2", "namespace-2", map[string]string{"app": "app-2"}, false, 0, false),
+			},
+		},
+		ExpectedReapableBudgets: 2,
+		ExpectedReapedBudgets:   2,
+	}
+
+	start := time.Now()
+	testCase.Run(t)
+	if elapsed := time.Since(start); elapsed >= time.Second {
+		t.Fatalf("assertion failed, expected slow PDB to be abandoned, run took: %v", elapsed)
+	}
+	_getPDB(t, reaper, "namespace-slow", "pdb-slow")
+}
+
+func TestPodDisruptionBudgetTimeoutRelists(t *testing.T) {
+	reaper := _fakeReaperContext()
+	reaper.ReapNotReadyThreshold = 30
+	reaper.AllNotReady = true
+	reaper.PodDisruptionBudgetTimeout = 50 * time.Millisecond
+	reaper.PodCountRetries = 5
+	reaper.PodCountRetryDelay = time.Second
+
+	testCase := ReaperUnitTest{
+		TestDescription: "Tests that the re-lists of a PDB are bounded by the timeout of its evaluation",
+		FakeReaper:      reaper,
+		Mocks: KubernetesMockAPI{
+			Namespaces: []MockNamespace{
+				_mockNamespace("namespace-1"),
+			},
+			PDBs: []MockPDB{
+				_mockPDB("pdb-1", "namespace-1", nil, &intStrOneInt, _selector("app=app-1"), 2, 0),
+			},
+			Pods: []MockPod{
+				_mockPod("pod-1", "namespace-1", map[string]string{"app": "app-1"}, false, 0, true),
+			},
+		},
+		ExpectedReapableBudgets: 0,
+		ExpectedReapedBudgets:   0,
+	}
+
+	start := time.Now()
+	testCase.Run(t)
+	if elapsed := time.Since(start); elapsed >= time.Second {
+		t.Fatalf("assertion failed, expected re-lists to be abandoned, run took: %v", elapsed)
+	}
+	_getPDB(t, reaper, "namespace-1", "pdb-1")
+}
+
+func _zeroExpectedPodsMocks() KubernetesMockAPI {
+	return KubernetesMockAPI{
+		Namespaces: []MockNamespace{
+			_mockNamespace("namespace-1"),
+		},
+		PDBs: []MockPDB{
+			_mockPDB("pdb-1", "namespace-1", &intStrOneInt, nil, _selector("app=app-1"), 0, 0),
+			_mockPDB("pdb-2", "namespace-1", &intStrOneInt, nil, _selector("app=app-2"), 0, 0),
+		},
+		Pods: []MockPod{
+			_mockPod("pod-1", "namespace-1", map[string]string{"app": "app-1"}, false, 0, false),
+		},
+	}
+}
+
+func TestZeroExpectedPodsSkipped(t *testing.T) {
+	reaper := _fakeReaperContext()
+	reaper.ReapMultiple = false
+	testCase := ReaperUnitTest{
+		TestDescription:         "Tests that PDBs expecting 0 pods are skipped by default even if their selector matches pods",
+		FakeReaper:              reaper,
+		Mocks:                   _zeroExpectedPodsMocks(),
+		ExpectedReapableBudgets: 0,
+		ExpectedReapedBudgets:   0,
+	}
+	testCase.Run(t)
+}
+
+func TestZeroExpectedPodsEvaluated(t *testing.T) {
+	reaper := _fakeReaperContext()
+	metrics := &fakeMetricsAPI{}
+	reaper.MetricsAPI = metrics
+	reaper.ReapMultiple = false
+	reaper.EvaluateZeroExpectedPods = true
+	testCase := ReaperUnitTest{
+		TestDescription:         "Tests that PDBs expecting 0 pods are evaluated using live pods when enabled",
+		FakeReaper:              reaper,
+		Mocks:                   _zeroExpectedPodsMocks(),
+		ExpectedReapableBudgets: 1,
+		ExpectedReapedBudgets:   1,
+	}
+	testCase.Run(t)
+
+	if value, ok := metrics.lastValue(PdbReaperZeroExpectedPodsMetricName, map[string]string{"pdb": "pdb-1"}); !ok || value != 1 {
+		t.Fatalf("assertion failed, expected zero expected pods metric value 1, got: %v", value)
+	}
+	if _, ok := metrics.lastValue(PdbReaperZeroExpectedPodsMetricName, map[string]string{"pdb": "pdb-2"}); ok {
+		t.Fatalf("assertion failed, expected no zero expected pods metric for pdb-2 which matches no pods")
+	}
+	_getPDB(t, reaper, "namespace-1", "pdb-2")
+}
+
+func TestZeroExpectedPodsPushgateway(t *testing.T) {
+	reaper := _fakeReaperContext()
+	pgw := _fakePushgateway(t)
+	reaper.MetricsAPI = common.NewPrometheusAPI(pgw.URL)
+	reaper.ReapMultiple = false
+	reaper.EvaluateZeroExpectedPods = true
+	testCase := ReaperUnitTest{
+		TestDescription:         "Tests that the zero expected pods metric is kept along the other metrics of the PDB on the pushgateway",
+		FakeReaper:              reaper,
+		Mocks:                   _zeroExpectedPodsMocks(),
+		ExpectedReapableBudgets: 1,
+		ExpectedReapedBudgets:   1,
+	}
+	testCase.Run(t)
+
+	pgw.assertPushed(t, map[string]string{"namespace": "namespace-1", "pdb": "pdb-1"}, map[string]float64{
+		PdbReaperZeroExpectedPodsMetricName: 1,
+		PdbReaperMatchedPodsMetricName:      1,
+	})
+}
+
+func TestResolvedBudgetMetric(t *testing.T) {
+	reaper := _fakeReaperContext()
+	metrics := &fakeMetricsAPI{}
+	reaper.MetricsAPI = metrics
+	reaper.DryRun = true
+
+	percent := intstr.FromString("30%")
+	testCase := ReaperUnitTest{
+		TestDescription: "Tests that the resolved budget value of a percentage-based blocking PDB is exposed",
+		FakeReaper:      reaper,
+		Mocks: KubernetesMockAPI{
+			Namespaces: []MockNamespace{
+				_mockNamespace("namespace-1"),
+				_mockNamespace("namespace-2"),
+			},
+			PDBs: []MockPDB{
+				_mockPDB("pdb-1", "namespace-1", &percent, nil, _selector("app=app-1"), 5, 0),
+				_mockPDB("pdb-2", "namespace-2", nil, &intStrZeroPercent, _selector("app=app-2"), 5, 0),
+			},
+			Pods: []MockPod{
+				_mockPod("pod-1", "namespace-1", map[string]string{"app": "app-1"}, false, 0, false),
+				_mockPod("pod-2", "namespace-2", map[string]string{"app": "app-2"}, false, 0, false),
+			},
+		},
+		ExpectedReapableBudgets: 1,
+		ExpectedReapedBudgets:   0,
+	}
+	testCase.Run(t)
+
+	// 30% of 5 expected pods rounds up to 2
+	if value, ok := metrics.lastValue(PdbReaperResolvedBudgetMetricName, map[string]string{"pdb": "pdb-1", "type": BudgetTypeMinAvailable}); !ok || value != 2 {
+		t.Fatalf("assertion failed, expected resolved minAvailable 2, got: %v", value)
+	}
+	if _, ok := metrics.lastValue(PdbReaperResolvedBudgetMetricName, map[string]string{"pdb": "pdb-1", "type": BudgetTypeMaxUnavailable}); ok {
+		t.Fatalf("assertion failed, expected no resolved maxUnavailable for pdb-1")
+	}
+	if value, ok := metrics.lastValue(PdbReaperResolvedBudgetMetricName, map[string]string{"pdb": "pdb-2", "type": BudgetTypeMaxUnavailable}); !ok || value != 0 {
+		t.Fatalf("assertion failed, expected resolved maxUnavailable 0, got: %v", value)
+	}
+}
+
+func TestExcludedPodDisruptionBudgetNames(t *testing.T) {
+	reaper := _fakeReaperContext()
+	reaper.ExcludedPodDisruptionBudgets = []string{"namespace-1/pdb-1", "pdb-protected"}
+	testCase := ReaperUnitTest{
+		TestDescription: "Tests that PDBs excluded by qualified or bare name are skipped",
+		FakeReaper:      reaper,
+		Mocks: KubernetesMockAPI{
+			Namespaces: []MockNamespace{
+				_mockNamespace("namespace-1"),
+				_mockNamespace("namespace-2"),
+				_mockNamespace("namespace-3"),
+			},
+			PDBs: []MockPDB{
+				_mockPDB("pdb-1", "namespace-1", nil, &intStrZeroInt, _selector("app=app-1"), 1, 0),
+				_mockPDB("pdb-1", "namespace-2", nil, &intStrZeroInt, _selector("app=app-1"), 1, 0),
+				_mockPDB("pdb-protected", "namespace-2", nil, &intStrZeroInt, _selector("app=app-2"), 1, 0),
+				_mockPDB("pdb-protected", "namespace-3", nil, &intStrZeroInt, _selector("app=app-3"), 1, 0),
+			},
+			Pods: []MockPod{
+				_mockPod("pod-1", "namespace-1", map[string]string{"app": "app-1"}, false, 0, false),
+				_mockPod("pod-1", "namespace-2", map[string]string{"app": "app-1"}, false, 0, false),
+				_mockPod("pod-2", "namespace-2", map[string]string{"app": "app-2"}, false, 0, false),
+				_mockPod("pod-3", "namespace-3", map[string]string{"app": "app-3"}, false, 0, false),
+			},
+		},
+		ExpectedReapableBudgets: 1,
+		ExpectedReapedBudgets:   1,
+	}
+	testCase.Run(t)
+
+	if reaper.ScannedPodDisruptionBudgetsCount != 1 {
+		t.Fatalf("assertion failed, expected scanned: 1, got: %v", reaper.ScannedPodDisruptionBudgetsCount)
+	}
+	_getPDB(t, reaper, "namespace-1", "pdb-1")
+	_getPDB(t, reaper, "namespace-2", "pdb-protected")
+	_getPDB(t, reaper, "namespace-3", "pdb-protected")
+}
+
+func TestProgressHeartbeats(t *testing.T) {
+	reaper := _fakeReaperContext()
+	var output bytes.Buffer
+	previousOut := log.Out
+	log.Out = &output
+	defer func() {
+		log.Out = previousOut
+	}()
+	reaper.ReapMultiple = false
+	reaper.ProgressEveryNamespaces = 2
+
+	mocks := KubernetesMockAPI{}
+	for i := 1; i <= 5; i++ {
+		namespace := fmt.Sprintf("namespace-%v", i)
+		mocks.Namespaces = append(mocks.Namespaces, _mockNamespace(namespace))
+		mocks.PDBs = append(mocks.PDBs, _mockPDB("pdb-1", namespace, nil, &intStrOneInt, _selector("app=app-1"), 1, 0))
+		mocks.Pods = append(mocks.Pods, _mockPod("pod-1", namespace, map[string]string{"app": "app-1"}, false, 0, false))
+	}
+
+	testCase := ReaperUnitTest{
+		TestDescription:         "Tests that progress is logged every N namespaces",
+		FakeReaper:              reaper,
+		Mocks:                   mocks,
+		ExpectedReapableBudgets: 0,
+		ExpectedReapedBudgets:   0,
+	}
+	testCase.Run(t)
+
+	var heartbeats []string
+	for _, line := range strings.Split(output.String(), "\n") {
+		if strings.Contains(line, "progress: blocking PDB detection") {
+			heartbeats = append(heartbeats, line)
+		}
+	}
+	if len(heartbeats) != 2 {
+		t.Fatalf("assertion failed, expected 2 progress logs, got: %v", heartbeats)
+	}
+	if !strings.Contains(heartbeats[0], "namespace 2/5") || !strings.Contains(heartbeats[1], "namespace 4/5") {
+		t.Fatalf("assertion failed, unexpected progress logs: %v", heartbeats)
+	}
+}
+
+func TestProgressHeartbeatsDisabled(t *testing.T) {
+	reaper := _fakeReaperContext()
+	var output bytes.Buffer
+	previousOut := log.Out
+	log.Out = &output
+	defer func() {
+		log.Out = previousOut
+	}()
+	testCase := ReaperUnitTest{
+		TestDescription:         "Tests that progress is not logged when disabled",
+		FakeReaper:              reaper,
+		Mocks:                   _reapWindowMocks(),
+		ExpectedReapableBudgets: 1,
+		ExpectedReapedBudgets:   1,
+	}
+	testCase.Run(t)
+
+	if strings.Contains(output.String(), "progress:") {
+		t.Fatalf("assertion failed, expected no progress logs")
+	}
+}
+
+func _reapOnlyIfPodsMatchMocks() KubernetesMockAPI {
+	return KubernetesMockAPI{
+		Namespaces: []MockNamespace{
+			_mockNamespace("namespace-1"),
+			_mockNamespace("namespace-2"),
+		},
+		PDBs: []MockPDB{
+			_mockPDB("pdb-dormant", "namespace-1", nil, &intStrZeroInt, _selector("app=app-1"), 1, 0),
+			_mockPDB("pdb-active", "namespace-2", nil, &intStrZeroInt, _selector("app=app-2"), 1, 0),
+		},
+		Pods: []MockPod{
+			_mockPod("pod-2", "namespace-2", map[string]string{"app": "app-2"}, false, 0, false),
+		},
+	}
+}
+
+func TestReapOnlyIfPodsMatch(t *testing.T) {
+	reaper := _fakeReaperContext()
+	reaper.ReapOnlyIfPodsMatch = true
+	testCase := ReaperUnitTest{
+		TestDescription:         "Tests that a misconfigured PDB matching no pods is spared when pods are required to match",
+		FakeReaper:              reaper,
+		Mocks:                   _reapOnlyIfPodsMatchMocks(),
+		ExpectedReapableBudgets: 1,
+		ExpectedReapedBudgets:   1,
+	}
+	testCase.Run(t)
+
+	_getPDB(t, reaper, "namespace-1", "pdb-dormant")
+}
+
+func TestReapOnlyIfPodsMatchDisabled(t *testing.T) {
+	reaper := _fakeReaperContext()
+	testCase := ReaperUnitTest{
+		TestDescription:         "Tests that a misconfigured PDB matching no pods is reaped by default",
+		FakeReaper:              reaper,
+		Mocks:                   _reapOnlyIfPodsMatchMocks(),
+		ExpectedReapableBudgets: 2,
+		ExpectedReapedBudgets:   2,
+	}
+	testCase.Run(t)
+}
+
+func _notReadySinceMocks(notReadySince time.Time) KubernetesMockAPI {
+	pod := _mockPod("pod-1", "namespace-1", map[string]string{"app": "app-1"}, false, 0, false)
+	pod.Phase = corev1.PodRunning
+	pod.Conditions = []corev1.PodCondition{
+		{
+			Type:               corev1.ContainersReady,
+			Status:             corev1.ConditionFalse,
+			LastTransitionTime: metav1.Time{Time: notReadySince},
+		},
+	}
+	return KubernetesMockAPI{
+		Namespaces: []MockNamespace{
+			_mockNamespace("namespace-1"),
+		},
+		PDBs: []MockPDB{
+			_mockPDB("pdb-1", "namespace-1", nil, &intStrOneInt, _selector("app=app-1"), 1, 0),
+		},
+		Pods: []MockPod{
+			pod,
+		},
+	}
+}
+
+func TestNotReadyThresholdFakeClock(t *testing.T) {
+	notReadySince := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name             string
+		now              time.Time
+		expectedReapable int
+	}{
+		{"BeforeThreshold", notReadySince.Add(599 * time.Second), 0},
+		{"AtThreshold", notReadySince.Add(600 * time.Second), 1},
+		{"AfterThreshold", notReadySince.Add(601 * time.Second), 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reaper := _fakeReaperContext()
+			reaper.ReapNotReadyThreshold = 600
+			reaper.Clock = fakeClock{tt.now}
+			testCase := ReaperUnitTest{
+				TestDescription:         "Tests the not-ready threshold boundary using a fake clock",
+				FakeReaper:              reaper,
+				Mocks:                   _notReadySinceMocks(notReadySince),
+				ExpectedReapableBudgets: tt.expectedReapable,
+				ExpectedReapedBudgets:   tt.expectedReapable,
+			}
+			testCase.Run(t)
+		})
+	}
+}
+
+func TestReapCooldownFakeClock(t *testing.T) {
+	reapedAt := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name           string
+		now            time.Time
+		expectedReaped int
+	}{
+		{"WithinCooldown", reapedAt.Add(time.Hour - time.Second), 0},
+		{"CooldownExpired", reapedAt.Add(time.Hour), 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reaper := _fakeReaperContext()
+			reaper.ReapCooldown = time.Hour
+			reaper.State.ReapedAt = map[string]time.Time{"namespace-1/pdb-1": reapedAt}
+			reaper.Clock = fakeClock{tt.now}
+			testCase := ReaperUnitTest{
+				TestDescription:         "Tests the reap cooldown boundary using a fake clock",
+				FakeReaper:              reaper,
+				Mocks:                   _reapWindowMocks(),
+				ExpectedReapableBudgets: 1,
+				ExpectedReapedBudgets:   tt.expectedReaped,
+			}
+			testCase.Run(t)
+		})
+	}
+}
+
+func _drainBlockingMocks() KubernetesMockAPI {
+	onCordonedNode := _mockPod("pod-1", "namespace-1", map[string]string{"app": "app-1"}, false, 0, false)
+	onCordonedNode.NodeName = "node-cordoned"
+	onTaintedNode := _mockPod("pod-2", "namespace-2", map[string]string{"app": "app-2"}, false, 0, false)
+	onTaintedNode.NodeName = "node-tainted"
+	onHealthyNode := _mockPod("pod-3", "namespace-3", map[string]string{"app": "app-3"}, false, 0, false)
+	onHealthyNode.NodeName = "node-healthy"
+	return KubernetesMockAPI{
+		Namespaces: []MockNamespace{
+			_mockNamespace("namespace-1"),
+			_mockNamespace("namespace-2"),
+			_mockNamespace("namespace-3"),
+		},
+		Nodes: []MockNode{
+			{Name: "node-cordoned", Unschedulable: true},
+			{Name: "node-tainted", Taints: []corev1.Taint{{Key: corev1.TaintNodeUnschedulable, Effect: corev1.TaintEffectNoSchedule}}},
+			{Name: "node-healthy"},
+		},
+		PDBs: []MockPDB{
+			// blocking but correctly configured, only reapable due to the drain
+			_mockPDB("pdb-1", "namespace-1", &intStrOneInt, nil, _selector("app=app-1"), 2, 0),
+			_mockPDB("pdb-2", "namespace-2", &intStrOneInt, nil, _selector("app=app-2"), 2, 0),
+			// misconfigured, but its pods are on a healthy node
+			_mockPDB("pdb-3", "namespace-3", nil, &intStrZeroInt, _selector("app=app-3"), 1, 0),
+		},
+		Pods: []MockPod{
+			onCordonedNode,
+			onTaintedNode,
+			onHealthyNode,
+		},
+	}
+}
+
+func TestReapDrainBlocking(t *testing.T) {
+	reaper := _fakeReaperContext()
+	reaper.ReapNotReady = false
+	reaper.ReapDrainBlocking = true
+	testCase := ReaperUnitTest{
+		TestDescription:         "Blocking PDBs with pods on cordoned nodes are reapable, along with misconfigured PDBs",
+		FakeReaper:              reaper,
+		Mocks:                   _drainBlockingMocks(),
+		ExpectedReapableBudgets: 3,
+		ExpectedReapedBudgets:   3,
+	}
+	testCase.Run(t)
+
+	for _, name := range []string{"namespace-1/pdb-1", "namespace-2/pdb-2"} {
+		if reasons := reaper.ReapableReasons[name]; !containsReason(reasons, ReasonBlockingNodeDrain) {
+			t.Fatalf("expected PDB %v to be reapable due to node drain, got reasons %v", name, reasons)
+		}
+	}
+	if reasons := reaper.ReapableReasons["namespace-3/pdb-3"]; containsReason(reasons, ReasonBlockingNodeDrain) {
+		t.Fatalf("expected PDB namespace-3/pdb-3 on a healthy node to not be reapable due to node drain")
+	}
+}
+
+func TestDrainBlockingOnly(t *testing.T) {
+	reaper := _fakeReaperContext()
+	reaper.ReapNotReady = false
+	reaper.ReapDrainBlocking = true
+	reaper.DrainBlockingOnly = true
+	testCase := ReaperUnitTest{
+		TestDescription:         "Only PDBs with pods on cordoned nodes are reaped, the misconfigured PDB on a healthy node is spared",
+		FakeReaper:              reaper,
+		Mocks:                   _drainBlockingMocks(),
+		ExpectedReapableBudgets: 2,
+		ExpectedReapedBudgets:   2,
+	}
+	testCase.Run(t)
+
+	if _, ok := reaper.ReapableReasons["namespace-3/pdb-3"]; ok {
+		t.Fatalf("expected PDB namespace-3/pdb-3 on a healthy node to be spared")
+	}
+}
+
+func TestDrainBlockingDisabled(t *testing.T) {
+	reaper := _fakeReaperContext()
+	reaper.ReapNotReady = false
+	testCase := ReaperUnitTest{
+		TestDescription:         "Correctly configured blocking PDBs are not reapable without --reap-drain-blocking",
+		FakeReaper:              reaper,
+		Mocks:                   _drainBlockingMocks(),
+		ExpectedReapableBudgets: 1,
+		ExpectedReapedBudgets:   1,
+	}
+	testCase.Run(t)
+}
+
+func TestReportWebhookOnError(t *testing.T) {
+	reports := make([]ErrorReport, 0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var report ErrorReport
+		if err := json.NewDecoder(r.Body).Decode(&report); err != nil {
+			t.Errorf("failed to decode error report: %v", err)
+		}
+		reports = append(reports, report)
+	}))
+	defer server.Close()
+
+	reaper := _fakeReaperContext()
+	reaper.ErrorWebhookURL = server.URL
+	reaper.Clock = fakeClock{time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)}
+	client := reaper.KubernetesClient.(*fake.Clientset)
+	client.PrependReactor("delete", "poddisruptionbudgets", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, fmt.Errorf("connection refused")
+	})
+
+	testCase := ReaperUnitTest{
+		Mocks: KubernetesMockAPI{
+			Namespaces: []MockNamespace{
+				_mockNamespace("namespace-1"),
+			},
+			PDBs: []MockPDB{
+				_mockPDB("pdb-1", "namespace-1", nil, &intStrZeroInt, _selector("app=app-1"), 1, 0),
+				_mockPDB("pdb-2", "namespace-1", nil, &intStrOneInt, _selector("app=app-2"), 1, 1),
+			},
+		},
+		FakeReaper: reaper,
+	}
+	_fakeAPI(&testCase)
+
+	err := reaper.execute()
+	if err == nil {
+		t.Fatalf("assertion failed, expected execution to fail")
+	}
+
+	if len(reports) != 1 {
+		t.Fatalf("assertion failed, expected 1 error report, got: %v", len(reports))
+	}
+	report := reports[0]
+	if report.Error != err.Error() {
+		t.Fatalf("assertion failed, expected error %q, got: %q", err.Error(), report.Error)
+	}
+	expectedChain := []string{
+		"failed to reap PDBs",
+		"failed to handle reapable PDBs",
+		"failed to delete offending PDB namespace-1/pdb-1",
+		"connection refused",
+	}
+	if strings.Join(report.Chain, "|") != strings.Join(expectedChain, "|") {
+		t.Fatalf("assertion failed, expected chain %v, got: %v", expectedChain, report.Chain)
+	}
+	if report.Code != ErrorCodeReapFailed {
+		t.Fatalf("assertion failed, expected code %v, got: %v", ErrorCodeReapFailed, report.Code)
+	}
+	if report.Scanned != 2 || report.Reapable != 1 || report.Reaped != 0 {
+		t.Fatalf("assertion failed, expected partial counts scanned=2 reapable=1 reaped=0, got: %+v", report)
+	}
+	if !report.Timestamp.Equal(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)) {
+		t.Fatalf("assertion failed, expected timestamp from the clock, got: %v", report.Timestamp)
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	mocks := KubernetesMockAPI{
+		Namespaces: []MockNamespace{
+			_mockNamespace("namespace-1"),
+		},
+		PDBs: []MockPDB{
+			_mockPDB("pdb-1", "namespace-1", nil, &intStrZeroInt, _selector("app=app-1"), 1, 0),
+		},
+	}
+	failing := func(verb string) k8stesting.ReactionFunc {
+		return func(action k8stesting.Action) (bool, runtime.Object, error) {
+			if action.GetVerb() == verb {
+				return true, nil, fmt.Errorf("connection refused")
+			}
+			return false, nil, nil
+		}
+	}
+
+	tests := []struct {
+		verb     string
+		expected *RunError
+		other    *RunError
+	}{
+		{"list", ErrScanFailed, ErrReapFailed},
+		{"delete", ErrReapFailed, ErrScanFailed},
+	}
+	for _, tt := range tests {
+		reaper := _fakeReaperContext()
+		_fakeAPI(&ReaperUnitTest{FakeReaper: reaper, Mocks: mocks})
+		client := reaper.KubernetesClient.(*fake.Clientset)
+		client.PrependReactor("*", "poddisruptionbudgets", failing(tt.verb))
+
+		err := errors.Wrap(reaper.execute(), "execution failed")
+		if !errors.Is(err, tt.expected) || errors.Is(err, tt.other) || errors.Is(err, ErrInvalidConfig) {
+			t.Fatalf("assertion failed, expected a failed %v to only match %v, got: %v", tt.verb, tt.expected, err)
+		}
+		var runErr *RunError
+		if !errors.As(err, &runErr) || runErr.Code != tt.expected.Code {
+			t.Fatalf("assertion failed, expected a failed %v to have code %v, got: %v", tt.verb, tt.expected.Code, runErr)
+		}
+		if !strings.Contains(err.Error(), "connection refused") {
+			t.Fatalf("assertion failed, expected the cause to be kept, got: %v", err)
+		}
+	}
+
+	err := Run(&Args{ReapNotReadyThreshold: -1})
+	if !errors.Is(err, ErrInvalidConfig) || errors.Is(err, ErrScanFailed) {
+		t.Fatalf("assertion failed, expected invalid arguments to match %v, got: %v", ErrInvalidConfig, err)
+	}
+	if code := errorCode(err); code != ErrorCodeInvalidConfig {
+		t.Fatalf("assertion failed, expected code %v, got: %v", ErrorCodeInvalidConfig, code)
+	}
+}
+
+func TestReportWebhookOnErrorSuccess(t *testing.T) {
+	called := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer server.Close()
+
+	reaper := _fakeReaperContext()
+	reaper.ErrorWebhookURL = server.URL
+	testCase := ReaperUnitTest{
+		TestDescription:         "The error webhook is not called when the run succeeds",
+		FakeReaper:              reaper,
+		Mocks:                   _reapWindowMocks(),
+		ExpectedReapableBudgets: 1,
+		ExpectedReapedBudgets:   1,
+	}
+	testCase.Run(t)
+
+	if called {
+		t.Fatalf("assertion failed, expected the error webhook to not be called")
+	}
+}
+
+func _duplicateSelectorMocks() KubernetesMockAPI {
+	return KubernetesMockAPI{
+		Namespaces: []MockNamespace{
+			_mockNamespace("namespace-1"),
+		},
+		PDBs: []MockPDB{
+			_mockPDB("pdb-1-abcde", "namespace-1", nil, &intStrOneInt, _selector("app=app-1,tier=web"), 2, 1),
+			_mockPDB("pdb-1-fghij", "namespace-1", nil, &intStrOneInt, _selector("tier=web,app=app-1"), 2, 1),
+			_mockPDB("pdb-2", "namespace-1", nil, &intStrOneInt, _selector("app=app-1"), 2, 1),
+		},
+	}
+}
+
+func TestReapDuplicateSelector(t *testing.T) {
+	reaper := _fakeReaperContext()
+	reaper.ReapMultiple = false
+	reaper.ReapDuplicateSelector = true
+	testCase := ReaperUnitTest{
+		TestDescription:         "PDBs sharing an identical selector are reapable even when no pods match",
+		FakeReaper:              reaper,
+		Mocks:                   _duplicateSelectorMocks(),
+		ExpectedReapableBudgets: 2,
+		ExpectedReapedBudgets:   2,
+	}
+	testCase.Run(t)
+
+	for _, name := range []string{"namespace-1/pdb-1-abcde", "namespace-1/pdb-1-fghij"} {
+		if reasons := reaper.ReapableReasons[name]; !containsReason(reasons, ReasonDuplicateSelector) {
+			t.Fatalf("expected PDB %v to be reapable due to duplicate selector, got reasons %v", name, reasons)
+		}
+	}
+	if _, ok := reaper.ReapableReasons["namespace-1/pdb-2"]; ok {
+		t.Fatalf("expected PDB namespace-1/pdb-2 with a distinct selector to not be reapable")
+	}
+}
+
+func TestReapDuplicateSelectorPrimaryReason(t *testing.T) {
+	reaper := _fakeReaperContext()
+	reaper.ReapDuplicateSelector = true
+	mocks := _duplicateSelectorMocks()
+	mocks.Pods = []MockPod{
+		_mockPod("pod-1", "namespace-1", map[string]string{"app": "app-1", "tier": "web"}, false, 0, false),
+	}
+	testCase := ReaperUnitTest{
+		TestDescription:         "PDBs sharing an identical selector which are also multiple are attributed to the duplicate selector",
+		FakeReaper:              reaper,
+		Mocks:                   mocks,
+		ExpectedReapableBudgets: 3,
+		ExpectedReapedBudgets:   3,
+	}
+	testCase.Run(t)
+
+	pdb := policyv1.PodDisruptionBudget{ObjectMeta: metav1.ObjectMeta{Name: "pdb-1-abcde", Namespace: "namespace-1"}}
+	if reason := reaper.primaryReason(pdb); reason != ReasonDuplicateSelector {
+		t.Fatalf("expected primary reason %v, got %v", ReasonDuplicateSelector, reason)
+	}
+	pdb.Name = "pdb-2"
+	if reason := reaper.primaryReason(pdb); reason != ReasonMultiple {
+		t.Fatalf("expected primary reason %v, got %v", ReasonMultiple, reason)
+	}
+}
+
+func TestMaxAgeToConsider(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	legacy := _mockPDB("pdb-legacy", "namespace-1", nil, &intStrZeroInt, _selector("app=app-1"), 1, 0)
+	legacy.CreationTimestamp = metav1.Time{Time: now.AddDate(-3, 0, 0)}
+	recent := _mockPDB("pdb-recent", "namespace-2", nil, &intStrZeroInt, _selector("app=app-2"), 1, 0)
+	recent.CreationTimestamp = metav1.Time{Time: now.Add(-24 * time.Hour)}
+
+	reaper := _fakeReaperContext()
+	reaper.MaxAgeToConsider = 365 * 24 * time.Hour
+	reaper.Clock = fakeClock{now}
+	testCase := ReaperUnitTest{
+		TestDescription: "PDBs older than --max-age-to-consider are ignored, recent misconfigured PDBs are reaped",
+		FakeReaper:      reaper,
+		Mocks: KubernetesMockAPI{
+			Namespaces: []MockNamespace{
+				_mockNamespace("namespace-1"),
+				_mockNamespace("namespace-2"),
+			},
+			PDBs: []MockPDB{
+				legacy,
+				recent,
+			},
+		},
+		ExpectedReapableBudgets: 1,
+		ExpectedReapedBudgets:   1,
+	}
+	testCase.Run(t)
+
+	if reaper.ScannedPodDisruptionBudgetsCount != 1 {
+		t.Fatalf("assertion failed, expected 1 scanned PDB, got: %v", reaper.ScannedPodDisruptionBudgetsCount)
+	}
+	if _, err := reaper.KubernetesClient.PolicyV1().PodDisruptionBudgets("namespace-1").Get(context.Background(), "pdb-legacy", metav1.GetOptions{}); err != nil {
+		t.Fatalf("assertion failed, expected pdb-legacy to remain: %v", err)
+	}
+}
+
+func TestFixManifestsDir(t *testing.T) {
+	dir := t.TempDir()
+	reaper := _fakeReaperContext()
+	reaper.FixManifestsDir = dir
+	testCase := ReaperUnitTest{
+		TestDescription: "Fixed manifests are written for misconfigured PDBs, and no PDBs are deleted",
+		FakeReaper:      reaper,
+		Mocks: KubernetesMockAPI{
+			Namespaces: []MockNamespace{
+				_mockNamespace("namespace-1"),
+			},
+			PDBs: []MockPDB{
+				_mockPDB("pdb-1", "namespace-1", &intStrOneInt, &intStrZeroInt, _selector("app=app-1"), 1, 0),
+			},
+		},
+		ExpectedReapableBudgets: 1,
+		ExpectedReapedBudgets:   0,
+	}
+	testCase.Run(t)
+
+	data, err := os.ReadFile(filepath.Join(dir, "namespace-1.pdb-1.yaml"))
+	if err != nil {
+		t.Fatalf("failed to read fixed manifest: %v", err)
+	}
+
+	var fixed policyv1.PodDisruptionBudget
+	if err := yaml.UnmarshalStrict(data, &fixed); err != nil {
+		t.Fatalf("failed to parse fixed manifest: %v", err)
+	}
+	if fixed.Kind != "PodDisruptionBudget" || fixed.APIVersion != "policy/v1" || fixed.Name != "pdb-1" || fixed.Namespace != "namespace-1" {
+		t.Fatalf("assertion failed, unexpected manifest identity: %+v", fixed.TypeMeta)
+	}
+	if fixed.Spec.MinAvailable != nil {
+		t.Fatalf("assertion failed, expected minAvailable to be removed, got: %v", fixed.Spec.MinAvailable)
+	}
+	if fixed.Spec.MaxUnavailable == nil || fixed.Spec.MaxUnavailable.IntValue() != 1 {
+		t.Fatalf("assertion failed, expected maxUnavailable 1, got: %v", fixed.Spec.MaxUnavailable)
+	}
+	if fixed.Spec.Selector == nil || fixed.Spec.Selector.MatchLabels["app"] != "app-1" {
+		t.Fatalf("assertion failed, expected the selector to be kept, got: %v", fixed.Spec.Selector)
+	}
+
+	if _, err := reaper.KubernetesClient.PolicyV1().PodDisruptionBudgets("namespace-1").Get(context.Background(), "pdb-1", metav1.GetOptions{}); err != nil {
+		t.Fatalf("assertion failed, expected pdb-1 to remain: %v", err)
+	}
+	events, err := reaper.KubernetesClient.CoreV1().Events("namespace-1").List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("failed to list events: %v", err)
+	}
+	if len(events.Items) != 0 {
+		t.Fatalf("assertion failed, expected no events to be published, got: %v", len(events.Items))
+	}
+}
+
+// blockingMetricsAPI is a ContextMetricsAPI whose pushes only return once their context is done
+type blockingMetricsAPI struct {
+	fakeMetricsAPI
+}
+
+func (m *blockingMetricsAPI) SetMetricValueContext(ctx context.Context, metricName string, tags map[string]string, value float64) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestCanceledRunContext(t *testing.T) {
+	reaper := _fakeReaperContext()
+	metrics := &fakeMetricsAPI{}
+	reaper.MetricsAPI = metrics
+	runCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+	reaper.runCtx = runCtx
+
+	pdb := policyv1.PodDisruptionBudget{ObjectMeta: metav1.ObjectMeta{Name: "pdb-1", Namespace: "namespace-1"}}
+	if err := reaper.publishEvent(pdb, ReasonBlocking, EventMessageBlockingFmt); !errors.Is(err, context.Canceled) {
+		t.Fatalf("assertion failed, expected publishEvent to fail with context canceled, got: %v", err)
+	}
+	events, err := reaper.KubernetesClient.CoreV1().Events("namespace-1").List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("failed to list events: %v", err)
+	}
+	if len(events.Items) != 0 {
+		t.Fatalf("assertion failed, expected no events to be published, got: %v", len(events.Items))
+	}
+
+	if err := reaper.exposeMetric(pdb, ReasonBlocking, 1); !errors.Is(err, context.Canceled) {
+		t.Fatalf("assertion failed, expected exposeMetric to fail with context canceled, got: %v", err)
+	}
+	if err := reaper.exposeClusterMetric(PdbReaperAffectedOwnersMetricName, 1); !errors.Is(err, context.Canceled) {
+		t.Fatalf("assertion failed, expected exposeClusterMetric to fail with context canceled, got: %v", err)
+	}
+	if len(metrics.Metrics) != 0 {
+		t.Fatalf("assertion failed, expected no metrics to be pushed, got: %+v", metrics.Metrics)
+	}
+}
+
+func TestCanceledRunContextInFlightPush(t *testing.T) {
+	reaper := _fakeReaperContext()
+	metrics := &blockingMetricsAPI{}
+	reaper.MetricsAPI = metrics
+	runCtx, cancel := context.WithCancel(context.Background())
+	reaper.runCtx = runCtx
+
+	done := make(chan error, 1)
+	go func() {
+		pdb := policyv1.PodDisruptionBudget{ObjectMeta: metav1.ObjectMeta{Name: "pdb-1", Namespace: "namespace-1"}}
+		done <- reaper.exposeMetric(pdb, ReasonBlocking, 1)
+	}()
+	cancel()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("assertion failed, expected the push to fail with context canceled, got: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("assertion failed, expected the push to return once the run context is canceled")
+	}
+	if len(metrics.Metrics) != 0 {
+		t.Fatalf("assertion failed, expected no metrics to be pushed, got: %+v", metrics.Metrics)
+	}
+}
+
+func TestProbePodLogs(t *testing.T) {
+	reaper := _fakeReaperContext()
+	reaper.ReapMisconfigured = false
+	reaper.ReapNotReady = false
+	reaper.ProbePodLogs = true
+	reaper.PodLogsLines = 10
+	// the fake clientset returns "fake logs" for any pod
+	reaper.PodLogsMaxBytes = 7
+	testCase := ReaperUnitTest{
+		TestDescription: "The last logs of a crashlooping container are included in the crashloop event, truncated",
+		FakeReaper:      reaper,
+		Mocks: KubernetesMockAPI{
+			Namespaces: []MockNamespace{
+				_mockNamespace("namespace-1"),
+			},
+			PDBs: []MockPDB{
+				_mockPDB("pdb-1", "namespace-1", &intStrOneInt, nil, _selector("app=app-1"), 2, 0),
+			},
+			Pods: []MockPod{
+				_mockPod("pod-1", "namespace-1", map[string]string{"app": "app-1"}, true, 5, false),
+			},
+		},
+		ExpectedReapableBudgets: 1,
+		ExpectedReapedBudgets:   1,
+	}
+	testCase.Run(t)
+
+	events, err := reaper.KubernetesClient.CoreV1().Events("namespace-1").List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("failed to list events: %v", err)
+	}
+	if len(events.Items) == 0 {
+		t.Fatalf("assertion failed, expected a crashloop event")
+	}
+	expected := "last logs of namespace-1/pod-1/app: ...logs"
+	if message := events.Items[0].Message; !strings.HasSuffix(message, expected) {
+		t.Fatalf("assertion failed, expected event message ending with %q, got: %q", expected, message)
+	}
+}
+
+func TestTruncateLogs(t *testing.T) {
+	tests := []struct {
+		logs     string
+		maxBytes int
+		expected string
+	}{
+		{"line-1\nline-2", 100, "line-1\nline-2"},
+		{"line-1\nline-2", 9, "...line-2"},
+		{"line-1\nline-2", 2, "-2"},
+	}
+	for _, tt := range tests {
+		if got := truncateLogs(tt.logs, tt.maxBytes); got != tt.expected {
+			t.Fatalf("assertion failed, expected truncateLogs(%q, %v) to be %q, got: %q", tt.logs, tt.maxBytes, tt.expected, got)
+		}
+	}
+}
+
+func _setDisruptionsAllowed(t *testing.T, reaper *ReaperContext, namespace, name string, disruptions int32) {
+	pdb := _getPDB(t, reaper, namespace, name)
+	pdb.Status.DisruptionsAllowed = disruptions
+	if _, err := reaper.KubernetesClient.PolicyV1().PodDisruptionBudgets(namespace).UpdateStatus(context.Background(), pdb, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("failed to update PDB status: %v", err)
+	}
+}
+
+func TestBlockingRuns(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	newReaper := func() *ReaperContext {
+		reaper := _fakeReaperContext()
+		reaper.KubernetesClient = client
+		reaper.ReapMultiple = false
+		reaper.DryRun = true
+		reaper.BlockingRuns = 3
+		reaper.StateConfigMapNamespace = "pdb-reaper"
+		reaper.StateConfigMapName = "pdb-reaper-state"
+		return reaper
+	}
+
+	testCase := ReaperUnitTest{
+		FakeReaper: newReaper(),
+		Mocks: KubernetesMockAPI{
+			Namespaces: []MockNamespace{
+				_mockNamespace("namespace-1"),
+			},
+			PDBs: []MockPDB{
+				_mockPDB("pdb-stuck", "namespace-1", nil, &intStrZeroInt, _selector("app=app-1"), 1, 0),
+				_mockPDB("pdb-flapping", "namespace-1", nil, &intStrZeroInt, _selector("app=app-2"), 1, 0),
+			},
+		},
+	}
+	_fakeAPI(&testCase)
+
+	// pdb-flapping allows a disruption in the second run, and is blocking in the others
+	flapping := []int32{0, 1, 0}
+	var reaper *ReaperContext
+	for run, disruptions := range flapping {
+		reaper = newReaper()
+		_setDisruptionsAllowed(t, reaper, "namespace-1", "pdb-flapping", disruptions)
+		if err := reaper.execute(); err != nil {
+			t.Fatalf("execution failed: %v", err)
+		}
+		if run < len(flapping)-1 && reaper.ReapablePodDisruptionBudgetsCount != 0 {
+			t.Fatalf("assertion failed, expected no reapable PDBs in run %v, got: %v", run+1, reaper.ReapablePodDisruptionBudgetsCount)
+		}
+	}
+
+	if _, ok := reaper.ReapableReasons["namespace-1/pdb-stuck"]; !ok {
+		t.Fatalf("assertion failed, expected pdb-stuck to be reapable after 3 blocking runs")
+	}
+	if _, ok := reaper.ReapableReasons["namespace-1/pdb-flapping"]; ok {
+		t.Fatalf("assertion failed, expected pdb-flapping to be spared")
+	}
+	if runs := reaper.State.BlockingRuns["namespace-1/pdb-flapping"]; runs != 1 {
+		t.Fatalf("assertion failed, expected pdb-flapping to have 1 consecutive blocking run, got: %v", runs)
+	}
+}
+
+func TestReportOnlyThreshold(t *testing.T) {
+	reaper := _fakeReaperContext()
+	reaper.ReapMultiple = false
+	thresholds, err := parseReportOnlyThresholds([]string{"2", "crashloop=0"})
+	if err != nil {
+		t.Fatalf("failed to parse thresholds: %v", err)
+	}
+	reaper.ReportOnlyThresholds = thresholds
+
+	testCase := ReaperUnitTest{
+		FakeReaper: reaper,
+		Mocks: KubernetesMockAPI{
+			Namespaces: []MockNamespace{
+				_mockNamespace("namespace-1"),
+			},
+			PDBs: []MockPDB{
+				_mockPDB("pdb-misconfigured", "namespace-1", nil, &intStrZeroInt, _selector("app=app-1"), 1, 0),
+				_mockPDB("pdb-crashloop", "namespace-1", nil, &intStrOneInt, _selector("app=app-2"), 1, 0),
+			},
+			Pods: []MockPod{
+				_mockPod("pod-2", "namespace-1", map[string]string{"app": "app-2"}, true, 6, false),
+			},
+		},
+	}
+	_fakeAPI(&testCase)
+
+	exists := func(name string) bool {
+		_, err := reaper.KubernetesClient.PolicyV1().PodDisruptionBudgets("namespace-1").Get(context.Background(), name, metav1.GetOptions{})
+		return err == nil
+	}
+
+	// crashloop PDBs are reaped right away, misconfigured PDBs are only reported in the first 2 runs
+	for run := 1; run <= 3; run++ {
+		if err := reaper.execute(); err != nil {
+			t.Fatalf("execution failed: %v", err)
+		}
+		if exists("pdb-crashloop") {
+			t.Fatalf("assertion failed, expected pdb-crashloop to be reaped in run %v", run)
+		}
+		if run <= 2 {
+			if !exists("pdb-misconfigured") {
+				t.Fatalf("assertion failed, expected pdb-misconfigured to only be reported in run %v", run)
+			}
+			if runs := reaper.State.ViolationStreaks["namespace-1/pdb-misconfigured"][ReapModeMisconfigured]; runs != run {
+				t.Fatalf("assertion failed, expected a streak of %v runs, got: %v", run, runs)
+			}
+		}
+	}
+
+	if exists("pdb-misconfigured") {
+		t.Fatalf("assertion failed, expected pdb-misconfigured to be reaped once its streak exceeded the threshold")
+	}
+}
+
+func TestReportOnlyThresholdReset(t *testing.T) {
+	reaper := _fakeReaperContext()
+	reaper.ReportOnlyThresholds = map[string]int{ReapModeMisconfigured: 1}
+	reaper.State.ViolationStreaks = map[string]map[string]int{
+		"namespace-1/pdb-recovered": {ReapModeMisconfigured: 5},
+	}
+
+	testCase := ReaperUnitTest{
+		FakeReaper: reaper,
+		Mocks: KubernetesMockAPI{
+			Namespaces: []MockNamespace{
+				_mockNamespace("namespace-1"),
+			},
+			PDBs: []MockPDB{
+				_mockPDB("pdb-recovered", "namespace-1", nil, &intStrOneInt, _selector("app=app-1"), 1, 1),
+			},
+		},
+	}
+	_fakeAPI(&testCase)
+
+	if err := reaper.execute(); err != nil {
+		t.Fatalf("execution failed: %v", err)
+	}
+	if _, ok := reaper.State.ViolationStreaks["namespace-1/pdb-recovered"]; ok {
+		t.Fatalf("assertion failed, expected the streak of a PDB which is no longer reapable to be reset")
+	}
+}
+
+func TestResetStaleMetrics(t *testing.T) {
+	reaper := _fakeReaperContext()
+	reaper.DryRun = true
+	reaper.ReapMultiple = false
+	reaper.ResetStaleMetrics = true
+	metrics := &fakeMetricsAPI{}
+	reaper.MetricsAPI = metrics
+	testCase := ReaperUnitTest{
+		FakeReaper: reaper,
+		Mocks: KubernetesMockAPI{
+			Namespaces: []MockNamespace{
+				_mockNamespace("namespace-1"),
+			},
+			PDBs: []MockPDB{
+				_mockPDB("pdb-resolved", "namespace-1", nil, &intStrZeroInt, _selector("app=app-1"), 1, 0),
+				_mockPDB("pdb-stuck", "namespace-1", nil, &intStrZeroInt, _selector("app=app-2"), 1, 0),
+			},
+		},
+	}
+	_fakeAPI(&testCase)
+
+	resultTags := func(name string) map[string]string {
+		return map[string]string{"pdb": name, "reason": ReasonBlocking.String()}
+	}
+
+	if err := reaper.execute(); err != nil {
+		t.Fatalf("execution failed: %v", err)
+	}
+	if v, ok := metrics.lastValue(PdbReaperResultMetricName, resultTags("pdb-resolved")); !ok || v != 1 {
+		t.Fatalf("assertion failed, expected pdb-resolved to be reported as reapable, got: %v", v)
+	}
+
+	// pdb-resolved allows a disruption in the second run and is no longer reapable
+	_setDisruptionsAllowed(t, reaper, "namespace-1", "pdb-resolved", 1)
+	if err := reaper.execute(); err != nil {
+		t.Fatalf("execution failed: %v", err)
+	}
+	if v, ok := metrics.lastValue(PdbReaperResultMetricName, resultTags("pdb-resolved")); !ok || v != 0 {
+		t.Fatalf("assertion failed, expected the metric of pdb-resolved to be reset to 0, got: %v", v)
+	}
+	if v, ok := metrics.lastValue(PdbReaperResultMetricName, resultTags("pdb-stuck")); !ok || v != 1 {
+		t.Fatalf("assertion failed, expected pdb-stuck to still be reported as reapable, got: %v", v)
+	}
+	if _, ok := reaper.State.ReapableReasons["namespace-1/pdb-resolved"]; ok {
+		t.Fatalf("assertion failed, expected pdb-resolved to no longer be tracked in the state")
+	}
+}
+
+func _multipleOverlapMocks() KubernetesMockAPI {
+	return KubernetesMockAPI{
+		Namespaces: []MockNamespace{
+			_mockNamespace("namespace-1"),
+		},
+		PDBs: []MockPDB{
+			_mockPDB("pdb-broad", "namespace-1", nil, &intStrOneInt, _selector("app=app-1"), 4, 1),
+			_mockPDB("pdb-narrow", "namespace-1", nil, &intStrOneInt, _selector("app=app-1,role=leader"), 1, 1),
+			_mockPDB("pdb-replica", "namespace-1", nil, &intStrOneInt, _selector("app=app-2"), 2, 1),
+			_mockPDB("pdb-replica-copy", "namespace-1", nil, &intStrOneInt, _selector("app=app-2,tier=web"), 2, 1),
+		},
+		Pods: []MockPod{
+			_mockPod("pod-1", "namespace-1", map[string]string{"app": "app-1", "role": "leader"}, false, 0, false),
+			_mockPod("pod-2", "namespace-1", map[string]string{"app": "app-1"}, false, 0, false),
+			_mockPod("pod-3", "namespace-1", map[string]string{"app": "app-1"}, false, 0, false),
+			_mockPod("pod-4", "namespace-1", map[string]string{"app": "app-1"}, false, 0, false),
+			_mockPod("pod-5", "namespace-1", map[string]string{"app": "app-2", "tier": "web"}, false, 0, false),
+			_mockPod("pod-6", "namespace-1", map[string]string{"app": "app-2", "tier": "web"}, false, 0, false),
+		},
+	}
+}
+
+func TestMultiplePermissiveOverlap(t *testing.T) {
+	reaper := _fakeReaperContext()
+	testCase := ReaperUnitTest{
+		TestDescription: "Overlapping PDBs are reaped even when each of them allows disruptions",
+		FakeReaper:      reaper,
+		Mocks: KubernetesMockAPI{
+			Namespaces: []MockNamespace{
+				_mockNamespace("namespace-1"),
+			},
+			PDBs: []MockPDB{
+				// the eviction API refuses to evict a pod matched by more than one PDB, whatever each of them allows
+				_mockPDB("pdb-permissive-1", "namespace-1", nil, &intStrOneInt, _selector("app=app-1"), 3, 2),
+				_mockPDB("pdb-permissive-2", "namespace-1", nil, &intStrOneInt, _selector("app=app-1,role=leader"), 1, 1),
+			},
+			Pods: []MockPod{
+				_mockPod("pod-1", "namespace-1", map[string]string{"app": "app-1", "role": "leader"}, false, 0, false),
+				_mockPod("pod-2", "namespace-1", map[string]string{"app": "app-1"}, false, 0, false),
+				_mockPod("pod-3", "namespace-1", map[string]string{"app": "app-1"}, false, 0, false),
+			},
+		},
+		ExpectedReapableBudgets: 2,
+		ExpectedReapedBudgets:   2,
+	}
+	testCase.Run(t)
+
+	for _, name := range []string{"namespace-1/pdb-permissive-1", "namespace-1/pdb-permissive-2"} {
+		if reasons := reaper.ReapableReasons[name]; !containsReason(reasons, ReasonMultiple) {
+			t.Fatalf("expected overlapping PDB %v to be reapable due to %v, got: %v", name, ReasonMultiple, reasons)
+		}
+	}
+}
+
+func TestEvaluationTimerClock(t *testing.T) {
+	reaper := _fakeReaperContext()
+	reaper.evaluationTimes = make(map[string]time.Duration)
+	started := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	reaper.Clock = fakeClock{started}
+
+	timer := reaper.newEvaluationTimer()
+	timer.start(policyv1.PodDisruptionBudget{ObjectMeta: metav1.ObjectMeta{Namespace: "namespace-1", Name: "pdb-1"}})
+	reaper.Clock = fakeClock{started.Add(2 * time.Second)}
+	timer.stop()
+
+	if elapsed := reaper.evaluationTimes["namespace-1/pdb-1"]; elapsed != 2*time.Second {
+		t.Fatalf("expected the evaluation time to be measured with the context clock, got: %v", elapsed)
+	}
+}
+
+func TestEvaluationLatencyPushgateway(t *testing.T) {
+	reaper := _fakeReaperContext()
+	pgw := _fakePushgateway(t)
+	reaper.MetricsAPI = common.NewPrometheusAPI(pgw.URL)
+	testCase := ReaperUnitTest{
+		TestDescription: "Tests that the evaluation histogram is kept along the other metrics of the PDB on the pushgateway",
+		FakeReaper:      reaper,
+		Mocks: KubernetesMockAPI{
+			Namespaces: []MockNamespace{
+				_mockNamespace("namespace-1"),
+			},
+			PDBs: []MockPDB{
+				_mockPDB("pdb-1", "namespace-1", nil, &intStrOneInt, _selector("app=app-1"), 1, 0),
+			},
+			Pods: []MockPod{
+				_mockPod("pod-1", "namespace-1", map[string]string{"app": "app-1"}, true, 6, false),
+			},
+		},
+		ExpectedReapableBudgets: 1,
+		ExpectedReapedBudgets:   1,
+	}
+	testCase.Run(t)
+
+	tags := map[string]string{"namespace": "namespace-1", "pdb": "pdb-1"}
+	pgw.assertPushed(t, tags, map[string]float64{
+		PdbReaperMatchedPodsMetricName: 1,
+	})
+	tags["reason"] = ReasonBlockingCrashLoop.String()
+	pgw.assertPushed(t, tags, map[string]float64{
+		PdbReaperEvaluationSecondsMetricName: 1,
+	})
+	tags["reason_code"] = strconv.Itoa(ReasonBlockingCrashLoop.Code())
+	pgw.assertPushed(t, tags, map[string]float64{
+		PdbReaperResultMetricName: 1,
+	})
+
+	// an observation doesn't replace a gauge pushed with the same tags
+	if err := reaper.observeMetricValue(PdbReaperEvaluationSecondsMetricName, tags, 0.5); err != nil {
+		t.Fatalf("failed to observe metric: %v", err)
+	}
+	pgw.assertPushed(t, tags, map[string]float64{
+		PdbReaperResultMetricName:            1,
+		PdbReaperEvaluationSecondsMetricName: 1,
+	})
+}
+
+func TestEvaluationLatencyMetric(t *testing.T) {
+	reaper := _fakeReaperContext()
+	metrics := &fakeMetricsAPI{}
+	reaper.MetricsAPI = metrics
+	testCase := ReaperUnitTest{
+		TestDescription: "An observation of the evaluation time is recorded for each evaluated PDB",
+		FakeReaper:      reaper,
+		Mocks: KubernetesMockAPI{
+			Namespaces: []MockNamespace{
+				_mockNamespace("namespace-1"),
+				_mockNamespace("namespace-2"),
+			},
+			PDBs: []MockPDB{
+				_mockPDB("pdb-1", "namespace-1", nil, &intStrZeroInt, _selector("app=app-1"), 1, 0),
+				_mockPDB("pdb-2", "namespace-1", nil, &intStrOneInt, _selector("app=app-2"), 1, 1),
+				_mockPDB("pdb-3", "namespace-2", nil, &intStrOneInt, _selector("app=app-3"), 2, 1),
+			},
+			Pods: []MockPod{
+				_mockPod("pod-1", "namespace-1", map[string]string{"app": "app-1"}, false, 0, false),
+				_mockPod("pod-2", "namespace-1", map[string]string{"app": "app-2"}, false, 0, false),
+			},
+		},
+		ExpectedReapableBudgets: 1,
+		ExpectedReapedBudgets:   1,
+	}
+	testCase.Run(t)
+
+	// pdb-1 and pdb-2 are evaluated by the multiple detection and pdb-1 also as blocking, pdb-3 is not evaluated
+	observed := make(map[string]string)
+	for _, observation := range metrics.Observations {
+		if observation.Name != PdbReaperEvaluationSecondsMetricName {
+			continue
+		}
+		namespacedName := observation.Tags["namespace"] + "/" + observation.Tags["pdb"]
+		if _, ok := observed[namespacedName]; ok {
+			t.Fatalf("expected a single observation for PDB %v", namespacedName)
+		}
+		if observation.Value < 0 {
+			t.Fatalf("expected a non-negative evaluation time for PDB %v, got: %v", namespacedName, observation.Value)
+		}
+		observed[namespacedName] = observation.Tags["reason"]
+	}
+
+	expected := map[string]string{
+		"namespace-1/pdb-1": EventReasonBlockingDetected,
+		"namespace-1/pdb-2": EvaluationReasonNone,
+	}
+	if len(observed) != len(expected) {
+		t.Fatalf("expected observations for %v, got: %v", expected, observed)
+	}
+	for name, reason := range expected {
+		if observed[name] != reason {
+			t.Fatalf("expected observation for PDB %v with reason %v, got: %v", name, reason, observed[name])
+		}
+	}
+}
+
+func TestFixOverlap(t *testing.T) {
+	reaper := _fakeReaperContext()
+	reaper.FixOverlap = true
+	// events are created with a generated name which the fake client does not generate, so they are recorded on create
+	var patched []string
+	client := reaper.KubernetesClient.(*fake.Clientset)
+	client.PrependReactor("create", "events", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		event := action.(k8stesting.CreateAction).GetObject().(*corev1.Event)
+		if event.Reason == EventReasonPodDisruptionBudgetPatched {
+			patched = append(patched, event.InvolvedObject.Name)
+		}
+		return false, nil, nil
+	})
+	testCase := ReaperUnitTest{
+		TestDescription: "Redundant overlapping PDBs are patched to a permissive maxUnavailable instead of being deleted",
+		FakeReaper:      reaper,
+		Mocks: KubernetesMockAPI{
+			Namespaces: []MockNamespace{
+				_mockNamespace("namespace-1"),
+			},
+			PDBs: []MockPDB{
+				_mockPDB("pdb-broad", "namespace-1", nil, &intStrOneInt, _selector("app=app-1"), 3, 1),
+				_mockPDB("pdb-leader", "namespace-1", nil, &intStrOneInt, _selector("app=app-1,role=leader"), 1, 0),
+			},
+			Pods: []MockPod{
+				_mockPod("pod-1", "namespace-1", map[string]string{"app": "app-1", "role": "leader"}, false, 0, false),
+				_mockPod("pod-2", "namespace-1", map[string]string{"app": "app-1"}, false, 0, false),
+				_mockPod("pod-3", "namespace-1", map[string]string{"app": "app-1"}, false, 0, false),
+			},
+		},
+		ExpectedReapableBudgets: 1,
+		ExpectedReapedBudgets:   0,
+	}
+	testCase.Run(t)
+
+	if reaper.PatchedPodDisruptionBudgetCount != 1 {
+		t.Fatalf("expected 1 patched PDB, got: %v", reaper.PatchedPodDisruptionBudgetCount)
+	}
+
+	leader, err := reaper.KubernetesClient.PolicyV1().PodDisruptionBudgets("namespace-1").Get(context.Background(), "pdb-leader", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("expected redundant PDB to be preserved, got: %v", err)
+	}
+	if leader.Spec.MaxUnavailable == nil || leader.Spec.MaxUnavailable.String() != FixOverlapMaxUnavailable || leader.Spec.MinAvailable != nil {
+		t.Fatalf("expected redundant PDB to be patched to maxUnavailable %v, got: minAvailable %v maxUnavailable %v", FixOverlapMaxUnavailable, leader.Spec.MinAvailable, leader.Spec.MaxUnavailable)
+	}
+
+	broad, err := reaper.KubernetesClient.PolicyV1().PodDisruptionBudgets("namespace-1").Get(context.Background(), "pdb-broad", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("expected kept PDB to be preserved, got: %v", err)
+	}
+	if broad.Spec.MaxUnavailable == nil || broad.Spec.MaxUnavailable.String() != intStrOneInt.String() {
+		t.Fatalf("expected kept PDB to be untouched, got: maxUnavailable %v", broad.Spec.MaxUnavailable)
+	}
+	if _, ok := reaper.ReapableReasons["namespace-1/pdb-broad"]; ok {
+		t.Fatalf("expected kept PDB to not be reapable")
+	}
+
+	if strings.Join(patched, ",") != "pdb-leader" {
+		t.Fatalf("expected a patched event on pdb-leader only, got: %v", patched)
+	}
+}
+
+func TestMultipleOverlapManyPodDisruptionBudgets(t *testing.T) {
+	reaper := _fakeReaperContext()
+	mocks := KubernetesMockAPI{
+		Namespaces: []MockNamespace{
+			_mockNamespace("namespace-1"),
+		},
+	}
+	// many PDBs selecting distinct pods, only the last two share a pod
+	for i := 0; i < 200; i++ {
+		app := fmt.Sprintf("app-%v", i)
+		mocks.PDBs = append(mocks.PDBs, _mockPDB(fmt.Sprintf("pdb-%v", i), "namespace-1", nil, &intStrOneInt, _selector("app="+app), 2, 1))
+		mocks.Pods = append(mocks.Pods,
+			_mockPod(fmt.Sprintf("pod-%v-1", i), "namespace-1", map[string]string{"app": app}, false, 0, false),
+			_mockPod(fmt.Sprintf("pod-%v-2", i), "namespace-1", map[string]string{"app": app}, false, 0, false),
+		)
+	}
+	mocks.PDBs = append(mocks.PDBs, _mockPDB("pdb-leader", "namespace-1", nil, &intStrOneInt, _selector("app=app-199,role=leader"), 1, 0))
+	mocks.Pods = append(mocks.Pods, _mockPod("pod-199-leader", "namespace-1", map[string]string{"app": "app-199", "role": "leader"}, false, 0, false))
+
+	testCase := ReaperUnitTest{
+		TestDescription:         "Overlap is detected across many PDBs in a namespace",
+		FakeReaper:              reaper,
+		Mocks:                   mocks,
+		ExpectedReapableBudgets: 2,
+		ExpectedReapedBudgets:   2,
+	}
+	testCase.Run(t)
+
+	for _, name := range []string{"namespace-1/pdb-199", "namespace-1/pdb-leader"} {
+		if reasons := reaper.ReapableReasons[name]; !containsReason(reasons, ReasonMultiple) {
+			t.Fatalf("expected overlapping PDB %v to be reapable due to %v, got: %v", name, ReasonMultiple, reasons)
+		}
+	}
+}
+
+func TestPodOverlapTracker(t *testing.T) {
+	pdbs := []policyv1.PodDisruptionBudget{
+		{ObjectMeta: metav1.ObjectMeta{Name: "pdb-1", Namespace: "namespace-1"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "pdb-2", Namespace: "namespace-1"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "pdb-3", Namespace: "namespace-1"}},
+	}
+	pods := func(names ...string) []corev1.Pod {
+		list := make([]corev1.Pod, 0)
+		for _, name := range names {
+			list = append(list, corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "namespace-1"}})
+		}
+		return list
+	}
+
+	overlap := newPodOverlapTracker()
+	overlap.add(pdbs[0], pods("pod-1", "pod-2"))
+	overlap.add(pdbs[1], pods("pod-3"))
+	overlap.add(pdbs[2], pods("pod-2", "pod-3"))
+
+	if shared := overlap.sharedPods(); strings.Join(shared, ",") != "pod-2,pod-3" {
+		t.Fatalf("expected shared pods pod-2,pod-3, got: %v", shared)
+	}
+	if names := overlap.podNames["namespace-1/pdb-3"]; strings.Join(names, ",") != "pod-2,pod-3" {
+		t.Fatalf("expected pod names pod-2,pod-3 for namespace-1/pdb-3, got: %v", names)
+	}
+	if ratio := podOverlapRatio(overlap.podNames["namespace-1/pdb-1"], overlap.podNames["namespace-1/pdb-3"]); ratio != 0.5 {
+		t.Fatalf("expected overlap ratio 0.5, got: %v", ratio)
+	}
+}
+
+func TestRequireAllPodsForMultiple(t *testing.T) {
+	reaper := _fakeReaperContext()
+	reaper.MultipleOverlapRatio = 1
+	testCase := ReaperUnitTest{
+		TestDescription:         "Multiple PDBs with a minor pod overlap are spared, PDBs matching the same pods are reaped",
+		FakeReaper:              reaper,
+		Mocks:                   _multipleOverlapMocks(),
+		ExpectedReapableBudgets: 2,
+		ExpectedReapedBudgets:   2,
+	}
+	testCase.Run(t)
+
+	for _, name := range []string{"namespace-1/pdb-broad", "namespace-1/pdb-narrow"} {
+		if _, ok := reaper.ReapableReasons[name]; ok {
+			t.Fatalf("expected PDB %v with a minor overlap to not be reapable", name)
+		}
+	}
+}
+
+func TestMultipleOverlapRatio(t *testing.T) {
+	reaper := _fakeReaperContext()
+	reaper.MultipleOverlapRatio = 0.25
+	testCase := ReaperUnitTest{
+		TestDescription:         "Multiple PDBs sharing at least --multiple-overlap-ratio of their pods are reaped",
+		FakeReaper:              reaper,
+		Mocks:                   _multipleOverlapMocks(),
+		ExpectedReapableBudgets: 4,
+		ExpectedReapedBudgets:   4,
+	}
+	testCase.Run(t)
+}
+
+func TestMultipleOverlapDisabled(t *testing.T) {
+	testCase := ReaperUnitTest{
+		TestDescription:         "Multiple PDBs sharing a single pod are reaped by default",
+		FakeReaper:              _fakeReaperContext(),
+		Mocks:                   _multipleOverlapMocks(),
+		ExpectedReapableBudgets: 4,
+		ExpectedReapedBudgets:   4,
+	}
+	testCase.Run(t)
+}
+
+func TestDryRunReapSimulation(t *testing.T) {
+	reaper := _fakeReaperContext()
+	output := &bytes.Buffer{}
+	reaper.DryRun = true
+	reaper.NDJSON = true
+	reaper.Output = output
+	testCase := ReaperUnitTest{
+		TestDescription: "Dry-run reports the current disruption allowance of reapable PDBs and that deletion removes all constraints",
+		FakeReaper:      reaper,
+		Mocks: KubernetesMockAPI{
+			Namespaces: []MockNamespace{
+				_mockNamespace("namespace-1"),
+			},
+			PDBs: []MockPDB{
+				_mockPDB("pdb-1", "namespace-1", nil, &intStrZeroInt, _selector("app=app-1"), 3, 0),
+			},
+			Pods: []MockPod{
+				_mockPod("pod-1", "namespace-1", map[string]string{"app": "app-1"}, false, 0, false),
+			},
+		},
+		ExpectedReapableBudgets: 1,
+		ExpectedReapedBudgets:   0,
+	}
+	testCase.Run(t)
+
+	var simulation *ReapSimulation
+	for _, line := range strings.Split(strings.TrimSpace(output.String()), "\n") {
+		var record ActionRecord
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("failed to parse NDJSON line %q: %v", line, err)
+		}
+		if record.Type == RecordTypeSimulation {
+			if record.PDB != "namespace-1/pdb-1" || record.Reason != ReasonBlocking {
+				t.Fatalf("assertion failed, unexpected simulation record %+v", record)
+			}
+			simulation = record.Simulation
+		}
+	}
+	if simulation == nil {
+		t.Fatalf("assertion failed, expected a simulation record")
+	}
+	if simulation.ExpectedPods != 3 || simulation.DisruptionsAllowed != 0 {
+		t.Fatalf("assertion failed, expected 0 disruptions allowed of 3 expected pods, got: %+v", simulation)
+	}
+	if simulation.MaxUnavailable == nil || *simulation.MaxUnavailable != 0 || simulation.MinAvailable != nil {
+		t.Fatalf("assertion failed, expected resolved maxUnavailable 0 and no minAvailable, got: %+v", simulation)
+	}
+	if simulation.PostReapDisruptionsAllowed != PostReapDisruptionsAllowedUnbounded {
+		t.Fatalf("assertion failed, expected post-reap disruptions %v, got: %v", PostReapDisruptionsAllowedUnbounded, simulation.PostReapDisruptionsAllowed)
+	}
+	if !strings.Contains(simulation.Note, "removes all disruption constraints") {
+		t.Fatalf("assertion failed, expected note on removed constraints, got: %v", simulation.Note)
+	}
+}
+
+func TestSimulateReapPercentage(t *testing.T) {
+	minAvailable := intstr.FromString("50%")
+	pdb := policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{Name: "pdb-1", Namespace: "namespace-1"},
+		Spec:       policyv1.PodDisruptionBudgetSpec{MinAvailable: &minAvailable},
+		Status:     policyv1.PodDisruptionBudgetStatus{ExpectedPods: 5, DisruptionsAllowed: 2},
+	}
+	simulation := simulateReap(pdb)
+	if simulation.MinAvailable == nil || *simulation.MinAvailable != 3 {
+		t.Fatalf("assertion failed, expected minAvailable 50%% of 5 to resolve to 3, got: %+v", simulation.MinAvailable)
+	}
+	if simulation.DisruptionsAllowed != 2 || simulation.MaxUnavailable != nil {
+		t.Fatalf("assertion failed, unexpected simulation %+v", simulation)
+	}
+}
+
+func _completedPodsMocks() KubernetesMockAPI {
+	succeeded := _mockPod("pod-1", "namespace-1", map[string]string{"app": "app-1"}, false, 0, true)
+	succeeded.Phase = corev1.PodSucceeded
+	failed := _mockPod("pod-2", "namespace-1", map[string]string{"app": "app-1"}, false, 0, true)
+	failed.Phase = corev1.PodFailed
+	return KubernetesMockAPI{
+		Namespaces: []MockNamespace{
+			_mockNamespace("namespace-1"),
+		},
+		PDBs: []MockPDB{
+			_mockPDB("pdb-1", "namespace-1", nil, &intStrOneInt, _selector("app=app-1"), 2, 0),
+		},
+		Pods: []MockPod{succeeded, failed},
+	}
+}
+
+func TestNotReadyCompletedPods(t *testing.T) {
+	reaper := _fakeReaperContext()
+	reaper.ReapNotReadyThreshold = 30
+	testCase := ReaperUnitTest{
+		TestDescription:         "Succeeded and Failed pods are not counted as not-ready",
+		FakeReaper:              reaper,
+		Mocks:                   _completedPodsMocks(),
+		ExpectedReapableBudgets: 0,
+		ExpectedReapedBudgets:   0,
+	}
+	testCase.Run(t)
+}
+
+func TestNotReadyCompletedPodsAllNotReady(t *testing.T) {
+	reaper := _fakeReaperContext()
+	reaper.ReapNotReadyThreshold = 30
+	reaper.AllNotReady = true
+	mocks := _completedPodsMocks()
+	mocks.Pods = append(mocks.Pods, _mockPod("pod-3", "namespace-1", map[string]string{"app": "app-1"}, false, 0, true))
+	testCase := ReaperUnitTest{
+		TestDescription:         "Completed pods do not prevent all live pods from being considered not-ready",
+		FakeReaper:              reaper,
+		Mocks:                   mocks,
+		ExpectedReapableBudgets: 1,
+		ExpectedReapedBudgets:   1,
+	}
+	testCase.Run(t)
+}
+
+func TestNotReadyPhase(t *testing.T) {
+	tests := []struct {
+		name             string
+		phase            corev1.PodPhase
+		includePending   bool
+		expectedNotReady int
+	}{
+		{"Running", corev1.PodRunning, false, 1},
+		{"Pending", corev1.PodPending, false, 0},
+		{"PendingIncluded", corev1.PodPending, true, 1},
+		{"RunningPendingIncluded", corev1.PodRunning, true, 1},
+		{"Unknown", corev1.PodUnknown, true, 0},
+		{"Succeeded", corev1.PodSucceeded, true, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reaper := _fakeReaperContext()
+			reaper.ReapNotReadyThreshold = 10
+			reaper.NotReadyIncludePending = tt.includePending
+			pod := _mockPod("pod-1", "namespace-1", map[string]string{"app": "app-1"}, false, 0, true)
+			pod.Phase = tt.phase
+			testCase := ReaperUnitTest{
+				TestDescription: "Pods are only counted as not-ready in the Running phase, and Pending with --not-ready-include-pending",
+				FakeReaper:      reaper,
+				Mocks: KubernetesMockAPI{
+					Namespaces: []MockNamespace{
+						_mockNamespace("namespace-1"),
+					},
+					PDBs: []MockPDB{
+						_mockPDB("pdb-1", "namespace-1", nil, &intStrOneInt, _selector("app=app-1"), 1, 0),
+					},
+					Pods: []MockPod{pod},
+				},
+				ExpectedReapableBudgets: tt.expectedNotReady,
+				ExpectedReapedBudgets:   tt.expectedNotReady,
+			}
+			testCase.Run(t)
+
+			if tt.expectedNotReady > 0 && !containsReason(reaper.ReapableReasons["namespace-1/pdb-1"], ReasonBlockingNotReadyState) {
+				t.Fatalf("expected PDB to be reapable due to %v, got: %v", ReasonBlockingNotReadyState, reaper.ReapableReasons["namespace-1/pdb-1"])
+			}
+		})
+	}
+}
+
+func TestIsPodsInNotReadyStateTerminated(t *testing.T) {
+	now := time.Now()
+	notReady := corev1.PodStatus{
+		Phase: corev1.PodSucceeded,
+		Conditions: []corev1.PodCondition{
+			{Type: corev1.ContainersReady, Status: corev1.ConditionFalse, LastTransitionTime: metav1.Time{Time: now.Add(-time.Hour)}},
+		},
+	}
+	pods := []corev1.Pod{{Status: notReady}}
+	if isPodsInNotReadyState(now, pods, 30, 0, nil, false, false) {
+		t.Fatalf("expected Succeeded pod to not be counted as not-ready")
+	}
+	if isPodsInNotReadyState(now, pods, 30, 1, nil, false, false) {
+		t.Fatalf("expected only terminated pods to not be considered all not-ready")
+	}
+	pods[0].Status.Phase = corev1.PodRunning
+	if !isPodsInNotReadyState(now, pods, 30, 0, nil, false, false) {
+		t.Fatalf("expected Running pod to be counted as not-ready")
+	}
+}
+
+func TestPodCountRetries(t *testing.T) {
+	tests := []struct {
+		name             string
+		retries          int
+		laggingLists     int
+		expectedReapable int
+		expectedLists    int
+	}{
+		{"NoRetries", 0, 1, 1, 1},
+		{"Reconciled", 2, 1, 0, 2},
+		{"Bounded", 2, 5, 1, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reaper := _fakeReaperContext()
+			reaper.ReapNotReadyThreshold = 30
+			reaper.AllNotReady = true
+			reaper.PodCountRetries = tt.retries
+			reaper.PodCountRetryDelay = time.Millisecond
+
+			// lagging lists are missing the ready pod, which makes all listed pods not-ready
+			var lists int
+			client := reaper.KubernetesClient.(*fake.Clientset)
+			client.PrependReactor("list", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				lists++
+				if lists > tt.laggingLists {
+					return false, nil, nil
+				}
+				obj, err := client.Tracker().List(corev1.SchemeGroupVersion.WithResource("pods"), corev1.SchemeGroupVersion.WithKind("Pod"), action.GetNamespace())
+				if err != nil {
+					return true, nil, err
+				}
+				podList := obj.(*corev1.PodList)
+				lagging := &corev1.PodList{}
+				for _, pod := range podList.Items {
+					if pod.GetName() != "pod-2" {
+						lagging.Items = append(lagging.Items, pod)
+					}
+				}
+				return true, lagging, nil
+			})
+
+			testCase := ReaperUnitTest{
+				TestDescription: "Pods are re-listed when fewer than the expected pods are listed",
+				FakeReaper:      reaper,
+				Mocks: KubernetesMockAPI{
+					Namespaces: []MockNamespace{
+						_mockNamespace("namespace-1"),
+					},
+					PDBs: []MockPDB{
+						_mockPDB("pdb-1", "namespace-1", nil, &intStrOneInt, _selector("app=app-1"), 2, 0),
+					},
+					Pods: []MockPod{
+						_mockPod("pod-1", "namespace-1", map[string]string{"app": "app-1"}, false, 0, true),
+						_mockPod("pod-2", "namespace-1", map[string]string{"app": "app-1"}, false, 0, false),
+					},
+				},
+				ExpectedReapableBudgets: tt.expectedReapable,
+				ExpectedReapedBudgets:   tt.expectedReapable,
+			}
+			testCase.Run(t)
+
+			if lists != tt.expectedLists {
+				t.Fatalf("expected pods to be listed %v times, got %v", tt.expectedLists, lists)
+			}
+		})
+	}
+}
+
+func TestEmitEventsAndMetrics(t *testing.T) {
+	tests := []struct {
+		name        string
+		dryRun      bool
+		emitEvents  bool
+		emitMetrics bool
+	}{
+		{"Silent", false, false, false},
+		{"SilentDryRun", true, false, false},
+		{"MetricsOnly", true, false, true},
+		{"EventsOnly", false, true, false},
+		{"All", true, true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metrics := &fakeMetricsAPI{}
+			reaper := _fakeReaperContext()
+			reaper.DryRun = tt.dryRun
+			reaper.EmitEvents = tt.emitEvents
+			reaper.EmitMetrics = tt.emitMetrics
+			reaper.MetricsAPI = metrics
+
+			expectedReaped := 1
+			if tt.dryRun {
+				expectedReaped = 0
+			}
+			testCase := ReaperUnitTest{
+				TestDescription: "Events and metrics are emitted according to --emit-events and --emit-metrics",
+				FakeReaper:      reaper,
+				Mocks: KubernetesMockAPI{
+					Namespaces: []MockNamespace{
+						_mockNamespace("namespace-1"),
+					},
+					PDBs: []MockPDB{
+						_mockPDB("pdb-1", "namespace-1", nil, &intStrZeroInt, _selector("app=app-1"), 1, 0),
+					},
+					Pods: []MockPod{
+						_mockPod("pod-1", "namespace-1", map[string]string{"app": "app-1"}, false, 0, false),
+					},
+				},
+				ExpectedReapableBudgets: 1,
+				ExpectedReapedBudgets:   expectedReaped,
+			}
+			testCase.Run(t)
+
+			events, err := reaper.KubernetesClient.CoreV1().Events("namespace-1").List(context.Background(), metav1.ListOptions{})
+			if err != nil {
+				t.Fatalf("failed to list events: %v", err)
+			}
+			if emitted := len(events.Items) > 0; emitted != tt.emitEvents {
+				t.Fatalf("expected events emitted = %t, got %v events", tt.emitEvents, len(events.Items))
+			}
+			if emitted := len(metrics.Metrics) > 0; emitted != tt.emitMetrics {
+				t.Fatalf("expected metrics emitted = %t, got %v metrics", tt.emitMetrics, len(metrics.Metrics))
+			}
+		})
+	}
+}
+
+func _validateMocks() KubernetesMockAPI {
+	return KubernetesMockAPI{
+		Namespaces: []MockNamespace{
+			_mockNamespace("namespace-1"),
+			_mockNamespace("namespace-2"),
+		},
+		PDBs: []MockPDB{
+			_mockPDB("pdb-misconfigured", "namespace-1", nil, &intStrZeroInt, _selector("app=app-1"), 1, 0),
+			_mockPDB("pdb-blocking", "namespace-1", nil, &intStrOneInt, _selector("app=app-2"), 2, 0),
+			_mockPDB("pdb-healthy", "namespace-1", nil, &intStrOneInt, _selector("app=app-3"), 2, 1),
+			_mockPDB("pdb-broad", "namespace-2", nil, &intStrOneInt, _selector("app=app-4"), 2, 1),
+			_mockPDB("pdb-narrow", "namespace-2", nil, &intStrOneInt, _selector("app=app-4,role=leader"), 1, 1),
+		},
+		Pods: []MockPod{
+			_mockPod("pod-1", "namespace-1", map[string]string{"app": "app-1"}, false, 0, false),
+			_mockPod("pod-2", "namespace-1", map[string]string{"app": "app-2"}, false, 0, false),
+			_mockPod("pod-3", "namespace-1", map[string]string{"app": "app-2"}, false, 0, false),
+			_mockPod("pod-4", "namespace-1", map[string]string{"app": "app-3"}, false, 0, false),
+			_mockPod("pod-5", "namespace-1", map[string]string{"app": "app-3"}, false, 0, false),
+			_mockPod("pod-6", "namespace-2", map[string]string{"app": "app-4", "role": "leader"}, false, 0, false),
+			_mockPod("pod-7", "namespace-2", map[string]string{"app": "app-4"}, false, 0, false),
+		},
+	}
+}
+
+func TestValidate(t *testing.T) {
+	reaper := _fakeReaperContext()
+	output := &bytes.Buffer{}
+	reaper.Lint = true
+	reaper.Output = output
+	testCase := ReaperUnitTest{FakeReaper: reaper, Mocks: _validateMocks()}
+	_fakeAPI(&testCase)
+
+	err := reaper.execute()
+	if !errors.Is(err, ErrValidationFindings) {
+		t.Fatalf("expected validation findings error, got: %v", err)
+	}
+	if code := ExitCode(errors.Wrap(err, "execution failed")); code != ExitCodeFindings {
+		t.Fatalf("expected exit code %v, got %v", ExitCodeFindings, code)
+	}
+
+	var report ValidationReport
+	if err := json.Unmarshal(output.Bytes(), &report); err != nil {
+		t.Fatalf("failed to parse validation report: %v", err)
+	}
+	if report.Scanned != 5 || report.Errors != 3 || report.Warnings != 1 {
+		t.Fatalf("expected 5 scanned, 3 errors and 1 warning, got: %+v", report)
+	}
+	expected := []Finding{
+		{PDB: "namespace-1/pdb-blocking", Check: "blocking", Severity: SeverityWarning},
+		{PDB: "namespace-1/pdb-misconfigured", Check: ReapModeMisconfigured, Severity: SeverityError},
+		{PDB: "namespace-2/pdb-broad", Check: ReapModeMultiple, Severity: SeverityError},
+		{PDB: "namespace-2/pdb-narrow", Check: ReapModeMultiple, Severity: SeverityError},
+	}
+	if len(report.Findings) != len(expected) {
+		t.Fatalf("expected %v findings, got: %+v", len(expected), report.Findings)
+	}
+	for i, finding := range report.Findings {
+		if finding.PDB != expected[i].PDB || finding.Check != expected[i].Check || finding.Severity != expected[i].Severity {
+			t.Fatalf("expected finding %+v, got: %+v", expected[i], finding)
+		}
+	}
+
+	pdbs, err := reaper.KubernetesClient.PolicyV1().PodDisruptionBudgets("").List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("failed to list PDBs: %v", err)
+	}
+	if len(pdbs.Items) != 5 {
+		t.Fatalf("expected no PDBs to be deleted, got %v PDBs", len(pdbs.Items))
+	}
+	events, err := reaper.KubernetesClient.CoreV1().Events("").List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("failed to list events: %v", err)
+	}
+	if len(events.Items) != 0 {
+		t.Fatalf("expected no events to be published, got %v events", len(events.Items))
+	}
+}
+
+func TestValidateNoFindings(t *testing.T) {
+	reaper := _fakeReaperContext()
+	reaper.Lint = true
+	mocks := _validateMocks()
+	mocks.PDBs = mocks.PDBs[2:4]
+	testCase := ReaperUnitTest{FakeReaper: reaper, Mocks: mocks}
+	_fakeAPI(&testCase)
+
+	err := reaper.execute()
+	if code := ExitCode(err); code != 0 {
+		t.Fatalf("expected exit code 0, got %v: %v", code, err)
+	}
+}
+
+func _maintenanceMocks(maintenanceLabel bool) KubernetesMockAPI {
+	onMaintenanceNode := _mockPod("pod-1", "namespace-1", map[string]string{"app": "app-1"}, false, 0, false)
+	onMaintenanceNode.NodeName = "node-1"
+	onHealthyNode := _mockPod("pod-2", "namespace-2", map[string]string{"app": "app-2"}, false, 0, false)
+	onHealthyNode.NodeName = "node-2"
+	maintenanceNode := MockNode{Name: "node-1"}
+	if maintenanceLabel {
+		maintenanceNode.Labels = map[string]string{"example.com/maintenance": "true"}
+	}
+	return KubernetesMockAPI{
+		Namespaces: []MockNamespace{
+			_mockNamespace("namespace-1"),
+			_mockNamespace("namespace-2"),
+		},
+		Nodes: []MockNode{
+			maintenanceNode,
+			{Name: "node-2"},
+		},
+		PDBs: []MockPDB{
+			_mockPDB("pdb-1", "namespace-1", nil, &intStrZeroInt, _selector("app=app-1"), 1, 0),
+			_mockPDB("pdb-2", "namespace-2", nil, &intStrZeroInt, _selector("app=app-2"), 1, 0),
+		},
+		Pods: []MockPod{
+			onMaintenanceNode,
+			onHealthyNode,
+		},
+	}
+}
+
+func TestNodeDrainIntegrationNodeLabel(t *testing.T) {
+	tests := []struct {
+		name             string
+		maintenanceLabel bool
+		expectedReaped   int
+	}{
+		{"NoMaintenance", false, 2},
+		{"Maintenance", true, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reaper := _fakeReaperContext()
+			reaper.NodeDrainIntegration = true
+			reaper.MaintenanceNodeLabel = "example.com/maintenance=true"
+			testCase := ReaperUnitTest{
+				TestDescription:         "During maintenance indicated by a node label only PDBs blocking maintenance nodes are reaped",
+				FakeReaper:              reaper,
+				Mocks:                   _maintenanceMocks(tt.maintenanceLabel),
+				ExpectedReapableBudgets: tt.expectedReaped,
+				ExpectedReapedBudgets:   tt.expectedReaped,
+			}
+			testCase.Run(t)
+
+			if _, ok := reaper.ReapableReasons["namespace-1/pdb-1"]; !ok {
+				t.Fatalf("expected PDB namespace-1/pdb-1 on the maintenance node to be reapable")
+			}
+		})
+	}
+}
+
+func TestNodeDrainIntegrationConfigMap(t *testing.T) {
+	tests := []struct {
+		name           string
+		maintenance    string
+		expectedReaped int
+	}{
+		{"NoMaintenance", "false", 2},
+		{"Maintenance", "true", 1},
+		{"Invalid", "soon", 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reaper := _fakeReaperContext()
+			reaper.NodeDrainIntegration = true
+			reaper.MaintenanceConfigMapNamespace = "kube-system"
+			reaper.MaintenanceConfigMapName = "maintenance"
+			cm := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "maintenance", Namespace: "kube-system"},
+				Data:       map[string]string{MaintenanceConfigMapKey: tt.maintenance},
+			}
+			if _, err := reaper.KubernetesClient.CoreV1().ConfigMaps("kube-system").Create(context.Background(), cm, metav1.CreateOptions{}); err != nil {
+				t.Fatalf("failed to create maintenance configmap: %v", err)
+			}
+
+			// during maintenance indicated by the configmap, cordoned nodes are the nodes being drained
+			mocks := _maintenanceMocks(false)
+			mocks.Nodes[0].Unschedulable = true
+			testCase := ReaperUnitTest{
+				TestDescription:         "During maintenance indicated by a configmap only PDBs blocking cordoned nodes are reaped",
+				FakeReaper:              reaper,
+				Mocks:                   mocks,
+				ExpectedReapableBudgets: tt.expectedReaped,
+				ExpectedReapedBudgets:   tt.expectedReaped,
+			}
+			testCase.Run(t)
+		})
+	}
+}
+
+func TestDeletionOrder(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name          string
+		order         string
+		expectedOrder []string
+	}{
+		{"OldestFirst", DeletionOrderOldestFirst, []string{"namespace-2/pdb-2", "namespace-3/pdb-3"}},
+		{"MostPodsFirst", DeletionOrderMostPodsFirst, []string{"namespace-1/pdb-1", "namespace-3/pdb-3"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			newest := _mockPDB("pdb-1", "namespace-1", nil, &intStrZeroInt, _selector("app=app-1"), 5, 0)
+			newest.CreationTimestamp = metav1.Time{Time: now.Add(-time.Hour)}
+			oldest := _mockPDB("pdb-2", "namespace-2", nil, &intStrZeroInt, _selector("app=app-2"), 1, 0)
+			oldest.CreationTimestamp = metav1.Time{Time: now.AddDate(-1, 0, 0)}
+			middle := _mockPDB("pdb-3", "namespace-3", nil, &intStrZeroInt, _selector("app=app-3"), 3, 0)
+			middle.CreationTimestamp = metav1.Time{Time: now.AddDate(0, -1, 0)}
+
+			reaper := _fakeReaperContext()
+			output := &bytes.Buffer{}
+			reaper.NDJSON = true
+			reaper.Output = output
+			reaper.DeletionOrder = tt.order
+			reaper.MaxReapsPerRun = 2
+			testCase := ReaperUnitTest{
+				TestDescription: "Reapable PDBs are deleted in the configured order up to --max-reaps-per-run",
+				FakeReaper:      reaper,
+				Mocks: KubernetesMockAPI{
+					Namespaces: []MockNamespace{
+						_mockNamespace("namespace-1"),
+						_mockNamespace("namespace-2"),
+						_mockNamespace("namespace-3"),
+					},
+					PDBs: []MockPDB{newest, oldest, middle},
+				},
+				ExpectedReapableBudgets: 3,
+				ExpectedReapedBudgets:   2,
+			}
+			testCase.Run(t)
+
+			deleted := make([]string, 0)
+			for _, line := range strings.Split(strings.TrimSpace(output.String()), "\n") {
+				var record ActionRecord
+				if err := json.Unmarshal([]byte(line), &record); err != nil {
+					t.Fatalf("failed to parse NDJSON line %q: %v", line, err)
+				}
+				if record.Type == RecordTypeDeletion {
+					deleted = append(deleted, record.PDB)
+				}
+			}
+			if strings.Join(deleted, ",") != strings.Join(tt.expectedOrder, ",") {
+				t.Fatalf("expected deletion order %v, got %v", tt.expectedOrder, deleted)
+			}
+		})
+	}
+}
+
+func TestNamespaceFairness(t *testing.T) {
+	reaper := _fakeReaperContext()
+	reaper.MaxReapsPerRun = 3
+	reaper.NamespaceFairness = true
+	mocks := KubernetesMockAPI{}
+	for namespace, count := range map[string]int{"namespace-1": 3, "namespace-2": 2, "namespace-3": 1} {
+		mocks.Namespaces = append(mocks.Namespaces, _mockNamespace(namespace))
+		for i := 1; i <= count; i++ {
+			app := fmt.Sprintf("app-%v", i)
+			mocks.PDBs = append(mocks.PDBs, _mockPDB("pdb-"+app, namespace, nil, &intStrZeroInt, _selector("app="+app), 1, 0))
+		}
+	}
+	testCase := ReaperUnitTest{
+		TestDescription:         "A capped reap is spread across namespaces",
+		FakeReaper:              reaper,
+		Mocks:                   mocks,
+		ExpectedReapableBudgets: 6,
+		ExpectedReapedBudgets:   3,
+	}
+	testCase.Run(t)
+
+	for _, namespace := range []string{"namespace-1", "namespace-2", "namespace-3"} {
+		pdbs, err := reaper.KubernetesClient.PolicyV1().PodDisruptionBudgets(namespace).List(context.Background(), metav1.ListOptions{})
+		if err != nil {
+			t.Fatalf("failed to list PDBs: %v", err)
+		}
+		expected := map[string]int{"namespace-1": 2, "namespace-2": 1, "namespace-3": 0}[namespace]
+		if len(pdbs.Items) != expected {
+			t.Fatalf("expected one PDB to be reaped in %v leaving %v, got: %v", namespace, expected, len(pdbs.Items))
+		}
+	}
+}
+
+func TestRoundRobinByNamespace(t *testing.T) {
+	pdbs := []policyv1.PodDisruptionBudget{
+		{ObjectMeta: metav1.ObjectMeta{Name: "pdb-1", Namespace: "namespace-1"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "pdb-2", Namespace: "namespace-1"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "pdb-3", Namespace: "namespace-1"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "pdb-1", Namespace: "namespace-2"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "pdb-1", Namespace: "namespace-3"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "pdb-2", Namespace: "namespace-3"}},
+	}
+	expected := []string{"namespace-1/pdb-1", "namespace-2/pdb-1", "namespace-3/pdb-1", "namespace-1/pdb-2", "namespace-3/pdb-2", "namespace-1/pdb-3"}
+	if got := pdbSliceNamespacedNames(roundRobinByNamespace(pdbs)); strings.Join(got, ",") != strings.Join(expected, ",") {
+		t.Fatalf("expected order %v, got %v", expected, got)
+	}
+}
+
+func _thresholdAnnotationMocks(crashloop bool, annotations map[string]map[string]string) KubernetesMockAPI {
+	mocks := KubernetesMockAPI{}
+	for i, name := range []string{"pdb-1", "pdb-2", "pdb-3"} {
+		namespace := fmt.Sprintf("namespace-%v", i+1)
+		app := fmt.Sprintf("app-%v", i+1)
+		pdb := _mockPDB(name, namespace, nil, &intStrOneInt, _selector("app="+app), 2, 0)
+		pdb.Annotations = annotations[name]
+		mocks.Namespaces = append(mocks.Namespaces, _mockNamespace(namespace))
+		mocks.PDBs = append(mocks.PDBs, pdb)
+		mocks.Pods = append(mocks.Pods, _mockPod(fmt.Sprintf("pod-%v", i+1), namespace, map[string]string{"app": app}, crashloop, 3, !crashloop))
+	}
+	return mocks
+}
+
+func TestCrashLoopThresholdAnnotation(t *testing.T) {
+	reaper := _fakeReaperContext()
+	reaper.ReapNotReady = false
+	testCase := ReaperUnitTest{
+		TestDescription: "The crashloop-threshold annotation overrides --crashloop-restart-count for a PDB",
+		FakeReaper:      reaper,
+		Mocks: _thresholdAnnotationMocks(true, map[string]map[string]string{
+			"pdb-1": {CrashLoopThresholdAnnotationKey: "2"},
+			"pdb-3": {CrashLoopThresholdAnnotationKey: "often"},
+		}),
+		ExpectedReapableBudgets: 1,
+		ExpectedReapedBudgets:   1,
+	}
+	testCase.Run(t)
+
+	if _, ok := reaper.ReapableReasons["namespace-1/pdb-1"]; !ok {
+		t.Fatalf("expected PDB namespace-1/pdb-1 with a lower crashloop threshold to be reapable")
+	}
+}
+
+func TestNotReadyThresholdAnnotation(t *testing.T) {
+	reaper := _fakeReaperContext()
+	reaper.ReapNotReadyThreshold = 3600
+	testCase := ReaperUnitTest{
+		TestDescription: "The not-ready-threshold annotation overrides --not-ready-threshold-seconds for a PDB",
+		FakeReaper:      reaper,
+		Mocks: _thresholdAnnotationMocks(false, map[string]map[string]string{
+			"pdb-1": {NotReadyThresholdAnnotationKey: "30"},
+			"pdb-3": {NotReadyThresholdAnnotationKey: "-30"},
+		}),
+		ExpectedReapableBudgets: 1,
+		ExpectedReapedBudgets:   1,
+	}
+	testCase.Run(t)
+
+	if _, ok := reaper.ReapableReasons["namespace-1/pdb-1"]; !ok {
+		t.Fatalf("expected PDB namespace-1/pdb-1 with a lower not-ready threshold to be reapable")
+	}
+}
+
+func TestCleanupAnnotationsPreservesThresholds(t *testing.T) {
+	reaper := _fakeReaperContext()
+	reaper.CleanupAnnotations = true
+	testCase := ReaperUnitTest{
+		TestDescription: "Threshold annotations set by owners are not removed by --cleanup-annotations",
+		FakeReaper:      reaper,
+		Mocks: _thresholdAnnotationMocks(false, map[string]map[string]string{
+			"pdb-1": {NotReadyThresholdAnnotationKey: "30", WouldReapReasonAnnotationKey: ReasonBlocking.String()},
+		}),
+	}
+	testCase.Run(t)
+
+	pdb := _getPDB(t, reaper, "namespace-1", "pdb-1")
+	if _, ok := pdb.Annotations[WouldReapReasonAnnotationKey]; ok {
+		t.Fatalf("expected managed annotation %v to be removed", WouldReapReasonAnnotationKey)
+	}
+	if pdb.Annotations[NotReadyThresholdAnnotationKey] != "30" {
+		t.Fatalf("expected threshold annotation to be preserved, got: %v", pdb.Annotations)
+	}
+}
+
+func TestReapableAndReapedCountMetrics(t *testing.T) {
+	metrics := &fakeMetricsAPI{}
+	reaper := _fakeReaperContext()
+	reaper.MetricsAPI = metrics
+	reaper.MaxReapsPerRun = 1
+	testCase := ReaperUnitTest{
+		TestDescription: "Reapable and reaped counts are exposed separately so deferred PDBs are visible",
+		FakeReaper:      reaper,
+		Mocks: KubernetesMockAPI{
+			Namespaces: []MockNamespace{
+				_mockNamespace("namespace-1"),
+				_mockNamespace("namespace-2"),
+				_mockNamespace("namespace-3"),
+			},
+			PDBs: []MockPDB{
+				_mockPDB("pdb-1", "namespace-1", nil, &intStrZeroInt, _selector("app=app-1"), 1, 0),
+				_mockPDB("pdb-2", "namespace-2", nil, &intStrZeroInt, _selector("app=app-2"), 1, 0),
+				_mockPDB("pdb-3", "namespace-3", nil, &intStrZeroInt, _selector("app=app-3"), 1, 0),
+			},
+		},
+		ExpectedReapableBudgets: 3,
+		ExpectedReapedBudgets:   1,
+	}
+	testCase.Run(t)
+
+	if value, ok := metrics.lastValue(PdbReaperReapableCountMetricName, nil); !ok || value != 3 {
+		t.Fatalf("expected %v to be 3, got %v (pushed: %t)", PdbReaperReapableCountMetricName, value, ok)
+	}
+	if value, ok := metrics.lastValue(PdbReaperReapedCountMetricName, nil); !ok || value != 1 {
+		t.Fatalf("expected %v to be 1, got %v (pushed: %t)", PdbReaperReapedCountMetricName, value, ok)
+	}
+}
+
+func _staleStatusMocks(staleCount int) KubernetesMockAPI {
+	mocks := KubernetesMockAPI{}
+	for i := 1; i <= 4; i++ {
+		namespace := fmt.Sprintf("namespace-%v", i)
+		pdb := _mockPDB(fmt.Sprintf("pdb-%v", i), namespace, nil, &intStrZeroInt, _selector(fmt.Sprintf("app=app-%v", i)), 1, 0)
+		pdb.Generation = 2
+		pdb.ObservedGeneration = 2
+		if i <= staleCount {
+			pdb.ObservedGeneration = 1
+		}
+		mocks.Namespaces = append(mocks.Namespaces, _mockNamespace(namespace))
+		mocks.PDBs = append(mocks.PDBs, pdb)
+	}
+	return mocks
+}
+
+func TestCheckDisruptionController(t *testing.T) {
+	tests := []struct {
+		name          string
+		staleCount    int
+		expectedReap  int
+		expectedStale float64
+	}{
+		{"Healthy", 1, 4, 0},
+		{"Stale", 2, 0, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metrics := &fakeMetricsAPI{}
+			reaper := _fakeReaperContext()
+			reaper.MetricsAPI = metrics
+			reaper.CheckDisruptionController = true
+			reaper.StaleStatusRatio = DefaultStaleStatusRatio
+			testCase := ReaperUnitTest{
+				TestDescription:         "Reaping is skipped when the status of too many PDBs is stale",
+				FakeReaper:              reaper,
+				Mocks:                   _staleStatusMocks(tt.staleCount),
+				ExpectedReapableBudgets: tt.expectedReap,
+				ExpectedReapedBudgets:   tt.expectedReap,
+			}
+			testCase.Run(t)
+
+			if value, ok := metrics.lastValue(PdbReaperStaleStatusMetricName, nil); !ok || value != tt.expectedStale {
+				t.Fatalf("expected %v to be %v, got %v (pushed: %t)", PdbReaperStaleStatusMetricName, tt.expectedStale, value, ok)
+			}
+		})
+	}
+}
+
+func TestCheckDisruptionControllerDisabled(t *testing.T) {
+	testCase := ReaperUnitTest{
+		TestDescription:         "Stale PDB status does not skip reaping unless --check-disruption-controller is set",
+		FakeReaper:              _fakeReaperContext(),
+		Mocks:                   _staleStatusMocks(4),
+		ExpectedReapableBudgets: 4,
+		ExpectedReapedBudgets:   4,
+	}
+	testCase.Run(t)
+}
+
+func _runSummaryEvent(t *testing.T, reaper *ReaperContext) RunResult {
+	events, err := reaper.KubernetesClient.CoreV1().Events("kube-system").List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("failed to list events: %v", err)
+	}
+	if len(events.Items) != 1 {
+		t.Fatalf("expected a single run summary event, got %v events", len(events.Items))
+	}
+	event := events.Items[0]
+	if event.Reason != EventReasonRunSummary || event.InvolvedObject.Kind != "ConfigMap" || event.InvolvedObject.Name != "pdb-reaper" {
+		t.Fatalf("unexpected run summary event %+v", event)
+	}
+
+	var result RunResult
+	if err := json.Unmarshal([]byte(event.Annotations[RunResultAnnotationKey]), &result); err != nil {
+		t.Fatalf("failed to parse run result annotation: %v", err)
+	}
+	return result
+}
+
+func TestRunSummaryEvent(t *testing.T) {
+	reaper := _fakeReaperContext()
+	reaper.SummaryEventObject = &corev1.ObjectReference{Kind: "ConfigMap", Namespace: "kube-system", Name: "pdb-reaper"}
+	testCase := ReaperUnitTest{
+		TestDescription: "A run summary event carries the run result as a JSON annotation",
+		FakeReaper:      reaper,
+		Mocks: KubernetesMockAPI{
+			Namespaces: []MockNamespace{
+				_mockNamespace("namespace-1"),
+				_mockNamespace("namespace-2"),
+			},
+			PDBs: []MockPDB{
+				_mockPDB("pdb-1", "namespace-1", nil, &intStrZeroInt, _selector("app=app-1"), 1, 0),
+				_mockPDB("pdb-2", "namespace-2", nil, &intStrOneInt, _selector("app=app-2"), 2, 1),
+			},
+		},
+		ExpectedReapableBudgets: 1,
+		ExpectedReapedBudgets:   1,
+	}
+	testCase.Run(t)
+
+	result := _runSummaryEvent(t, reaper)
+	if result.Scanned != 2 || result.Reapable != 1 || result.Reaped != 1 || result.Truncated {
+		t.Fatalf("unexpected run result %+v", result)
+	}
+	if reasons := result.Reasons["namespace-1/pdb-1"]; !containsReason(reasons, ReasonBlocking) {
+		t.Fatalf("expected namespace-1/pdb-1 to be reapable due to %v, got %v", ReasonBlocking, reasons)
+	}
+	if len(result.Deleted) != 1 || result.Deleted[0] != "namespace-1/pdb-1" {
+		t.Fatalf("expected namespace-1/pdb-1 to be deleted, got %v", result.Deleted)
+	}
+}
+
+func TestDumpEvents(t *testing.T) {
+	reaper := _fakeReaperContext()
+	var output bytes.Buffer
+	previousOut := log.Out
+	log.Out = &output
+	defer func() {
+		log.Out = previousOut
+	}()
+	reaper.DryRun = true
+	reaper.DumpEvents = true
+	reaper.NamespaceFindingsEvents = true
+	reaper.SummaryEventObject = &corev1.ObjectReference{Kind: "ConfigMap", Namespace: "kube-system", Name: "pdb-reaper"}
+	var created int
+	reaper.KubernetesClient.(*fake.Clientset).PrependReactor("create", "events", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		created++
+		return false, nil, nil
+	})
+	testCase := ReaperUnitTest{
+		TestDescription: "With --dump-events a dry-run captures the events in the run result instead of creating them",
+		FakeReaper:      reaper,
+		Mocks: KubernetesMockAPI{
+			Namespaces: []MockNamespace{
+				_mockNamespace("namespace-1"),
+			},
+			PDBs: []MockPDB{
+				_mockPDB("pdb-1", "namespace-1", nil, &intStrZeroInt, _selector("app=app-1"), 1, 0),
+			},
+		},
+		ExpectedReapableBudgets: 1,
+		ExpectedReapedBudgets:   0,
+	}
+	testCase.Run(t)
+
+	if created != 0 {
+		t.Fatalf("expected no events to be created, got: %v", created)
+	}
+	if !strings.Contains(output.String(), "run result:") {
+		t.Fatalf("expected the run result to be logged, got: %v", output.String())
+	}
+
+	result := reaper.newRunResult()
+	if len(result.Events) != 2 {
+		t.Fatalf("expected the run result to list 2 events, got: %+v", result.Events)
+	}
+	event := result.Events[0]
+	if event.PDB != "namespace-1/pdb-1" || event.Reason != EventReasonBlockingDetected || event.Type != "Normal" || event.Message != fmt.Sprintf(EventMessageBlockingFmt, "namespace-1/pdb-1") {
+		t.Fatalf("unexpected dumped event %+v", event)
+	}
+	event = result.Events[1]
+	if event.Namespace != "namespace-1" || event.Reason != EventReasonNamespaceFindings || event.Type != "Normal" {
+		t.Fatalf("unexpected dumped findings event %+v", event)
+	}
+}
+
+func TestRunSummaryEventTruncated(t *testing.T) {
+	maxBytes := runResultMaxBytes
+	runResultMaxBytes = 150
+	defer func() {
+		runResultMaxBytes = maxBytes
+	}()
+
+	reaper := _fakeReaperContext()
+	reaper.SummaryEventObject = &corev1.ObjectReference{Kind: "ConfigMap", Namespace: "kube-system", Name: "pdb-reaper"}
+	testCase := ReaperUnitTest{
+		TestDescription:         "The per-PDB details of a run result which exceeds the size limit are dropped",
+		FakeReaper:              reaper,
+		Mocks:                   _staleStatusMocks(0),
+		ExpectedReapableBudgets: 4,
+		ExpectedReapedBudgets:   4,
+	}
+	testCase.Run(t)
+
+	result := _runSummaryEvent(t, reaper)
+	if !result.Truncated || result.Reasons != nil || result.Deleted != nil {
+		t.Fatalf("expected a truncated run result, got %+v", result)
+	}
+	if result.Scanned != 4 || result.Reaped != 4 {
+		t.Fatalf("expected counts to be kept in a truncated run result, got %+v", result)
+	}
+}
+
+func _intstrPDB(minAvailable, maxUnavailable *intstr.IntOrString) policyv1.PodDisruptionBudget {
+	return policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{Name: "pdb", Namespace: "namespace-1"},
+		Spec:       policyv1.PodDisruptionBudgetSpec{MinAvailable: minAvailable, MaxUnavailable: maxUnavailable},
+		Status:     policyv1.PodDisruptionBudgetStatus{ExpectedPods: 2},
+	}
+}
+
+func TestIsMisconfiguredMalformedValues(t *testing.T) {
+	str := func(s string) *intstr.IntOrString { v := intstr.FromString(s); return &v }
+	num := func(i int) *intstr.IntOrString { v := intstr.FromInt(i); return &v }
+	tests := []struct {
+		name           string
+		minAvailable   *intstr.IntOrString
+		maxUnavailable *intstr.IntOrString
+		expected       bool
+	}{
+		{"MaxUnavailableEmpty", nil, str(""), true},
+		{"MaxUnavailableNotPercent", nil, str("abc"), true},
+		{"MaxUnavailableBadPercent", nil, str("x%"), true},
+		{"MaxUnavailableNegative", nil, num(-1), true},
+		{"MaxUnavailableNegativePercent", nil, str("-50%"), true},
+		{"MaxUnavailableOverPercent", nil, str("150%"), false},
+		{"MaxUnavailableValid", nil, num(1), false},
+		{"MinAvailableEmpty", str(""), nil, true},
+		{"MinAvailableOverflowPercent", str("99999999999999999999%"), nil, true},
+		{"MinAvailableOverPercent", str("150%"), nil, true},
+		{"MinAvailableOverExpected", num(3), nil, true},
+		{"MinAvailableNegative", num(-1), nil, false},
+		{"MinAvailableValid", str("50%"), nil, false},
+		{"InvalidType", &intstr.IntOrString{Type: 5}, nil, true},
+	}
+	pods := make([]corev1.Pod, 2)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			misconfigured, err := isMisconfigured(_intstrPDB(tt.minAvailable, tt.maxUnavailable), pods)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if misconfigured != tt.expected {
+				t.Fatalf("expected misconfigured: %v, got: %v", tt.expected, misconfigured)
+			}
+		})
+	}
+}
+
+func FuzzIsMisconfigured(f *testing.F) {
+	for _, seed := range []string{"", "0", "-1", "50%", "150%", "-10%", "%", "1e3%", "99999999999999999999%"} {
+		f.Add(seed, true, 2)
+		f.Add(seed, false, 0)
+	}
+	f.Fuzz(func(t *testing.T, value string, useMinAvailable bool, podCount int) {
+		if podCount < 0 || podCount > 100 {
+			t.Skip()
+		}
+		v := intstr.Parse(value)
+		pdb := _intstrPDB(nil, &v)
+		if useMinAvailable {
+			pdb = _intstrPDB(&v, nil)
+		}
+		if _, err := isMisconfigured(pdb, make([]corev1.Pod, podCount)); err != nil {
+			t.Fatalf("unexpected error for value '%v': %v", value, err)
+		}
+	})
+}
+
+func TestPDBLabelRequired(t *testing.T) {
+	reaper := _fakeReaperContext()
+	reaper.RequiredPDBLabel = "pdb-reaper/managed=true"
+	labeled := _mockPDB("pdb-1", "namespace-1", nil, &intStrZeroInt, _selector("app=app-1"), 1, 0)
+	labeled.Labels = map[string]string{"pdb-reaper/managed": "true"}
+	optedOut := _mockPDB("pdb-3", "namespace-1", nil, &intStrZeroInt, _selector("app=app-1"), 1, 0)
+	optedOut.Labels = map[string]string{"pdb-reaper/managed": "false"}
+	testCase := ReaperUnitTest{
+		TestDescription: "Only PDBs carrying --pdb-label-required are processed, other PDBs are ignored",
+		FakeReaper:      reaper,
+		Mocks: KubernetesMockAPI{
+			Namespaces: []MockNamespace{
+				_mockNamespace("namespace-1"),
+			},
+			PDBs: []MockPDB{
+				labeled,
+				_mockPDB("pdb-2", "namespace-1", nil, &intStrZeroInt, _selector("app=app-1"), 1, 0),
+				optedOut,
+			},
+			Pods: []MockPod{
+				_mockPod("pod-1", "namespace-1", map[string]string{"app": "app-1"}, false, 0, false),
+			},
+		},
+		ExpectedReapableBudgets: 1,
+		ExpectedReapedBudgets:   1,
+	}
+	testCase.Run(t)
+
+	if reaper.ScannedPodDisruptionBudgetsCount != 1 {
+		t.Fatalf("assertion failed, expected scanned: 1, got: %v", reaper.ScannedPodDisruptionBudgetsCount)
+	}
+	if len(reaper.NamespacesWithMultiplePodDisruptionBudgets) != 0 {
+		t.Fatalf("assertion failed, expected unlabeled PDBs to be excluded from multiple PDB detection")
+	}
+	for _, name := range []string{"pdb-2", "pdb-3"} {
+		if _, err := reaper.KubernetesClient.PolicyV1().PodDisruptionBudgets("namespace-1").Get(context.Background(), name, metav1.GetOptions{}); err != nil {
+			t.Fatalf("expected PDB %v without the required label to not be deleted: %v", name, err)
+		}
+	}
+}
+
+func TestReportWebhookOnErrorProxy(t *testing.T) {
+	proxied := make([]string, 0)
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = append(proxied, r.URL.String())
+	}))
+	defer proxy.Close()
+
+	reaper := _fakeReaperContext()
+	reaper.ErrorWebhookURL = "http://hooks.example.com/pdb-reaper"
+	transport, err := newHTTPTransport(proxy.URL, "")
+	if err != nil {
+		t.Fatalf("failed to create transport: %v", err)
+	}
+	reaper.HTTPTransport = transport
+
+	if err := reaper.reportErrorWebhook(errors.New("connection refused")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(proxied) != 1 || proxied[0] != reaper.ErrorWebhookURL {
+		t.Fatalf("assertion failed, expected the error report to be sent through the proxy, got: %v", proxied)
+	}
+}
+
+func TestReportWebhookOnErrorCABundle(t *testing.T) {
+	called := false
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer server.Close()
+
+	reaper := _fakeReaperContext()
+	reaper.ErrorWebhookURL = server.URL
+	if err := reaper.reportErrorWebhook(errors.New("connection refused")); err == nil {
+		t.Fatalf("assertion failed, expected the webhook certificate to not be trusted without a CA bundle")
+	}
+
+	caBundle := filepath.Join(t.TempDir(), "ca.pem")
+	data := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(caBundle, data, 0600); err != nil {
+		t.Fatalf("failed to write CA bundle: %v", err)
+	}
+	transport, err := newHTTPTransport("", caBundle)
+	if err != nil {
+		t.Fatalf("failed to create transport: %v", err)
+	}
+	reaper.HTTPTransport = transport
+
+	if err := reaper.reportErrorWebhook(errors.New("connection refused")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !called {
+		t.Fatalf("assertion failed, expected the error webhook to be called")
+	}
+}
+
+func TestProtectedPriorityClasses(t *testing.T) {
+	reaper := _fakeReaperContext()
+	reaper.ProtectedPriorityClasses = DefaultProtectedPriorityClasses
+	criticalPod := _mockPod("pod-1", "namespace-1", map[string]string{"app": "app-1"}, false, 0, false)
+	criticalPod.PriorityClassName = "system-cluster-critical"
+	normalPod := _mockPod("pod-2", "namespace-2", map[string]string{"app": "app-2"}, false, 0, false)
+	normalPod.PriorityClassName = "high-priority"
+	testCase := ReaperUnitTest{
+		TestDescription: "PDBs selecting pods with a protected priority class are spared, other PDBs are reaped",
+		FakeReaper:      reaper,
+		Mocks: KubernetesMockAPI{
+			Namespaces: []MockNamespace{
+				_mockNamespace("namespace-1"),
+				_mockNamespace("namespace-2"),
+			},
+			PDBs: []MockPDB{
+				_mockPDB("pdb-1", "namespace-1", nil, &intStrZeroInt, _selector("app=app-1"), 1, 0),
+				_mockPDB("pdb-2", "namespace-2", nil, &intStrZeroInt, _selector("app=app-2"), 1, 0),
+			},
+			Pods: []MockPod{criticalPod, normalPod},
+		},
+		ExpectedReapableBudgets: 2,
+		ExpectedReapedBudgets:   1,
+	}
+	testCase.Run(t)
+
+	if _, err := reaper.KubernetesClient.PolicyV1().PodDisruptionBudgets("namespace-1").Get(context.Background(), "pdb-1", metav1.GetOptions{}); err != nil {
+		t.Fatalf("expected PDB of critical pods to not be deleted: %v", err)
+	}
+}
+
+func TestProtectedPriorityClassesDisabled(t *testing.T) {
+	reaper := _fakeReaperContext()
+	criticalPod := _mockPod("pod-1", "namespace-1", map[string]string{"app": "app-1"}, false, 0, false)
+	criticalPod.PriorityClassName = "system-node-critical"
+	testCase := ReaperUnitTest{
+		TestDescription: "PDBs of critical pods are reaped when no priority classes are protected",
+		FakeReaper:      reaper,
+		Mocks: KubernetesMockAPI{
+			Namespaces: []MockNamespace{
+				_mockNamespace("namespace-1"),
+			},
+			PDBs: []MockPDB{
+				_mockPDB("pdb-1", "namespace-1", nil, &intStrZeroInt, _selector("app=app-1"), 1, 0),
+			},
+			Pods: []MockPod{criticalPod},
+		},
+		ExpectedReapableBudgets: 1,
+		ExpectedReapedBudgets:   1,
+	}
+	testCase.Run(t)
+}
+
+func TestInspect(t *testing.T) {
+	reaper := _fakeReaperContext()
+	output := &bytes.Buffer{}
+	reaper.InspectPDB = "namespace-1/pdb-1"
+	reaper.Output = output
+	metrics := &fakeMetricsAPI{}
+	reaper.MetricsAPI = metrics
+	testCase := ReaperUnitTest{
+		FakeReaper: reaper,
+		Mocks: KubernetesMockAPI{
+			Namespaces: []MockNamespace{
+				_mockNamespace("namespace-1"),
+			},
+			PDBs: []MockPDB{
+				_mockPDB("pdb-1", "namespace-1", nil, &intStrOneInt, _selector("app=app-1"), 1, 0),
+				_mockPDB("pdb-2", "namespace-1", nil, &intStrOneInt, _selector("app=app-2"), 1, 0),
+			},
+			Pods: []MockPod{
+				_mockPod("pod-1", "namespace-1", map[string]string{"app": "app-1"}, true, 6, false),
+				_mockPod("pod-2", "namespace-1", map[string]string{"app": "app-2"}, true, 6, false),
+			},
+		},
+	}
+	_fakeAPI(&testCase)
+
+	if err := reaper.execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var report InspectReport
+	if err := json.Unmarshal(output.Bytes(), &report); err != nil {
+		t.Fatalf("failed to parse inspect report: %v", err)
+	}
+	if report.PDB != "namespace-1/pdb-1" || report.DisruptionsAllowed != 0 || report.ExpectedPods != 1 || report.MaxUnavailable != "1" {
+		t.Fatalf("expected the status of namespace-1/pdb-1, got: %+v", report)
+	}
+	if strings.Join(report.MatchedPods, ",") != "namespace-1/pod-1" {
+		t.Fatalf("expected matched pods [namespace-1/pod-1], got: %v", report.MatchedPods)
+	}
+	if len(report.Rules) != len(ReapModes) {
+		t.Fatalf("expected a result for each of %v reap modes, got: %+v", len(ReapModes), report.Rules)
+	}
+	for _, rule := range report.Rules {
+		switch rule.Rule {
+		case ReapModeCrashLoop:
+			if !rule.Enabled || !rule.Matched || rule.Details != "1/1 pods crashlooping with at least 5 restarts, pod fraction 0" {
+				t.Fatalf("expected the crashloop rule to match, got: %+v", rule)
+			}
+		case ReapModeMisconfigured, ReapModeNotReady, ReapModeMultiple:
+			if rule.Matched {
+				t.Fatalf("expected the %v rule to not match, got: %+v", rule.Rule, rule)
+			}
+		}
+	}
+	if len(report.Reasons) != 1 || report.Reasons[0] != ReasonBlockingCrashLoop {
+		t.Fatalf("expected reasons [%v], got: %v", ReasonBlockingCrashLoop, report.Reasons)
+	}
+	if report.Action != InspectActionDelete {
+		t.Fatalf("expected action %v, got: %v (%v)", InspectActionDelete, report.Action, report.ActionDetails)
+	}
+
+	// inspect has no side effects
+	pdbs, err := reaper.KubernetesClient.PolicyV1().PodDisruptionBudgets("namespace-1").List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("failed to list PDBs: %v", err)
+	}
+	if len(pdbs.Items) != 2 {
+		t.Fatalf("expected no PDBs to be deleted, got %v PDBs", len(pdbs.Items))
+	}
+	events, err := reaper.KubernetesClient.CoreV1().Events("namespace-1").List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("failed to list events: %v", err)
+	}
+	if len(events.Items) != 0 || len(metrics.Metrics) != 0 {
+		t.Fatalf("expected no events or metrics, got %v events and %v metrics", len(events.Items), len(metrics.Metrics))
+	}
+}
+
+func TestInspectSharesRunRules(t *testing.T) {
+	inspect := func(t *testing.T, reaper *ReaperContext, pdbs []MockPDB, pods []MockPod) InspectReport {
+		output := &bytes.Buffer{}
+		reaper.InspectPDB = "namespace-1/pdb-1"
+		reaper.Output = output
+		testCase := ReaperUnitTest{
+			FakeReaper: reaper,
+			Mocks: KubernetesMockAPI{
+				Namespaces: []MockNamespace{
+					_mockNamespace("namespace-1"),
+				},
+				PDBs: pdbs,
+				Pods: pods,
+			},
+		}
+		_fakeAPI(&testCase)
+		if err := reaper.execute(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var report InspectReport
+		if err := json.Unmarshal(output.Bytes(), &report); err != nil {
+			t.Fatalf("failed to parse inspect report: %v", err)
+		}
+		return report
+	}
+
+	t.Run("ForbiddenPods", func(t *testing.T) {
+		reaper := _fakeReaperContext()
+		reaper.KubernetesClient.(*fake.Clientset).PrependReactor("list", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			return true, nil, kerrors.NewForbidden(schema.GroupResource{Resource: "pods"}, "", errors.New("pods is forbidden"))
+		})
+		report := inspect(t, reaper, []MockPDB{
+			_mockPDB("pdb-1", "namespace-1", nil, &intStrOneInt, _selector("app=app-1"), 1, 0),
+		}, []MockPod{
+			_mockPod("pod-1", "namespace-1", map[string]string{"app": "app-1"}, true, 6, false),
+		})
+		for _, rule := range report.Rules {
+			if rule.Rule == ReapModeCrashLoop && rule.Matched {
+				t.Fatalf("expected the crashloop rule to not match without pods, got: %+v", rule)
+			}
+		}
+		if report.Action != InspectActionNone {
+			t.Fatalf("expected action %v, got: %v (%v)", InspectActionNone, report.Action, report.ActionDetails)
+		}
+	})
+
+	t.Run("DrainBlockingOnly", func(t *testing.T) {
+		reaper := _fakeReaperContext()
+		reaper.DrainBlockingOnly = true
+		report := inspect(t, reaper, []MockPDB{
+			_mockPDB("pdb-1", "namespace-1", nil, &intStrOneInt, _selector("app=app-1"), 1, 0),
+			_mockPDB("pdb-2", "namespace-1", nil, &intStrOneInt, _selector("app=app-1"), 1, 0),
+		}, []MockPod{
+			_mockPod("pod-1", "namespace-1", map[string]string{"app": "app-1"}, true, 6, false),
+		})
+		for _, rule := range report.Rules {
+			if rule.Matched {
+				t.Fatalf("expected no rule to match without pods on cordoned nodes, got: %+v", rule)
+			}
+		}
+		if len(report.Reasons) != 0 {
+			t.Fatalf("expected no reasons, got: %v", report.Reasons)
+		}
+	})
+}
+
+func TestDrainAssist(t *testing.T) {
+	reaper := _fakeReaperContext()
+	output := &bytes.Buffer{}
+	reaper.DrainAssistNode = "node-1"
+	reaper.Output = output
+	onNode := func(pod MockPod, node string) MockPod {
+		pod.NodeName = node
+		return pod
+	}
+	testCase := ReaperUnitTest{
+		FakeReaper: reaper,
+		Mocks: KubernetesMockAPI{
+			Namespaces: []MockNamespace{
+				_mockNamespace("namespace-1"),
+			},
+			PDBs: []MockPDB{
+				_mockPDB("pdb-blocking", "namespace-1", nil, &intStrZeroInt, _selector("app=app-1"), 2, 0),
+				_mockPDB("pdb-other-node", "namespace-1", nil, &intStrZeroInt, _selector("app=app-2"), 1, 0),
+				_mockPDB("pdb-allowing", "namespace-1", nil, &intStrOneInt, _selector("app=app-3"), 2, 1),
+				_mockPDB("pdb-partial", "namespace-1", nil, &intStrOneInt, _selector("app=app-4"), 3, 1),
+			},
+			Pods: []MockPod{
+				onNode(_mockPod("pod-1", "namespace-1", map[string]string{"app": "app-1"}, false, 0, false), "node-1"),
+				onNode(_mockPod("pod-2", "namespace-1", map[string]string{"app": "app-1"}, false, 0, false), "node-2"),
+				onNode(_mockPod("pod-3", "namespace-1", map[string]string{"app": "app-2"}, false, 0, false), "node-2"),
+				onNode(_mockPod("pod-4", "namespace-1", map[string]string{"app": "app-3"}, false, 0, false), "node-1"),
+				onNode(_mockPod("pod-5", "namespace-1", map[string]string{"app": "app-4"}, false, 0, false), "node-1"),
+				onNode(_mockPod("pod-6", "namespace-1", map[string]string{"app": "app-4"}, false, 0, false), "node-1"),
+			},
+		},
+	}
+	_fakeAPI(&testCase)
+
+	if err := reaper.execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var report DrainAssistReport
+	if err := json.Unmarshal(output.Bytes(), &report); err != nil {
+		t.Fatalf("failed to parse drain assist report: %v", err)
+	}
+	if report.Node != "node-1" || len(report.Blocking) != 2 {
+		t.Fatalf("expected 2 PDBs blocking node-1, got: %+v", report)
+	}
+	if report.Blocking[0].PDB != "namespace-1/pdb-blocking" || strings.Join(report.Blocking[0].Pods, ",") != "pod-1" {
+		t.Fatalf("expected pdb-blocking with pod-1, got: %+v", report.Blocking[0])
+	}
+	if report.Blocking[1].PDB != "namespace-1/pdb-partial" || strings.Join(report.Blocking[1].Pods, ",") != "pod-5,pod-6" {
+		t.Fatalf("expected pdb-partial with pod-5,pod-6, got: %+v", report.Blocking[1])
+	}
+
+	pdbs, err := reaper.KubernetesClient.PolicyV1().PodDisruptionBudgets("").List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("failed to list PDBs: %v", err)
+	}
+	if len(pdbs.Items) != 4 {
+		t.Fatalf("expected no PDBs to be deleted, got %v PDBs", len(pdbs.Items))
+	}
+}
+
+func TestRecreatedWithinWindow(t *testing.T) {
+	tests := []struct {
+		name          string
+		reapedAgo     time.Duration
+		window        time.Duration
+		expectedTotal float64
+	}{
+		{"WithinWindow", 10 * time.Minute, time.Hour, 1},
+		{"OutsideWindow", 2 * time.Hour, time.Hour, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+			metrics := &fakeMetricsAPI{}
+			reaper := _fakeReaperContext()
+			reaper.MetricsAPI = metrics
+			reaper.Clock = fakeClock{now}
+			reaper.RecreateWindow = tt.window
+			reaper.State.ReapedAt = map[string]time.Time{"namespace-1/pdb-1": now.Add(-tt.reapedAgo)}
+			recreated := _mockPDB("pdb-1", "namespace-1", nil, &intStrOneInt, _selector("app=app-1"), 1, 1)
+			recreated.CreationTimestamp = metav1.NewTime(now.Add(-5 * time.Minute))
+			testCase := ReaperUnitTest{
+				FakeReaper: reaper,
+				Mocks: KubernetesMockAPI{
+					Namespaces: []MockNamespace{
+						_mockNamespace("namespace-1"),
+					},
+					PDBs: []MockPDB{recreated},
+				},
+			}
+			testCase.Run(t)
+
+			if value, ok := metrics.lastValue(PdbReaperRecreatedMetricName, nil); !ok || value != tt.expectedTotal {
+				t.Fatalf("expected %v to be %v, got %v (pushed: %t)", PdbReaperRecreatedMetricName, tt.expectedTotal, value, ok)
+			}
+			events, err := reaper.KubernetesClient.CoreV1().Events("namespace-1").List(context.Background(), metav1.ListOptions{})
+			if err != nil {
+				t.Fatalf("failed to list events: %v", err)
+			}
+			warned := len(events.Items) == 1 && events.Items[0].Reason == EventReasonRecreatedDetected && events.Items[0].Type == "Warning"
+			if warned != (tt.expectedTotal > 0) {
+				t.Fatalf("expected recreated warning event: %t, got events: %+v", tt.expectedTotal > 0, events.Items)
+			}
+
+			// the same recreation is only counted once
+			if err := reaper.execute(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if value, _ := metrics.lastValue(PdbReaperRecreatedMetricName, nil); value != tt.expectedTotal {
+				t.Fatalf("expected %v to remain %v, got %v", PdbReaperRecreatedMetricName, tt.expectedTotal, value)
+			}
+		})
+	}
+}
+
+func TestRecreatedPushgateway(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	reaper := _fakeReaperContext()
+	pgw := _fakePushgateway(t)
+	reaper.MetricsAPI = common.NewPrometheusAPI(pgw.URL)
+	reaper.Clock = fakeClock{now}
+	reaper.RecreateWindow = time.Hour
+	reaper.State.ReapedAt = map[string]time.Time{"namespace-1/pdb-1": now.Add(-10 * time.Minute)}
+	recreated := _mockPDB("pdb-1", "namespace-1", nil, &intStrOneInt, _selector("app=app-1"), 1, 1)
+	recreated.CreationTimestamp = metav1.NewTime(now.Add(-5 * time.Minute))
+	testCase := ReaperUnitTest{
+		TestDescription: "Tests that the recreated metric is kept along the other cluster metrics on the pushgateway",
+		FakeReaper:      reaper,
+		Mocks: KubernetesMockAPI{
+			Namespaces: []MockNamespace{
+				_mockNamespace("namespace-1"),
+			},
+			PDBs: []MockPDB{recreated},
+		},
+	}
+	testCase.Run(t)
+
+	pgw.assertPushed(t, nil, map[string]float64{
+		PdbReaperRecreatedMetricName:     1,
+		PdbReaperReapableCountMetricName: 0,
+		PdbReaperReapedCountMetricName:   0,
+	})
+}
+
+func TestUpdateDebounce(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	reaper := _fakeReaperContext()
+	reaper.Clock = fakeClock{now}
+	reaper.DryRun = true
+	reaper.UpdateDebounce = 5 * time.Minute
+	testCase := ReaperUnitTest{
+		TestDescription: "Repeated updates of a PDB within --update-debounce collapse into a single evaluation",
+		FakeReaper:      reaper,
+		Mocks: KubernetesMockAPI{
+			Namespaces: []MockNamespace{
+				_mockNamespace("namespace-1"),
+			},
+			PDBs: []MockPDB{
+				_mockPDB("pdb-1", "namespace-1", nil, &intStrZeroInt, _selector("app=app-1"), 1, 0),
+				_mockPDB("pdb-2", "namespace-1", nil, &intStrZeroInt, _selector("app=app-2"), 1, 0),
+			},
+		},
+		ExpectedReapableBudgets: 2,
+		ExpectedReapedBudgets:   0,
+	}
+	testCase.Run(t)
+
+	update := func(resourceVersion string) {
+		pdbs := reaper.KubernetesClient.PolicyV1().PodDisruptionBudgets("namespace-1")
+		pdb, err := pdbs.Get(context.Background(), "pdb-1", metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("failed to get PDB: %v", err)
+		}
+		pdb.ResourceVersion = resourceVersion
+		if _, err := pdbs.Update(context.Background(), pdb, metav1.UpdateOptions{}); err != nil {
+			t.Fatalf("failed to update PDB: %v", err)
+		}
+	}
+	run := func(after time.Duration) {
+		reaper.Clock = fakeClock{now.Add(after)}
+		if err := reaper.execute(); err != nil {
+			t.Fatalf("execution failed: %v", err)
+		}
+	}
+
+	// updates within the debounce window hold back the evaluation of pdb-1, while pdb-2 is evaluated on every run
+	for i, after := range []time.Duration{time.Minute, 2 * time.Minute, 4 * time.Minute} {
+		update(strconv.Itoa(i + 2))
+		run(after)
+		if reaper.ScannedPodDisruptionBudgetsCount != 1 || reaper.ReapablePodDisruptionBudgetsCount != 1 {
+			t.Fatalf("expected only pdb-2 to be evaluated %v after the first run, got %v scanned and %v reapable", after, reaper.ScannedPodDisruptionBudgetsCount, reaper.ReapablePodDisruptionBudgetsCount)
+		}
+	}
+
+	// once the window has passed, the updates are evaluated once
+	run(5 * time.Minute)
+	if reaper.ScannedPodDisruptionBudgetsCount != 2 || reaper.ReapablePodDisruptionBudgetsCount != 2 {
+		t.Fatalf("expected both PDBs to be evaluated, got %v scanned and %v reapable", reaper.ScannedPodDisruptionBudgetsCount, reaper.ReapablePodDisruptionBudgetsCount)
+	}
+	if evaluated := reaper.State.Evaluated["namespace-1/pdb-1"]; evaluated.ResourceVersion != "4" || !evaluated.At.Equal(now.Add(5*time.Minute)) {
+		t.Fatalf("expected pdb-1 to be evaluated at resource version 4, got: %+v", evaluated)
+	}
+
+	// a PDB which didn't change since its last evaluation is evaluated again
+	run(6 * time.Minute)
+	if reaper.ScannedPodDisruptionBudgetsCount != 2 {
+		t.Fatalf("expected both PDBs to be evaluated, got %v scanned", reaper.ScannedPodDisruptionBudgetsCount)
+	}
+}
+
+func TestCSVOutput(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	reaper := _fakeReaperContext()
+	reaper.Clock = fakeClock{now}
+	reaper.CSVOutput = filepath.Join(t.TempDir(), "findings.csv")
+	reaper.MaxReapsPerRun = 1
+	reaper.DeletionOrder = DeletionOrderOldestFirst
+	newer := _mockPDB("pdb-1", "namespace-1", nil, &intStrZeroInt, _selector("app=app-1"), 1, 0)
+	newer.CreationTimestamp = metav1.NewTime(now.Add(-time.Hour))
+	older := _mockPDB("pdb-2", "namespace-2", &intStrHundredPercent, nil, _selector("app=app-2"), 2, 0)
+	older.CreationTimestamp = metav1.NewTime(now.Add(-2 * time.Hour))
+	testCase := ReaperUnitTest{
+		TestDescription: "Reapable PDBs are written to --csv-output with the action taken",
+		FakeReaper:      reaper,
+		Mocks: KubernetesMockAPI{
+			Namespaces: []MockNamespace{
+				_mockNamespace("namespace-1"),
+				_mockNamespace("namespace-2"),
+			},
+			PDBs: []MockPDB{newer, older},
+			Pods: []MockPod{
+				_mockPod("pod-1", "namespace-1", map[string]string{"app": "app-1"}, false, 0, false),
+				_mockPod("pod-2", "namespace-2", map[string]string{"app": "app-2"}, false, 0, false),
+				_mockPod("pod-3", "namespace-2", map[string]string{"app": "app-2"}, false, 0, false),
+			},
+		},
+		ExpectedReapableBudgets: 2,
+		ExpectedReapedBudgets:   1,
+	}
+	testCase.Run(t)
+
+	data, err := os.ReadFile(reaper.CSVOutput)
+	if err != nil {
+		t.Fatalf("failed to read CSV output: %v", err)
+	}
+	expected := strings.Join([]string{
+		"namespace,name,reason,matched_pods,resolved_budget,age,action",
+		"namespace-1,pdb-1,BlockingPodDisruptionBudget,1,maxUnavailable=0,1h0m0s,deferred",
+		"namespace-2,pdb-2,BlockingPodDisruptionBudget,2,minAvailable=2,2h0m0s,deleted",
+	}, "\n") + "\n"
+	if string(data) != expected {
+		t.Fatalf("expected CSV output:\n%v\ngot:\n%v", expected, string(data))
+	}
+}
+
+func TestSelectorMatchedNothing(t *testing.T) {
+	metrics := &fakeMetricsAPI{}
+	reaper := _fakeReaperContext()
+	reaper.MetricsAPI = metrics
+	testCase := ReaperUnitTest{
+		TestDescription: "PDBs expecting 0 pods are told apart by whether their selector references a label key no pod carries",
+		FakeReaper:      reaper,
+		Mocks: KubernetesMockAPI{
+			Namespaces: []MockNamespace{
+				_mockNamespace("namespace-1"),
+			},
+			PDBs: []MockPDB{
+				_mockPDB("pdb-unknown-key", "namespace-1", nil, &intStrOneInt, _selector("app=app-1,tier=web"), 0, 0),
+				_mockPDB("pdb-scaled-to-zero", "namespace-1", nil, &intStrOneInt, _selector("app=app-2"), 0, 0),
+				_mockPDB("pdb-not-in", "namespace-1", nil, &intStrOneInt, _selector("app=app-3,tier!=web"), 0, 0),
+			},
+			Pods: []MockPod{
+				_mockPod("pod-1", "namespace-1", map[string]string{"app": "app-1"}, false, 0, false),
+			},
+		},
+		ExpectedReapableBudgets: 0,
+		ExpectedReapedBudgets:   0,
+	}
+	testCase.Run(t)
+
+	expected := map[string]float64{"pdb-unknown-key": 1, "pdb-scaled-to-zero": 0, "pdb-not-in": 0}
+	for name, want := range expected {
+		value, ok := metrics.lastValue(PdbReaperSelectorMatchedNothingMetricName, map[string]string{"pdb": name})
+		if !ok || value != want {
+			t.Fatalf("expected %v of %v to be %v, got %v (pushed: %t)", PdbReaperSelectorMatchedNothingMetricName, name, want, value, ok)
+		}
+	}
+}
+
+func TestAnnotateWorkloads(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	reaper := _fakeReaperContext()
+	reaper.Clock = fakeClock{now}
+	reaper.AnnotateWorkloads = true
+	controller := true
+	ownedBy := func(kind, name string) []metav1.OwnerReference {
+		return []metav1.OwnerReference{{Kind: kind, Name: name, Controller: &controller}}
+	}
+	apps := reaper.KubernetesClient.AppsV1()
+	if _, err := apps.Deployments("namespace-1").Create(context.Background(), &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "namespace-1"}}, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("failed to create deployment: %v", err)
+	}
+	rs := &appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "namespace-1", OwnerReferences: ownedBy("Deployment", "web")}}
+	if _, err := apps.ReplicaSets("namespace-1").Create(context.Background(), rs, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("failed to create replicaset: %v", err)
+	}
+	for _, name := range []string{"db", "cache"} {
+		if _, err := apps.StatefulSets("namespace-1").Create(context.Background(), &appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "namespace-1"}}, metav1.CreateOptions{}); err != nil {
+			t.Fatalf("failed to create statefulset: %v", err)
+		}
+	}
+
+	webPod := _mockPod("pod-1", "namespace-1", map[string]string{"app": "web"}, false, 0, false)
+	webPod.OwnerReferences = ownedBy("ReplicaSet", "web-1")
+	dbPod := _mockPod("pod-2", "namespace-1", map[string]string{"app": "db"}, false, 0, false)
+	dbPod.OwnerReferences = ownedBy("StatefulSet", "db")
+	cachePod := _mockPod("pod-3", "namespace-1", map[string]string{"app": "cache"}, false, 0, false)
+	cachePod.OwnerReferences = ownedBy("StatefulSet", "cache")
+	testCase := ReaperUnitTest{
+		TestDescription: "The workloads owning the pods of reaped PDBs are annotated with the reaped PDB",
+		FakeReaper:      reaper,
+		Mocks: KubernetesMockAPI{
+			Namespaces: []MockNamespace{
+				_mockNamespace("namespace-1"),
+			},
+			PDBs: []MockPDB{
+				_mockPDB("pdb-web", "namespace-1", nil, &intStrZeroInt, _selector("app=web"), 1, 0),
+				_mockPDB("pdb-db", "namespace-1", nil, &intStrZeroInt, _selector("app=db"), 1, 0),
+				_mockPDB("pdb-cache", "namespace-1", nil, &intStrOneInt, _selector("app=cache"), 1, 1),
+			},
+			Pods: []MockPod{webPod, dbPod, cachePod},
+		},
+		ExpectedReapableBudgets: 2,
+		ExpectedReapedBudgets:   2,
+	}
+	testCase.Run(t)
+
+	deployment, err := apps.Deployments("namespace-1").Get(context.Background(), "web", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get deployment: %v", err)
+	}
+	if deployment.Annotations[LastReapedPDBAnnotationKey] != "pdb-web" || deployment.Annotations[LastReapedAtAnnotationKey] != "2024-01-01T12:00:00Z" {
+		t.Fatalf("expected deployment to be annotated with the reaped PDB, got: %v", deployment.Annotations)
+	}
+	db, err := apps.StatefulSets("namespace-1").Get(context.Background(), "db", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get statefulset: %v", err)
+	}
+	if db.Annotations[LastReapedPDBAnnotationKey] != "pdb-db" {
+		t.Fatalf("expected statefulset to be annotated with the reaped PDB, got: %v", db.Annotations)
+	}
+	cache, err := apps.StatefulSets("namespace-1").Get(context.Background(), "cache", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get statefulset: %v", err)
+	}
+	if _, ok := cache.Annotations[LastReapedPDBAnnotationKey]; ok {
+		t.Fatalf("expected workload of a PDB which was not reaped to not be annotated, got: %v", cache.Annotations)
+	}
+}
+
+func TestExcludedNamespacesConfigMap(t *testing.T) {
+	tests := []struct {
+		name             string
+		configMap        bool
+		expectedExcluded []string
+		expectedReapable int
+	}{
+		{"Merged", true, []string{"namespace-1", "namespace-2", "namespace-3"}, 1},
+		{"MissingConfigMap", false, []string{"namespace-1"}, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reaper := _fakeReaperContext()
+			reaper.ExcludedNamespaces = []string{"namespace-1"}
+			reaper.flagExcludedNamespaces = []string{"namespace-1"}
+			reaper.ExcludedNamespacesConfigMapNamespace = "kube-system"
+			reaper.ExcludedNamespacesConfigMapName = "pdb-reaper-exclusions"
+			reaper.ExcludedNamespacesConfigMapKey = DefaultExcludedNamespacesConfigMapKey
+			if tt.configMap {
+				cm := &corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Name: "pdb-reaper-exclusions", Namespace: "kube-system"},
+					Data:       map[string]string{DefaultExcludedNamespacesConfigMapKey: "namespace-2, namespace-1\nnamespace-3\n"},
+				}
+				if _, err := reaper.KubernetesClient.CoreV1().ConfigMaps("kube-system").Create(context.Background(), cm, metav1.CreateOptions{}); err != nil {
+					t.Fatalf("failed to create configmap: %v", err)
+				}
+			}
+			mocks := KubernetesMockAPI{}
+			for i := 1; i <= 4; i++ {
+				namespace := fmt.Sprintf("namespace-%v", i)
+				mocks.Namespaces = append(mocks.Namespaces, _mockNamespace(namespace))
+				mocks.PDBs = append(mocks.PDBs, _mockPDB("pdb-1", namespace, nil, &intStrZeroInt, _selector("app=app-1"), 1, 0))
+			}
+			testCase := ReaperUnitTest{
+				TestDescription:         "Namespaces listed in the excluded namespaces configmap are merged with the flag entries",
+				FakeReaper:              reaper,
+				Mocks:                   mocks,
+				ExpectedReapableBudgets: tt.expectedReapable,
+				ExpectedReapedBudgets:   tt.expectedReapable,
+			}
+			testCase.Run(t)
+
+			if strings.Join(reaper.ExcludedNamespaces, ",") != strings.Join(tt.expectedExcluded, ",") {
+				t.Fatalf("expected excluded namespaces %v, got: %v", tt.expectedExcluded, reaper.ExcludedNamespaces)
+			}
+		})
+	}
+}
+
+func TestEnforceNamespaceLabel(t *testing.T) {
+	reaper := _fakeReaperContext()
+	reaper.EnforceNamespaceLabel = "pdb-reaper=enforce"
+	metrics := &fakeMetricsAPI{}
+	reaper.MetricsAPI = metrics
+
+	enforced := _mockNamespace("namespace-1")
+	enforced.Labels = map[string]string{"pdb-reaper": "enforce"}
+	testCase := ReaperUnitTest{
+		TestDescription: "Reapable PDBs in namespaces without the enforce label are only reported",
+		FakeReaper:      reaper,
+		Mocks: KubernetesMockAPI{
+			Namespaces: []MockNamespace{
+				enforced,
+				_mockNamespace("namespace-2"),
+			},
+			PDBs: []MockPDB{
+				_mockPDB("pdb-1", "namespace-1", nil, &intStrZeroInt, _selector("app=app-1"), 1, 0),
+				_mockPDB("pdb-1", "namespace-2", nil, &intStrZeroInt, _selector("app=app-1"), 1, 0),
+			},
+		},
+		ExpectedReapableBudgets: 2,
+		ExpectedReapedBudgets:   1,
+	}
+	testCase.Run(t)
+
+	if _, err := reaper.KubernetesClient.PolicyV1().PodDisruptionBudgets("namespace-2").Get(context.Background(), "pdb-1", metav1.GetOptions{}); err != nil {
+		t.Fatalf("expected PDB in report-only namespace to be kept: %v", err)
+	}
+
+	events, err := reaper.KubernetesClient.CoreV1().Events("namespace-2").List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("failed to list events: %v", err)
+	}
+	if len(events.Items) == 0 || events.Items[0].GetLabels()[ModeLabelKey] != ModeReport {
+		t.Fatalf("expected event in namespace-2 to be labeled %v=%v, got: %+v", ModeLabelKey, ModeReport, events.Items)
+	}
+
+	if _, ok := metrics.lastValue(PdbReaperResultMetricName, map[string]string{"namespace": "namespace-2", "mode": ModeReport}); !ok {
+		t.Fatalf("expected result metric for namespace-2 to be tagged mode=%v", ModeReport)
+	}
+	if _, ok := metrics.lastValue(PdbReaperDeletedMetricName, map[string]string{"namespace": "namespace-1", "mode": ModeEnforce}); !ok {
+		t.Fatalf("expected deleted metric for namespace-1 to be tagged mode=%v", ModeEnforce)
+	}
+	if _, ok := metrics.lastValue(PdbReaperDeletedMetricName, map[string]string{"namespace": "namespace-2"}); ok {
+		t.Fatalf("expected no deleted metric for report-only namespace-2")
+	}
+}
+
+func TestConfirmWithEviction(t *testing.T) {
+	tests := []struct {
+		name          string
+		evictionError error
+		expected      float64
+	}{
+		{"Allowed", nil, 1},
+		{"StillBlocked", errors.New("Cannot evict pod as it would violate the pod's disruption budget."), 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reaper := _fakeReaperContext()
+			reaper.ConfirmWithEviction = true
+			metrics := &fakeMetricsAPI{}
+			reaper.MetricsAPI = metrics
+			var evictions []*policyv1.Eviction
+			client := reaper.KubernetesClient.(*fake.Clientset)
+			client.PrependReactor("create", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				if action.GetSubresource() != "eviction" {
+					return false, nil, nil
+				}
+				evictions = append(evictions, action.(k8stesting.CreateAction).GetObject().(*policyv1.Eviction))
+				return true, nil, tt.evictionError
+			})
+
+			pod := _mockPod("pod-1", "namespace-1", map[string]string{"app": "app-1"}, false, 0, false)
+			pod.Phase = corev1.PodRunning
+			testCase := ReaperUnitTest{
+				TestDescription: "A dry-run eviction is issued against a pod of a reaped PDB",
+				FakeReaper:      reaper,
+				Mocks: KubernetesMockAPI{
+					Namespaces: []MockNamespace{
+						_mockNamespace("namespace-1"),
+					},
+					PDBs: []MockPDB{
+						_mockPDB("pdb-1", "namespace-1", nil, &intStrZeroInt, _selector("app=app-1"), 1, 0),
+					},
+					Pods: []MockPod{pod},
+				},
+				ExpectedReapableBudgets: 1,
+				ExpectedReapedBudgets:   1,
+			}
+			testCase.Run(t)
+
+			if len(evictions) != 1 {
+				t.Fatalf("expected 1 eviction, got: %v", len(evictions))
+			}
+			if evictions[0].GetName() != "pod-1" || evictions[0].DeleteOptions == nil || len(evictions[0].DeleteOptions.DryRun) != 1 || evictions[0].DeleteOptions.DryRun[0] != metav1.DryRunAll {
+				t.Fatalf("expected a dry-run eviction of pod-1, got: %+v", evictions[0])
+			}
+			value, ok := metrics.lastValue(PdbReaperEvictionConfirmedMetricName, map[string]string{"namespace": "namespace-1", "pdb": "pdb-1"})
+			if !ok || value != tt.expected {
+				t.Fatalf("expected %v to be %v, got: %v", PdbReaperEvictionConfirmedMetricName, tt.expected, value)
+			}
+		})
+	}
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestThrottleTransport(t *testing.T) {
+	tests := []struct {
+		name           string
+		retryAfter     []string
+		retries        int
+		expectedWaits  []time.Duration
+		expectedStatus int
+	}{
+		// the client retries responses with Retry-After itself
+		{"RetryAfterLeftToClient", []string{"2"}, 3, nil, http.StatusTooManyRequests},
+		{"BackoffWithoutRetryAfter", []string{"", ""}, 3, []time.Duration{time.Second, 2 * time.Second}, http.StatusOK},
+		{"CappedAtMaxWait", []string{"", "", "", "", "", "", ""}, 7, []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 16 * time.Second, 32 * time.Second, time.Minute}, http.StatusOK},
+		{"RetriesExhausted", []string{"", "", ""}, 2, []time.Duration{time.Second, 2 * time.Second}, http.StatusTooManyRequests},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			next := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				defer func() { calls++ }()
+				resp := &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: ioutil.NopCloser(strings.NewReader("{}"))}
+				if calls < len(tt.retryAfter) {
+					resp.StatusCode = http.StatusTooManyRequests
+					if tt.retryAfter[calls] != "" {
+						resp.Header.Set("Retry-After", tt.retryAfter[calls])
+					}
+				}
+				return resp, nil
+			})
+			var waits []time.Duration
+			transport := &throttleTransport{
+				next:    next,
+				retries: tt.retries,
+				backoff: time.Second,
+				maxWait: time.Minute,
+				sleep: func(_ context.Context, d time.Duration) error {
+					waits = append(waits, d)
+					return nil
+				},
+			}
+
+			req, _ := http.NewRequest(http.MethodGet, "https://kubernetes.default/api/v1/pods", nil)
+			resp, err := transport.RoundTrip(req)
+			if err != nil {
+				t.Fatalf("round trip failed: %v", err)
+			}
+			if resp.StatusCode != tt.expectedStatus {
+				t.Fatalf("expected status %v, got: %v", tt.expectedStatus, resp.StatusCode)
+			}
+			if fmt.Sprint(waits) != fmt.Sprint(tt.expectedWaits) {
+				t.Fatalf("expected waits %v, got: %v", tt.expectedWaits, waits)
+			}
+		})
+	}
+}
+
+func TestThrottleTransportClient(t *testing.T) {
+	throttled := map[string]bool{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Method + " " + r.URL.Path
+		if !throttled[key] {
+			throttled[key] = true
+			// the list is throttled without Retry-After, the delete with it
+			if r.Method == http.MethodDelete {
+				w.Header().Set("Retry-After", "1")
+			}
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodDelete {
+			w.Write([]byte(`{"kind":"Status","apiVersion":"v1","status":"Success"}`))
+			return
+		}
+		w.Write([]byte(`{"kind":"PodDisruptionBudgetList","apiVersion":"policy/v1","items":[]}`))
+	}))
+	defer server.Close()
+
+	var waits []time.Duration
+	reaper := _fakeReaperContext()
+	reaper.ThrottleRetries = 1
+	reaper.ThrottleBackoff = time.Second
+	reaper.ThrottleMaxWait = time.Minute
+	config := &rest.Config{Host: server.URL}
+	for _, opt := range reaper.throttleConfig() {
+		opt(config)
+	}
+	config.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		rt.(*throttleTransport).sleep = func(_ context.Context, d time.Duration) error {
+			waits = append(waits, d)
+			return nil
+		}
+		return rt
+	})
+	client, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	if _, err := client.PolicyV1().PodDisruptionBudgets("").List(context.Background(), metav1.ListOptions{}); err != nil {
+		t.Fatalf("expected throttled list to be retried, got: %v", err)
+	}
+	if err := client.PolicyV1().PodDisruptionBudgets("namespace-1").Delete(context.Background(), "pdb-1", metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("expected throttled delete to be retried, got: %v", err)
+	}
+	// only the list is retried by the transport, the delete with Retry-After is retried by the client
+	if fmt.Sprint(waits) != fmt.Sprint([]time.Duration{time.Second}) {
+		t.Fatalf("expected a single backoff for the request throttled without Retry-After, got: %v", waits)
+	}
+}
+
+func TestPodFraction(t *testing.T) {
+	tests := []struct {
+		name     string
+		crashing bool
+	}{
+		{"CrashLoop", true},
+		{"NotReady", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reaper := _fakeReaperContext()
+			reaper.AllCrashLoop = true
+			reaper.AllNotReady = true
+			reaper.CrashLoopPodFraction = 0.5
+			reaper.NotReadyPodFraction = 0.5
+
+			mocks := KubernetesMockAPI{
+				Namespaces: []MockNamespace{
+					_mockNamespace("namespace-1"),
+					_mockNamespace("namespace-2"),
+				},
+				PDBs: []MockPDB{
+					_mockPDB("pdb-1", "namespace-1", nil, &intStrOneInt, _selector("app=app-1"), 4, 0),
+					_mockPDB("pdb-2", "namespace-2", nil, &intStrOneInt, _selector("app=app-2"), 4, 0),
+				},
+			}
+			// 2 of 4 pods cross the 50% fraction in namespace-1, 1 of 4 pods does not in namespace-2
+			affected := map[string]int{"namespace-1": 2, "namespace-2": 1}
+			for i, namespace := range []string{"namespace-1", "namespace-2"} {
+				for j := 0; j < 4; j++ {
+					isAffected := j < affected[namespace]
+					name := fmt.Sprintf("pod-%v%c", i+1, 'a'+j)
+					labels := map[string]string{"app": fmt.Sprintf("app-%v", i+1)}
+					mocks.Pods = append(mocks.Pods, _mockPod(name, namespace, labels, tt.crashing && isAffected, 6, !tt.crashing && isAffected))
+				}
+			}
+			testCase := ReaperUnitTest{
+				TestDescription:         "PDBs are reapable when at least the configured fraction of pods are affected",
+				FakeReaper:              reaper,
+				Mocks:                   mocks,
+				ExpectedReapableBudgets: 1,
+				ExpectedReapedBudgets:   1,
+			}
+			testCase.Run(t)
+
+			if _, err := reaper.KubernetesClient.PolicyV1().PodDisruptionBudgets("namespace-2").Get(context.Background(), "pdb-2", metav1.GetOptions{}); err != nil {
+				t.Fatalf("expected pdb-2 below the fraction to be kept: %v", err)
+			}
+		})
+	}
+}
+
+func TestSelfTest(t *testing.T) {
+	tests := []struct {
+		name           string
+		createError    error
+		strict         bool
+		expectedErr    bool
+		expectedMetric float64
+		expectedReaped int
+	}{
+		{"Passed", nil, false, false, 1, 1},
+		{"FailedContinues", errors.New("forbidden"), false, false, 0, 1},
+		{"FailedStrictAborts", errors.New("forbidden"), true, true, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reaper := _fakeReaperContext()
+			reaper.SelfTest = true
+			reaper.StrictSelfTest = tt.strict
+			reaper.SelfTestNamespace = DefaultSelfTestNamespace
+			metrics := &fakeMetricsAPI{}
+			reaper.MetricsAPI = metrics
+			client := reaper.KubernetesClient.(*fake.Clientset)
+			_fakeAPI(&ReaperUnitTest{
+				FakeReaper: reaper,
+				Mocks: KubernetesMockAPI{
+					Namespaces: []MockNamespace{
+						_mockNamespace("namespace-1"),
+					},
+					PDBs: []MockPDB{
+						_mockPDB("pdb-1", "namespace-1", nil, &intStrZeroInt, _selector("app=app-1"), 1, 0),
+					},
+				},
+			})
+			if tt.createError != nil {
+				client.PrependReactor("create", "poddisruptionbudgets", func(action k8stesting.Action) (bool, runtime.Object, error) {
+					return true, nil, tt.createError
+				})
+			}
+			client.ClearActions()
+			err := reaper.execute()
+			if (err != nil) != tt.expectedErr {
+				t.Fatalf("expected error: %v, got: %v", tt.expectedErr, err)
+			}
+			if reaper.ReapedPodDisruptionBudgetCount != tt.expectedReaped {
+				t.Fatalf("expected reaped: %v, got: %v", tt.expectedReaped, reaper.ReapedPodDisruptionBudgetCount)
+			}
+			if value, ok := metrics.lastValue(PdbReaperSelfTestMetricName, nil); !ok || value != tt.expectedMetric {
+				t.Fatalf("expected %v to be %v, got: %v", PdbReaperSelfTestMetricName, tt.expectedMetric, value)
+			}
+			if tt.createError != nil {
+				return
+			}
+
+			var lifecycle []string
+			for _, action := range client.Actions() {
+				if action.GetNamespace() == DefaultSelfTestNamespace && action.GetResource().Resource == "poddisruptionbudgets" {
+					lifecycle = append(lifecycle, action.GetVerb())
+				}
+			}
+			if strings.Join(lifecycle, ",") != "create,list,delete" {
+				t.Fatalf("expected the self-test PDB to be created, listed and deleted, got: %v", lifecycle)
+			}
+			if _, err := client.PolicyV1().PodDisruptionBudgets(DefaultSelfTestNamespace).Get(context.Background(), SelfTestPDBName, metav1.GetOptions{}); err == nil {
+				t.Fatalf("expected the self-test PDB to be deleted")
+			}
+		})
+	}
+}
+
+func TestBlockingDurationPushgateway(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	reaper := _fakeReaperContext()
+	reaper.Clock = fakeClock{now}
+	reaper.DryRun = true
+	pgw := _fakePushgateway(t)
+	reaper.MetricsAPI = common.NewPrometheusAPI(pgw.URL)
+
+	blocking := _mockPDB("pdb-1", "namespace-1", nil, &intStrZeroInt, _selector("app=app-1"), 1, 0)
+	blocking.Conditions = []metav1.Condition{{
+		Type:               policyv1.DisruptionAllowedCondition,
+		Status:             metav1.ConditionFalse,
+		Reason:             policyv1.InsufficientPodsReason,
+		LastTransitionTime: metav1.Time{Time: now.Add(-30 * time.Minute)},
+	}}
+	testCase := ReaperUnitTest{
+		TestDescription: "Tests that the blocking duration metric is kept along the other metrics of the PDB on the pushgateway",
+		FakeReaper:      reaper,
+		Mocks: KubernetesMockAPI{
+			Namespaces: []MockNamespace{
+				_mockNamespace("namespace-1"),
+			},
+			PDBs: []MockPDB{blocking},
+			Pods: []MockPod{
+				_mockPod("pod-1", "namespace-1", map[string]string{"app": "app-1"}, false, 0, false),
+			},
+		},
+		ExpectedReapableBudgets: 1,
+		ExpectedReapedBudgets:   0,
+	}
+	testCase.Run(t)
+
+	pgw.assertPushed(t, map[string]string{"namespace": "namespace-1", "pdb": "pdb-1"}, map[string]float64{
+		PdbReaperBlockingDurationMetricName: 1800,
+		PdbReaperMatchedPodsMetricName:      1,
+	})
+}
+
+func TestBlockingDurationMetric(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	reaper := _fakeReaperContext()
+	reaper.Clock = fakeClock{now}
+	metrics := &fakeMetricsAPI{}
+	reaper.MetricsAPI = metrics
+
+	blocking := _mockPDB("pdb-1", "namespace-1", nil, &intStrZeroInt, _selector("app=app-1"), 1, 0)
+	blocking.Conditions = []metav1.Condition{{
+		Type:               policyv1.DisruptionAllowedCondition,
+		Status:             metav1.ConditionFalse,
+		Reason:             policyv1.InsufficientPodsReason,
+		LastTransitionTime: metav1.Time{Time: now.Add(-90 * time.Minute)},
+	}}
+	testCase := ReaperUnitTest{
+		TestDescription: "The blocking duration is derived from the DisruptionAllowed condition",
+		FakeReaper:      reaper,
+		Mocks: KubernetesMockAPI{
+			Namespaces: []MockNamespace{
+				_mockNamespace("namespace-1"),
+				_mockNamespace("namespace-2"),
+			},
+			PDBs: []MockPDB{
+				blocking,
+				_mockPDB("pdb-2", "namespace-2", nil, &intStrZeroInt, _selector("app=app-2"), 1, 0),
+			},
+		},
+		ExpectedReapableBudgets: 2,
+		ExpectedReapedBudgets:   2,
+	}
+	testCase.Run(t)
+
+	value, ok := metrics.lastValue(PdbReaperBlockingDurationMetricName, map[string]string{"namespace": "namespace-1", "pdb": "pdb-1"})
+	if !ok || value != 5400 {
+		t.Fatalf("expected %v of pdb-1 to be 5400, got: %v", PdbReaperBlockingDurationMetricName, value)
+	}
+	if _, ok := metrics.lastValue(PdbReaperBlockingDurationMetricName, map[string]string{"pdb": "pdb-2"}); ok {
+		t.Fatalf("expected no %v for pdb-2 without a DisruptionAllowed condition", PdbReaperBlockingDurationMetricName)
+	}
+}
+
+func TestMaxNamespaces(t *testing.T) {
+	tests := []struct {
+		name          string
+		maxNamespaces int
+		expectedErr   bool
+		expectedReap  int
+	}{
+		{"WithinLimit", 2, false, 2},
+		{"ExceedsLimit", 1, true, 0},
+		{"Disabled", 0, false, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reaper := _fakeReaperContext()
+			reaper.MaxNamespaces = tt.maxNamespaces
+			reaper.ExcludedNamespaces = []string{"namespace-3"}
+			mocks := KubernetesMockAPI{}
+			for i := 1; i <= 3; i++ {
+				namespace := fmt.Sprintf("namespace-%v", i)
+				mocks.Namespaces = append(mocks.Namespaces, _mockNamespace(namespace))
+				mocks.PDBs = append(mocks.PDBs, _mockPDB("pdb-1", namespace, nil, &intStrZeroInt, _selector("app=app-1"), 1, 0))
+			}
+			_fakeAPI(&ReaperUnitTest{FakeReaper: reaper, Mocks: mocks})
+
+			err := reaper.execute()
+			if (err != nil) != tt.expectedErr {
+				t.Fatalf("expected error: %v, got: %v", tt.expectedErr, err)
+			}
+			if err != nil && !strings.Contains(err.Error(), "--max-namespaces") {
+				t.Fatalf("expected error to mention --max-namespaces, got: %v", err)
+			}
+			if reaper.ReapedPodDisruptionBudgetCount != tt.expectedReap {
+				t.Fatalf("expected reaped: %v, got: %v", tt.expectedReap, reaper.ReapedPodDisruptionBudgetCount)
+			}
+		})
+	}
+}
+
+func TestDiagnostics(t *testing.T) {
+	reaper := _fakeReaperContext()
+	testCase := ReaperUnitTest{
+		TestDescription: "The diagnostics of a PDB reapable for multiple reasons carry each reason and the values behind them",
+		FakeReaper:      reaper,
+		Mocks: KubernetesMockAPI{
+			Namespaces: []MockNamespace{
+				_mockNamespace("namespace-1"),
+			},
+			PDBs: []MockPDB{
+				_mockPDB("pdb-1", "namespace-1", nil, &intStrZeroInt, _selector("app=app-1"), 3, 0),
+			},
+			Pods: []MockPod{
+				_mockPod("pod-1", "namespace-1", map[string]string{"app": "app-1"}, true, 6, false),
+				_mockPod("pod-2", "namespace-1", map[string]string{"app": "app-1"}, true, 6, false),
+				_mockPod("pod-3", "namespace-1", map[string]string{"app": "app-1"}, false, 0, true),
+			},
+		},
+		ExpectedReapableBudgets: 1,
+		ExpectedReapedBudgets:   1,
+	}
+	testCase.Run(t)
+
+	diagnostics, ok := reaper.Diagnostics["namespace-1/pdb-1"]
+	if !ok {
+		t.Fatalf("expected diagnostics for namespace-1/pdb-1, got: %+v", reaper.Diagnostics)
+	}
+	expected := Diagnostics{
+		Reasons:            []Reason{ReasonBlocking, ReasonBlockingCrashLoop, ReasonBlockingNotReadyState},
+		ExpectedPods:       3,
+		DisruptionsAllowed: 0,
+		ResolvedBudget:     map[string]int{BudgetTypeMaxUnavailable: 0},
+		MatchedPods:        3,
+		CrashLoopPods:      2,
+		NotReadyPods:       1,
+	}
+	if fmt.Sprintf("%+v", *diagnostics) != fmt.Sprintf("%+v", expected) {
+		t.Fatalf("expected diagnostics %+v, got: %+v", expected, *diagnostics)
+	}
+}
+
+func TestZeroMaxUnavailable(t *testing.T) {
+	reaper := _fakeReaperContext()
+	reaper.ReapMisconfigured = false
+	reaper.ReapCrashLoop = false
+	reaper.ReapNotReady = false
+	reaper.ReapZeroMaxUnavailable = true
+	intStrTenPercent := intstr.FromString("10%")
+	testCase := ReaperUnitTest{
+		TestDescription: "PDBs whose maxUnavailable resolves to 0 are reapable regardless of pod state",
+		FakeReaper:      reaper,
+		Mocks: KubernetesMockAPI{
+			Namespaces: []MockNamespace{
+				_mockNamespace("namespace-1"),
+				_mockNamespace("namespace-2"),
+				_mockNamespace("namespace-3"),
+				_mockNamespace("namespace-4"),
+			},
+			PDBs: []MockPDB{
+				// healthy pods, the PDB is not even blocking
+				_mockPDB("pdb-1", "namespace-1", nil, &intStrZeroInt, _selector("app=app-1"), 2, 1),
+				_mockPDB("pdb-2", "namespace-2", nil, &intStrZeroPercent, _selector("app=app-2"), 2, 0),
+				// 10% of 2 expected pods is rounded up to 1
+				_mockPDB("pdb-3", "namespace-3", nil, &intStrTenPercent, _selector("app=app-3"), 2, 1),
+				_mockPDB("pdb-4", "namespace-4", &intStrOneInt, nil, _selector("app=app-4"), 2, 1),
+			},
+			Pods: []MockPod{
+				_mockPod("pod-1a", "namespace-1", map[string]string{"app": "app-1"}, false, 0, false),
+				_mockPod("pod-1b", "namespace-1", map[string]string{"app": "app-1"}, false, 0, false),
+				_mockPod("pod-2a", "namespace-2", map[string]string{"app": "app-2"}, false, 0, false),
+				_mockPod("pod-2b", "namespace-2", map[string]string{"app": "app-2"}, false, 0, false),
+			},
+		},
+		ExpectedReapableBudgets: 2,
+		ExpectedReapedBudgets:   2,
+	}
+	testCase.Run(t)
+
+	for _, name := range []string{"namespace-1/pdb-1", "namespace-2/pdb-2"} {
+		if reasons := reaper.ReapableReasons[name]; len(reasons) != 1 || reasons[0] != ReasonZeroMaxUnavailable {
+			t.Fatalf("expected %v to be reapable due to %v, got: %v", name, ReasonZeroMaxUnavailable, reasons)
+		}
+	}
+}
+
+func TestQuietNamespaces(t *testing.T) {
+	reaper := _fakeReaperContext()
+	reaper.QuietNamespaces = []string{"namespace-1"}
+	metrics := &fakeMetricsAPI{}
+	reaper.MetricsAPI = metrics
+	testCase := ReaperUnitTest{
+		TestDescription: "No events are published in quiet namespaces, which are still reaped",
+		FakeReaper:      reaper,
+		Mocks: KubernetesMockAPI{
+			Namespaces: []MockNamespace{
+				_mockNamespace("namespace-1"),
+				_mockNamespace("namespace-2"),
+			},
+			PDBs: []MockPDB{
+				_mockPDB("pdb-1", "namespace-1", nil, &intStrZeroInt, _selector("app=app-1"), 1, 0),
+				_mockPDB("pdb-1", "namespace-2", nil, &intStrZeroInt, _selector("app=app-1"), 1, 0),
+			},
+		},
+		ExpectedReapableBudgets: 2,
+		ExpectedReapedBudgets:   2,
+	}
+	testCase.Run(t)
+
+	for namespace, expected := range map[string]bool{"namespace-1": false, "namespace-2": true} {
+		events, err := reaper.KubernetesClient.CoreV1().Events(namespace).List(context.Background(), metav1.ListOptions{})
+		if err != nil {
+			t.Fatalf("failed to list events: %v", err)
+		}
+		if (len(events.Items) > 0) != expected {
+			t.Fatalf("expected events in %v: %v, got: %v", namespace, expected, len(events.Items))
+		}
+		if _, ok := metrics.lastValue(PdbReaperDeletedMetricName, map[string]string{"namespace": namespace}); !ok {
+			t.Fatalf("expected deleted metric for %v", namespace)
+		}
+		if _, err := reaper.KubernetesClient.PolicyV1().PodDisruptionBudgets(namespace).Get(context.Background(), "pdb-1", metav1.GetOptions{}); err == nil {
+			t.Fatalf("expected pdb-1 in %v to be deleted", namespace)
+		}
+	}
+}
+
+func TestProcessedGeneration(t *testing.T) {
+	reaper := _fakeReaperContext()
+	reaper.StampProcessedGeneration = true
+	pdb := _mockPDB("pdb-1", "namespace-1", nil, &intStrOneInt, _selector("app=app-1"), 1, 0)
+	pdb.Generation = 3
+	_fakeAPI(&ReaperUnitTest{FakeReaper: reaper, Mocks: KubernetesMockAPI{
+		Namespaces: []MockNamespace{
+			_mockNamespace("namespace-1"),
+		},
+		PDBs: []MockPDB{pdb},
+		Pods: []MockPod{
+			_mockPod("pod-1", "namespace-1", map[string]string{"app": "app-1"}, false, 0, false),
+		},
+	}})
+
+	// a retried list returns the same PDB twice
+	client := reaper.KubernetesClient.(*fake.Clientset)
+	client.PrependReactor("list", "poddisruptionbudgets", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		obj, err := client.Tracker().List(policyv1.SchemeGroupVersion.WithResource("poddisruptionbudgets"), policyv1.SchemeGroupVersion.WithKind("PodDisruptionBudget"), action.GetNamespace())
+		if err != nil {
+			return true, nil, err
+		}
+		list := obj.(*policyv1.PodDisruptionBudgetList)
+		list.Items = append(list.Items, list.Items...)
+		return true, list, nil
+	})
+
+	if err := reaper.execute(); err != nil {
+		t.Fatalf("execute failed: %v", err)
+	}
+
+	evaluated := 0
+	for _, action := range client.Actions() {
+		if list, ok := action.(k8stesting.ListAction); ok && action.GetResource().Resource == "pods" && list.GetListRestrictions().Labels.String() == "app=app-1" {
+			evaluated++
+		}
+	}
+	if evaluated != 1 {
+		t.Fatalf("expected pdb-1 to be evaluated once, got: %v", evaluated)
+	}
+	if len(reaper.ReapablePodDisruptionBudgets) != 0 {
+		t.Fatalf("expected pdb-1 not to overlap with itself, got: %v reapable", len(reaper.ReapablePodDisruptionBudgets))
+	}
+
+	annotated, err := reaper.KubernetesClient.PolicyV1().PodDisruptionBudgets("namespace-1").Get(context.Background(), "pdb-1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get pdb-1: %v", err)
+	}
+	if annotated.Annotations[ProcessedGenerationAnnotationKey] != "3" {
+		t.Fatalf("expected processed generation 3, got: %v", annotated.Annotations[ProcessedGenerationAnnotationKey])
+	}
+}
+
+func TestHealthScorePushgateway(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	reaper := _fakeReaperContext()
+	reaper.Clock = fakeClock{now}
+	pgw := _fakePushgateway(t)
+	reaper.MetricsAPI = common.NewPrometheusAPI(pgw.URL)
+	if err := reaper.applyReapModes([]string{ReapModeHealthScore}); err != nil {
+		t.Fatalf("failed to apply reap modes: %v", err)
+	}
+	reaper.HealthScoreThreshold = 0.3
+	reaper.HealthScoreBlockingDuration = 24 * time.Hour
+	weights, err := parseHealthScoreWeights(nil)
+	if err != nil {
+		t.Fatalf("failed to parse weights: %v", err)
+	}
+	reaper.HealthScoreWeights = weights
+
+	crashLoop := _mockPDB("pdb-1", "namespace-1", nil, &intStrOneInt, _selector("app=app-1"), 2, 0)
+	crashLoop.Conditions = []metav1.Condition{{
+		Type:               policyv1.DisruptionAllowedCondition,
+		Status:             metav1.ConditionFalse,
+		Reason:             policyv1.InsufficientPodsReason,
+		LastTransitionTime: metav1.Time{Time: now.Add(-12 * time.Hour)},
+	}}
+	testCase := ReaperUnitTest{
+		TestDescription: "Tests that the health score metric is kept along the other metrics of the PDB on the pushgateway",
+		FakeReaper:      reaper,
+		Mocks: KubernetesMockAPI{
+			Namespaces: []MockNamespace{
+				_mockNamespace("namespace-1"),
+			},
+			PDBs: []MockPDB{crashLoop},
+			Pods: []MockPod{
+				_mockPod("pod-1a", "namespace-1", map[string]string{"app": "app-1"}, true, 6, false),
+				_mockPod("pod-1b", "namespace-1", map[string]string{"app": "app-1"}, false, 0, false),
+			},
+		},
+		ExpectedReapableBudgets: 0,
+		ExpectedReapedBudgets:   0,
+	}
+	testCase.Run(t)
+
+	tags := map[string]string{"namespace": "namespace-1", "pdb": "pdb-1"}
+	if score, ok := pgw.value(PdbReaperHealthScoreMetricName, tags); !ok || math.Abs(score-0.2) > 0.001 {
+		t.Fatalf("expected health score 0.2 on the pushgateway, got: %v (found %v)", score, ok)
+	}
+	pgw.assertPushed(t, tags, map[string]float64{
+		PdbReaperBlockingDurationMetricName: 43200,
+		PdbReaperMatchedPodsMetricName:      2,
+	})
+}
+
+func TestHealthScore(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	reaper := _fakeReaperContext()
+	reaper.Clock = fakeClock{now}
+	metrics := &fakeMetricsAPI{}
+	reaper.MetricsAPI = metrics
+	if err := reaper.applyReapModes([]string{ReapModeHealthScore}); err != nil {
+		t.Fatalf("failed to apply reap modes: %v", err)
+	}
+	reaper.HealthScoreThreshold = 0.3
+	reaper.HealthScoreBlockingDuration = 24 * time.Hour
+	weights, err := parseHealthScoreWeights(nil)
+	if err != nil {
+		t.Fatalf("failed to parse weights: %v", err)
+	}
+	reaper.HealthScoreWeights = weights
+
+	// blocking for half of --health-score-blocking-duration
+	crashLoop := _mockPDB("pdb-2", "namespace-2", nil, &intStrOneInt, _selector("app=app-2"), 2, 0)
+	crashLoop.Conditions = []metav1.Condition{{
+		Type:               policyv1.DisruptionAllowedCondition,
+		Status:             metav1.ConditionFalse,
+		Reason:             policyv1.InsufficientPodsReason,
+		LastTransitionTime: metav1.Time{Time: now.Add(-12 * time.Hour)},
+	}}
+	testCase := ReaperUnitTest{
+		TestDescription: "Blocking PDBs are reapable when their weighted health score exceeds the threshold",
+		FakeReaper:      reaper,
+		Mocks: KubernetesMockAPI{
+			Namespaces: []MockNamespace{
+				_mockNamespace("namespace-1"),
+				_mockNamespace("namespace-2"),
+				_mockNamespace("namespace-3"),
+				_mockNamespace("namespace-4"),
+			},
+			PDBs: []MockPDB{
+				_mockPDB("pdb-1", "namespace-1", nil, &intStrOneInt, _selector("app=app-1"), 2, 0),
+				crashLoop,
+				_mockPDB("pdb-3", "namespace-3", nil, &intStrZeroInt, _selector("app=app-3"), 2, 0),
+				_mockPDB("pdb-4a", "namespace-4", nil, &intStrOneInt, _selector("app=app-4"), 2, 0),
+				_mockPDB("pdb-4b", "namespace-4", nil, &intStrOneInt, _selector("app=app-4"), 2, 0),
+			},
+			Pods: []MockPod{
+				_mockPod("pod-1a", "namespace-1", map[string]string{"app": "app-1"}, false, 0, false),
+				_mockPod("pod-1b", "namespace-1", map[string]string{"app": "app-1"}, false, 0, false),
+				_mockPod("pod-2a", "namespace-2", map[string]string{"app": "app-2"}, true, 6, false),
+				_mockPod("pod-2b", "namespace-2", map[string]string{"app": "app-2"}, false, 0, false),
+				_mockPod("pod-3a", "namespace-3", map[string]string{"app": "app-3"}, true, 6, false),
+				_mockPod("pod-3b", "namespace-3", map[string]string{"app": "app-3"}, true, 6, false),
+				_mockPod("pod-4a", "namespace-4", map[string]string{"app": "app-4"}, false, 0, false),
+				_mockPod("pod-4b", "namespace-4", map[string]string{"app": "app-4"}, false, 0, false),
+			},
+		},
+		ExpectedReapableBudgets: 1,
+		ExpectedReapedBudgets:   1,
+	}
+	testCase.Run(t)
+
+	// pdb-2 is half crashlooping and half blocking for 24h, pdb-3 is misconfigured and crashlooping, pdb-4a overlaps
+	for name, expected := range map[string]float64{"pdb-1": 0, "pdb-2": 0.2, "pdb-3": 0.4, "pdb-4a": 0.2} {
+		score, ok := metrics.lastValue(PdbReaperHealthScoreMetricName, map[string]string{"pdb": name})
+		if !ok || math.Abs(score-expected) > 0.001 {
+			t.Fatalf("expected health score %v for %v, got: %v", expected, name, score)
+		}
+	}
+	if reasons := reaper.ReapableReasons["namespace-3/pdb-3"]; len(reasons) != 1 || reasons[0] != ReasonHealthScore {
+		t.Fatalf("expected namespace-3/pdb-3 to be reapable due to %v, got: %v", ReasonHealthScore, reasons)
+	}
+
+	// weighting crashloop higher puts pdb-2 above the threshold
+	weights, err = parseHealthScoreWeights([]string{"crashloop=3", "overlap=0"})
+	if err != nil {
+		t.Fatalf("failed to parse weights: %v", err)
+	}
+	components := map[string]float64{HealthScoreCrashLoop: 0.5, HealthScoreBlockingDuration: 0.5}
+	if score := healthScore(components, weights); math.Abs(score-2.0/6) > 0.001 || score <= reaper.HealthScoreThreshold {
+		t.Fatalf("expected weighted health score %v, got: %v", 2.0/6, score)
+	}
+
+	for _, invalid := range [][]string{{"crashloop"}, {"orphaned=1"}, {"crashloop=-1"}, {"misconfigured=0", "crashloop=0", "not-ready=0", "blocking-duration=0", "overlap=0"}} {
+		if _, err := parseHealthScoreWeights(invalid); err == nil {
+			t.Fatalf("expected weights %v to be invalid", invalid)
+		}
+	}
+}
+
+func TestSecretBackupSink(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	reaper := _fakeReaperContext()
+	reaper.Clock = fakeClock{now}
+	reaper.BackupSink = &SecretBackupSink{Namespace: "backups"}
+	testCase := ReaperUnitTest{
+		TestDescription: "Reaped PDBs are backed up to a Secret before they are deleted",
+		FakeReaper:      reaper,
+		Mocks: KubernetesMockAPI{
+			Namespaces: []MockNamespace{
+				_mockNamespace("namespace-1"),
+				_mockNamespace("backups"),
+			},
+			PDBs: []MockPDB{
+				_mockPDB("pdb-1", "namespace-1", nil, &intStrZeroInt, _selector("app=app-1"), 1, 0),
+			},
+		},
+		ExpectedReapableBudgets: 1,
+		ExpectedReapedBudgets:   1,
+	}
+	testCase.Run(t)
+
+	secret, err := reaper.KubernetesClient.CoreV1().Secrets("backups").Get(context.Background(), "pdb-reaper-backup-namespace-1.pdb-1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get backup secret: %v", err)
+	}
+	if secret.Annotations[BackupOfAnnotationKey] != "namespace-1/pdb-1" || secret.Annotations[BackedUpAtAnnotationKey] != "2024-01-01T12:00:00Z" {
+		t.Fatalf("unexpected backup secret annotations: %v", secret.Annotations)
+	}
+
+	var backup policyv1.PodDisruptionBudget
+	if err := yaml.Unmarshal(secret.Data[BackupSecretDataKey], &backup); err != nil {
+		t.Fatalf("failed to unmarshal backup: %v", err)
+	}
+	if backup.Kind != "PodDisruptionBudget" || backup.Namespace != "namespace-1" || backup.Name != "pdb-1" {
+		t.Fatalf("unexpected backup metadata: %+v", backup.ObjectMeta)
+	}
+	if backup.Spec.MaxUnavailable == nil || backup.Spec.MaxUnavailable.IntValue() != 0 || backup.Spec.Selector.MatchLabels["app"] != "app-1" {
+		t.Fatalf("unexpected backup spec: %+v", backup.Spec)
+	}
+	if backup.Status.ExpectedPods != 0 || backup.ResourceVersion != "" {
+		t.Fatalf("expected backup without status and server populated metadata, got: %+v", backup)
+	}
+
+	// a later backup of the same PDB replaces the previous one
+	sink := &SecretBackupSink{Client: reaper.KubernetesClient, Context: context.Background()}
+	if err := sink.Store(backup, []byte("replaced"), now); err != nil {
+		t.Fatalf("failed to store backup: %v", err)
+	}
+	if err := sink.Store(backup, []byte("replaced again"), now); err != nil {
+		t.Fatalf("failed to replace backup: %v", err)
+	}
+	secret, err = reaper.KubernetesClient.CoreV1().Secrets("namespace-1").Get(context.Background(), "pdb-reaper-backup-namespace-1.pdb-1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get backup secret: %v", err)
+	}
+	if string(secret.Data[BackupSecretDataKey]) != "replaced again" {
+		t.Fatalf("expected backup to be replaced, got: %v", string(secret.Data[BackupSecretDataKey]))
+	}
+}
+
+func TestBackupFailureSkipsDeletion(t *testing.T) {
+	reaper := _fakeReaperContext()
+	reaper.BackupSink = &SecretBackupSink{}
+	_fakeAPI(&ReaperUnitTest{FakeReaper: reaper, Mocks: KubernetesMockAPI{
+		Namespaces: []MockNamespace{
+			_mockNamespace("namespace-1"),
+		},
+		PDBs: []MockPDB{
+			_mockPDB("pdb-1", "namespace-1", nil, &intStrZeroInt, _selector("app=app-1"), 1, 0),
+		},
+	}})
+	client := reaper.KubernetesClient.(*fake.Clientset)
+	client.PrependReactor("create", "secrets", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, fmt.Errorf("forbidden")
+	})
+
+	if err := reaper.execute(); err != nil {
+		t.Fatalf("execute failed: %v", err)
+	}
+	if reaper.ReapedPodDisruptionBudgetCount != 0 {
+		t.Fatalf("expected no PDBs to be reaped, got: %v", reaper.ReapedPodDisruptionBudgetCount)
+	}
+	if _, err := reaper.KubernetesClient.PolicyV1().PodDisruptionBudgets("namespace-1").Get(context.Background(), "pdb-1", metav1.GetOptions{}); err != nil {
+		t.Fatalf("expected pdb-1 not to be deleted: %v", err)
+	}
+}
+
+func TestExcludeDaemonSetPods(t *testing.T) {
+	reaper := _fakeReaperContext()
+	reaper.ExcludeDaemonSetPods = true
+	controller := true
+	daemonSetPod := func(name, namespace string, labels map[string]string, crashloop bool, restarts int32, notReadyState bool) MockPod {
+		pod := _mockPod(name, namespace, labels, crashloop, restarts, notReadyState)
+		pod.OwnerReferences = []metav1.OwnerReference{{Kind: "DaemonSet", Name: "agent", Controller: &controller}}
+		return pod
+	}
+	testCase := ReaperUnitTest{
+		TestDescription: "DaemonSet pods are left out of crashloop and not-ready detection",
+		FakeReaper:      reaper,
+		Mocks: KubernetesMockAPI{
+			Namespaces: []MockNamespace{
+				_mockNamespace("namespace-1"),
+				_mockNamespace("namespace-2"),
+				_mockNamespace("namespace-3"),
+			},
+			PDBs: []MockPDB{
+				_mockPDB("pdb-1", "namespace-1", nil, &intStrOneInt, _selector("app=app-1"), 1, 0),
+				_mockPDB("pdb-2", "namespace-2", nil, &intStrOneInt, _selector("app=app-2"), 2, 0),
+				_mockPDB("pdb-3", "namespace-3", nil, &intStrOneInt, _selector("app=app-3"), 1, 0),
+			},
+			Pods: []MockPod{
+				// only DaemonSet pods are crashlooping or not-ready
+				daemonSetPod("pod-1", "namespace-1", map[string]string{"app": "app-1"}, true, 6, false),
+				daemonSetPod("pod-3", "namespace-3", map[string]string{"app": "app-3"}, false, 0, true),
+				// a crashlooping pod of another workload still makes the PDB reapable
+				daemonSetPod("pod-2a", "namespace-2", map[string]string{"app": "app-2"}, true, 6, false),
+				_mockPod("pod-2b", "namespace-2", map[string]string{"app": "app-2"}, true, 6, false),
+			},
+		},
+		ExpectedReapableBudgets: 1,
+		ExpectedReapedBudgets:   1,
+	}
+	testCase.Run(t)
+
+	if reasons := reaper.ReapableReasons["namespace-2/pdb-2"]; len(reasons) != 1 || reasons[0] != ReasonBlockingCrashLoop {
+		t.Fatalf("expected namespace-2/pdb-2 to be reapable due to %v, got: %v", ReasonBlockingCrashLoop, reasons)
+	}
+	if diagnostics := reaper.Diagnostics["namespace-2/pdb-2"]; diagnostics.MatchedPods != 2 || diagnostics.CrashLoopPods != 1 {
+		t.Fatalf("expected 1 of 2 matched pods to be counted as crashlooping, got: %+v", diagnostics)
+	}
+}
+
+func TestDeleteGraceSeconds(t *testing.T) {
+	for _, gracePeriod := range []*int64{nil, func(v int64) *int64 { return &v }(30)} {
+		reaper := _fakeReaperContext()
+		reaper.DeleteGraceSeconds = gracePeriod
+		_fakeAPI(&ReaperUnitTest{FakeReaper: reaper, Mocks: KubernetesMockAPI{
+			Namespaces: []MockNamespace{
+				_mockNamespace("namespace-1"),
+			},
+			PDBs: []MockPDB{
+				_mockPDB("pdb-1", "namespace-1", nil, &intStrZeroInt, _selector("app=app-1"), 1, 0),
+			},
+		}})
+		if err := reaper.execute(); err != nil {
+			t.Fatalf("execute failed: %v", err)
+		}
+
+		var deletes int
+		for _, action := range reaper.KubernetesClient.(*fake.Clientset).Actions() {
+			deleteAction, ok := action.(k8stesting.DeleteAction)
+			if !ok || action.GetResource().Resource != "poddisruptionbudgets" {
+				continue
+			}
+			deletes++
+			actual := deleteAction.GetDeleteOptions().GracePeriodSeconds
+			if (actual == nil) != (gracePeriod == nil) || (actual != nil && *actual != *gracePeriod) {
+				t.Fatalf("expected grace period %v on delete, got: %v", gracePeriod, actual)
+			}
+		}
+		if deletes != 1 {
+			t.Fatalf("expected 1 PDB delete, got: %v", deletes)
+		}
+	}
+}
+
+func TestAnnotateDeleteReason(t *testing.T) {
+	reaper := _fakeReaperContext()
+	reaper.AnnotateDeleteReason = true
+	_fakeAPI(&ReaperUnitTest{FakeReaper: reaper, Mocks: KubernetesMockAPI{
+		Namespaces: []MockNamespace{
+			_mockNamespace("namespace-1"),
+		},
+		PDBs: []MockPDB{
+			_mockPDB("pdb-1", "namespace-1", nil, &intStrZeroInt, _selector("app=app-1"), 1, 0),
+		},
+	}})
+	client := reaper.KubernetesClient.(*fake.Clientset)
+	var annotated string
+	client.PrependReactor("delete", "poddisruptionbudgets", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		pdb, err := client.Tracker().Get(policyv1.SchemeGroupVersion.WithResource("poddisruptionbudgets"), action.GetNamespace(), action.(k8stesting.DeleteAction).GetName())
+		if err == nil {
+			annotated = pdb.(*policyv1.PodDisruptionBudget).Annotations[DeleteReasonAnnotationKey]
+		}
+		return false, nil, nil
+	})
+
+	if err := reaper.execute(); err != nil {
+		t.Fatalf("execute failed: %v", err)
+	}
+
+	var verbs []string
+	for _, action := range client.Actions() {
+		if action.GetResource().Resource == "poddisruptionbudgets" && (action.GetVerb() == "patch" || action.GetVerb() == "delete") {
+			verbs = append(verbs, action.GetVerb())
+		}
+	}
+	if strings.Join(verbs, ",") != "patch,delete" {
+		t.Fatalf("expected the PDB to be annotated then deleted, got: %v", verbs)
+	}
+	if annotated != EventReasonBlockingDetected {
+		t.Fatalf("expected the deleted PDB to carry reason %v, got: '%v'", EventReasonBlockingDetected, annotated)
+	}
+}
+
+func TestBatchMetrics(t *testing.T) {
+	reaper := _fakeReaperContext()
+	metrics := &fakeMetricsAPI{}
+	buffered := common.NewBufferedMetricsAPI(metrics)
+	reaper.MetricsAPI = buffered
+	_fakeAPI(&ReaperUnitTest{FakeReaper: reaper, Mocks: KubernetesMockAPI{
+		Namespaces: []MockNamespace{
+			_mockNamespace("namespace-1"),
+		},
+		PDBs: []MockPDB{
+			_mockPDB("pdb-1", "namespace-1", nil, &intStrZeroInt, _selector("app=app-1"), 1, 0),
+		},
+	}})
+
+	reaper.resetRunState()
+	if err := reaper.scan(); err != nil {
+		t.Fatalf("scan failed: %v", err)
+	}
+	if buffered.Buffered() == 0 || len(metrics.Metrics) != 0 {
+		t.Fatalf("expected metric values to be buffered, got %v buffered and %v sent", buffered.Buffered(), len(metrics.Metrics))
+	}
+
+	if err := reaper.execute(); err != nil {
+		t.Fatalf("execute failed: %v", err)
+	}
+	if buffered.Buffered() != 0 {
+		t.Fatalf("expected buffered metric values to be flushed, got: %v", buffered.Buffered())
+	}
+	if value, ok := metrics.lastValue(PdbReaperDeletedMetricName, map[string]string{"pdb": "pdb-1"}); !ok || value != 1 {
+		t.Fatalf("expected the deleted metric to be flushed, got: %v", value)
+	}
+}
+
+func TestServerVersion(t *testing.T) {
+	tests := []struct {
+		name              string
+		serverVersion     string
+		strict            bool
+		expectedError     string
+		expectedEvictions int
+	}{
+		{"New", "v1.28.2", false, "", 1},
+		{"Old", "v1.21.14-eks-1", false, "", 0},
+		{"OldStrict", "v1.21.14-eks-1", true, "--confirm-with-eviction-after-delete needs server version 1.22 or newer", 0},
+		{"OlderThanMin", "v1.20.0", false, "server version v1.20.0 is older than --min-kubernetes-version 1.21", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reaper := _fakeReaperContext()
+			reaper.ConfirmWithEviction = true
+			reaper.MinKubernetesVersion = "1.21"
+			reaper.StrictVersionCheck = tt.strict
+			pod := _mockPod("pod-1", "namespace-1", map[string]string{"app": "app-1"}, false, 0, false)
+			pod.Phase = corev1.PodRunning
+			_fakeAPI(&ReaperUnitTest{FakeReaper: reaper, Mocks: KubernetesMockAPI{
+				Namespaces: []MockNamespace{
+					_mockNamespace("namespace-1"),
+				},
+				PDBs: []MockPDB{
+					_mockPDB("pdb-1", "namespace-1", nil, &intStrZeroInt, _selector("app=app-1"), 1, 0),
+				},
+				Pods: []MockPod{pod},
+			}})
+			client := reaper.KubernetesClient.(*fake.Clientset)
+			client.Discovery().(*fakediscovery.FakeDiscovery).FakedServerVersion = &apiversion.Info{GitVersion: tt.serverVersion}
+
+			err := reaper.execute()
+			if tt.expectedError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expectedError) {
+					t.Fatalf("expected error '%v', got: %v", tt.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("execute failed: %v", err)
+			}
+
+			var evictions int
+			for _, action := range client.Actions() {
+				if action.GetVerb() == "create" && action.GetSubresource() == "eviction" {
+					evictions++
+				}
+			}
+			if evictions != tt.expectedEvictions {
+				t.Fatalf("expected %v evictions, got: %v", tt.expectedEvictions, evictions)
+			}
+		})
+	}
+}
+
+func TestMixedControllers(t *testing.T) {
+	reaper := _fakeReaperContext()
+	if err := reaper.applyReapModes([]string{ReapModeMixedControllers}); err != nil {
+		t.Fatalf("failed to apply reap modes: %v", err)
+	}
+	controller := true
+	ownedPod := func(name, namespace, app, kind, owner, hash string) MockPod {
+		labels := map[string]string{"app": app}
+		if hash != "" {
+			labels[appsv1.DefaultDeploymentUniqueLabelKey] = hash
+		}
+		pod := _mockPod(name, namespace, labels, false, 0, false)
+		pod.OwnerReferences = []metav1.OwnerReference{{Kind: kind, Name: owner, Controller: &controller}}
+		return pod
+	}
+	testCase := ReaperUnitTest{
+		TestDescription: "Blocking PDBs matching pods of multiple controllers are reapable",
+		FakeReaper:      reaper,
+		Mocks: KubernetesMockAPI{
+			Namespaces: []MockNamespace{
+				_mockNamespace("namespace-1"),
+				_mockNamespace("namespace-2"),
+			},
+			PDBs: []MockPDB{
+				_mockPDB("pdb-1", "namespace-1", nil, &intStrOneInt, _selector("app=web"), 2, 0),
+				_mockPDB("pdb-2", "namespace-2", nil, &intStrOneInt, _selector("app=web"), 2, 0),
+			},
+			Pods: []MockPod{
+				// two Deployments sharing the app label
+				ownedPod("web-6d4cf56db6-a1", "namespace-1", "web", "ReplicaSet", "web-6d4cf56db6", "6d4cf56db6"),
+				ownedPod("web-canary-7f9c8b5d4-b1", "namespace-1", "web", "ReplicaSet", "web-canary-7f9c8b5d4", "7f9c8b5d4"),
+				// a single Deployment in the middle of a rollout
+				ownedPod("web-6d4cf56db6-a1", "namespace-2", "web", "ReplicaSet", "web-6d4cf56db6", "6d4cf56db6"),
+				ownedPod("web-58b7d6c9f-c1", "namespace-2", "web", "ReplicaSet", "web-58b7d6c9f", "58b7d6c9f"),
+			},
+		},
+		ExpectedReapableBudgets: 1,
+		ExpectedReapedBudgets:   1,
+	}
+	testCase.Run(t)
+
+	if reasons := reaper.ReapableReasons["namespace-1/pdb-1"]; len(reasons) != 1 || reasons[0] != ReasonMixedControllers {
+		t.Fatalf("expected namespace-1/pdb-1 to be reapable due to %v, got: %v", ReasonMixedControllers, reasons)
+	}
+	events, err := reaper.KubernetesClient.CoreV1().Events("namespace-1").List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("failed to list events: %v", err)
+	}
+	var found bool
+	for _, event := range events.Items {
+		if event.Reason == EventReasonMixedControllersDetected && strings.Contains(event.Message, "Deployment/web, Deployment/web-canary") {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("expected a %v event listing both Deployments, got: %+v", EventReasonMixedControllersDetected, events.Items)
+	}
+}
+
+func TestSingleNode(t *testing.T) {
+	reaper := _fakeReaperContext()
+	if err := reaper.applyReapModes([]string{ReapModeSingleNode}); err != nil {
+		t.Fatalf("failed to apply reap modes: %v", err)
+	}
+	onNode := func(name, namespace, node string) MockPod {
+		pod := _mockPod(name, namespace, map[string]string{"app": "web"}, false, 0, false)
+		pod.NodeName = node
+		return pod
+	}
+	testCase := ReaperUnitTest{
+		TestDescription: "Blocking PDBs whose pods are all on a single node are reapable",
+		FakeReaper:      reaper,
+		Mocks: KubernetesMockAPI{
+			Namespaces: []MockNamespace{
+				_mockNamespace("namespace-1"),
+				_mockNamespace("namespace-2"),
+				_mockNamespace("namespace-3"),
+				_mockNamespace("namespace-4"),
+			},
+			PDBs: []MockPDB{
+				_mockPDB("pdb-1", "namespace-1", nil, &intStrOneInt, _selector("app=web"), 2, 0),
+				_mockPDB("pdb-2", "namespace-2", nil, &intStrOneInt, _selector("app=web"), 2, 0),
+				_mockPDB("pdb-3", "namespace-3", nil, &intStrOneInt, _selector("app=web"), 2, 0),
+				_mockPDB("pdb-4", "namespace-4", nil, &intStrOneInt, _selector("app=web"), 1, 0),
+			},
+			Pods: []MockPod{
+				// all pods on a single node
+				onNode("pod-1", "namespace-1", "node-1"),
+				onNode("pod-2", "namespace-1", "node-1"),
+				// pods spread over two nodes
+				onNode("pod-1", "namespace-2", "node-1"),
+				onNode("pod-2", "namespace-2", "node-2"),
+				// a pod which is not scheduled yet
+				onNode("pod-1", "namespace-3", "node-1"),
+				onNode("pod-2", "namespace-3", ""),
+				// a single pod is always on a single node
+				onNode("pod-1", "namespace-4", "node-1"),
+			},
+		},
+		ExpectedReapableBudgets: 1,
+		ExpectedReapedBudgets:   1,
+	}
+	testCase.Run(t)
+
+	if reasons := reaper.ReapableReasons["namespace-1/pdb-1"]; len(reasons) != 1 || reasons[0] != ReasonSingleNode {
+		t.Fatalf("expected namespace-1/pdb-1 to be reapable due to %v, got: %v", ReasonSingleNode, reasons)
+	}
+	events, err := reaper.KubernetesClient.CoreV1().Events("namespace-1").List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("failed to list events: %v", err)
+	}
+	var found bool
+	for _, event := range events.Items {
+		if event.Reason == EventReasonSingleNodeDetected && event.Message == fmt.Sprintf(EventMessageSingleNodeFmt, "namespace-1/pdb-1", "node-1") {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("expected a %v event naming node-1, got: %+v", EventReasonSingleNodeDetected, events.Items)
+	}
+}
+
+func TestPodListForbidden(t *testing.T) {
+	reaper := _fakeReaperContext()
+	client := reaper.KubernetesClient.(*fake.Clientset)
+	client.PrependReactor("list", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetNamespace() != "namespace-2" {
+			return false, nil, nil
+		}
+		return true, nil, kerrors.NewForbidden(schema.GroupResource{Resource: "pods"}, "", errors.New("pods is forbidden"))
+	})
+	testCase := ReaperUnitTest{
+		TestDescription: "Namespaces in which listing pods is forbidden degrade to status-based detection",
+		FakeReaper:      reaper,
+		Mocks: KubernetesMockAPI{
+			Namespaces: []MockNamespace{
+				_mockNamespace("namespace-1"),
+				_mockNamespace("namespace-2"),
+			},
+			PDBs: []MockPDB{
+				// pods can be listed, crashloop is detected
+				_mockPDB("pdb-1", "namespace-1", nil, &intStrOneInt, _selector("app=web"), 1, 0),
+				// misconfigured according to its status
+				_mockPDB("pdb-1", "namespace-2", &intStrOneInt, nil, _selector("app=db"), 1, 0),
+				// crashlooping and overlapping, which can't be detected without listing pods
+				_mockPDB("pdb-2", "namespace-2", nil, &intStrOneInt, _selector("app=web"), 2, 0),
+				_mockPDB("pdb-3", "namespace-2", nil, &intStrOneInt, _selector("app=web"), 2, 1),
+			},
+			Pods: []MockPod{
+				_mockPod("pod-1", "namespace-1", map[string]string{"app": "web"}, true, 5, false),
+				_mockPod("pod-1", "namespace-2", map[string]string{"app": "db"}, false, 0, false),
+				_mockPod("pod-2", "namespace-2", map[string]string{"app": "web"}, true, 5, false),
+				_mockPod("pod-3", "namespace-2", map[string]string{"app": "web"}, true, 5, false),
+			},
+		},
+		ExpectedReapableBudgets: 2,
+		ExpectedReapedBudgets:   2,
+	}
+	testCase.Run(t)
+
+	if reasons := reaper.ReapableReasons["namespace-1/pdb-1"]; len(reasons) != 1 || reasons[0] != ReasonBlockingCrashLoop {
+		t.Fatalf("expected namespace-1/pdb-1 to be reapable due to %v, got: %v", ReasonBlockingCrashLoop, reasons)
+	}
+	if reasons := reaper.ReapableReasons["namespace-2/pdb-1"]; len(reasons) != 1 || reasons[0] != ReasonBlocking {
+		t.Fatalf("expected namespace-2/pdb-1 to be reapable due to %v, got: %v", ReasonBlocking, reasons)
+	}
+	if !reaper.degradedNamespaces["namespace-2"] || reaper.degradedNamespaces["namespace-1"] {
+		t.Fatalf("expected only namespace-2 to be degraded, got: %v", reaper.degradedNamespaces)
+	}
+}
+
+func TestMatchedPodsSummaryPushgateway(t *testing.T) {
+	reaper := _fakeReaperContext()
+	pgw := _fakePushgateway(t)
+	reaper.MetricsAPI = common.NewPrometheusAPI(pgw.URL)
+	testCase := ReaperUnitTest{
+		TestDescription: "Tests that the average and max pods matched per PDB are both kept on the pushgateway",
+		FakeReaper:      reaper,
+		Mocks: KubernetesMockAPI{
+			Namespaces: []MockNamespace{
+				_mockNamespace("namespace-1"),
+			},
+			PDBs: []MockPDB{
+				_mockPDB("pdb-1", "namespace-1", nil, &intStrOneInt, _selector("app=app-1"), 1, 1),
+				_mockPDB("pdb-2", "namespace-1", nil, &intStrOneInt, _selector("app=app-2"), 3, 1),
+			},
+			Pods: []MockPod{
+				_mockPod("pod-1", "namespace-1", map[string]string{"app": "app-1"}, false, 0, false),
+				_mockPod("pod-2a", "namespace-1", map[string]string{"app": "app-2"}, false, 0, false),
+				_mockPod("pod-2b", "namespace-1", map[string]string{"app": "app-2"}, false, 0, false),
+				_mockPod("pod-2c", "namespace-1", map[string]string{"app": "app-2"}, false, 0, false),
+			},
+		},
+		ExpectedReapableBudgets: 0,
+		ExpectedReapedBudgets:   0,
+	}
+	testCase.Run(t)
+
+	pgw.assertPushed(t, map[string]string{"namespace": "namespace-1"}, map[string]float64{
+		PdbReaperMatchedPodsAverageMetricName: 2,
+		PdbReaperMatchedPodsMaxMetricName:     3,
+	})
+}
+
+func TestMatchedPodsSummaryMetrics(t *testing.T) {
+	reaper := _fakeReaperContext()
+	metrics := &fakeMetricsAPI{}
+	reaper.MetricsAPI = metrics
+	pods := func(namespace, app string, count int) []MockPod {
+		mocks := make([]MockPod, 0, count)
+		for i := 1; i <= count; i++ {
+			mocks = append(mocks, _mockPod(fmt.Sprintf("%v-%v", app, i), namespace, map[string]string{"app": app}, false, 0, false))
+		}
+		return mocks
+	}
+	mockPods := pods("namespace-1", "app-1", 1)
+	mockPods = append(mockPods, pods("namespace-1", "app-2", 2)...)
+	mockPods = append(mockPods, pods("namespace-1", "app-3", 6)...)
+	mockPods = append(mockPods, pods("namespace-2", "app-4", 2)...)
+	mockPods = append(mockPods, pods("namespace-2", "app-5", 4)...)
+	testCase := ReaperUnitTest{
+		TestDescription: "The average and max pods matched per PDB are exposed for each namespace",
+		FakeReaper:      reaper,
+		Mocks: KubernetesMockAPI{
+			Namespaces: []MockNamespace{
+				_mockNamespace("namespace-1"),
+				_mockNamespace("namespace-2"),
+			},
+			PDBs: []MockPDB{
+				_mockPDB("pdb-1", "namespace-1", nil, &intStrOneInt, _selector("app=app-1"), 1, 1),
+				_mockPDB("pdb-2", "namespace-1", nil, &intStrOneInt, _selector("app=app-2"), 2, 1),
+				_mockPDB("pdb-3", "namespace-1", nil, &intStrOneInt, _selector("app=app-3"), 6, 1),
+				_mockPDB("pdb-4", "namespace-2", nil, &intStrOneInt, _selector("app=app-4"), 2, 1),
+				_mockPDB("pdb-5", "namespace-2", nil, &intStrOneInt, _selector("app=app-5"), 4, 1),
+			},
+			Pods: mockPods,
+		},
+		ExpectedReapableBudgets: 0,
+		ExpectedReapedBudgets:   0,
+	}
+	testCase.Run(t)
+
+	expected := map[string][2]float64{
+		"namespace-1": {3, 6},
+		"namespace-2": {3, 4},
+	}
+	for namespace, values := range expected {
+		tags := map[string]string{"namespace": namespace}
+		if v, ok := metrics.lastValue(PdbReaperMatchedPodsAverageMetricName, tags); !ok || v != values[0] {
+			t.Fatalf("expected %v average of %v for %v, got: %v", PdbReaperMatchedPodsAverageMetricName, values[0], namespace, v)
+		}
+		if v, ok := metrics.lastValue(PdbReaperMatchedPodsMaxMetricName, tags); !ok || v != values[1] {
+			t.Fatalf("expected %v max of %v for %v, got: %v", PdbReaperMatchedPodsMaxMetricName, values[1], namespace, v)
+		}
+	}
+}
+
+func TestPausedNamespace(t *testing.T) {
+	paused := _mockNamespace("namespace-1")
+	paused.Annotations = map[string]string{PausedAnnotationKey: "true"}
+	unpaused := _mockNamespace("namespace-2")
+	unpaused.Annotations = map[string]string{PausedAnnotationKey: "false"}
+	mocks := KubernetesMockAPI{
+		Namespaces: []MockNamespace{
+			paused,
+			unpaused,
+			_mockNamespace("namespace-3"),
+		},
+		PDBs: []MockPDB{
+			_mockPDB("pdb-1", "namespace-1", nil, &intStrZeroInt, _selector("app=app-1"), 1, 0),
+			_mockPDB("pdb-1", "namespace-2", nil, &intStrZeroInt, _selector("app=app-1"), 1, 0),
+			_mockPDB("pdb-1", "namespace-3", nil, &intStrZeroInt, _selector("app=app-1"), 1, 0),
+		},
+	}
+
+	reaper := _fakeReaperContext()
+	reaper.HonorPausedNamespaces = true
+	testCase := ReaperUnitTest{
+		TestDescription:         "Reapable PDBs in paused namespaces are detected but not reaped",
+		FakeReaper:              reaper,
+		Mocks:                   mocks,
+		ExpectedReapableBudgets: 3,
+		ExpectedReapedBudgets:   2,
+	}
+	testCase.Run(t)
+	if _, err := reaper.KubernetesClient.PolicyV1().PodDisruptionBudgets("namespace-1").Get(context.Background(), "pdb-1", metav1.GetOptions{}); err != nil {
+		t.Fatalf("expected PDB in paused namespace to be kept: %v", err)
+	}
+
+	reaper = _fakeReaperContext()
+	reaper.HonorPausedNamespaces = true
+	reaper.PausedSkipDetection = true
+	testCase = ReaperUnitTest{
+		TestDescription:         "PDBs in paused namespaces are not evaluated with --paused-skip-detection",
+		FakeReaper:              reaper,
+		Mocks:                   mocks,
+		ExpectedReapableBudgets: 2,
+		ExpectedReapedBudgets:   2,
+	}
+	testCase.Run(t)
+	if _, ok := reaper.ReapableReasons["namespace-1/pdb-1"]; ok {
+		t.Fatalf("expected PDB in paused namespace not to be evaluated")
+	}
+
+	reaper = _fakeReaperContext()
+	testCase = ReaperUnitTest{
+		TestDescription:         "The paused annotation is ignored without --honor-paused-namespaces",
+		FakeReaper:              reaper,
+		Mocks:                   mocks,
+		ExpectedReapableBudgets: 3,
+		ExpectedReapedBudgets:   3,
+	}
+	testCase.Run(t)
+}
+
+func TestMaxWritesPerRun(t *testing.T) {
+	mocks := KubernetesMockAPI{
+		Namespaces: []MockNamespace{
+			_mockNamespace("namespace-1"),
+			_mockNamespace("namespace-2"),
+			_mockNamespace("namespace-3"),
+		},
+		PDBs: []MockPDB{
+			_mockPDB("pdb-1", "namespace-1", nil, &intStrZeroInt, _selector("app=app-1"), 1, 0),
+			_mockPDB("pdb-1", "namespace-2", nil, &intStrZeroInt, _selector("app=app-1"), 1, 0),
+			_mockPDB("pdb-1", "namespace-3", nil, &intStrZeroInt, _selector("app=app-1"), 1, 0),
+		},
+	}
+	countEvents := func(reaper *ReaperContext) *int {
+		var created int
+		client := reaper.KubernetesClient.(*fake.Clientset)
+		client.PrependReactor("create", "events", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			created++
+			return false, nil, nil
+		})
+		return &created
+	}
+
+	reaper := _fakeReaperContext()
+	reaper.MaxWritesPerRun = 2
+	metrics := &fakeMetricsAPI{}
+	reaper.MetricsAPI = metrics
+	created := countEvents(reaper)
+	testCase := ReaperUnitTest{
+		TestDescription:         "Events beyond --max-writes-per-run are skipped while deletions proceed",
+		FakeReaper:              reaper,
+		Mocks:                   mocks,
+		ExpectedReapableBudgets: 3,
+		ExpectedReapedBudgets:   3,
+	}
+	testCase.Run(t)
+	if *created != 2 {
+		t.Fatalf("expected 2 events to be created, got: %v", *created)
+	}
+	if reaper.SuppressedWritesCount == 0 {
+		t.Fatalf("expected writes beyond the cap to be suppressed")
+	}
+	if v, ok := metrics.lastValue(PdbReaperSuppressedWritesMetricName, nil); !ok || v != float64(reaper.SuppressedWritesCount) {
+		t.Fatalf("expected %v metric of %v, got: %v", PdbReaperSuppressedWritesMetricName, reaper.SuppressedWritesCount, v)
+	}
+
+	reaper = _fakeReaperContext()
+	reaper.MaxWritesPerRun = 2
+	reaper.DeferReapsOnWriteCap = true
+	created = countEvents(reaper)
+	testCase = ReaperUnitTest{
+		TestDescription:         "Deletions are deferred once --max-writes-per-run is reached with --defer-reaps-on-write-cap",
+		FakeReaper:              reaper,
+		Mocks:                   mocks,
+		ExpectedReapableBudgets: 3,
+		ExpectedReapedBudgets:   0,
+	}
+	testCase.Run(t)
+	if *created != 2 {
+		t.Fatalf("expected 2 events to be created, got: %v", *created)
+	}
+
+	reaper = _fakeReaperContext()
+	reaper.MaxWritesPerRun = 3
+	reaper.SummaryEventObject = &corev1.ObjectReference{Kind: "ConfigMap", Namespace: "kube-system", Name: "pdb-reaper"}
+	created = countEvents(reaper)
+	testCase = ReaperUnitTest{
+		TestDescription:         "The run summary event counts against --max-writes-per-run",
+		FakeReaper:              reaper,
+		Mocks:                   mocks,
+		ExpectedReapableBudgets: 3,
+		ExpectedReapedBudgets:   3,
+	}
+	testCase.Run(t)
+	if *created != 3 {
+		t.Fatalf("expected 3 events to be created, got: %v", *created)
+	}
+	events, err := reaper.KubernetesClient.CoreV1().Events("kube-system").List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("failed to list events: %v", err)
+	}
+	if len(events.Items) != 0 {
+		t.Fatalf("expected the run summary event to be suppressed, got: %+v", events.Items)
+	}
+}
+
+func TestMaxWritesPerRunPushgateway(t *testing.T) {
+	reaper := _fakeReaperContext()
+	reaper.MaxWritesPerRun = 1
+	pgw := _fakePushgateway(t)
+	reaper.MetricsAPI = common.NewPrometheusAPI(pgw.URL)
+	testCase := ReaperUnitTest{
+		TestDescription: "Tests that the suppressed writes metric is kept along the other cluster metrics on the pushgateway",
+		FakeReaper:      reaper,
+		Mocks: KubernetesMockAPI{
+			Namespaces: []MockNamespace{
+				_mockNamespace("namespace-1"),
+				_mockNamespace("namespace-2"),
+			},
+			PDBs: []MockPDB{
+				_mockPDB("pdb-1", "namespace-1", nil, &intStrZeroInt, _selector("app=app-1"), 1, 0),
+				_mockPDB("pdb-1", "namespace-2", nil, &intStrZeroInt, _selector("app=app-1"), 1, 0),
+			},
+		},
+		ExpectedReapableBudgets: 2,
+		ExpectedReapedBudgets:   2,
+	}
+	testCase.Run(t)
+	if reaper.SuppressedWritesCount == 0 {
+		t.Fatalf("expected writes beyond the cap to be suppressed")
+	}
+
+	pgw.assertPushed(t, nil, map[string]float64{
+		PdbReaperSuppressedWritesMetricName: float64(reaper.SuppressedWritesCount),
+		PdbReaperReapableCountMetricName:    2,
+		PdbReaperReapedCountMetricName:      2,
+	})
+}
+
+func TestRunSummaryEventFixManifestsDir(t *testing.T) {
+	reaper := _fakeReaperContext()
+	reaper.FixManifestsDir = t.TempDir()
+	reaper.SummaryEventObject = &corev1.ObjectReference{Kind: "ConfigMap", Namespace: "kube-system", Name: "pdb-reaper"}
+	testCase := ReaperUnitTest{
+		TestDescription: "No run summary event is created with --fix-manifests-dir",
+		FakeReaper:      reaper,
+		Mocks: KubernetesMockAPI{
+			Namespaces: []MockNamespace{
+				_mockNamespace("namespace-1"),
+			},
+			PDBs: []MockPDB{
+				_mockPDB("pdb-1", "namespace-1", nil, &intStrZeroInt, _selector("app=app-1"), 1, 0),
+			},
+		},
+		ExpectedReapableBudgets: 1,
+		ExpectedReapedBudgets:   0,
+	}
+	testCase.Run(t)
+
+	events, err := reaper.KubernetesClient.CoreV1().Events("kube-system").List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("failed to list events: %v", err)
+	}
+	if len(events.Items) != 0 {
+		t.Fatalf("expected no run summary event, got: %+v", events.Items)
+	}
+}
+
+func TestExpectedPodsSource(t *testing.T) {
+	minAvailablePercent := intstr.FromString("50%")
+	minAvailableTwo := intstr.FromInt(2)
+	mocks := KubernetesMockAPI{
+		Namespaces: []MockNamespace{
+			_mockNamespace("namespace-1"),
+			_mockNamespace("namespace-2"),
+		},
+		PDBs: []MockPDB{
+			// 2 of 3 expected pods are required, but only 2 pods are live
+			_mockPDB("pdb-1", "namespace-1", &minAvailableTwo, nil, _selector("app=app-1"), 3, 0),
+			// 50% of 4 expected pods are required, but only 1 pod is live
+			_mockPDB("pdb-1", "namespace-2", &minAvailablePercent, nil, _selector("app=app-1"), 4, 0),
+		},
+		Pods: []MockPod{
+			_mockPod("pod-1", "namespace-1", map[string]string{"app": "app-1"}, false, 0, false),
+			_mockPod("pod-2", "namespace-1", map[string]string{"app": "app-1"}, false, 0, false),
+			_mockPod("pod-1", "namespace-2", map[string]string{"app": "app-1"}, false, 0, false),
+		},
+	}
+
+	reaper := _fakeReaperContext()
+	reaper.ExpectedPodsSource = ExpectedPodsSourceStatus
+	testCase := ReaperUnitTest{
+		TestDescription:         "Budgets are resolved against the expected pods of the status",
+		FakeReaper:              reaper,
+		Mocks:                   mocks,
+		ExpectedReapableBudgets: 0,
+		ExpectedReapedBudgets:   0,
+	}
+	testCase.Run(t)
+
+	reaper = _fakeReaperContext()
+	reaper.ExpectedPodsSource = ExpectedPodsSourceLive
+	testCase = ReaperUnitTest{
+		TestDescription:         "Budgets are resolved against the live pods with --expected-pods-source=live",
+		FakeReaper:              reaper,
+		Mocks:                   mocks,
+		ExpectedReapableBudgets: 2,
+		ExpectedReapedBudgets:   2,
+	}
+	testCase.Run(t)
+	for _, namespacedName := range []string{"namespace-1/pdb-1", "namespace-2/pdb-1"} {
+		if reasons := reaper.ReapableReasons[namespacedName]; len(reasons) != 1 || reasons[0] != ReasonBlocking {
+			t.Fatalf("expected %v to be reapable due to %v, got: %v", namespacedName, ReasonBlocking, reasons)
+		}
+	}
+}
+
+func TestNamespaceFindingsEvents(t *testing.T) {
+	reaper := _fakeReaperContext()
+	reaper.NamespaceFindingsEvents = true
+	reaper.NamespaceFindingsOnly = true
+	var events []*corev1.Event
+	client := reaper.KubernetesClient.(*fake.Clientset)
+	client.PrependReactor("create", "events", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		event := action.(k8stesting.CreateAction).GetObject().(*corev1.Event)
+		events = append(events, event)
+		return true, event, nil
+	})
+	testCase := ReaperUnitTest{
+		TestDescription: "One event per namespace summarizes its findings",
+		FakeReaper:      reaper,
+		Mocks: KubernetesMockAPI{
+			Namespaces: []MockNamespace{
+				_mockNamespace("namespace-1"),
+				_mockNamespace("namespace-2"),
+			},
+			PDBs: []MockPDB{
+				_mockPDB("pdb-2", "namespace-1", nil, &intStrZeroInt, _selector("app=app-2"), 1, 0),
+				_mockPDB("pdb-1", "namespace-1", nil, &intStrZeroInt, _selector("app=app-1"), 1, 0),
+				_mockPDB("pdb-3", "namespace-1", nil, &intStrOneInt, _selector("app=app-3"), 1, 0),
+				_mockPDB("pdb-1", "namespace-2", nil, &intStrZeroInt, _selector("app=app-1"), 1, 0),
+			},
+			Pods: []MockPod{
+				_mockPod("pod-1", "namespace-1", map[string]string{"app": "app-1"}, false, 0, false),
+				_mockPod("pod-2", "namespace-1", map[string]string{"app": "app-2"}, false, 0, false),
+				_mockPod("pod-3", "namespace-1", map[string]string{"app": "app-3"}, true, 5, false),
+				_mockPod("pod-1", "namespace-2", map[string]string{"app": "app-1"}, false, 0, false),
+			},
+		},
+		ExpectedReapableBudgets: 4,
+		ExpectedReapedBudgets:   4,
+	}
+	testCase.Run(t)
+
+	findings := make(map[string]string)
+	var deleted int
+	for _, event := range events {
+		switch event.Reason {
+		case EventReasonNamespaceFindings:
+			if event.InvolvedObject.Kind != "Namespace" || event.InvolvedObject.Name != event.Namespace {
+				t.Fatalf("expected findings event to involve namespace %v, got: %+v", event.Namespace, event.InvolvedObject)
+			}
+			findings[event.Namespace] = event.Message
+		case EventReasonPodDisruptionBudgetDeleted:
+			deleted++
+		default:
+			t.Fatalf("expected no per-PDB detection events with --namespace-findings-only, got: %v", event.Reason)
+		}
+	}
+
+	expected := map[string]string{
+		"namespace-1": fmt.Sprintf(EventMessageNamespaceFindingsFmt, 3, "namespace-1", "BlockingPodDisruptionBudget=2 (pdb-1,pdb-2); BlockingPodDisruptionBudgetWithCrashLoop=1 (pdb-3)"),
+		"namespace-2": fmt.Sprintf(EventMessageNamespaceFindingsFmt, 1, "namespace-2", "BlockingPodDisruptionBudget=1 (pdb-1)"),
+	}
+	if len(findings) != len(expected) {
+		t.Fatalf("expected findings events for %v, got: %v", expected, findings)
+	}
+	for namespace, message := range expected {
+		if findings[namespace] != message {
+			t.Fatalf("expected findings event in %v to be '%v', got: '%v'", namespace, message, findings[namespace])
+		}
+	}
+	if deleted != 4 {
+		t.Fatalf("expected deletion events to still be published, got: %v", deleted)
+	}
+}
+
+func TestCrashLoopPredicate(t *testing.T) {
+	waiting := func(reason string, restarts int32) corev1.ContainerStatus {
+		return corev1.ContainerStatus{RestartCount: restarts, State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: reason}}}
+	}
+	terminated := func(exitCode, restarts int32) corev1.ContainerStatus {
+		return corev1.ContainerStatus{RestartCount: restarts, State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: exitCode}}}
+	}
+	tests := []struct {
+		name              string
+		reasons           []string
+		includeTerminated bool
+		minExitCode       int
+		status            corev1.ContainerStatus
+		expected          bool
+	}{
+		{"DefaultCrashLoopBackOff", nil, false, 1, waiting(ReasonCrashLoopBackOff, 5), true},
+		{"DefaultBelowRestarts", nil, false, 1, waiting(ReasonCrashLoopBackOff, 4), false},
+		{"DefaultOtherReason", nil, false, 1, waiting("RunContainerError", 5), false},
+		{"DefaultTerminated", nil, false, 1, terminated(1, 5), false},
+		{"CustomReason", []string{ReasonCrashLoopBackOff, "RunContainerError"}, false, 1, waiting("RunContainerError", 5), true},
+		{"CustomReasonExcludesDefault", []string{"RunContainerError"}, false, 1, waiting(ReasonCrashLoopBackOff, 5), false},
+		{"TerminatedNonZero", nil, true, 1, terminated(1, 5), true},
+		{"TerminatedZero", nil, true, 1, terminated(0, 5), false},
+		{"TerminatedBelowMinExitCode", nil, true, 128, terminated(1, 5), false},
+		{"TerminatedAboveMinExitCode", nil, true, 128, terminated(137, 5), true},
+		{"TerminatedBelowRestarts", nil, true, 1, terminated(1, 4), false},
+		{"TerminatedStillCountsWaiting", nil, true, 1, waiting(ReasonCrashLoopBackOff, 5), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reaper := _fakeReaperContext()
+			reaper.CrashLoopReasons = tt.reasons
+			reaper.CrashLoopIncludeTerminated = tt.includeTerminated
+			reaper.CrashLoopMinExitCode = tt.minExitCode
+			if got := reaper.crashLoopPredicate(5)(tt.status); got != tt.expected {
+				t.Fatalf("expected crashlooping: %v, got: %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestPlanApply(t *testing.T) {
+	planPath := filepath.Join(t.TempDir(), "plan.json")
+	reaper := _fakeReaperContext()
+	reaper.PlanOut = planPath
+	testCase := ReaperUnitTest{
+		TestDescription: "Planned deletions are written to the plan instead of being made",
+		FakeReaper:      reaper,
+		Mocks: KubernetesMockAPI{
+			Namespaces: []MockNamespace{
+				_mockNamespace("namespace-1"),
+				_mockNamespace("namespace-2"),
+				_mockNamespace("namespace-3"),
+			},
+			PDBs: []MockPDB{
+				_mockPDB("pdb-1", "namespace-1", nil, &intStrZeroInt, _selector("app=app-1"), 1, 0),
+				_mockPDB("pdb-1", "namespace-2", nil, &intStrZeroInt, _selector("app=app-1"), 1, 0),
+				_mockPDB("pdb-1", "namespace-3", nil, &intStrZeroInt, _selector("app=app-1"), 1, 0),
+			},
+		},
+		ExpectedReapableBudgets: 3,
+		ExpectedReapedBudgets:   0,
+	}
+	testCase.Run(t)
+
+	plan, err := readPlan(planPath)
+	if err != nil {
+		t.Fatalf("failed to read plan: %v", err)
+	}
+	if len(plan.Reaps) != 3 {
+		t.Fatalf("expected 3 planned deletions, got: %+v", plan.Reaps)
+	}
+	for _, planned := range plan.Reaps {
+		if planned.PrimaryReason != ReasonBlocking || len(planned.Reasons) != 1 {
+			t.Fatalf("expected planned deletion of %v/%v for %v, got: %+v", planned.Namespace, planned.Name, ReasonBlocking, planned)
+		}
+	}
+
+	// namespace-2/pdb-1 changes and namespace-3/pdb-1 is deleted between plan and apply
+	client := reaper.KubernetesClient
+	changed, err := client.PolicyV1().PodDisruptionBudgets("namespace-2").Get(context.Background(), "pdb-1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get PDB: %v", err)
+	}
+	changed.Generation++
+	changed.Spec.MaxUnavailable = &intStrOneInt
+	if _, err := client.PolicyV1().PodDisruptionBudgets("namespace-2").Update(context.Background(), changed, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("failed to update PDB: %v", err)
+	}
+	if err := client.PolicyV1().PodDisruptionBudgets("namespace-3").Delete(context.Background(), "pdb-1", metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("failed to delete PDB: %v", err)
+	}
+
+	applier := _fakeReaperContext()
+	applier.KubernetesClient = client
+	applier.ApplyPlan = planPath
+	if err := applier.execute(); err != nil {
+		t.Fatalf("execution failed: %v", err)
+	}
+	if applier.ReapedPodDisruptionBudgetCount != 1 || applier.SkippedPlannedReapsCount != 2 {
+		t.Fatalf("expected 1 planned PDB to be reaped and 2 to be skipped, got: %v reaped and %v skipped", applier.ReapedPodDisruptionBudgetCount, applier.SkippedPlannedReapsCount)
+	}
+	if _, err := client.PolicyV1().PodDisruptionBudgets("namespace-1").Get(context.Background(), "pdb-1", metav1.GetOptions{}); !kerrors.IsNotFound(err) {
+		t.Fatalf("expected planned PDB namespace-1/pdb-1 to be deleted, got: %v", err)
+	}
+	if _, err := client.PolicyV1().PodDisruptionBudgets("namespace-2").Get(context.Background(), "pdb-1", metav1.GetOptions{}); err != nil {
+		t.Fatalf("expected changed PDB namespace-2/pdb-1 to be kept: %v", err)
+	}
+}
+
+func TestPlatformExclusionsConfigMap(t *testing.T) {
+	tests := []struct {
+		name             string
+		localConfigMap   string
+		expectedExcluded []string
+		expectedReapable int
+	}{
+		{"LocalConfigMapOmitsPlatform", "namespace-2", []string{"namespace-1", "namespace-2", "namespace-3"}, 1},
+		{"NoLocalConfigMap", "", []string{"namespace-1", "namespace-3"}, 2},
+		{"LocalConfigMapOverlapsPlatform", "namespace-3", []string{"namespace-1", "namespace-3"}, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reaper := _fakeReaperContext()
+			reaper.ExcludedNamespaces = []string{"namespace-1"}
+			reaper.flagExcludedNamespaces = []string{"namespace-1"}
+			reaper.PlatformExclusionsConfigMapNamespace = "platform-system"
+			reaper.PlatformExclusionsConfigMapName = "pdb-reaper-mandatory-exclusions"
+			platform := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "pdb-reaper-mandatory-exclusions", Namespace: "platform-system"},
+				Data:       map[string]string{DefaultExcludedNamespacesConfigMapKey: "namespace-3"},
+			}
+			if _, err := reaper.KubernetesClient.CoreV1().ConfigMaps("platform-system").Create(context.Background(), platform, metav1.CreateOptions{}); err != nil {
+				t.Fatalf("failed to create configmap: %v", err)
+			}
+			if tt.localConfigMap != "" {
+				reaper.ExcludedNamespacesConfigMapNamespace = "kube-system"
+				reaper.ExcludedNamespacesConfigMapName = "pdb-reaper-exclusions"
+				reaper.ExcludedNamespacesConfigMapKey = DefaultExcludedNamespacesConfigMapKey
+				local := &corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Name: "pdb-reaper-exclusions", Namespace: "kube-system"},
+					Data:       map[string]string{DefaultExcludedNamespacesConfigMapKey: tt.localConfigMap},
+				}
+				if _, err := reaper.KubernetesClient.CoreV1().ConfigMaps("kube-system").Create(context.Background(), local, metav1.CreateOptions{}); err != nil {
+					t.Fatalf("failed to create configmap: %v", err)
+				}
+			}
+			mocks := KubernetesMockAPI{}
+			for i := 1; i <= 4; i++ {
+				namespace := fmt.Sprintf("namespace-%v", i)
+				mocks.Namespaces = append(mocks.Namespaces, _mockNamespace(namespace))
+				mocks.PDBs = append(mocks.PDBs, _mockPDB("pdb-1", namespace, nil, &intStrZeroInt, _selector("app=app-1"), 1, 0))
+			}
+			testCase := ReaperUnitTest{
+				TestDescription:         "Platform exclusions always apply, even when the local exclusions omit them",
+				FakeReaper:              reaper,
+				Mocks:                   mocks,
+				ExpectedReapableBudgets: tt.expectedReapable,
+				ExpectedReapedBudgets:   tt.expectedReapable,
+			}
+			testCase.Run(t)
+
+			if strings.Join(reaper.ExcludedNamespaces, ",") != strings.Join(tt.expectedExcluded, ",") {
+				t.Fatalf("expected excluded namespaces %v, got: %v", tt.expectedExcluded, reaper.ExcludedNamespaces)
+			}
+			if _, err := reaper.KubernetesClient.PolicyV1().PodDisruptionBudgets("namespace-3").Get(context.Background(), "pdb-1", metav1.GetOptions{}); err != nil {
+				t.Fatalf("expected PDB in platform excluded namespace to be kept: %v", err)
+			}
+		})
+	}
+
+	t.Run("UnreadableConfigMap", func(t *testing.T) {
+		reaper := _fakeReaperContext()
+		reaper.PlatformExclusionsConfigMapNamespace = "platform-system"
+		reaper.PlatformExclusionsConfigMapName = "pdb-reaper-mandatory-exclusions"
+		reaper.KubernetesClient.(*fake.Clientset).PrependReactor("get", "configmaps", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			return true, nil, kerrors.NewForbidden(schema.GroupResource{Resource: "configmaps"}, "pdb-reaper-mandatory-exclusions", errors.New("forbidden"))
+		})
+		if err := reaper.loadExcludedNamespaces(); err == nil {
+			t.Fatal("expected an error when the platform exclusions configmap can't be read")
+		}
+	})
+
+	t.Run("MissingConfigMap", func(t *testing.T) {
+		reaper := _fakeReaperContext()
+		reaper.PlatformExclusionsConfigMapNamespace = "platform-system"
+		reaper.PlatformExclusionsConfigMapName = "pdb-reaper-mandatory-exclusions"
+		if err := reaper.loadExcludedNamespaces(); err == nil {
+			t.Fatal("expected an error when the platform exclusions configmap does not exist")
+		}
+	})
+}
+
+func TestStaleSelector(t *testing.T) {
+	reaper := _fakeReaperContext()
+	if err := reaper.applyReapModes([]string{ReapModeStaleSelector}); err != nil {
+		t.Fatalf("failed to apply reap modes: %v", err)
+	}
+	deployment := func(name, namespace string, labels map[string]string) {
+		d := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec: appsv1.DeploymentSpec{
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": labels["app"]}},
+				Template: corev1.PodTemplateSpec{ObjectMeta: metav1.ObjectMeta{Labels: labels}},
+			},
+		}
+		if _, err := reaper.KubernetesClient.AppsV1().Deployments(namespace).Create(context.Background(), d, metav1.CreateOptions{}); err != nil {
+			t.Fatalf("failed to create deployment: %v", err)
+		}
+	}
+	relabeled := map[string]string{"app": "web", "version": "v2"}
+	// relabeled from version=v1, the PDB still selects the old labels
+	deployment("web", "namespace-1", relabeled)
+	// the PDB follows the relabel
+	deployment("web", "namespace-2", relabeled)
+	// a deployment unrelated to the PDB
+	deployment("web", "namespace-3", relabeled)
+
+	testCase := ReaperUnitTest{
+		TestDescription: "PDBs left orphaned by a relabeled deployment are reapable",
+		FakeReaper:      reaper,
+		Mocks: KubernetesMockAPI{
+			Namespaces: []MockNamespace{
+				_mockNamespace("namespace-1"),
+				_mockNamespace("namespace-2"),
+				_mockNamespace("namespace-3"),
+				_mockNamespace("namespace-4"),
+			},
+			PDBs: []MockPDB{
+				_mockPDB("pdb-1", "namespace-1", nil, &intStrOneInt, _selector("app=web,version=v1"), 2, 0),
+				_mockPDB("pdb-1", "namespace-2", nil, &intStrOneInt, _selector("app=web,version=v2"), 2, 1),
+				_mockPDB("pdb-1", "namespace-3", nil, &intStrOneInt, _selector("app=api"), 0, 0),
+				// no deployment in the namespace
+				_mockPDB("pdb-1", "namespace-4", nil, &intStrOneInt, _selector("app=web,version=v1"), 0, 0),
+			},
+			Pods: []MockPod{
+				_mockPod("pod-1", "namespace-1", relabeled, false, 0, false),
+				_mockPod("pod-2", "namespace-1", relabeled, false, 0, false),
+				_mockPod("pod-1", "namespace-2", relabeled, false, 0, false),
+				_mockPod("pod-2", "namespace-2", relabeled, false, 0, false),
+				_mockPod("pod-1", "namespace-3", relabeled, false, 0, false),
+			},
+		},
+		ExpectedReapableBudgets: 1,
+		ExpectedReapedBudgets:   1,
+	}
+	testCase.Run(t)
+
+	if reasons := reaper.ReapableReasons["namespace-1/pdb-1"]; len(reasons) != 1 || reasons[0] != ReasonStaleSelector {
+		t.Fatalf("expected namespace-1/pdb-1 to be reapable due to %v, got: %v", ReasonStaleSelector, reasons)
+	}
+	events, err := reaper.KubernetesClient.CoreV1().Events("namespace-1").List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("failed to list events: %v", err)
+	}
+	var found bool
+	for _, event := range events.Items {
+		if event.Reason == EventReasonStaleSelectorDetected && event.Message == fmt.Sprintf(EventMessageStaleSelectorFmt, "namespace-1/pdb-1", "web", "version=v1") {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("expected a %v event naming deployment web, got: %+v", EventReasonStaleSelectorDetected, events.Items)
+	}
+}
+
+func TestCrashLoopPrecedenceWithoutCrashLoopReaping(t *testing.T) {
+	reaper := _fakeReaperContext()
+	reaper.ReapNotReadyThreshold = 10
+	// the defaults of --reap-crashloop and --crashloop-precedence
+	reaper.ReapCrashLoop = false
+	reaper.CrashLoopPrecedence = true
+	testCase := ReaperUnitTest{
+		TestDescription:         "Tests that crashlooping pods are still evaluated as not-ready when crashloop reaping is disabled",
+		FakeReaper:              reaper,
+		Mocks:                   _crashLoopPrecedenceMocks(),
+		ExpectedReapableBudgets: 1,
+		ExpectedReapedBudgets:   1,
+	}
+	testCase.Run(t)
+
+	reasons := reaper.ReapableReasons["namespace-1/pdb-1"]
+	if len(reasons) != 1 || reasons[0] != ReasonBlockingNotReadyState {
+		t.Fatalf("assertion failed, expected reasons [%v], got: %v", ReasonBlockingNotReadyState, reasons)
+	}
+}
+
+func TestProtectedPriorityClassPodListForbidden(t *testing.T) {
+	reaper := _fakeReaperContext()
+	reaper.ProtectedPriorityClasses = DefaultProtectedPriorityClasses
+	client := reaper.KubernetesClient.(*fake.Clientset)
+	client.PrependReactor("list", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetNamespace() != "namespace-1" {
+			return false, nil, nil
+		}
+		return true, nil, kerrors.NewForbidden(schema.GroupResource{Resource: "pods"}, "", errors.New("pods is forbidden"))
+	})
+	testCase := ReaperUnitTest{
+		TestDescription: "A PDB whose pods can't be checked for protected priority classes is skipped without aborting the run",
+		FakeReaper:      reaper,
+		Mocks: KubernetesMockAPI{
+			Namespaces: []MockNamespace{
+				_mockNamespace("namespace-1"),
+				_mockNamespace("namespace-2"),
+			},
+			PDBs: []MockPDB{
+				_mockPDB("pdb-1", "namespace-1", nil, &intStrZeroInt, _selector("app=app-1"), 1, 0),
+				_mockPDB("pdb-1", "namespace-2", nil, &intStrZeroInt, _selector("app=app-1"), 1, 0),
+			},
+			Pods: []MockPod{
+				_mockPod("pod-1", "namespace-1", map[string]string{"app": "app-1"}, false, 0, false),
+				_mockPod("pod-1", "namespace-2", map[string]string{"app": "app-1"}, false, 0, false),
+			},
+		},
+		ExpectedReapableBudgets: 2,
+		ExpectedReapedBudgets:   1,
+	}
+	testCase.Run(t)
+
+	if _, err := reaper.KubernetesClient.PolicyV1().PodDisruptionBudgets("namespace-1").Get(context.Background(), "pdb-1", metav1.GetOptions{}); err != nil {
+		t.Fatalf("expected namespace-1/pdb-1 to be kept: %v", err)
+	}
+	if _, err := reaper.KubernetesClient.PolicyV1().PodDisruptionBudgets("namespace-2").Get(context.Background(), "pdb-1", metav1.GetOptions{}); !kerrors.IsNotFound(err) {
+		t.Fatalf("expected namespace-2/pdb-1 to be reaped, got: %v", err)
+	}
+}
+
+func TestZeroMaxUnavailableScaledToZero(t *testing.T) {
+	reaper := _fakeReaperContext()
+	reaper.ReapMisconfigured = false
+	reaper.ReapCrashLoop = false
+	reaper.ReapNotReady = false
+	reaper.ReapZeroMaxUnavailable = true
+	intStrQuarterPercent := intstr.FromString("25%")
+	testCase := ReaperUnitTest{
+		TestDescription: "Percentages of a workload scaled to zero are not treated as maxUnavailable 0",
+		FakeReaper:      reaper,
+		Mocks: KubernetesMockAPI{
+			Namespaces: []MockNamespace{
+				_mockNamespace("namespace-1"),
+				_mockNamespace("namespace-2"),
+			},
+			PDBs: []MockPDB{
+				// 25% of 0 expected pods resolves to 0 while the workload is scaled to zero
+				_mockPDB("pdb-1", "namespace-1", nil, &intStrQuarterPercent, _selector("app=app-1"), 0, 0),
+				// a literal 0 forbids all disruptions once the workload is scaled up
+				_mockPDB("pdb-2", "namespace-2", nil, &intStrZeroInt, _selector("app=app-2"), 0, 0),
+			},
+		},
+		ExpectedReapableBudgets: 1,
+		ExpectedReapedBudgets:   1,
+	}
+	testCase.Run(t)
+
+	if reasons := reaper.ReapableReasons["namespace-2/pdb-2"]; len(reasons) != 1 || reasons[0] != ReasonZeroMaxUnavailable {
+		t.Fatalf("expected namespace-2/pdb-2 to be reapable due to %v, got: %v", ReasonZeroMaxUnavailable, reasons)
+	}
+}
+
+func TestPlanApplyReapChecks(t *testing.T) {
+	planPath := filepath.Join(t.TempDir(), "plan.json")
+	plan := Plan{Timestamp: time.Now()}
+	for _, namespace := range []string{"namespace-1", "namespace-2", "namespace-3"} {
+		plan.Reaps = append(plan.Reaps, PlannedReap{Namespace: namespace, Name: "pdb-1", Reasons: []Reason{ReasonBlocking}, PrimaryReason: ReasonBlocking})
+	}
+	data, err := json.Marshal(plan)
+	if err != nil {
+		t.Fatalf("failed to marshal plan: %v", err)
+	}
+	if err := os.WriteFile(planPath, data, 0644); err != nil {
+		t.Fatalf("failed to write plan: %v", err)
+	}
+
+	reaper := _fakeReaperContext()
+	reaper.ApplyPlan = planPath
+	reaper.HonorPausedNamespaces = true
+	reaper.ProtectedPriorityClasses = DefaultProtectedPriorityClasses
+	paused := _mockNamespace("namespace-1")
+	paused.Annotations = map[string]string{PausedAnnotationKey: "true"}
+	critical := _mockPod("pod-1", "namespace-2", map[string]string{"app": "app-1"}, false, 0, false)
+	critical.PriorityClassName = "system-cluster-critical"
+	testCase := ReaperUnitTest{
+		TestDescription: "Planned deletions are subject to the same checks as the deletions of a run",
+		FakeReaper:      reaper,
+		Mocks: KubernetesMockAPI{
+			Namespaces: []MockNamespace{
+				paused,
+				_mockNamespace("namespace-2"),
+				_mockNamespace("namespace-3"),
+			},
+			PDBs: []MockPDB{
+				_mockPDB("pdb-1", "namespace-1", nil, &intStrZeroInt, _selector("app=app-1"), 1, 0),
+				_mockPDB("pdb-1", "namespace-2", nil, &intStrZeroInt, _selector("app=app-1"), 1, 0),
+				_mockPDB("pdb-1", "namespace-3", nil, &intStrZeroInt, _selector("app=app-1"), 1, 0),
+			},
+			Pods: []MockPod{
+				critical,
+				_mockPod("pod-1", "namespace-3", map[string]string{"app": "app-1"}, false, 0, false),
+			},
+		},
+		ExpectedReapableBudgets: 0,
+		ExpectedReapedBudgets:   1,
+	}
+	testCase.Run(t)
+
+	if reaper.SkippedPlannedReapsCount != 2 {
+		t.Fatalf("expected the planned PDBs in the paused namespace and of the protected pods to be skipped, got: %v skipped", reaper.SkippedPlannedReapsCount)
+	}
+	for _, namespace := range []string{"namespace-1", "namespace-2"} {
+		if _, err := reaper.KubernetesClient.PolicyV1().PodDisruptionBudgets(namespace).Get(context.Background(), "pdb-1", metav1.GetOptions{}); err != nil {
+			t.Fatalf("expected planned PDB %v/pdb-1 to be kept: %v", namespace, err)
+		}
+	}
+}
+
+func TestSelfTestDryRun(t *testing.T) {
+	reaper := _fakeReaperContext()
+	reaper.DryRun = true
+	reaper.SelfTest = true
+	reaper.SelfTestNamespace = DefaultSelfTestNamespace
+	metrics := &fakeMetricsAPI{}
+	reaper.MetricsAPI = metrics
+	client := reaper.KubernetesClient.(*fake.Clientset)
+	testCase := ReaperUnitTest{
+		TestDescription: "The self-test only sends server-side dry run writes with --dry-run",
+		FakeReaper:      reaper,
+		Mocks: KubernetesMockAPI{
+			Namespaces: []MockNamespace{
+				_mockNamespace("namespace-1"),
+			},
+			PDBs: []MockPDB{
+				_mockPDB("pdb-1", "namespace-1", nil, &intStrZeroInt, _selector("app=app-1"), 1, 0),
+			},
+		},
+		ExpectedReapableBudgets: 1,
+		ExpectedReapedBudgets:   0,
+	}
+	testCase.Run(t)
+
+	if value, ok := metrics.lastValue(PdbReaperSelfTestMetricName, nil); !ok || value != 1 {
+		t.Fatalf("expected %v to be 1, got: %v", PdbReaperSelfTestMetricName, value)
+	}
+	// the fake client does not record create options, the delete is checked instead
+	var deletes int
+	for _, action := range client.Actions() {
+		if action.GetNamespace() != DefaultSelfTestNamespace || action.GetVerb() != "delete" {
+			continue
+		}
+		deletes++
+		if dryRun := action.(k8stesting.DeleteAction).GetDeleteOptions().DryRun; len(dryRun) != 1 || dryRun[0] != metav1.DryRunAll {
+			t.Fatalf("expected the self-test delete to be a dry run, got: %v", dryRun)
+		}
+	}
+	if deletes != 1 {
+		t.Fatalf("expected 1 self-test delete, got: %v", deletes)
+	}
+}
@@ -0,0 +1,173 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pdbreaper
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"time"
+
+	"github.com/keikoproj/governor/pkg/reaper/common"
+	"github.com/pkg/errors"
+	policyv1 "k8s.io/api/policy/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// Plan is the set of deletions a run intends to make, written by --plan-out and executed by --apply
+type Plan struct {
+	Timestamp time.Time     `json:"timestamp"`
+	Reaps     []PlannedReap `json:"reaps"`
+}
+
+// PlannedReap is a PDB a plan intends to delete, identified by its UID and generation so that a PDB which was
+// recreated or changed since the plan was made is not deleted
+type PlannedReap struct {
+	Namespace     string    `json:"namespace"`
+	Name          string    `json:"name"`
+	UID           types.UID `json:"uid"`
+	Generation    int64     `json:"generation"`
+	Reasons       []Reason  `json:"reasons"`
+	PrimaryReason Reason    `json:"primaryReason"`
+}
+
+// planReap adds a PDB which would be deleted to the plan
+func (ctx *ReaperContext) planReap(pdb policyv1.PodDisruptionBudget) {
+	log.Infof("--plan-out is set, planning deletion of PDB %v", pdbNamespacedName(pdb))
+	ctx.plannedReaps = append(ctx.plannedReaps, PlannedReap{
+		Namespace:     pdb.GetNamespace(),
+		Name:          pdb.GetName(),
+		UID:           pdb.GetUID(),
+		Generation:    pdb.GetGeneration(),
+		Reasons:       ctx.ReapableReasons[pdbNamespacedName(pdb)],
+		PrimaryReason: ctx.primaryReason(pdb),
+	})
+}
+
+// writePlan writes the planned deletions of the run to --plan-out
+func (ctx *ReaperContext) writePlan() error {
+	if ctx.PlanOut == "" {
+		return nil
+	}
+
+	plan := Plan{
+		Timestamp: ctx.now().UTC(),
+		Reaps:     ctx.plannedReaps,
+	}
+	if plan.Reaps == nil {
+		plan.Reaps = make([]PlannedReap, 0)
+	}
+	data, err := json.MarshalIndent(plan, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal plan")
+	}
+	if err := os.WriteFile(ctx.PlanOut, data, 0644); err != nil {
+		return errors.Wrapf(err, "failed to write plan %v", ctx.PlanOut)
+	}
+	log.Infof("wrote plan of %v deletions to %v, execute it with --apply", len(plan.Reaps), ctx.PlanOut)
+	return nil
+}
+
+// readPlan reads the plan of --apply
+func readPlan(path string) (*Plan, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read plan %v", path)
+	}
+	plan := &Plan{}
+	if err := json.Unmarshal(data, plan); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal plan %v", path)
+	}
+	return plan, nil
+}
+
+// applyPlan executes exactly the deletions of the --apply plan, each PDB is re-validated and skipped when it no longer
+// exists, was recreated, changed since the plan was made or is now excluded, and is subject to the same checks and
+// limits as a deletion in a run
+func (ctx *ReaperContext) applyPlan() error {
+	plan, err := readPlan(ctx.ApplyPlan)
+	if err != nil {
+		return err
+	}
+	log.Infof("applying plan %v of %v deletions made at %v", ctx.ApplyPlan, len(plan.Reaps), plan.Timestamp.Format(time.RFC3339))
+	ctx.pruneReapedState()
+
+	if ctx.isOutsideReapWindow(len(plan.Reaps)) {
+		return nil
+	}
+
+	var attempted int
+	for i, planned := range plan.Reaps {
+		namespacedName := planned.Namespace + "/" + planned.Name
+		pdb, err := ctx.KubernetesClient.PolicyV1().PodDisruptionBudgets(planned.Namespace).Get(context.Background(), planned.Name, metav1.GetOptions{})
+		if err != nil {
+			if kerrors.IsNotFound(err) {
+				log.Warnf("planned PDB %v no longer exists, skipping it", namespacedName)
+				ctx.SkippedPlannedReapsCount++
+				continue
+			}
+			return errors.Wrapf(err, "failed to get planned PDB %v", namespacedName)
+		}
+		if reason := ctx.planChange(planned, *pdb); reason != "" {
+			log.Warnf("planned PDB %v %v since the plan was made, skipping it", namespacedName, reason)
+			ctx.SkippedPlannedReapsCount++
+			continue
+		}
+
+		ctx.ReapableReasons[namespacedName] = planned.Reasons
+		// during maintenance only PDBs blocking a drain are reaped, as in a run
+		if ctx.isDrainBlockingOnly() && !containsReason(planned.Reasons, ReasonBlockingNodeDrain) {
+			log.Infof("planned PDB %v is not blocking a drain, skipping it due to --drain-blocking-only", namespacedName)
+			ctx.SkippedPlannedReapsCount++
+			continue
+		}
+		if ctx.isReapSkipped(*pdb) {
+			ctx.SkippedPlannedReapsCount++
+			continue
+		}
+		if ctx.isReapDeferred(attempted, len(plan.Reaps)-i) {
+			break
+		}
+		attempted++
+
+		if ctx.DryRun {
+			log.Warnf("DryRun is on, planned PDB %v will not be deleted", namespacedName)
+			continue
+		}
+		if _, err := ctx.reapPodDisruptionBudget(*pdb, planned.PrimaryReason); err != nil {
+			return err
+		}
+	}
+	log.Infof("applied plan %v, %v PDBs were reaped and %v were skipped", ctx.ApplyPlan, ctx.ReapedPodDisruptionBudgetCount, ctx.SkippedPlannedReapsCount)
+	return nil
+}
+
+// planChange describes how a PDB changed since it was planned, or returns an empty string when it still matches
+func (ctx *ReaperContext) planChange(planned PlannedReap, pdb policyv1.PodDisruptionBudget) string {
+	switch {
+	case pdb.GetUID() != planned.UID:
+		return "was recreated"
+	case pdb.GetGeneration() != planned.Generation:
+		return "changed"
+	case pdb.GetDeletionTimestamp() != nil:
+		return "started terminating"
+	case common.StringSliceContains(ctx.ExcludedNamespaces, pdb.GetNamespace()) || ctx.isExcludedPodDisruptionBudget(pdb):
+		return "was excluded"
+	}
+	return ""
+}
@@ -0,0 +1,72 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pdbreaper
+
+import (
+	"math"
+
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+)
+
+const (
+	// ExpectedPodsSourceStatus resolves budgets against the expected pods of the PDB status, as the disruption
+	// controller does
+	ExpectedPodsSourceStatus = "status"
+	// ExpectedPodsSourceLive resolves budgets against the live pods matched by the selector
+	ExpectedPodsSourceLive = "live"
+
+	// expectedPodsDisagreementRatio is the relative difference between the live and expected pod counts above which
+	// the disagreement is logged
+	expectedPodsDisagreementRatio = 0.1
+)
+
+// ExpectedPodsSources are all known sources of the pod count budgets are resolved against
+var ExpectedPodsSources = [...]string{ExpectedPodsSourceStatus, ExpectedPodsSourceLive}
+
+// statusPodCount returns the expected pods of a PDB status, expected pods are not reported for PDBs selecting bare
+// pods, in which case the live pod count is used
+func statusPodCount(pdb policyv1.PodDisruptionBudget, livePods int) int {
+	if pdb.Status.ExpectedPods == 0 {
+		return livePods
+	}
+	return int(pdb.Status.ExpectedPods)
+}
+
+// budgetPodCount returns the pod count the budget of a PDB is resolved against according to --expected-pods-source,
+// a significant disagreement between the live and expected pod counts is logged
+func (ctx *ReaperContext) budgetPodCount(pdb policyv1.PodDisruptionBudget, pods []corev1.Pod) int {
+	var (
+		livePods     = len(pods)
+		expectedPods = int(pdb.Status.ExpectedPods)
+	)
+	if expectedPods != 0 && livePods != expectedPods {
+		difference := math.Abs(float64(livePods-expectedPods)) / math.Max(float64(livePods), float64(expectedPods))
+		if difference > expectedPodsDisagreementRatio {
+			log.Warnf("pdb %v matches %v live pods but its status is expecting %v pods, resolving its budget against the %v count", pdbNamespacedName(pdb), livePods, expectedPods, ctx.ExpectedPodsSource)
+		}
+	}
+
+	if ctx.ExpectedPodsSource == ExpectedPodsSourceLive {
+		return livePods
+	}
+	return statusPodCount(pdb, livePods)
+}
+
+// isPodDisruptionBudgetMisconfigured resolves the budget of a PDB against the pod count of --expected-pods-source
+func (ctx *ReaperContext) isPodDisruptionBudgetMisconfigured(pdb policyv1.PodDisruptionBudget, pods []corev1.Pod) (bool, error) {
+	return isMisconfiguredWithPodCount(pdb, ctx.budgetPodCount(pdb, pods))
+}
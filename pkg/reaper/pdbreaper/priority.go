@@ -0,0 +1,48 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pdbreaper
+
+import (
+	"github.com/keikoproj/governor/pkg/reaper/common"
+	"github.com/pkg/errors"
+	policyv1 "k8s.io/api/policy/v1"
+)
+
+// DefaultProtectedPriorityClasses are the priority classes of pods whose PDBs are never reaped by default
+var DefaultProtectedPriorityClasses = []string{"system-cluster-critical", "system-node-critical"}
+
+// protectedPriorityClass returns the priority class of the first pod selected by a PDB which carries one of
+// --protected-priority-classes, such PDBs protect critical workloads and are spared
+func (ctx *ReaperContext) protectedPriorityClass(pdb policyv1.PodDisruptionBudget) (string, error) {
+	if len(ctx.ProtectedPriorityClasses) == 0 {
+		return "", nil
+	}
+
+	selector, err := common.GetSelectorString(pdb.Spec.Selector)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get label selector from structured selector %+v", pdb.Spec.Selector)
+	}
+	pods, err := ctx.listPodsWithSelector(ctx.evaluationContext(pdb), pdb.GetNamespace(), selector)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to list PDB pods")
+	}
+	for _, pod := range pods {
+		if common.StringSliceContains(ctx.ProtectedPriorityClasses, pod.Spec.PriorityClassName) {
+			return pod.Spec.PriorityClassName, nil
+		}
+	}
+	return "", nil
+}
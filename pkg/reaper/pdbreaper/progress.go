@@ -0,0 +1,59 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pdbreaper
+
+import (
+	"time"
+)
+
+// progressReporter logs periodic heartbeats while namespaces are evaluated, every N namespaces and/or every interval
+type progressReporter struct {
+	phase    string
+	total    int
+	done     int
+	every    int
+	interval time.Duration
+	started  time.Time
+	last     time.Time
+	now      func() time.Time
+}
+
+func (ctx *ReaperContext) newProgressReporter(phase string, total int) *progressReporter {
+	now := ctx.now()
+	return &progressReporter{
+		phase:    phase,
+		total:    total,
+		every:    ctx.ProgressEveryNamespaces,
+		interval: ctx.ProgressInterval,
+		started:  now,
+		last:     now,
+		now:      ctx.now,
+	}
+}
+
+// step marks the start of evaluating a namespace, and logs progress when due
+func (p *progressReporter) step() {
+	p.done++
+
+	now := p.now()
+	dueByCount := p.every > 0 && p.done%p.every == 0
+	dueByTime := p.interval > 0 && now.Sub(p.last) >= p.interval
+	if !dueByCount && !dueByTime {
+		return
+	}
+	p.last = now
+	log.Infof("progress: %v is evaluating namespace %v/%v (%v elapsed)", p.phase, p.done, p.total, now.Sub(p.started).Round(time.Millisecond))
+}
@@ -0,0 +1,159 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pdbreaper
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RequiredPermissions are the permissions pdb-reaper needs to scan and reap PDBs
+var RequiredPermissions = []authorizationv1.ResourceAttributes{
+	{Verb: "list", Group: "policy", Resource: "poddisruptionbudgets"},
+	{Verb: "delete", Group: "policy", Resource: "poddisruptionbudgets"},
+	{Verb: "list", Group: "", Resource: "pods"},
+}
+
+// DrainPermissions are the additional permissions needed when node drain state is evaluated
+var DrainPermissions = []authorizationv1.ResourceAttributes{
+	{Verb: "list", Group: "", Resource: "nodes"},
+}
+
+// MaintenancePermissions are the additional permissions needed to read the --node-drain-integration indicators
+var MaintenancePermissions = []authorizationv1.ResourceAttributes{
+	{Verb: "list", Group: "", Resource: "nodes"},
+	{Verb: "get", Group: "", Resource: "configmaps"},
+}
+
+// ExcludedNamespacesPermissions are the additional permissions needed to read the --excluded-namespaces-configmap
+var ExcludedNamespacesPermissions = []authorizationv1.ResourceAttributes{
+	{Verb: "get", Group: "", Resource: "configmaps"},
+}
+
+// PodLogsPermissions are the additional permissions needed when --probe-pod-logs is set
+var PodLogsPermissions = []authorizationv1.ResourceAttributes{
+	{Verb: "get", Group: "", Resource: "pods", Subresource: "log"},
+}
+
+// requiredPermissions returns the permissions needed by the enabled options
+func (ctx *ReaperContext) requiredPermissions() []authorizationv1.ResourceAttributes {
+	permissions := append([]authorizationv1.ResourceAttributes{}, RequiredPermissions...)
+	if ctx.NodeDrainIntegration {
+		permissions = append(permissions, MaintenancePermissions...)
+	} else if ctx.isDrainAware() {
+		permissions = append(permissions, DrainPermissions...)
+	}
+	if ctx.ProbePodLogs {
+		permissions = append(permissions, PodLogsPermissions...)
+	}
+	if ctx.ExcludedNamespacesConfigMapName != "" || ctx.PlatformExclusionsConfigMapName != "" {
+		permissions = append(permissions, ExcludedNamespacesPermissions...)
+	}
+	if ctx.EnforceNamespaceLabel != "" {
+		permissions = append(permissions, EnforcePermissions...)
+	}
+	if ctx.HonorPausedNamespaces {
+		permissions = append(permissions, PausedPermissions...)
+	}
+	if ctx.AnnotateWorkloads {
+		permissions = append(permissions, WorkloadAnnotationPermissions...)
+	}
+	if ctx.StampProcessedGeneration {
+		permissions = append(permissions, ProcessedGenerationPermissions...)
+	}
+	if ctx.AnnotateDeleteReason {
+		permissions = append(permissions, DeleteReasonPermissions...)
+	}
+	if _, ok := ctx.BackupSink.(*SecretBackupSink); ok {
+		permissions = append(permissions, BackupPermissions...)
+	}
+	if ctx.ConfirmWithEviction && ctx.isFeatureSupported(FeatureConfirmWithEviction) {
+		permissions = append(permissions, EvictionPermissions...)
+	}
+	if ctx.SelfTest {
+		permissions = append(permissions, SelfTestPermissions...)
+	}
+	if ctx.ReapStaleSelector {
+		permissions = append(permissions, StaleSelectorPermissions...)
+	}
+	return permissions
+}
+
+// checkPermissions performs a SelfSubjectAccessReview for each required permission and returns the permissions which
+// are not allowed
+func (ctx *ReaperContext) checkPermissions() ([]string, error) {
+	denied := make([]string, 0)
+	for _, attributes := range ctx.requiredPermissions() {
+		attributes := attributes
+		review := &authorizationv1.SelfSubjectAccessReview{
+			Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+				ResourceAttributes: &attributes,
+			},
+		}
+
+		result, err := ctx.KubernetesClient.AuthorizationV1().SelfSubjectAccessReviews().Create(context.Background(), review, metav1.CreateOptions{})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to review access to %v", permissionString(attributes))
+		}
+		if !result.Status.Allowed {
+			denied = append(denied, permissionString(attributes))
+		}
+	}
+	return denied, nil
+}
+
+// selfCheckRBAC verifies the required permissions at startup, when --strict-rbac is set insufficient permissions fail
+// the run
+func (ctx *ReaperContext) selfCheckRBAC() error {
+	denied, err := ctx.checkPermissions()
+	if err != nil {
+		log.Warnf("unable to verify RBAC permissions: %v", err)
+		ctx.exposeClusterMetric(PdbReaperRBACSufficientMetricName, 0)
+		if ctx.StrictRBAC {
+			return errors.Wrap(err, "failed to verify RBAC permissions")
+		}
+		return nil
+	}
+
+	if len(denied) > 0 {
+		log.Warnf("insufficient RBAC permissions, missing: %v", strings.Join(denied, ", "))
+		ctx.exposeClusterMetric(PdbReaperRBACSufficientMetricName, 0)
+		if ctx.StrictRBAC {
+			return errors.Errorf("insufficient RBAC permissions, missing: %v", strings.Join(denied, ", "))
+		}
+		return nil
+	}
+
+	log.Info("RBAC permissions are sufficient")
+	ctx.exposeClusterMetric(PdbReaperRBACSufficientMetricName, 1)
+	return nil
+}
+
+func permissionString(attributes authorizationv1.ResourceAttributes) string {
+	resource := attributes.Resource
+	if attributes.Subresource != "" {
+		resource = fmt.Sprintf("%v/%v", attributes.Resource, attributes.Subresource)
+	}
+	if attributes.Group != "" {
+		resource = fmt.Sprintf("%v.%v", resource, attributes.Group)
+	}
+	return fmt.Sprintf("%v %v", attributes.Verb, resource)
+}
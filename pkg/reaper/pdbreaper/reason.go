@@ -0,0 +1,136 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pdbreaper
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+)
+
+// Reason is the reason a PDB was detected or acted on, the numeric codes are stable and new reasons must only be
+// appended
+type Reason int
+
+const (
+	ReasonUnknown Reason = iota
+	ReasonPodDisruptionBudgetDeleted
+	ReasonBlocking
+	ReasonMultiple
+	ReasonBlockingCrashLoop
+	ReasonBlockingNotReadyState
+	ReasonBlockingNodeDrain
+	ReasonDuplicateSelector
+	ReasonRecreated
+	ReasonZeroMaxUnavailable
+	ReasonHealthScore
+	ReasonMixedControllers
+	ReasonPodDisruptionBudgetPatched
+	ReasonSingleNode
+	ReasonStaleSelector
+)
+
+// Reasons are all known reasons
+var Reasons = [...]Reason{ReasonPodDisruptionBudgetDeleted, ReasonBlocking, ReasonMultiple, ReasonBlockingCrashLoop,
+	ReasonBlockingNotReadyState, ReasonBlockingNodeDrain, ReasonDuplicateSelector, ReasonRecreated, ReasonZeroMaxUnavailable,
+	ReasonHealthScore, ReasonMixedControllers, ReasonPodDisruptionBudgetPatched,
+	ReasonSingleNode, ReasonStaleSelector}
+
+var reasonNames = map[Reason]string{
+	ReasonUnknown:                    "Unknown",
+	ReasonPodDisruptionBudgetDeleted: EventReasonPodDisruptionBudgetDeleted,
+	ReasonBlocking:                   EventReasonBlockingDetected,
+	ReasonMultiple:                   EventReasonMultipleDetected,
+	ReasonBlockingCrashLoop:          EventReasonBlockingCrashLoopDetected,
+	ReasonBlockingNotReadyState:      EventReasonBlockingNotReadyStateDetected,
+	ReasonBlockingNodeDrain:          EventReasonBlockingNodeDrainDetected,
+	ReasonDuplicateSelector:          EventReasonDuplicateSelectorDetected,
+	ReasonRecreated:                  EventReasonRecreatedDetected,
+	ReasonZeroMaxUnavailable:         EventReasonZeroMaxUnavailableDetected,
+	ReasonHealthScore:                EventReasonHealthScoreDetected,
+	ReasonMixedControllers:           EventReasonMixedControllersDetected,
+	ReasonPodDisruptionBudgetPatched: EventReasonPodDisruptionBudgetPatched,
+	ReasonSingleNode:                 EventReasonSingleNodeDetected,
+	ReasonStaleSelector:              EventReasonStaleSelectorDetected,
+}
+
+// String returns the event reason of a Reason
+func (r Reason) String() string {
+	if name, ok := reasonNames[r]; ok {
+		return name
+	}
+	return reasonNames[ReasonUnknown]
+}
+
+// Code returns the stable numeric code of a Reason
+func (r Reason) Code() int {
+	return int(r)
+}
+
+// ParseReason returns the Reason for an event reason
+func ParseReason(name string) (Reason, error) {
+	for reason, reasonName := range reasonNames {
+		if reasonName == name {
+			return reason, nil
+		}
+	}
+	return ReasonUnknown, errors.Errorf("unknown reason '%v'", name)
+}
+
+// MarshalJSON encodes a Reason as its event reason
+func (r Reason) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.String())
+}
+
+// UnmarshalJSON decodes a Reason from its event reason or its numeric code
+func (r *Reason) UnmarshalJSON(data []byte) error {
+	var code int
+	if err := json.Unmarshal(data, &code); err == nil {
+		if _, ok := reasonNames[Reason(code)]; !ok {
+			return errors.Errorf("unknown reason code %v", code)
+		}
+		*r = Reason(code)
+		return nil
+	}
+
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return errors.Wrap(err, "failed to unmarshal reason")
+	}
+	reason, err := ParseReason(name)
+	if err != nil {
+		return err
+	}
+	*r = reason
+	return nil
+}
+
+func containsReason(reasons []Reason, reason Reason) bool {
+	for _, r := range reasons {
+		if r == reason {
+			return true
+		}
+	}
+	return false
+}
+
+func reasonStrings(reasons []Reason) []string {
+	names := make([]string, 0, len(reasons))
+	for _, reason := range reasons {
+		names = append(names, reason.String())
+	}
+	return names
+}
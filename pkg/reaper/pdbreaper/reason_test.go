@@ -0,0 +1,71 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pdbreaper
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReason_StableCodes(t *testing.T) {
+	tests := []struct {
+		reason Reason
+		code   int
+		name   string
+	}{
+		{ReasonUnknown, 0, "Unknown"},
+		{ReasonPodDisruptionBudgetDeleted, 1, EventReasonPodDisruptionBudgetDeleted},
+		{ReasonBlocking, 2, EventReasonBlockingDetected},
+		{ReasonMultiple, 3, EventReasonMultipleDetected},
+		{ReasonBlockingCrashLoop, 4, EventReasonBlockingCrashLoopDetected},
+		{ReasonBlockingNotReadyState, 5, EventReasonBlockingNotReadyStateDetected},
+		{ReasonBlockingNodeDrain, 6, EventReasonBlockingNodeDrainDetected},
+		{ReasonDuplicateSelector, 7, EventReasonDuplicateSelectorDetected},
+		{ReasonRecreated, 8, EventReasonRecreatedDetected},
+		{ReasonZeroMaxUnavailable, 9, EventReasonZeroMaxUnavailableDetected},
+		{ReasonHealthScore, 10, EventReasonHealthScoreDetected},
+		{ReasonMixedControllers, 11, EventReasonMixedControllersDetected},
+		{ReasonPodDisruptionBudgetPatched, 12, EventReasonPodDisruptionBudgetPatched},
+		{ReasonSingleNode, 13, EventReasonSingleNodeDetected},
+		{ReasonStaleSelector, 14, EventReasonStaleSelectorDetected},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.code, tt.reason.Code())
+			assert.Equal(t, tt.name, tt.reason.String())
+
+			data, err := json.Marshal(tt.reason)
+			assert.NoError(t, err)
+			assert.Equal(t, `"`+tt.name+`"`, string(data))
+
+			var decoded Reason
+			assert.NoError(t, json.Unmarshal(data, &decoded))
+			assert.Equal(t, tt.reason, decoded)
+		})
+	}
+}
+
+func TestReason_UnmarshalJSON(t *testing.T) {
+	var reason Reason
+	assert.NoError(t, json.Unmarshal([]byte(`4`), &reason))
+	assert.Equal(t, ReasonBlockingCrashLoop, reason)
+
+	assert.Error(t, json.Unmarshal([]byte(`99`), &reason))
+	assert.Error(t, json.Unmarshal([]byte(`"NotAReason"`), &reason))
+	assert.Equal(t, "Unknown", Reason(99).String())
+}
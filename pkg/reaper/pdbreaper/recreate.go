@@ -0,0 +1,78 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pdbreaper
+
+import (
+	"fmt"
+	"time"
+
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const PdbReaperRecreatedMetricName = "governor_pdb_reaper_recreated_total"
+
+// detectRecreatedDisruptionBudgets publishes a warning event for each scanned PDB which was recreated within
+// --recreate-window of being reaped, such PDBs are usually recreated by a controller or GitOps and indicate a
+// delete/recreate loop
+func (ctx *ReaperContext) detectRecreatedDisruptionBudgets() {
+	if ctx.RecreateWindow == 0 {
+		return
+	}
+	if ctx.State.Recreated == nil {
+		ctx.State.Recreated = make(map[string]time.Time)
+	}
+
+	for _, pdb := range ctx.ScannedPodDisruptionBudgets {
+		namespacedName := pdbNamespacedName(pdb)
+		reapedAt, ok := ctx.State.ReapedAt[namespacedName]
+		if !ok {
+			continue
+		}
+
+		// creation timestamps have a precision of seconds
+		createdAt := pdb.CreationTimestamp.Time
+		if createdAt.Before(reapedAt.Truncate(time.Second)) || createdAt.Sub(reapedAt) >= ctx.RecreateWindow {
+			continue
+		}
+		if counted, ok := ctx.State.Recreated[namespacedName]; ok && counted.Equal(createdAt) {
+			continue
+		}
+		ctx.State.Recreated[namespacedName] = createdAt
+		ctx.State.RecreatedTotal++
+
+		after := createdAt.Sub(reapedAt).Round(time.Second)
+		source := recreateSource(pdb)
+		log.Warnf("PDB %v was recreated %v after being reaped, fix its source %v to stop the delete/recreate loop", namespacedName, after, source)
+		if err := ctx.publishEvent(pdb, ReasonRecreated, EventMessageRecreatedFmt, after, source); err != nil {
+			log.Warnf(err.Error())
+		}
+	}
+	ctx.exposeClusterMetric(PdbReaperRecreatedMetricName, float64(ctx.State.RecreatedTotal))
+}
+
+// recreateSource returns the likely creator of a PDB, its controller owner or the field manager which created it
+func recreateSource(pdb policyv1.PodDisruptionBudget) string {
+	if owner := metav1.GetControllerOf(&pdb); owner != nil {
+		return fmt.Sprintf("%v/%v", owner.Kind, owner.Name)
+	}
+	for _, entry := range pdb.GetManagedFields() {
+		if entry.Operation == metav1.ManagedFieldsOperationUpdate || entry.Operation == metav1.ManagedFieldsOperationApply {
+			return fmt.Sprintf("manager %v", entry.Manager)
+		}
+	}
+	return "controller"
+}
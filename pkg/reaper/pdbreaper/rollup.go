@@ -0,0 +1,137 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pdbreaper
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/keikoproj/governor/pkg/reaper/common"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	EventReasonNamespaceFindings     = "PodDisruptionBudgetReaperNamespaceFindings"
+	EventMessageNamespaceFindingsFmt = "pdb-reaper found %v reapable PDBs in namespace %v: %v"
+)
+
+// namespaceFindings groups the names of the reapable PDBs of each namespace by reason
+func (ctx *ReaperContext) namespaceFindings() map[string]map[Reason][]string {
+	findings := make(map[string]map[Reason][]string)
+	for namespacedName, reasons := range ctx.ReapableReasons {
+		namespace, name, _ := strings.Cut(namespacedName, "/")
+		if findings[namespace] == nil {
+			findings[namespace] = make(map[Reason][]string)
+		}
+		for _, reason := range reasons {
+			findings[namespace][reason] = append(findings[namespace][reason], name)
+		}
+	}
+	for _, byReason := range findings {
+		for _, names := range byReason {
+			sort.Strings(names)
+		}
+	}
+	return findings
+}
+
+// formatNamespaceFindings formats the findings of a namespace as the count and names of the PDBs of each reason,
+// ordered by reason code
+func formatNamespaceFindings(byReason map[Reason][]string) string {
+	reasons := make([]Reason, 0, len(byReason))
+	for reason := range byReason {
+		reasons = append(reasons, reason)
+	}
+	sort.Slice(reasons, func(i, j int) bool { return reasons[i] < reasons[j] })
+
+	parts := make([]string, 0, len(reasons))
+	for _, reason := range reasons {
+		names := byReason[reason]
+		parts = append(parts, fmt.Sprintf("%v=%v (%v)", reason, len(names), strings.Join(names, ",")))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// publishNamespaceFindings publishes one event per namespace on the Namespace object, summarizing the findings of the
+// run in that namespace
+func (ctx *ReaperContext) publishNamespaceFindings() error {
+	if !ctx.NamespaceFindingsEvents || !ctx.EmitEvents || ctx.FixManifestsDir != "" {
+		return nil
+	}
+
+	findings := ctx.namespaceFindings()
+	namespaces := make([]string, 0, len(findings))
+	for namespace := range findings {
+		namespaces = append(namespaces, namespace)
+	}
+	sort.Strings(namespaces)
+
+	now := ctx.now()
+	for _, namespace := range namespaces {
+		if common.StringSliceContains(ctx.QuietNamespaces, namespace) {
+			log.Infof("not publishing findings event in quiet namespace %v", namespace)
+			continue
+		}
+
+		var count int
+		for namespacedName := range ctx.ReapableReasons {
+			if strings.HasPrefix(namespacedName, namespace+"/") {
+				count++
+			}
+		}
+		message := fmt.Sprintf(EventMessageNamespaceFindingsFmt, count, namespace, formatNamespaceFindings(findings[namespace]))
+
+		if ctx.DryRun && ctx.DumpEvents {
+			log.Infof("DryRun is on, capturing findings event in namespace %v instead of publishing it", namespace)
+			ctx.dumpedEvents = append(ctx.dumpedEvents, DumpedEvent{
+				Namespace: namespace,
+				Reason:    EventReasonNamespaceFindings,
+				Type:      "Normal",
+				Message:   message,
+				Timestamp: now.UTC(),
+			})
+			continue
+		}
+
+		if !ctx.allowWrite(fmt.Sprintf("findings event in namespace %v", namespace)) {
+			continue
+		}
+		event := &corev1.Event{
+			ObjectMeta: metav1.ObjectMeta{
+				GenerateName: "pdb-reaper-findings-",
+				Namespace:    namespace,
+				Labels:       ctx.clusterLabels(),
+			},
+			InvolvedObject: corev1.ObjectReference{
+				Kind:       "Namespace",
+				APIVersion: "v1",
+				Name:       namespace,
+			},
+			Reason:         EventReasonNamespaceFindings,
+			Message:        message,
+			Type:           "Normal",
+			FirstTimestamp: metav1.NewTime(now),
+			LastTimestamp:  metav1.NewTime(now),
+		}
+		if _, err := ctx.KubernetesClient.CoreV1().Events(namespace).Create(ctx.runContext(), event, metav1.CreateOptions{}); err != nil {
+			return errors.Wrapf(err, "failed to publish findings event in namespace %v", namespace)
+		}
+	}
+	return nil
+}
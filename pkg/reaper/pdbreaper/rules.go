@@ -0,0 +1,292 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pdbreaper
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/keikoproj/governor/pkg/reaper/common"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+)
+
+// ruleResult is the result of a reap mode evaluated against a single PDB. The detection handlers of a run and --inspect
+// evaluate the same rules, the handlers act on the result while --inspect only reports it.
+type ruleResult struct {
+	matched bool
+	// details are the values behind the result
+	details string
+	// count is the number of pods counted by the rule, e.g. the crashlooping pods
+	count int
+	// eventArgs are the arguments of the event message published when the PDB is marked reapable
+	eventArgs []interface{}
+}
+
+// nonBlockingReason returns why a scanned PDB is not blocking, or an empty string when it is blocking
+func (ctx *ReaperContext) nonBlockingReason(pdb policyv1.PodDisruptionBudget) string {
+	switch {
+	case pdb.Status.DisruptionsAllowed != 0:
+		return fmt.Sprintf("it is allowing %v disruptions", pdb.Status.DisruptionsAllowed)
+	case pdb.Status.ExpectedPods == 0 && !ctx.isZeroExpectedPodsEvaluated(pdb):
+		return "it is expecting 0 pods"
+	}
+	return ""
+}
+
+// evaluateDrainBlocking matches a PDB with pods on cordoned/draining nodes
+func (ctx *ReaperContext) evaluateDrainBlocking(pods []corev1.Pod) (ruleResult, error) {
+	if !ctx.isDrainAware() {
+		return ruleResult{details: "node drain state is not evaluated"}, nil
+	}
+	drainingPods, err := ctx.podsOnDrainingNodes(pods)
+	if err != nil {
+		return ruleResult{}, errors.Wrap(err, "failed to determine pods on draining nodes")
+	}
+	return ruleResult{
+		matched: len(drainingPods) > 0,
+		details: fmt.Sprintf("%v pods on cordoned/draining nodes %v", len(drainingPods), podSliceNodeNames(drainingPods)),
+		count:   len(drainingPods),
+	}, nil
+}
+
+// evaluateMisconfigured matches a blocking PDB whose budget can't be satisfied by its pods
+func (ctx *ReaperContext) evaluateMisconfigured(pdb policyv1.PodDisruptionBudget, pods []corev1.Pod) (ruleResult, error) {
+	misconfigured, err := ctx.isPodDisruptionBudgetMisconfigured(pdb, pods)
+	if err != nil {
+		return ruleResult{}, errors.Wrap(err, "failed to determine if PDB is misconfigured")
+	}
+	result := ruleResult{
+		matched: misconfigured,
+		details: fmt.Sprintf("minAvailable=%v maxUnavailable=%v expectedPods=%v matchedPods=%v", pdb.Spec.MinAvailable, pdb.Spec.MaxUnavailable, pdb.Status.ExpectedPods, len(pods)),
+	}
+
+	// a dormant PDB whose selector matches no pods may be intentional, and is not blocking any disruption
+	if misconfigured && ctx.ReapOnlyIfPodsMatch && len(pods) == 0 {
+		log.Infof("PDB %v is misconfigured but its selector matches no pods, not marking it reapable", pdbNamespacedName(pdb))
+		result.matched = false
+		result.details += ", spared by --reap-only-if-pods-match"
+	}
+	return result, nil
+}
+
+// evaluateStatusOnlyMisconfigured matches a blocking PDB whose pods can't be listed and whose budget can't be satisfied
+// by the expected pods reported in its status
+func (ctx *ReaperContext) evaluateStatusOnlyMisconfigured(pdb policyv1.PodDisruptionBudget) (ruleResult, error) {
+	expectedPods := int(pdb.Status.ExpectedPods)
+	misconfigured, err := isMisconfiguredWithPodCount(pdb, expectedPods)
+	if err != nil {
+		return ruleResult{}, errors.Wrap(err, "failed to determine if PDB is misconfigured")
+	}
+	result := ruleResult{
+		matched: misconfigured,
+		details: fmt.Sprintf("minAvailable=%v maxUnavailable=%v expectedPods=%v, pods can't be listed", pdb.Spec.MinAvailable, pdb.Spec.MaxUnavailable, pdb.Status.ExpectedPods),
+	}
+	if misconfigured && ctx.ReapOnlyIfPodsMatch && expectedPods == 0 {
+		log.Infof("PDB %v is misconfigured but expects no pods, not marking it reapable", pdbNamespacedName(pdb))
+		result.matched = false
+		result.details += ", spared by --reap-only-if-pods-match"
+	}
+	return result, nil
+}
+
+// evaluateMixedControllers matches a PDB selecting the pods of more than one controller
+func evaluateMixedControllers(pods []corev1.Pod) ruleResult {
+	controllers := workloadRefStrings(podControllers(pods))
+	return ruleResult{
+		matched:   len(controllers) > 1,
+		details:   fmt.Sprintf("pods of controllers %v", controllers),
+		eventArgs: []interface{}{strings.Join(controllers, ", ")},
+	}
+}
+
+// evaluateSingleNode matches a PDB whose pods are all scheduled on the same node
+func evaluateSingleNode(pods []corev1.Pod) ruleResult {
+	nodeName, ok := singleNodeName(pods)
+	return ruleResult{
+		matched:   ok,
+		details:   fmt.Sprintf("pods on nodes %v", podSliceNodeNames(pods)),
+		eventArgs: []interface{}{nodeName},
+	}
+}
+
+// evaluateCrashLoop matches a blocking PDB whose pods are crashlooping
+func (ctx *ReaperContext) evaluateCrashLoop(pdb policyv1.PodDisruptionBudget, statePods []corev1.Pod) ruleResult {
+	threshold := ctx.crashLoopThreshold(pdb)
+	isCrashLooping := ctx.crashLoopPredicate(threshold)
+	crashLooping := countCrashloopingPods(statePods, isCrashLooping)
+	return ruleResult{
+		matched: len(statePods) > 0 && isPodsInCrashloop(statePods, isCrashLooping, ctx.crashLoopPodFraction()),
+		details: fmt.Sprintf("%v/%v pods crashlooping with at least %v restarts, pod fraction %v", crashLooping, len(statePods), threshold, ctx.crashLoopPodFraction()),
+		count:   crashLooping,
+	}
+}
+
+// evaluateNotReady matches a blocking PDB whose pods are not-ready, with --crashloop-precedence the pods which are
+// accounted for by crashloop detection are not counted again
+func (ctx *ReaperContext) evaluateNotReady(pdb policyv1.PodDisruptionBudget, statePods []corev1.Pod) ruleResult {
+	notReadyPods := statePods
+	if ctx.ReapCrashLoop && ctx.CrashLoopPrecedence {
+		notReadyPods = excludeCrashloopingPods(statePods, ctx.crashLoopPredicate(ctx.crashLoopThreshold(pdb)))
+	}
+	threshold := ctx.notReadyThreshold(pdb)
+	podCount, notReadyCount := countNotReadyPods(ctx.now(), notReadyPods, threshold, ctx.NotReadyGateTypes, ctx.ReadinessProbeGrace, ctx.NotReadyIncludePending)
+	return ruleResult{
+		matched: len(notReadyPods) > 0 && isPodsInNotReadyState(ctx.now(), notReadyPods, threshold, ctx.notReadyPodFraction(), ctx.NotReadyGateTypes, ctx.ReadinessProbeGrace, ctx.NotReadyIncludePending),
+		details: fmt.Sprintf("%v/%v pods not-ready for at least %vs, pod fraction %v", notReadyCount, podCount, threshold, ctx.notReadyPodFraction()),
+		count:   notReadyCount,
+	}
+}
+
+// evaluateZeroMaxUnavailable matches a PDB whose maxUnavailable resolves to 0, with --drain-blocking-only only when it
+// has pods on cordoned/draining nodes
+func (ctx *ReaperContext) evaluateZeroMaxUnavailable(pdb policyv1.PodDisruptionBudget) (ruleResult, error) {
+	result := ruleResult{
+		matched: isZeroMaxUnavailable(pdb),
+		details: fmt.Sprintf("maxUnavailable=%v expectedPods=%v", pdb.Spec.MaxUnavailable, pdb.Status.ExpectedPods),
+	}
+	if !result.matched || !ctx.isDrainBlockingOnly() {
+		return result, nil
+	}
+
+	drainBlocking, err := ctx.filterDrainBlocking([]policyv1.PodDisruptionBudget{pdb})
+	if err != nil {
+		return ruleResult{}, err
+	}
+	if len(drainBlocking) == 0 {
+		log.Infof("PDB %v has no pods on cordoned/draining nodes, sparing it due to --drain-blocking-only", pdbNamespacedName(pdb))
+		result.matched = false
+		result.details += ", no pods on cordoned/draining nodes, spared by --drain-blocking-only"
+	}
+	return result, nil
+}
+
+// groupBySelector groups PDBs by their label selector, the selectors are returned in the order they are first seen and
+// PDBs without a selector are left out
+func groupBySelector(pdbs []policyv1.PodDisruptionBudget) ([]string, map[string][]policyv1.PodDisruptionBudget, error) {
+	selectors := make([]string, 0)
+	selectorPDBs := make(map[string][]policyv1.PodDisruptionBudget)
+	for _, pdb := range pdbs {
+		if pdb.Spec.Selector == nil {
+			continue
+		}
+		labelSelector, err := common.GetSelectorString(pdb.Spec.Selector)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "failed to get label selector from structured selector %+v", pdb.Spec.Selector)
+		}
+		if _, ok := selectorPDBs[labelSelector]; !ok {
+			selectors = append(selectors, labelSelector)
+		}
+		selectorPDBs[labelSelector] = append(selectorPDBs[labelSelector], pdb)
+	}
+	return selectors, selectorPDBs, nil
+}
+
+// reapableDuplicateSelector returns the reapable PDBs of a group sharing an identical selector, with
+// --drain-blocking-only only those with pods on cordoned/draining nodes
+func (ctx *ReaperContext) reapableDuplicateSelector(duplicates []policyv1.PodDisruptionBudget) ([]policyv1.PodDisruptionBudget, error) {
+	if len(duplicates) < 2 {
+		return nil, nil
+	}
+	if !ctx.isDrainBlockingOnly() {
+		return duplicates, nil
+	}
+
+	drainBlocking, err := ctx.filterDrainBlocking(duplicates)
+	if err != nil {
+		return nil, err
+	}
+	if len(drainBlocking) == 0 {
+		log.Infof("PDBs %+v have no pods on cordoned/draining nodes, sparing them due to --drain-blocking-only", pdbSliceNamespacedNames(duplicates))
+	}
+	return drainBlocking, nil
+}
+
+// listOverlapPods lists the pods of a PDB for multiple PDB detection, a PDB whose pods can't be listed in time or at all
+// is excluded from the detection
+func (ctx *ReaperContext) listOverlapPods(pdb policyv1.PodDisruptionBudget) ([]corev1.Pod, bool, error) {
+	labelSelector, err := common.GetSelectorString(pdb.Spec.Selector)
+	if err != nil {
+		return nil, false, errors.Wrapf(err, "failed to get label selector from structured selector %+v", pdb.Spec.Selector)
+	}
+
+	pods, err := ctx.listPodsWithSelector(ctx.evaluationContext(pdb), pdb.GetNamespace(), labelSelector)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			log.Warnf("evaluation of PDB %v timed out after %v, excluding it from multiple PDB detection: %v", pdbNamespacedName(pdb), ctx.PodDisruptionBudgetTimeout, err)
+			return nil, false, nil
+		}
+		if ctx.isPodListForbidden(pdb.GetNamespace(), err) {
+			log.Warnf("pods of PDB %v can't be listed, excluding it from multiple PDB detection", pdbNamespacedName(pdb))
+			return nil, false, nil
+		}
+		return nil, false, errors.Wrap(err, "failed to list PDB pods")
+	}
+	return pods, true, nil
+}
+
+// reapableMultiple returns the PDBs of a namespace which are reapable for selecting the same pods, and with
+// --fix-overlap the PDBs which are kept untouched. With --multiple-overlap-ratio only PDBs which substantially overlap
+// another PDB are considered, so that an intentional minor overlap is spared, and with --drain-blocking-only only the
+// drainBlocking PDBs are.
+func (ctx *ReaperContext) reapableMultiple(evaluated []policyv1.PodDisruptionBudget, overlap *podOverlapTracker, drainBlocking []policyv1.PodDisruptionBudget) ([]policyv1.PodDisruptionBudget, []policyv1.PodDisruptionBudget) {
+	if len(overlap.sharedPods()) == 0 {
+		return nil, nil
+	}
+
+	pdbs := sharingPodDisruptionBudgets(evaluated, overlap)
+	if ctx.MultipleOverlapRatio > 0 {
+		pdbs = overlappingPodDisruptionBudgets(evaluated, overlap, ctx.MultipleOverlapRatio)
+	}
+	if ctx.isDrainBlockingOnly() {
+		pdbs = intersectPodDisruptionBudgets(pdbs, drainBlocking)
+	}
+
+	// with --fix-overlap the PDB selecting the most pods is kept untouched, the redundant PDBs are patched
+	var kept []policyv1.PodDisruptionBudget
+	if ctx.FixOverlap && len(pdbs) > 0 {
+		kept, pdbs = redundantOverlappingPodDisruptionBudgets(pdbs, overlap)
+	}
+	return pdbs, kept
+}
+
+// evaluateStaleSelector matches a PDB whose selector matches no pods, when a deployment in its namespace shares some of
+// the selector labels but its pod template no longer matches the selector
+func (ctx *ReaperContext) evaluateStaleSelector(pdb policyv1.PodDisruptionBudget, pods []corev1.Pod) (ruleResult, error) {
+	switch {
+	case ctx.isDrainBlockingOnly():
+		return ruleResult{details: "PDBs matching no pods cannot block the drain of a node, spared by --drain-blocking-only"}, nil
+	case pdb.Spec.Selector == nil || len(pdb.Spec.Selector.MatchLabels) == 0:
+		return ruleResult{details: "selector has no matchLabels"}, nil
+	case len(pods) > 0:
+		return ruleResult{details: fmt.Sprintf("selector matches %v pods", len(pods))}, nil
+	}
+
+	deployment, staleLabels, err := ctx.staleSelectorDeployment(pdb)
+	if err != nil {
+		return ruleResult{}, err
+	}
+	if deployment == nil {
+		return ruleResult{details: "selector matches no pods, but no deployment in the namespace shares its labels"}, nil
+	}
+	return ruleResult{
+		matched:   true,
+		details:   fmt.Sprintf("selector matches no pods, deployment %v no longer carries %v", deployment.GetName(), staleLabels),
+		eventArgs: []interface{}{deployment.GetName(), staleLabels},
+	}, nil
+}
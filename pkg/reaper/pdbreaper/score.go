@@ -0,0 +1,186 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pdbreaper
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const (
+	PdbReaperHealthScoreMetricName = "governor_pdb_reaper_health_score"
+
+	HealthScoreMisconfigured    = "misconfigured"
+	HealthScoreCrashLoop        = "crashloop"
+	HealthScoreNotReady         = "not-ready"
+	HealthScoreBlockingDuration = "blocking-duration"
+	HealthScoreOverlap          = "overlap"
+
+	DefaultHealthScoreThreshold        = 0.5
+	DefaultHealthScoreBlockingDuration = 24 * time.Hour
+)
+
+// HealthScoreComponents are the components combined into the health score of a PDB
+var HealthScoreComponents = [...]string{HealthScoreMisconfigured, HealthScoreCrashLoop, HealthScoreNotReady,
+	HealthScoreBlockingDuration, HealthScoreOverlap}
+
+// parseHealthScoreWeights parses --health-score-weights entries in the form component=weight, components which are
+// not set keep a weight of 1
+func parseHealthScoreWeights(entries []string) (map[string]float64, error) {
+	weights := make(map[string]float64)
+	for _, component := range HealthScoreComponents {
+		weights[component] = 1
+	}
+
+	for _, entry := range entries {
+		parts := strings.SplitN(entry, "=", 2)
+		if len(parts) != 2 {
+			return nil, errors.Errorf("--health-score-weights value '%v' must be in the form component=weight", entry)
+		}
+		component := strings.TrimSpace(parts[0])
+		if _, ok := weights[component]; !ok {
+			return nil, errors.Errorf("--health-score-weights component '%v' is not one of %v", component, strings.Join(HealthScoreComponents[:], ","))
+		}
+		weight, err := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+		if err != nil || weight < 0 {
+			return nil, errors.Errorf("--health-score-weights weight '%v' of %v must be a non-negative number", parts[1], component)
+		}
+		weights[component] = weight
+	}
+
+	var total float64
+	for _, weight := range weights {
+		total += weight
+	}
+	if total == 0 {
+		return nil, errors.New("--health-score-weights must have at least one non-zero weight")
+	}
+	return weights, nil
+}
+
+// healthScoreComponents returns the value between 0 and 1 of each health score component of a blocking PDB
+func (ctx *ReaperContext) healthScoreComponents(pdb policyv1.PodDisruptionBudget, pods []corev1.Pod) (map[string]float64, error) {
+	components := make(map[string]float64)
+
+	misconfigured, err := ctx.isPodDisruptionBudgetMisconfigured(pdb, pods)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to determine if PDB is misconfigured")
+	}
+	if misconfigured {
+		components[HealthScoreMisconfigured] = 1
+	}
+
+	if statePods := ctx.statePods(pdb, pods); len(statePods) > 0 {
+		components[HealthScoreCrashLoop] = float64(countCrashloopingPods(statePods, ctx.crashLoopPredicate(ctx.crashLoopThreshold(pdb)))) / float64(len(statePods))
+		podCount, notReadyCount := countNotReadyPods(ctx.now(), statePods, ctx.notReadyThreshold(pdb), ctx.NotReadyGateTypes, ctx.ReadinessProbeGrace, ctx.NotReadyIncludePending)
+		if podCount > 0 {
+			components[HealthScoreNotReady] = float64(notReadyCount) / float64(podCount)
+		}
+	}
+
+	// the blocking duration saturates at --health-score-blocking-duration
+	if duration, ok := ctx.blockingDuration(pdb); ok && ctx.HealthScoreBlockingDuration > 0 {
+		components[HealthScoreBlockingDuration] = duration.Seconds() / ctx.HealthScoreBlockingDuration.Seconds()
+		if components[HealthScoreBlockingDuration] > 1 {
+			components[HealthScoreBlockingDuration] = 1
+		}
+	}
+
+	overlap, err := ctx.overlapFraction(pdb, pods)
+	if err != nil {
+		return nil, err
+	}
+	components[HealthScoreOverlap] = overlap
+	return components, nil
+}
+
+// healthScore returns the weighted average of the health score components, between 0 for a healthy PDB and 1 for a
+// PDB which is unhealthy by every component
+func healthScore(components, weights map[string]float64) float64 {
+	var score, total float64
+	for component, weight := range weights {
+		score += components[component] * weight
+		total += weight
+	}
+	if total == 0 {
+		return 0
+	}
+	return score / total
+}
+
+// overlapFraction returns the fraction of the pods of a PDB which are also matched by another PDB in its namespace
+func (ctx *ReaperContext) overlapFraction(pdb policyv1.PodDisruptionBudget, pods []corev1.Pod) (float64, error) {
+	if len(pods) == 0 {
+		return 0, nil
+	}
+
+	selectors := make([]labels.Selector, 0)
+	for _, other := range ctx.NamespacesWithMultiplePodDisruptionBudgets[pdb.GetNamespace()] {
+		if other.GetName() == pdb.GetName() {
+			continue
+		}
+		selector, err := metav1.LabelSelectorAsSelector(other.Spec.Selector)
+		if err != nil {
+			return 0, errors.Wrapf(err, "failed to parse selector of PDB %v", pdbNamespacedName(other))
+		}
+		selectors = append(selectors, selector)
+	}
+
+	var shared int
+	for _, pod := range pods {
+		for _, selector := range selectors {
+			if !selector.Empty() && selector.Matches(labels.Set(pod.GetLabels())) {
+				shared++
+				break
+			}
+		}
+	}
+	return float64(shared) / float64(len(pods)), nil
+}
+
+// evaluateHealthScore computes the health score of a blocking PDB, which matches when it exceeds
+// --health-score-threshold
+func (ctx *ReaperContext) evaluateHealthScore(pdb policyv1.PodDisruptionBudget, pods []corev1.Pod) (ruleResult, float64, error) {
+	components, err := ctx.healthScoreComponents(pdb, pods)
+	if err != nil {
+		return ruleResult{}, 0, errors.Wrap(err, "failed to compute PDB health score")
+	}
+	score := healthScore(components, ctx.HealthScoreWeights)
+
+	names := make([]string, 0, len(components))
+	for component := range components {
+		names = append(names, component)
+	}
+	sort.Strings(names)
+	values := make([]string, 0, len(names))
+	for _, component := range names {
+		values = append(values, component+"="+strconv.FormatFloat(components[component], 'f', 2, 64))
+	}
+	return ruleResult{
+		matched:   score > ctx.HealthScoreThreshold,
+		details:   fmt.Sprintf("score %.2f of threshold %v, components %v", score, ctx.HealthScoreThreshold, strings.Join(values, ",")),
+		eventArgs: []interface{}{score, ctx.HealthScoreThreshold},
+	}, score, nil
+}
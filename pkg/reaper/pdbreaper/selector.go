@@ -0,0 +1,93 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pdbreaper
+
+import (
+	"github.com/pkg/errors"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/selection"
+)
+
+const PdbReaperSelectorMatchedNothingMetricName = "governor_pdb_reaper_selector_matched_nothing"
+
+// classifyZeroExpectedPods tells apart the two causes of a PDB expecting 0 pods: a selector which references a label key
+// no pod in the namespace carries matches nothing and is likely broken, while otherwise the workload is assumed to be
+// scaled to zero
+func (ctx *ReaperContext) classifyZeroExpectedPods(pdb policyv1.PodDisruptionBudget) {
+	matchedNothing, key, err := ctx.isSelectorMatchingNothing(pdb)
+	if err != nil {
+		log.Warnf("failed to classify pdb %v expecting 0 pods: %v", pdbNamespacedName(pdb), err)
+		return
+	}
+
+	if matchedNothing {
+		log.Warnf("pdb %v is expecting 0 pods since its selector references label key '%v' which no pod in the namespace carries", pdbNamespacedName(pdb), key)
+		ctx.exposePodDisruptionBudgetMetric(pdb, PdbReaperSelectorMatchedNothingMetricName, 1)
+		return
+	}
+	log.Infof("pdb %v is expecting 0 pods, its workload is likely scaled to zero", pdbNamespacedName(pdb))
+	ctx.exposePodDisruptionBudgetMetric(pdb, PdbReaperSelectorMatchedNothingMetricName, 0)
+}
+
+// isSelectorMatchingNothing returns true and the label key if the selector of a PDB requires a label key which no pod in
+// its namespace carries
+func (ctx *ReaperContext) isSelectorMatchingNothing(pdb policyv1.PodDisruptionBudget) (bool, string, error) {
+	selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+	if err != nil {
+		return false, "", errors.Wrapf(err, "failed to parse selector %+v", pdb.Spec.Selector)
+	}
+	requirements, _ := selector.Requirements()
+	if len(requirements) == 0 {
+		return false, "", nil
+	}
+
+	keys, err := ctx.namespacePodLabelKeys(pdb.GetNamespace())
+	if err != nil {
+		return false, "", err
+	}
+	for _, requirement := range requirements {
+		switch requirement.Operator() {
+		case selection.NotIn, selection.NotEquals, selection.DoesNotExist:
+			// satisfied by pods without the key
+			continue
+		}
+		if !keys[requirement.Key()] {
+			return true, requirement.Key(), nil
+		}
+	}
+	return false, "", nil
+}
+
+// namespacePodLabelKeys returns the label keys carried by any pod in a namespace, listed once per namespace and run
+func (ctx *ReaperContext) namespacePodLabelKeys(namespace string) (map[string]bool, error) {
+	if keys, ok := ctx.podLabelKeys[namespace]; ok {
+		return keys, nil
+	}
+
+	pods, err := ctx.listPodsWithSelector(ctx.runContext(), namespace, "")
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list pods in namespace %v", namespace)
+	}
+	keys := make(map[string]bool)
+	for _, pod := range pods {
+		for key := range pod.GetLabels() {
+			keys[key] = true
+		}
+	}
+	ctx.podLabelKeys[namespace] = keys
+	return keys, nil
+}
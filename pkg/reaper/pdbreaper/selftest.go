@@ -0,0 +1,113 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pdbreaper
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	PdbReaperSelfTestMetricName = "governor_pdb_reaper_self_test_passed"
+
+	DefaultSelfTestNamespace = "default"
+	SelfTestPDBName          = "pdb-reaper-self-test"
+	SelfTestLabelKey         = "pdb-reaper/self-test"
+)
+
+// SelfTestPermissions are the additional permissions needed when --self-test is set
+var SelfTestPermissions = []authorizationv1.ResourceAttributes{
+	{Verb: "create", Group: "policy", Resource: "poddisruptionbudgets"},
+}
+
+// selfTest creates a PDB which matches no pods, confirms it is listed and deletes it, to verify permissions and API
+// availability before acting on real PDBs. With --dry-run or --fix-manifests-dir the create and delete are server-side
+// dry runs, so nothing is written. When --strict-self-test is set a failure fails the run.
+func (ctx *ReaperContext) selfTest() error {
+	if !ctx.SelfTest {
+		return nil
+	}
+
+	if err := ctx.runSelfTest(); err != nil {
+		log.Warnf("self-test failed: %v", err)
+		ctx.exposeClusterMetric(PdbReaperSelfTestMetricName, 0)
+		if ctx.StrictSelfTest {
+			return err
+		}
+		return nil
+	}
+
+	log.Infof("self-test passed, created, listed and deleted pdb %v/%v", ctx.SelfTestNamespace, SelfTestPDBName)
+	ctx.exposeClusterMetric(PdbReaperSelfTestMetricName, 1)
+	return nil
+}
+
+func (ctx *ReaperContext) runSelfTest() error {
+	client := ctx.KubernetesClient.PolicyV1().PodDisruptionBudgets(ctx.SelfTestNamespace)
+	maxUnavailable := intstr.FromInt(1)
+	pdb := &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      SelfTestPDBName,
+			Namespace: ctx.SelfTestNamespace,
+			Labels:    map[string]string{SelfTestLabelKey: "true"},
+		},
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			MaxUnavailable: &maxUnavailable,
+			Selector:       &metav1.LabelSelector{MatchLabels: map[string]string{SelfTestLabelKey: "true"}},
+		},
+	}
+
+	// in modes which must not write to the cluster the requests are only validated by the API server
+	var dryRun []string
+	if ctx.DryRun || ctx.FixManifestsDir != "" {
+		dryRun = []string{metav1.DryRunAll}
+	}
+
+	// a PDB left behind by an interrupted self-test is reused
+	_, err := client.Create(context.Background(), pdb, metav1.CreateOptions{DryRun: dryRun})
+	if err != nil && !kerrors.IsAlreadyExists(err) {
+		return errors.Wrapf(err, "failed to create pdb %v/%v", ctx.SelfTestNamespace, SelfTestPDBName)
+	}
+
+	pdbs, err := client.List(context.Background(), metav1.ListOptions{LabelSelector: SelfTestLabelKey + "=true"})
+	if err != nil {
+		return errors.Wrapf(err, "failed to list pdb %v/%v", ctx.SelfTestNamespace, SelfTestPDBName)
+	}
+	listed := false
+	for _, item := range pdbs.Items {
+		if item.GetName() == SelfTestPDBName {
+			listed = true
+			break
+		}
+	}
+
+	err = client.Delete(context.Background(), SelfTestPDBName, metav1.DeleteOptions{DryRun: dryRun})
+	if err != nil && !kerrors.IsNotFound(err) {
+		return errors.Wrapf(err, "failed to delete pdb %v/%v", ctx.SelfTestNamespace, SelfTestPDBName)
+	}
+
+	// a dry run create is not persisted, listing only verifies access
+	if !listed && dryRun == nil {
+		return errors.Errorf("created pdb %v/%v was not listed", ctx.SelfTestNamespace, SelfTestPDBName)
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pdbreaper
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/keikoproj/governor/pkg/reaper/common"
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// StaleSelectorPermissions are the additional permissions needed when --reap-stale-selector is set
+var StaleSelectorPermissions = []authorizationv1.ResourceAttributes{
+	{Verb: "list", Group: "apps", Resource: "deployments"},
+}
+
+// handleStaleSelectorDisruptionBudgets marks scanned PDBs whose selector matches no pods as reapable, when a deployment in
+// their namespace shares some of the selector labels but its pod template no longer matches the selector, which is what a
+// PDB is left with after the pod template labels of its deployment changed
+func (ctx *ReaperContext) handleStaleSelectorDisruptionBudgets() error {
+
+	if !ctx.ReapStaleSelector {
+		return nil
+	}
+
+	if ctx.isDrainBlockingOnly() {
+		log.Info("PDBs matching no pods cannot block the drain of a node, skipping stale selector detection due to --drain-blocking-only")
+		return nil
+	}
+
+	for _, pdb := range ctx.ScannedPodDisruptionBudgets {
+		if pdb.Spec.Selector == nil || len(pdb.Spec.Selector.MatchLabels) == 0 {
+			ctx.exposeMetric(pdb, ReasonStaleSelector, 0)
+			continue
+		}
+
+		labelSelector, err := common.GetSelectorString(pdb.Spec.Selector)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get label selector from structured selector %+v", pdb.Spec.Selector)
+		}
+		pods, err := ctx.listPodsWithSelector(ctx.evaluationContext(pdb), pdb.GetNamespace(), labelSelector)
+		if err != nil {
+			if errors.Is(err, context.DeadlineExceeded) {
+				log.Warnf("evaluation of PDB %v timed out after %v, skipping it: %v", pdbNamespacedName(pdb), ctx.PodDisruptionBudgetTimeout, err)
+				continue
+			}
+			if ctx.isPodListForbidden(pdb.GetNamespace(), err) {
+				continue
+			}
+			return errors.Wrap(err, "failed to list PDB pods")
+		}
+
+		stale, err := ctx.evaluateStaleSelector(pdb, pods)
+		if err != nil {
+			return err
+		}
+		if !stale.matched {
+			ctx.exposeMetric(pdb, ReasonStaleSelector, 0)
+			continue
+		}
+
+		log.Infof("PDB %v is marked reapable due to a stale selector, %v", pdbNamespacedName(pdb), stale.details)
+		ctx.markReapable(pdb, ReasonStaleSelector, EventMessageStaleSelectorFmt, stale.eventArgs...)
+	}
+	return nil
+}
+
+// staleSelectorDeployment returns the first deployment, by name, in the namespace of a PDB whose pod template labels share
+// at least one of the selector's matchLabels without matching the selector, and the selector labels it no longer carries
+func (ctx *ReaperContext) staleSelectorDeployment(pdb policyv1.PodDisruptionBudget) (*appsv1.Deployment, string, error) {
+	if pdb.Spec.Selector == nil || len(pdb.Spec.Selector.MatchLabels) == 0 {
+		return nil, "", nil
+	}
+	selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+	if err != nil {
+		return nil, "", errors.Wrapf(err, "failed to parse selector %+v", pdb.Spec.Selector)
+	}
+
+	deployments, err := ctx.listNamespaceDeployments(pdb.GetNamespace())
+	if err != nil {
+		return nil, "", err
+	}
+	for i := range deployments {
+		deployment := &deployments[i]
+		templateLabels := deployment.Spec.Template.GetLabels()
+		if selector.Matches(labels.Set(templateLabels)) {
+			continue
+		}
+		if staleLabels, ok := staleSelectorLabels(pdb.Spec.Selector.MatchLabels, templateLabels); ok {
+			return deployment, staleLabels, nil
+		}
+	}
+	return nil, "", nil
+}
+
+// staleSelectorLabels returns the matchLabels a pod template no longer carries, when the template still shares at least
+// one of them, e.g. a selector app=web,version=v1 against a template relabeled to app=web,version=v2
+func staleSelectorLabels(matchLabels, templateLabels map[string]string) (string, bool) {
+	shared := 0
+	stale := make([]string, 0)
+	for key, value := range matchLabels {
+		if templateValue, ok := templateLabels[key]; ok && templateValue == value {
+			shared++
+			continue
+		}
+		stale = append(stale, fmt.Sprintf("%v=%v", key, value))
+	}
+	if shared == 0 || len(stale) == 0 {
+		return "", false
+	}
+	sort.Strings(stale)
+	return strings.Join(stale, ","), true
+}
+
+// listNamespaceDeployments returns the deployments in a namespace sorted by name, listed once per namespace and run
+func (ctx *ReaperContext) listNamespaceDeployments(namespace string) ([]appsv1.Deployment, error) {
+	if deployments, ok := ctx.namespaceDeployments[namespace]; ok {
+		return deployments, nil
+	}
+
+	deploymentList, err := ctx.KubernetesClient.AppsV1().Deployments(namespace).List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list deployments in namespace %v", namespace)
+	}
+	deployments := deploymentList.Items
+	sort.Slice(deployments, func(i, j int) bool {
+		return deployments[i].GetName() < deployments[j].GetName()
+	})
+	ctx.namespaceDeployments[namespace] = deployments
+	return deployments, nil
+}
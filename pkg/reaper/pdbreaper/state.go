@@ -0,0 +1,127 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pdbreaper
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const stateConfigMapKey = "state"
+
+// ReaperState holds the pdb-reaper state which is carried over between runs
+type ReaperState struct {
+	CircuitBreakerTripped bool                 `json:"circuitBreakerTripped,omitempty"`
+	ReapedAt              map[string]time.Time `json:"reapedAt,omitempty"`
+	// BlockingRuns is the number of consecutive runs in which each PDB allowed zero disruptions
+	BlockingRuns map[string]int `json:"blockingRuns,omitempty"`
+	// ViolationStreaks is the number of consecutive runs in which each PDB was reapable, by reap mode
+	ViolationStreaks map[string]map[string]int `json:"violationStreaks,omitempty"`
+	// Recreated is the creation time of the last counted recreation of each reaped PDB
+	Recreated map[string]time.Time `json:"recreated,omitempty"`
+	// RecreatedTotal is the number of reaped PDBs recreated within --recreate-window
+	RecreatedTotal int `json:"recreatedTotal,omitempty"`
+	// ReapableReasons are the reasons each PDB was reapable for in the last run, see --reset-stale-metrics
+	ReapableReasons map[string][]Reason `json:"reapableReasons,omitempty"`
+	// Observed is set by the first run which reached the reap stage, see --skip-first-run-reap
+	Observed bool `json:"observed,omitempty"`
+	// Evaluated is the resource version each PDB was last evaluated at, see --update-debounce
+	Evaluated map[string]Evaluation `json:"evaluated,omitempty"`
+}
+
+// loadState reads the persisted state from the state ConfigMap, when no ConfigMap is configured the state is kept in memory
+func (ctx *ReaperContext) loadState() error {
+	if ctx.StateConfigMapName == "" {
+		return nil
+	}
+
+	cm, err := ctx.KubernetesClient.CoreV1().ConfigMaps(ctx.StateConfigMapNamespace).Get(context.Background(), ctx.StateConfigMapName, metav1.GetOptions{})
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			log.Infof("state configmap %v/%v not found, starting with empty state", ctx.StateConfigMapNamespace, ctx.StateConfigMapName)
+			ctx.State = ReaperState{}
+			return nil
+		}
+		return errors.Wrap(err, "failed to get state configmap")
+	}
+
+	state := ReaperState{}
+	if data, ok := cm.Data[stateConfigMapKey]; ok && data != "" {
+		if err := json.Unmarshal([]byte(data), &state); err != nil {
+			return errors.Wrap(err, "failed to unmarshal state")
+		}
+	}
+	ctx.State = state
+	return nil
+}
+
+// isFirstRun returns true when --skip-first-run-reap is set and no previous run has observed the cluster, which marks
+// the cluster as observed so subsequent runs reap
+func (ctx *ReaperContext) isFirstRun() bool {
+	if !ctx.SkipFirstRunReap || ctx.State.Observed {
+		return false
+	}
+
+	log.Warnf("first run with --skip-first-run-reap, %v reapable PDBs will not be deleted until the next run", ctx.ReapablePodDisruptionBudgetsCount)
+	ctx.State.Observed = true
+	return true
+}
+
+// saveState persists the state to the state ConfigMap, creating it if it does not exist
+func (ctx *ReaperContext) saveState() error {
+	if ctx.StateConfigMapName == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(ctx.State)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal state")
+	}
+
+	configMaps := ctx.KubernetesClient.CoreV1().ConfigMaps(ctx.StateConfigMapNamespace)
+	cm, err := configMaps.Get(context.Background(), ctx.StateConfigMapName, metav1.GetOptions{})
+	if err != nil {
+		if !kerrors.IsNotFound(err) {
+			return errors.Wrap(err, "failed to get state configmap")
+		}
+		cm = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      ctx.StateConfigMapName,
+				Namespace: ctx.StateConfigMapNamespace,
+			},
+			Data: map[string]string{stateConfigMapKey: string(data)},
+		}
+		if _, err = configMaps.Create(context.Background(), cm, metav1.CreateOptions{}); err != nil {
+			return errors.Wrap(err, "failed to create state configmap")
+		}
+		return nil
+	}
+
+	if cm.Data == nil {
+		cm.Data = make(map[string]string)
+	}
+	cm.Data[stateConfigMapKey] = string(data)
+	if _, err = configMaps.Update(context.Background(), cm, metav1.UpdateOptions{}); err != nil {
+		return errors.Wrap(err, "failed to update state configmap")
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pdbreaper
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/keikoproj/governor/pkg/reaper/common"
+	"github.com/pkg/errors"
+	policyv1 "k8s.io/api/policy/v1"
+)
+
+// parseReportOnlyThresholds parses --report-only-threshold entries in the form mode=runs, a bare number sets the
+// threshold of every reap mode which is not set explicitly
+func parseReportOnlyThresholds(entries []string) (map[string]int, error) {
+	var (
+		thresholds  = make(map[string]int)
+		defaultRuns int
+		hasDefault  bool
+	)
+
+	for _, entry := range entries {
+		parts := strings.SplitN(entry, "=", 2)
+		if len(parts) == 1 {
+			runs, err := parseReportOnlyRuns(parts[0], entry)
+			if err != nil {
+				return nil, err
+			}
+			defaultRuns, hasDefault = runs, true
+			continue
+		}
+
+		mode := strings.TrimSpace(parts[0])
+		if !common.StringSliceContains(ReapModes[:], mode) {
+			return nil, errors.Errorf("--report-only-threshold mode '%v' is not one of %v", mode, strings.Join(ReapModes[:], ","))
+		}
+		runs, err := parseReportOnlyRuns(parts[1], entry)
+		if err != nil {
+			return nil, err
+		}
+		thresholds[mode] = runs
+	}
+
+	if hasDefault {
+		for _, mode := range ReapModes {
+			if _, ok := thresholds[mode]; !ok {
+				thresholds[mode] = defaultRuns
+			}
+		}
+	}
+	return thresholds, nil
+}
+
+func parseReportOnlyRuns(value, entry string) (int, error) {
+	runs, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || runs < 0 {
+		return 0, errors.Errorf("--report-only-threshold value '%v' must be a non-negative number of runs", entry)
+	}
+	return runs, nil
+}
+
+// reasonReapMode returns the reap mode which detects a reason
+func reasonReapMode(reason Reason) (string, bool) {
+	for mode, modeReason := range ReapModeReasons {
+		if modeReason == reason {
+			return mode, true
+		}
+	}
+	return "", false
+}
+
+// updateViolationStreaks counts the consecutive runs in which each PDB was reapable for each reap mode, a run in which
+// a PDB is not reapable for a mode resets its streak
+func (ctx *ReaperContext) updateViolationStreaks() {
+	if len(ctx.ReportOnlyThresholds) == 0 {
+		return
+	}
+
+	streaks := make(map[string]map[string]int)
+	for namespacedName, reasons := range ctx.ReapableReasons {
+		for _, reason := range reasons {
+			mode, ok := reasonReapMode(reason)
+			if !ok {
+				continue
+			}
+			if streaks[namespacedName] == nil {
+				streaks[namespacedName] = make(map[string]int)
+			}
+			streaks[namespacedName][mode] = ctx.State.ViolationStreaks[namespacedName][mode] + 1
+		}
+	}
+	ctx.State.ViolationStreaks = streaks
+}
+
+// isReportOnly returns true when none of the reasons a PDB is reapable for has exceeded its --report-only-threshold,
+// along with the longest streak and its threshold
+func (ctx *ReaperContext) isReportOnly(pdb policyv1.PodDisruptionBudget) (bool, int, int) {
+	if len(ctx.ReportOnlyThresholds) == 0 {
+		return false, 0, 0
+	}
+
+	var (
+		namespacedName = pdbNamespacedName(pdb)
+		longest        int
+		threshold      int
+	)
+	for _, reason := range ctx.ReapableReasons[namespacedName] {
+		mode, ok := reasonReapMode(reason)
+		if !ok {
+			return false, 0, 0
+		}
+		runs := ctx.State.ViolationStreaks[namespacedName][mode]
+		if runs > ctx.ReportOnlyThresholds[mode] {
+			return false, runs, ctx.ReportOnlyThresholds[mode]
+		}
+		if runs >= longest {
+			longest, threshold = runs, ctx.ReportOnlyThresholds[mode]
+		}
+	}
+	return true, longest, threshold
+}
@@ -0,0 +1,148 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pdbreaper
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	RunResultAnnotationKey    = "pdb-reaper/run-result"
+	EventReasonRunSummary     = "PodDisruptionBudgetReaperRunSummary"
+	EventMessageRunSummaryFmt = "pdb-reaper scanned %v PDBs, %v were reapable and %v were reaped"
+)
+
+// runResultMaxBytes bounds the run result annotation, well below the 256KiB limit on the total size of annotations
+var runResultMaxBytes = 64 * 1024
+
+// RunResult is the result of a run, carried as JSON by the run summary event
+type RunResult struct {
+	Cluster   string              `json:"cluster,omitempty"`
+	DryRun    bool                `json:"dryRun"`
+	Scanned   int                 `json:"scanned"`
+	Reapable  int                 `json:"reapable"`
+	Reaped    int                 `json:"reaped"`
+	Patched   int                 `json:"patched,omitempty"`
+	Reasons   map[string][]Reason `json:"reasons,omitempty"`
+	Deleted   []string            `json:"deleted,omitempty"`
+	Events    []DumpedEvent       `json:"events,omitempty"`
+	Truncated bool                `json:"truncated,omitempty"`
+	Timestamp time.Time           `json:"timestamp"`
+}
+
+// DumpedEvent is an event which would have been published, captured in the run result with --dump-events
+type DumpedEvent struct {
+	PDB       string    `json:"pdb,omitempty"`
+	Namespace string    `json:"namespace,omitempty"`
+	Reason    string    `json:"reason"`
+	Type      string    `json:"type"`
+	Message   string    `json:"message"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// newRunResult summarizes the current run
+func (ctx *ReaperContext) newRunResult() RunResult {
+	return RunResult{
+		Cluster:   ctx.ClusterName,
+		DryRun:    ctx.DryRun,
+		Scanned:   ctx.ScannedPodDisruptionBudgetsCount,
+		Reapable:  ctx.ReapablePodDisruptionBudgetsCount,
+		Reaped:    ctx.ReapedPodDisruptionBudgetCount,
+		Patched:   ctx.PatchedPodDisruptionBudgetCount,
+		Reasons:   ctx.ReapableReasons,
+		Deleted:   ctx.reapedNames,
+		Events:    ctx.dumpedEvents,
+		Timestamp: ctx.now().UTC(),
+	}
+}
+
+// marshalRunResult marshals a run result, the per-PDB details are dropped when it exceeds maxBytes
+func marshalRunResult(result RunResult, maxBytes int) ([]byte, error) {
+	data, err := json.Marshal(result)
+	if err != nil || len(data) <= maxBytes {
+		return data, err
+	}
+
+	result.Reasons = nil
+	result.Deleted = nil
+	result.Events = nil
+	result.Truncated = true
+	return json.Marshal(result)
+}
+
+// publishRunSummary publishes a single event on the --summary-event-object, whose annotation carries the run result
+func (ctx *ReaperContext) publishRunSummary() error {
+	// with --dump-events a dry-run logs the run result rather than publishing it, so the captured events are reported
+	if ctx.DryRun && ctx.DumpEvents {
+		data, err := json.Marshal(ctx.newRunResult())
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal run result")
+		}
+		log.Infof("DryRun is on, run result: %s", data)
+		return nil
+	}
+
+	if ctx.SummaryEventObject == nil || !ctx.EmitEvents || ctx.FixManifestsDir != "" {
+		return nil
+	}
+	if !ctx.allowWrite("run summary event") {
+		return nil
+	}
+
+	result := ctx.newRunResult()
+	data, err := marshalRunResult(result, runResultMaxBytes)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal run result")
+	}
+
+	now := ctx.now()
+	object := ctx.SummaryEventObject
+	event := &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "pdb-reaper-run-",
+			Namespace:    object.Namespace,
+			Labels:       ctx.clusterLabels(),
+			Annotations:  map[string]string{RunResultAnnotationKey: string(data)},
+		},
+		InvolvedObject: *object,
+		Reason:         EventReasonRunSummary,
+		Message:        fmt.Sprintf(EventMessageRunSummaryFmt, result.Scanned, result.Reapable, result.Reaped),
+		Type:           "Normal",
+		FirstTimestamp: metav1.NewTime(now),
+		LastTimestamp:  metav1.NewTime(now),
+	}
+	_, err = ctx.KubernetesClient.CoreV1().Events(object.Namespace).Create(ctx.runContext(), event, metav1.CreateOptions{})
+	if err != nil {
+		return errors.Wrap(err, "failed to publish run summary event")
+	}
+	return nil
+}
+
+// parseSummaryEventObject parses an object reference in the form kind/namespace/name
+func parseSummaryEventObject(value string) (*corev1.ObjectReference, error) {
+	parts := strings.Split(value, "/")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return nil, errors.Errorf("--summary-event-object value '%v' must be in the form kind/namespace/name", value)
+	}
+	return &corev1.ObjectReference{Kind: parts[0], Namespace: parts[1], Name: parts[2]}, nil
+}
@@ -0,0 +1,80 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pdbreaper
+
+import (
+	"strconv"
+
+	policyv1 "k8s.io/api/policy/v1"
+)
+
+const (
+	CrashLoopThresholdAnnotationKey = "pdb-reaper/crashloop-threshold"
+	NotReadyThresholdAnnotationKey  = "pdb-reaper/not-ready-threshold"
+)
+
+// OwnerAnnotationKeys are set by PDB owners to configure pdb-reaper, and are not removed by --cleanup-annotations
+var OwnerAnnotationKeys = []string{CrashLoopThresholdAnnotationKey, NotReadyThresholdAnnotationKey}
+
+// crashLoopThreshold returns the crashloop restart count of a PDB, overridden by the crashloop-threshold annotation
+func (ctx *ReaperContext) crashLoopThreshold(pdb policyv1.PodDisruptionBudget) int {
+	return annotationThreshold(pdb, CrashLoopThresholdAnnotationKey, ctx.CrashLoopRestartCount)
+}
+
+// notReadyThreshold returns the not-ready threshold seconds of a PDB, overridden by the not-ready-threshold annotation
+func (ctx *ReaperContext) notReadyThreshold(pdb policyv1.PodDisruptionBudget) int {
+	return annotationThreshold(pdb, NotReadyThresholdAnnotationKey, ctx.ReapNotReadyThreshold)
+}
+
+// annotationThreshold returns the value of a threshold annotation on a PDB, the global value is returned when the
+// annotation is not set or is not a positive integer
+func annotationThreshold(pdb policyv1.PodDisruptionBudget, key string, global int) int {
+	value, ok := pdb.GetAnnotations()[key]
+	if !ok {
+		return global
+	}
+
+	threshold, err := strconv.Atoi(value)
+	if err != nil || threshold < 1 {
+		log.Warnf("ignoring invalid value '%v' of annotation %v on PDB %v, using the global value %v", value, key, pdbNamespacedName(pdb), global)
+		return global
+	}
+	log.Infof("PDB %v overrides %v with %v", pdbNamespacedName(pdb), key, threshold)
+	return threshold
+}
+
+// crashLoopPodFraction returns the minimum fraction of pods in CrashLoopBackOff for a PDB to be reapable,
+// --crashloop-pod-fraction takes precedence over --all-crashloop
+func (ctx *ReaperContext) crashLoopPodFraction() float64 {
+	return podFraction(ctx.CrashLoopPodFraction, ctx.AllCrashLoop)
+}
+
+// notReadyPodFraction returns the minimum fraction of pods in not-ready state for a PDB to be reapable,
+// --not-ready-pod-fraction takes precedence over --all-not-ready
+func (ctx *ReaperContext) notReadyPodFraction() float64 {
+	return podFraction(ctx.NotReadyPodFraction, ctx.AllNotReady)
+}
+
+// podFraction returns the fraction when set, otherwise 1 when all pods are required and 0, any pod, when not
+func podFraction(fraction float64, allPods bool) float64 {
+	if fraction > 0 {
+		return fraction
+	}
+	if allPods {
+		return 1
+	}
+	return 0
+}
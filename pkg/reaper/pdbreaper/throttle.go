@@ -0,0 +1,113 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pdbreaper
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/keikoproj/governor/pkg/reaper/common"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	DefaultThrottleRetries = 5
+	DefaultThrottleBackoff = time.Second
+	DefaultThrottleMaxWait = time.Minute
+)
+
+// throttleTransport retries requests the API server throttled with 429 Too Many Requests without a Retry-After header,
+// waiting for an exponential backoff capped at maxWait. Responses with Retry-After are returned as is, since the client
+// already retries them after the given duration
+type throttleTransport struct {
+	next    http.RoundTripper
+	retries int
+	backoff time.Duration
+	maxWait time.Duration
+	sleep   func(context.Context, time.Duration) error
+}
+
+func (t *throttleTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := t.next.RoundTrip(req)
+		if err != nil || resp.StatusCode != http.StatusTooManyRequests || attempt >= t.retries {
+			return resp, err
+		}
+		if resp.Header.Get("Retry-After") != "" {
+			return resp, nil
+		}
+		if req.Body != nil && req.GetBody == nil {
+			return resp, nil
+		}
+
+		wait := t.backoffWait(attempt)
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		log.Warnf("API server throttled %v %v, retrying in %v (%v/%v)", req.Method, req.URL.Path, wait, attempt+1, t.retries)
+		if err := t.sleep(req.Context(), wait); err != nil {
+			return nil, err
+		}
+
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+	}
+}
+
+// backoffWait returns how long to wait before retrying a throttled request
+func (t *throttleTransport) backoffWait(attempt int) time.Duration {
+	wait := t.backoff << uint(attempt)
+	if t.maxWait > 0 && wait > t.maxWait {
+		wait = t.maxWait
+	}
+	return wait
+}
+
+// sleepContext waits for the given duration unless the context is done first
+func sleepContext(ctx context.Context, d time.Duration) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(d):
+		return nil
+	}
+}
+
+// throttleConfig returns the client config options which retry API server requests throttled without Retry-After, none
+// when --throttle-retries is 0
+func (ctx *ReaperContext) throttleConfig() []common.ConfigOption {
+	if ctx.ThrottleRetries == 0 {
+		return nil
+	}
+	return []common.ConfigOption{func(config *rest.Config) {
+		config.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+			return &throttleTransport{
+				next:    rt,
+				retries: ctx.ThrottleRetries,
+				backoff: ctx.ThrottleBackoff,
+				maxWait: ctx.ThrottleMaxWait,
+				sleep:   sleepContext,
+			}
+		})
+	}}
+}
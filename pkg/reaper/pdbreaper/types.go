@@ -16,115 +16,1135 @@ limitations under the License.
 package pdbreaper
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
 
 	"github.com/keikoproj/governor/pkg/reaper/common"
 	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/version"
 	"k8s.io/client-go/kubernetes"
 )
 
+const (
+	ReapModeMisconfigured      = "misconfigured"
+	ReapModeCrashLoop          = "crashloop"
+	ReapModeNotReady           = "not-ready"
+	ReapModeMultiple           = "multiple"
+	ReapModeDrainBlocking      = "drain-blocking"
+	ReapModeDuplicateSelector  = "duplicate-selector"
+	ReapModeZeroMaxUnavailable = "zero-max-unavailable"
+	ReapModeHealthScore        = "health-score"
+	ReapModeMixedControllers   = "mixed-controllers"
+	ReapModeSingleNode         = "single-node"
+	ReapModeStaleSelector      = "stale-selector"
+)
+
+var ReapModes = [...]string{ReapModeMisconfigured, ReapModeCrashLoop, ReapModeNotReady, ReapModeMultiple, ReapModeDrainBlocking,
+	ReapModeDuplicateSelector, ReapModeZeroMaxUnavailable, ReapModeHealthScore, ReapModeMixedControllers, ReapModeSingleNode,
+	ReapModeStaleSelector}
+
+// ReapModeReasons maps each reap mode to the reason used when a PDB is detected by it
+var ReapModeReasons = map[string]Reason{
+	ReapModeMisconfigured:      ReasonBlocking,
+	ReapModeCrashLoop:          ReasonBlockingCrashLoop,
+	ReapModeNotReady:           ReasonBlockingNotReadyState,
+	ReapModeMultiple:           ReasonMultiple,
+	ReapModeDrainBlocking:      ReasonBlockingNodeDrain,
+	ReapModeDuplicateSelector:  ReasonDuplicateSelector,
+	ReapModeZeroMaxUnavailable: ReasonZeroMaxUnavailable,
+	ReapModeHealthScore:        ReasonHealthScore,
+	ReapModeMixedControllers:   ReasonMixedControllers,
+	ReapModeSingleNode:         ReasonSingleNode,
+	ReapModeStaleSelector:      ReasonStaleSelector,
+}
+
+// DefaultOwnerLabel is the PDB label used to count distinct owners affected by reaping
+const DefaultOwnerLabel = "team"
+
+// DefaultReapReasonPriority is the order used to pick the primary reason when a PDB is reapable for multiple reasons
+var DefaultReapReasonPriority = []string{ReapModeDrainBlocking, ReapModeZeroMaxUnavailable, ReapModeMisconfigured,
+	ReapModeDuplicateSelector, ReapModeStaleSelector, ReapModeMixedControllers, ReapModeSingleNode, ReapModeMultiple, ReapModeCrashLoop, ReapModeNotReady,
+	ReapModeHealthScore}
+
 // Args is the argument struct for pdb-reaper
 type Args struct {
-	K8sConfigPath         string
-	DryRun                bool
-	LocalMode             bool
-	ReapMisconfigured     bool
-	ReapMultiple          bool
-	ReapCrashLoop         bool
-	AllCrashLoop          bool
-	ExcludedNamespaces    []string
-	CrashLoopRestartCount int
-	ReapNotReady          bool
-	ReapNotReadyThreshold int
-	AllNotReady           bool
-	PromPushgateway       string
+	K8sConfigPath                  string
+	Clusters                       []string
+	DryRun                         bool
+	DryRunAnnotate                 bool
+	DumpEvents                     bool
+	FixManifestsDir                string
+	CleanupAnnotations             bool
+	Validate                       bool
+	DrainAssist                    bool
+	Node                           string
+	Inspect                        string
+	LocalMode                      bool
+	ReapMisconfigured              bool
+	ReapOnlyIfPodsMatch            bool
+	ExcludeDaemonSetPods           bool
+	ReapMultiple                   bool
+	ReapDuplicateSelector          bool
+	ReapZeroMaxUnavailable         bool
+	ReapHealthScore                bool
+	ReapMixedControllers           bool
+	ReapSingleNode                 bool
+	ReapStaleSelector              bool
+	HealthScoreThreshold           float64
+	HealthScoreWeights             []string
+	HealthScoreBlockingDuration    time.Duration
+	RequireAllPodsForMultiple      bool
+	MultipleOverlapRatio           float64
+	FixOverlap                     bool
+	ReapCrashLoop                  bool
+	AllCrashLoop                   bool
+	CrashLoopPodFraction           float64
+	ReapDrainBlocking              bool
+	DrainBlockingOnly              bool
+	NodeDrainIntegration           bool
+	MaintenanceNodeLabel           string
+	MaintenanceConfigMap           string
+	ExcludedNamespaces             []string
+	ExcludedNamespacesConfigMap    string
+	ExcludedNamespacesConfigMapKey string
+	PlatformExclusionsConfigMap    string
+	ExcludedPDBNames               []string
+	QuietNamespaces                []string
+	ProtectedPriorityClasses       []string
+	PDBLabelRequired               string
+	EnforceNamespaceLabel          string
+	HonorPausedNamespaces          bool
+	PausedSkipDetection            bool
+	CrashLoopRestartCount          int
+	CrashLoopReasons               []string
+	CrashLoopIncludeTerminated     bool
+	CrashLoopMinExitCode           int
+	ProbePodLogs                   bool
+	PodLogsLines                   int
+	PodLogsMaxBytes                int
+	ReapNotReady                   bool
+	ReapNotReadyThreshold          int
+	EvaluateZeroExpectedPods       bool
+	AllNotReady                    bool
+	NotReadyPodFraction            float64
+	NotReadyGateTypes              []string
+	ReadinessProbeGrace            bool
+	NotReadyIncludePending         bool
+	ExpectedPodsSource             string
+	CrashLoopPrecedence            bool
+	ReapModes                      []string
+	ReapReasonPriority             []string
+	ReapCooldown                   time.Duration
+	RecreateWindow                 time.Duration
+	UpdateDebounce                 time.Duration
+	MaxReapsPerRun                 int
+	MaxWritesPerRun                int
+	DeferReapsOnWriteCap           bool
+	MaxNamespaces                  int
+	DeletionOrder                  string
+	NamespaceFairness              bool
+	BlockingRuns                   int
+	ReportOnlyThreshold            []string
+	MaxAgeToConsider               time.Duration
+	ReapWindow                     string
+	PDBTimeout                     time.Duration
+	PodCountRetries                int
+	PodCountRetryDelay             time.Duration
+	ThrottleRetries                int
+	ThrottleBackoff                time.Duration
+	ThrottleMaxWait                time.Duration
+	ProgressEveryNamespaces        int
+	ProgressInterval               time.Duration
+	ReapWindowTimezone             string
+	NDJSON                         bool
+	CSVOutput                      string
+	PlanOut                        string
+	Apply                          string
+	AnnotateWorkloads              bool
+	StampProcessedGeneration       bool
+	ConfirmWithEviction            bool
+	DeleteGraceSeconds             int64
+	MinKubernetesVersion           string
+	StrictVersionCheck             bool
+	AnnotateDeleteReason           bool
+	BackupSink                     string
+	BackupSecretNamespace          string
+	BackupEndpoint                 string
+	BackupRegion                   string
+	BackupBucket                   string
+	EmitEvents                     bool
+	EmitMetrics                    bool
+	ResetStaleMetrics              bool
+	OwnerLabel                     string
+	StrictRBAC                     bool
+	SelfTest                       bool
+	SelfTestNamespace              string
+	StrictSelfTest                 bool
+	PromPushgateway                string
+	StatsdAddress                  string
+	StatsdPrefix                   string
+	BatchMetrics                   bool
+	ErrorWebhook                   string
+	HTTPProxy                      string
+	HTTPCABundle                   string
+	SummaryEventObject             string
+	NamespaceFindingsEvents        bool
+	NamespaceFindingsOnly          bool
+	MaxReapableRatio               float64
+	SkipFirstRunReap               bool
+	CheckDisruptionController      bool
+	StaleStatusRatio               float64
+	StateConfigMap                 string
 }
 
 // ReaperContext holds the context of the pdb-reaper and target cluster
 type ReaperContext struct {
 	KubernetesClient                           kubernetes.Interface
 	KubernetesConfigPath                       string
+	Clusters                                   []ClusterTarget
+	ClusterName                                string
+	ClusterResults                             map[string]ClusterResult
 	DryRun                                     bool
+	DryRunAnnotate                             bool
+	DumpEvents                                 bool
+	FixManifestsDir                            string
+	CleanupAnnotations                         bool
+	Lint                                       bool
+	DrainAssistNode                            string
+	InspectPDB                                 string
 	LocalMode                                  bool
 	ReapMisconfigured                          bool
+	ReapOnlyIfPodsMatch                        bool
+	ExcludeDaemonSetPods                       bool
 	ReapMultiple                               bool
+	ReapDuplicateSelector                      bool
+	ReapZeroMaxUnavailable                     bool
+	ReapHealthScore                            bool
+	ReapMixedControllers                       bool
+	ReapSingleNode                             bool
+	ReapStaleSelector                          bool
+	HealthScoreThreshold                       float64
+	HealthScoreWeights                         map[string]float64
+	HealthScoreBlockingDuration                time.Duration
+	MultipleOverlapRatio                       float64
+	FixOverlap                                 bool
 	ReapCrashLoop                              bool
 	AllCrashLoop                               bool
+	CrashLoopPodFraction                       float64
+	ReapDrainBlocking                          bool
+	DrainBlockingOnly                          bool
+	NodeDrainIntegration                       bool
+	MaintenanceNodeLabel                       string
+	MaintenanceConfigMapNamespace              string
+	MaintenanceConfigMapName                   string
 	CrashLoopRestartCount                      int
+	CrashLoopReasons                           []string
+	CrashLoopIncludeTerminated                 bool
+	CrashLoopMinExitCode                       int
+	ProbePodLogs                               bool
+	PodLogsLines                               int
+	PodLogsMaxBytes                            int
 	ReapNotReady                               bool
 	ReapNotReadyThreshold                      int
+	EvaluateZeroExpectedPods                   bool
 	AllNotReady                                bool
+	NotReadyPodFraction                        float64
+	NotReadyGateTypes                          []string
+	ReadinessProbeGrace                        bool
+	NotReadyIncludePending                     bool
+	ExpectedPodsSource                         string
+	CrashLoopPrecedence                        bool
+	ReapReasonPriority                         []string
 	ReapablePodDisruptionBudgets               []policyv1.PodDisruptionBudget
+	ReapableReasons                            map[string][]Reason
+	Diagnostics                                map[string]*Diagnostics
+	ScannedPodDisruptionBudgets                []policyv1.PodDisruptionBudget
 	ClusterBlockingPodDisruptionBudgets        map[string][]policyv1.PodDisruptionBudget
 	NamespacesWithMultiplePodDisruptionBudgets map[string][]policyv1.PodDisruptionBudget
 	ExcludedNamespaces                         []string
+	ExcludedNamespacesConfigMapNamespace       string
+	ExcludedNamespacesConfigMapName            string
+	ExcludedNamespacesConfigMapKey             string
+	PlatformExclusionsConfigMapNamespace       string
+	PlatformExclusionsConfigMapName            string
+	PlatformExcludedNamespaces                 []string
+	ExcludedPodDisruptionBudgets               []string
+	QuietNamespaces                            []string
+	ProtectedPriorityClasses                   []string
+	RequiredPDBLabel                           string
+	EnforceNamespaceLabel                      string
+	HonorPausedNamespaces                      bool
+	PausedSkipDetection                        bool
 	ReapablePodDisruptionBudgetsCount          int
 	ReapedPodDisruptionBudgetCount             int
+	PatchedPodDisruptionBudgetCount            int
 	PromPushgateway                            string
+	StatsdAddress                              string
+	ErrorWebhookURL                            string
+	HTTPTransport                              http.RoundTripper
+	SummaryEventObject                         *corev1.ObjectReference
+	NamespaceFindingsEvents                    bool
+	NamespaceFindingsOnly                      bool
 	MetricsAPI                                 common.MetricsAPI
+	MaxReapableRatio                           float64
+	SkipFirstRunReap                           bool
+	CheckDisruptionController                  bool
+	StaleStatusRatio                           float64
+	ReapCooldown                               time.Duration
+	RecreateWindow                             time.Duration
+	UpdateDebounce                             time.Duration
+	MaxReapsPerRun                             int
+	MaxWritesPerRun                            int
+	DeferReapsOnWriteCap                       bool
+	SuppressedWritesCount                      int
+	MaxNamespaces                              int
+	DeletionOrder                              string
+	NamespaceFairness                          bool
+	BlockingRuns                               int
+	ReportOnlyThresholds                       map[string]int
+	MaxAgeToConsider                           time.Duration
+	ReapWindow                                 *ReapWindow
+	PodDisruptionBudgetTimeout                 time.Duration
+	PodCountRetries                            int
+	PodCountRetryDelay                         time.Duration
+	ThrottleRetries                            int
+	ThrottleBackoff                            time.Duration
+	ThrottleMaxWait                            time.Duration
+	ProgressEveryNamespaces                    int
+	ProgressInterval                           time.Duration
+	NDJSON                                     bool
+	CSVOutput                                  string
+	PlanOut                                    string
+	ApplyPlan                                  string
+	SkippedPlannedReapsCount                   int
+	AnnotateWorkloads                          bool
+	StampProcessedGeneration                   bool
+	ConfirmWithEviction                        bool
+	DeleteGraceSeconds                         *int64
+	MinKubernetesVersion                       string
+	StrictVersionCheck                         bool
+	AnnotateDeleteReason                       bool
+	BackupSink                                 BackupSink
+	EmitEvents                                 bool
+	EmitMetrics                                bool
+	ResetStaleMetrics                          bool
+	OwnerLabel                                 string
+	StrictRBAC                                 bool
+	SelfTest                                   bool
+	SelfTestNamespace                          string
+	StrictSelfTest                             bool
+	Output                                     io.Writer
+	ScannedPodDisruptionBudgetsCount           int
+	StateConfigMapNamespace                    string
+	StateConfigMapName                         string
+	State                                      ReaperState
+	Clock                                      Clock
+
+	drainingNodes map[string]bool
+	// pausedNamespaces are the namespaces annotated with PausedAnnotationKey in the current run
+	pausedNamespaces map[string]bool
+	// enforcedNamespaces are the namespaces carrying --enforce-namespace-label in the current run
+	enforcedNamespaces map[string]bool
+	// flagExcludedNamespaces are the --excluded-namespaces, merged with the --excluded-namespaces-configmap on each run
+	flagExcludedNamespaces []string
+	reapedNames            []string
+	// plannedReaps are the deletions planned by the current run with --plan-out
+	plannedReaps []PlannedReap
+	// writes are the events and annotation updates made in the current run, capped by --max-writes-per-run
+	writes int
+	// degradedNamespaces are the namespaces in which listing pods was forbidden in the current run
+	degradedNamespaces map[string]bool
+	// dumpedEvents are the events captured instead of published with --dump-events in the current run
+	dumpedEvents []DumpedEvent
+	// overlapFixes are the redundant overlapping PDBs patched rather than deleted by --fix-overlap in the current run
+	overlapFixes map[string]bool
+	// unsupportedFeatures are the version gated features disabled for the server version in the current run
+	unsupportedFeatures map[string]bool
+	// processedGenerations are the generations of the PDBs evaluated in the current run
+	processedGenerations map[string]int64
+	// evaluationContexts bound the evaluation of each PDB by --pdb-timeout in the current run
+	evaluationContexts map[string]context.Context
+	evaluationCancels  []context.CancelFunc
+	// evaluationTimes are the time spent evaluating each PDB by the detections of the current run
+	evaluationTimes map[string]time.Duration
+	// matchedPods is the number of pods matched by each PDB whose pods were listed in the current run
+	matchedPods map[string]int
+	// podLabelKeys are the label keys carried by pods in each namespace, listed in the current run
+	podLabelKeys map[string]map[string]bool
+	// namespaceDeployments are the deployments in each namespace, listed in the current run
+	namespaceDeployments map[string][]appsv1.Deployment
+	// inMaintenance and maintenanceNodes are the maintenance indicators evaluated for the current run
+	inMaintenance    bool
+	maintenanceNodes map[string]bool
+	runCtx           context.Context
 }
 
 func NewReaperContext(args *Args) *ReaperContext {
+	ctx, err := newReaperContext(args)
+	if err != nil {
+		log.Fatalf("failed to validate arguments: %v", err.Error())
+	}
+	return ctx
+}
+
+func newReaperContext(args *Args) (*ReaperContext, error) {
 	ctx := &ReaperContext{
 		ExcludedNamespaces:                         make([]string, 0),
 		ReapablePodDisruptionBudgets:               make([]policyv1.PodDisruptionBudget, 0),
+		ReapableReasons:                            make(map[string][]Reason),
+		Diagnostics:                                make(map[string]*Diagnostics),
+		ScannedPodDisruptionBudgets:                make([]policyv1.PodDisruptionBudget, 0),
 		ClusterBlockingPodDisruptionBudgets:        make(map[string][]policyv1.PodDisruptionBudget),
 		NamespacesWithMultiplePodDisruptionBudgets: make(map[string][]policyv1.PodDisruptionBudget),
 	}
 
 	if err := ctx.validate(args); err != nil {
-		log.Fatalf("failed to validate arguments: %v", err.Error())
+		return nil, &RunError{Code: ErrorCodeInvalidConfig, Err: err}
+	}
+
+	if args.NDJSON || args.Validate || args.DrainAssist || args.Inspect != "" {
+		ctx.Output = os.Stdout
 	}
 
 	if args.PromPushgateway != "" {
-		ctx.MetricsAPI = common.NewPrometheusAPI(args.PromPushgateway)
+		prometheusAPI := common.NewPrometheusAPI(args.PromPushgateway)
+		if ctx.HTTPTransport != nil {
+			prometheusAPI.Client = ctx.httpClient(0)
+		}
+		ctx.MetricsAPI = prometheusAPI
 	}
 
-	return ctx
+	if args.StatsdAddress != "" {
+		ctx.MetricsAPI = common.NewStatsdAPI(args.StatsdAddress, args.StatsdPrefix)
+	}
+
+	if args.BatchMetrics && ctx.MetricsAPI != nil {
+		ctx.MetricsAPI = common.NewBufferedMetricsAPI(ctx.MetricsAPI)
+	}
+
+	return ctx, nil
+}
+
+// validateRunOnce validates the arguments of a single run, outside of daemon mode no state is kept in memory between
+// invocations
+func validateRunOnce(args *Args) error {
+	if args.StateConfigMap != "" {
+		return nil
+	}
+	if args.SkipFirstRunReap {
+		return errors.Errorf("cannot use --skip-first-run-reap without --state-configmap outside of daemon mode, every run would be a first run")
+	}
+	if len(args.ReportOnlyThreshold) > 0 {
+		return errors.Errorf("cannot use --report-only-threshold without --state-configmap outside of daemon mode, streaks would never exceed the threshold")
+	}
+	if args.BlockingRuns > 1 {
+		return errors.Errorf("cannot use --blocking-runs greater than 1 without --state-configmap outside of daemon mode, no PDB would ever be considered blocking")
+	}
+	if args.MaxReapableRatio > 0 {
+		return errors.Errorf("cannot use --max-reapable-ratio without --state-configmap outside of daemon mode, a tripped circuit breaker would never be confirmed")
+	}
+	if args.ReapCooldown > 0 {
+		return errors.Errorf("cannot use --reap-cooldown without --state-configmap outside of daemon mode, reaped PDBs would never be in cooldown")
+	}
+	if args.RecreateWindow > 0 {
+		return errors.Errorf("cannot use --recreate-window without --state-configmap outside of daemon mode, recreated PDBs would never be detected")
+	}
+	if args.UpdateDebounce > 0 {
+		return errors.Errorf("cannot use --update-debounce without --state-configmap outside of daemon mode, updates would never be debounced")
+	}
+	if args.ResetStaleMetrics && args.EmitMetrics && (args.PromPushgateway != "" || args.StatsdAddress != "") {
+		log.Warnf("--reset-stale-metrics has no effect without --state-configmap outside of daemon mode, the PDBs reapable in the previous run are not known")
+	}
+	return nil
+}
+
+// applyReapModes enables the reap modes in the given list, and disables all other modes
+func (ctx *ReaperContext) applyReapModes(modes []string) error {
+	for _, mode := range modes {
+		if !common.StringSliceContains(ReapModes[:], mode) {
+			return errors.Errorf("--reap-modes value '%v' is not one of %v", mode, strings.Join(ReapModes[:], ","))
+		}
+	}
+
+	ctx.ReapMisconfigured = common.StringSliceContains(modes, ReapModeMisconfigured)
+	ctx.ReapCrashLoop = common.StringSliceContains(modes, ReapModeCrashLoop)
+	ctx.ReapNotReady = common.StringSliceContains(modes, ReapModeNotReady)
+	ctx.ReapMultiple = common.StringSliceContains(modes, ReapModeMultiple)
+	ctx.ReapDrainBlocking = common.StringSliceContains(modes, ReapModeDrainBlocking)
+	ctx.ReapDuplicateSelector = common.StringSliceContains(modes, ReapModeDuplicateSelector)
+	ctx.ReapZeroMaxUnavailable = common.StringSliceContains(modes, ReapModeZeroMaxUnavailable)
+	ctx.ReapHealthScore = common.StringSliceContains(modes, ReapModeHealthScore)
+	ctx.ReapMixedControllers = common.StringSliceContains(modes, ReapModeMixedControllers)
+	ctx.ReapSingleNode = common.StringSliceContains(modes, ReapModeSingleNode)
+	ctx.ReapStaleSelector = common.StringSliceContains(modes, ReapModeStaleSelector)
+	return nil
+}
+
+// resetRunState clears the results of a previous run so the context can be executed again
+func (ctx *ReaperContext) resetRunState() {
+	ctx.ReapablePodDisruptionBudgets = make([]policyv1.PodDisruptionBudget, 0)
+	ctx.ReapableReasons = make(map[string][]Reason)
+	ctx.Diagnostics = make(map[string]*Diagnostics)
+	ctx.ScannedPodDisruptionBudgets = make([]policyv1.PodDisruptionBudget, 0)
+	ctx.ClusterBlockingPodDisruptionBudgets = make(map[string][]policyv1.PodDisruptionBudget)
+	ctx.NamespacesWithMultiplePodDisruptionBudgets = make(map[string][]policyv1.PodDisruptionBudget)
+	ctx.ReapablePodDisruptionBudgetsCount = 0
+	ctx.ReapedPodDisruptionBudgetCount = 0
+	ctx.PatchedPodDisruptionBudgetCount = 0
+	ctx.SuppressedWritesCount = 0
+	ctx.SkippedPlannedReapsCount = 0
+	ctx.plannedReaps = nil
+	ctx.writes = 0
+	ctx.ScannedPodDisruptionBudgetsCount = 0
+	ctx.drainingNodes = nil
+	ctx.reapedNames = nil
+	ctx.dumpedEvents = nil
+	ctx.degradedNamespaces = make(map[string]bool)
+	ctx.overlapFixes = make(map[string]bool)
+	ctx.matchedPods = make(map[string]int)
+	ctx.evaluationTimes = make(map[string]time.Duration)
+	ctx.processedGenerations = make(map[string]int64)
+	ctx.unsupportedFeatures = make(map[string]bool)
+	ctx.podLabelKeys = make(map[string]map[string]bool)
+	ctx.namespaceDeployments = make(map[string][]appsv1.Deployment)
 }
 
 func (ctx *ReaperContext) validate(args *Args) error {
 	ctx.DryRun = args.DryRun
+	ctx.DryRunAnnotate = args.DryRunAnnotate
+	ctx.DumpEvents = args.DumpEvents
+	ctx.EmitEvents = args.EmitEvents
+	ctx.EmitMetrics = args.EmitMetrics
+	ctx.ResetStaleMetrics = args.ResetStaleMetrics
+	ctx.FixManifestsDir = args.FixManifestsDir
+	ctx.CleanupAnnotations = args.CleanupAnnotations
+	ctx.Lint = args.Validate
+	if args.DrainAssist {
+		ctx.DrainAssistNode = args.Node
+	}
+	ctx.InspectPDB = args.Inspect
+	ctx.NDJSON = args.NDJSON
+	ctx.CSVOutput = args.CSVOutput
+	ctx.PlanOut = args.PlanOut
+	ctx.ApplyPlan = args.Apply
+	ctx.AnnotateWorkloads = args.AnnotateWorkloads
+	ctx.StampProcessedGeneration = args.StampProcessedGeneration
+	ctx.ConfirmWithEviction = args.ConfirmWithEviction
+	ctx.StrictRBAC = args.StrictRBAC
+
+	ctx.AnnotateDeleteReason = args.AnnotateDeleteReason
+
+	if args.MinKubernetesVersion != "" {
+		if _, err := version.ParseGeneric(args.MinKubernetesVersion); err != nil {
+			return errors.Errorf("--min-kubernetes-version value '%v' is not a version", args.MinKubernetesVersion)
+		}
+		log.Infof("Minimum server version = %v", args.MinKubernetesVersion)
+	}
+	if args.StrictVersionCheck && args.MinKubernetesVersion == "" {
+		return errors.Errorf("--strict-version-check requires --min-kubernetes-version")
+	}
+	ctx.MinKubernetesVersion = args.MinKubernetesVersion
+	ctx.StrictVersionCheck = args.StrictVersionCheck
+
+	// a negative grace period leaves it to the API default
+	if args.DeleteGraceSeconds >= 0 {
+		gracePeriod := args.DeleteGraceSeconds
+		ctx.DeleteGraceSeconds = &gracePeriod
+		log.Infof("Grace period of PDB deletions = %vs", gracePeriod)
+	}
+
+	switch args.BackupSink {
+	case "":
+	case BackupSinkSecret:
+		ctx.BackupSink = &SecretBackupSink{Namespace: args.BackupSecretNamespace}
+	case BackupSinkObjectStore:
+		if args.BackupBucket == "" {
+			return errors.Errorf("--backup-sink=%v requires --backup-bucket", BackupSinkObjectStore)
+		}
+		region := args.BackupRegion
+		if region == "" {
+			region = DefaultBackupRegion
+		}
+		ctx.BackupSink = NewObjectStoreBackupSink(args.BackupEndpoint, region, args.BackupBucket)
+	default:
+		return errors.Errorf("--backup-sink value '%v' is not one of %v", args.BackupSink, strings.Join(BackupSinks[:], ","))
+	}
+	if ctx.BackupSink != nil {
+		log.Infof("Back up PDBs before deletion to %v", ctx.BackupSink)
+	}
+
+	if args.StrictSelfTest && !args.SelfTest {
+		return errors.Errorf("--strict-self-test requires --self-test")
+	}
+	ctx.SelfTest = args.SelfTest
+	ctx.StrictSelfTest = args.StrictSelfTest
+	ctx.SelfTestNamespace = args.SelfTestNamespace
+	if ctx.SelfTestNamespace == "" {
+		ctx.SelfTestNamespace = DefaultSelfTestNamespace
+	}
+	ctx.OwnerLabel = args.OwnerLabel
+	if ctx.OwnerLabel == "" {
+		ctx.OwnerLabel = DefaultOwnerLabel
+	}
 	ctx.LocalMode = args.LocalMode
 	ctx.ReapMisconfigured = args.ReapMisconfigured
+	ctx.ReapOnlyIfPodsMatch = args.ReapOnlyIfPodsMatch
+	ctx.ExcludeDaemonSetPods = args.ExcludeDaemonSetPods
 	ctx.ReapCrashLoop = args.ReapCrashLoop
 	ctx.ReapMultiple = args.ReapMultiple
+	ctx.ReapDuplicateSelector = args.ReapDuplicateSelector
+	ctx.ReapZeroMaxUnavailable = args.ReapZeroMaxUnavailable
+	ctx.ReapHealthScore = args.ReapHealthScore
+	ctx.ReapMixedControllers = args.ReapMixedControllers
+	ctx.ReapSingleNode = args.ReapSingleNode
+	ctx.ReapStaleSelector = args.ReapStaleSelector
+
+	if args.HealthScoreThreshold < 0 || args.HealthScoreThreshold > 1 {
+		return errors.Errorf("--health-score-threshold value must be between 0 and 1")
+	}
+	ctx.HealthScoreThreshold = args.HealthScoreThreshold
+	if args.HealthScoreBlockingDuration < 0 {
+		return errors.Errorf("--health-score-blocking-duration value must not be negative")
+	}
+	ctx.HealthScoreBlockingDuration = args.HealthScoreBlockingDuration
+	weights, err := parseHealthScoreWeights(args.HealthScoreWeights)
+	if err != nil {
+		return err
+	}
+	ctx.HealthScoreWeights = weights
+
+	if args.MultipleOverlapRatio < 0 || args.MultipleOverlapRatio > 1 {
+		return errors.Errorf("--multiple-overlap-ratio value must be between 0 and 1")
+	}
+	ctx.MultipleOverlapRatio = args.MultipleOverlapRatio
+	if args.RequireAllPodsForMultiple {
+		ctx.MultipleOverlapRatio = 1
+	}
+	ctx.FixOverlap = args.FixOverlap
 	ctx.AllCrashLoop = args.AllCrashLoop
+	if args.CrashLoopPodFraction < 0 || args.CrashLoopPodFraction > 1 {
+		return errors.Errorf("--crashloop-pod-fraction value must be between 0 and 1")
+	}
+	ctx.CrashLoopPodFraction = args.CrashLoopPodFraction
+	ctx.ReapDrainBlocking = args.ReapDrainBlocking
+	ctx.DrainBlockingOnly = args.DrainBlockingOnly
+
+	if args.NodeDrainIntegration {
+		if args.MaintenanceNodeLabel == "" && args.MaintenanceConfigMap == "" {
+			return errors.Errorf("--node-drain-integration requires --maintenance-node-label or --maintenance-configmap")
+		}
+		if args.MaintenanceNodeLabel != "" {
+			if _, err := labels.Parse(args.MaintenanceNodeLabel); err != nil {
+				return errors.Errorf("--maintenance-node-label value '%v' must be in the form key or key=value", args.MaintenanceNodeLabel)
+			}
+		}
+		if args.MaintenanceConfigMap != "" {
+			parts := strings.Split(args.MaintenanceConfigMap, "/")
+			if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+				return errors.Errorf("--maintenance-configmap value '%v' must be in the form namespace/name", args.MaintenanceConfigMap)
+			}
+			ctx.MaintenanceConfigMapNamespace = parts[0]
+			ctx.MaintenanceConfigMapName = parts[1]
+		}
+	}
+	ctx.NodeDrainIntegration = args.NodeDrainIntegration
+	ctx.MaintenanceNodeLabel = args.MaintenanceNodeLabel
 	ctx.ExcludedNamespaces = args.ExcludedNamespaces
+	ctx.flagExcludedNamespaces = args.ExcludedNamespaces
+
+	if args.ExcludedNamespacesConfigMap != "" {
+		parts := strings.Split(args.ExcludedNamespacesConfigMap, "/")
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return errors.Errorf("--excluded-namespaces-configmap value '%v' must be in the form namespace/name", args.ExcludedNamespacesConfigMap)
+		}
+		ctx.ExcludedNamespacesConfigMapNamespace = parts[0]
+		ctx.ExcludedNamespacesConfigMapName = parts[1]
+		ctx.ExcludedNamespacesConfigMapKey = args.ExcludedNamespacesConfigMapKey
+		if ctx.ExcludedNamespacesConfigMapKey == "" {
+			ctx.ExcludedNamespacesConfigMapKey = DefaultExcludedNamespacesConfigMapKey
+		}
+	}
+
+	if args.PlatformExclusionsConfigMap != "" {
+		parts := strings.Split(args.PlatformExclusionsConfigMap, "/")
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return errors.Errorf("--platform-exclusions-configmap value '%v' must be in the form namespace/name", args.PlatformExclusionsConfigMap)
+		}
+		ctx.PlatformExclusionsConfigMapNamespace = parts[0]
+		ctx.PlatformExclusionsConfigMapName = parts[1]
+	}
+
+	for _, name := range args.ExcludedPDBNames {
+		parts := strings.Split(name, "/")
+		if len(parts) > 2 || common.StringSliceContains(parts, "") {
+			return errors.Errorf("--exclude-pdb-names value '%v' must be in the form namespace/name or name", name)
+		}
+	}
+	ctx.ExcludedPodDisruptionBudgets = args.ExcludedPDBNames
+	ctx.QuietNamespaces = args.QuietNamespaces
+
+	if args.PDBLabelRequired != "" {
+		if _, err := labels.Parse(args.PDBLabelRequired); err != nil {
+			return errors.Errorf("--pdb-label-required value '%v' must be in the form key or key=value", args.PDBLabelRequired)
+		}
+	}
+	ctx.RequiredPDBLabel = args.PDBLabelRequired
+
+	if args.EnforceNamespaceLabel != "" {
+		if _, err := labels.Parse(args.EnforceNamespaceLabel); err != nil {
+			return errors.Errorf("--enforce-namespace-label value '%v' must be in the form key or key=value", args.EnforceNamespaceLabel)
+		}
+	}
+	ctx.EnforceNamespaceLabel = args.EnforceNamespaceLabel
+	if args.PausedSkipDetection && !args.HonorPausedNamespaces {
+		return errors.New("cannot use --paused-skip-detection without --honor-paused-namespaces")
+	}
+	ctx.HonorPausedNamespaces = args.HonorPausedNamespaces
+	ctx.PausedSkipDetection = args.PausedSkipDetection
+	ctx.ProtectedPriorityClasses = args.ProtectedPriorityClasses
 	ctx.ReapNotReady = args.ReapNotReady
 	ctx.AllNotReady = args.AllNotReady
+	if args.NotReadyPodFraction < 0 || args.NotReadyPodFraction > 1 {
+		return errors.Errorf("--not-ready-pod-fraction value must be between 0 and 1")
+	}
+	ctx.NotReadyPodFraction = args.NotReadyPodFraction
+	ctx.NotReadyGateTypes = args.NotReadyGateTypes
+	ctx.ReadinessProbeGrace = args.ReadinessProbeGrace
+	ctx.NotReadyIncludePending = args.NotReadyIncludePending
+
+	ctx.ExpectedPodsSource = ExpectedPodsSourceStatus
+	if args.ExpectedPodsSource != "" {
+		if !common.StringSliceContains(ExpectedPodsSources[:], args.ExpectedPodsSource) {
+			return errors.Errorf("--expected-pods-source value '%v' is not one of %v", args.ExpectedPodsSource, strings.Join(ExpectedPodsSources[:], ","))
+		}
+		ctx.ExpectedPodsSource = args.ExpectedPodsSource
+	}
+	ctx.EvaluateZeroExpectedPods = args.EvaluateZeroExpectedPods
+	ctx.CrashLoopPrecedence = args.CrashLoopPrecedence
+
+	if len(args.ReapModes) > 0 {
+		if err := ctx.applyReapModes(args.ReapModes); err != nil {
+			return err
+		}
+	}
 	ctx.PromPushgateway = args.PromPushgateway
 
+	if args.StatsdAddress != "" {
+		if args.PromPushgateway != "" {
+			return errors.Errorf("cannot use --statsd-address with --prometheus-pushgateway")
+		}
+		if _, _, err := net.SplitHostPort(args.StatsdAddress); err != nil {
+			return errors.Errorf("--statsd-address value '%v' must be in the form host:port", args.StatsdAddress)
+		}
+	}
+	ctx.StatsdAddress = args.StatsdAddress
+
+	if args.ErrorWebhook != "" {
+		webhookURL, err := url.Parse(args.ErrorWebhook)
+		if err != nil || (webhookURL.Scheme != "http" && webhookURL.Scheme != "https") || webhookURL.Host == "" {
+			return errors.Errorf("--report-webhook-on-error value '%v' must be an http or https URL", args.ErrorWebhook)
+		}
+		ctx.ErrorWebhookURL = args.ErrorWebhook
+	}
+
+	if args.HTTPProxy != "" || args.HTTPCABundle != "" {
+		transport, err := newHTTPTransport(args.HTTPProxy, args.HTTPCABundle)
+		if err != nil {
+			return err
+		}
+		ctx.HTTPTransport = transport
+	}
+
+	if args.SummaryEventObject != "" {
+		object, err := parseSummaryEventObject(args.SummaryEventObject)
+		if err != nil {
+			return err
+		}
+		ctx.SummaryEventObject = object
+	}
+
+	if args.NamespaceFindingsOnly && !args.NamespaceFindingsEvents {
+		return errors.New("cannot use --namespace-findings-only without --annotate-findings-to-namespace-events")
+	}
+	ctx.NamespaceFindingsEvents = args.NamespaceFindingsEvents
+	ctx.NamespaceFindingsOnly = args.NamespaceFindingsOnly
+
 	if args.CrashLoopRestartCount < 1 {
 		return errors.Errorf("--crashloop-restart-count value cannot be less than 1")
 	}
+
+	ctx.CrashLoopReasons = DefaultCrashLoopReasons
+	if len(args.CrashLoopReasons) > 0 {
+		for _, reason := range args.CrashLoopReasons {
+			if strings.TrimSpace(reason) == "" {
+				return errors.New("--crashloop-reasons cannot contain an empty reason")
+			}
+		}
+		ctx.CrashLoopReasons = args.CrashLoopReasons
+	}
+	if args.CrashLoopMinExitCode < 0 {
+		return errors.Errorf("--crashloop-min-exit-code value cannot be negative")
+	}
+	ctx.CrashLoopIncludeTerminated = args.CrashLoopIncludeTerminated
+	ctx.CrashLoopMinExitCode = args.CrashLoopMinExitCode
 	ctx.CrashLoopRestartCount = args.CrashLoopRestartCount
 
+	ctx.ProbePodLogs = args.ProbePodLogs
+	if args.ProbePodLogs && (args.PodLogsLines < 1 || args.PodLogsMaxBytes < 1) {
+		return errors.Errorf("--probe-pod-logs-lines and --probe-pod-logs-max-bytes values cannot be less than 1")
+	}
+	ctx.PodLogsLines = args.PodLogsLines
+	ctx.PodLogsMaxBytes = args.PodLogsMaxBytes
+
 	if args.ReapNotReadyThreshold < 1 {
 		return errors.Errorf("--not-ready-threshold-seconds value cannot be less than 1")
 	}
 	ctx.ReapNotReadyThreshold = args.ReapNotReadyThreshold
 
+	for _, mode := range args.ReapReasonPriority {
+		if !common.StringSliceContains(ReapModes[:], mode) {
+			return errors.Errorf("--reap-reason-priority value '%v' is not one of %v", mode, strings.Join(ReapModes[:], ","))
+		}
+	}
+	ctx.ReapReasonPriority = args.ReapReasonPriority
+
+	if args.DryRunAnnotate && !args.DryRun {
+		return errors.Errorf("cannot use --dry-run-annotate without --dry-run")
+	}
+
+	if args.DumpEvents && !args.DryRun {
+		return errors.Errorf("cannot use --dump-events without --dry-run")
+	}
+
+	if args.FixManifestsDir != "" && (args.DryRunAnnotate || args.CleanupAnnotations) {
+		return errors.Errorf("cannot use --fix-manifests-dir with --dry-run-annotate or --cleanup-annotations")
+	}
+
+	if args.Validate && (len(args.Clusters) > 0 || args.NDJSON || args.FixManifestsDir != "" || args.CleanupAnnotations) {
+		return errors.Errorf("cannot use --validate with --cluster, --ndjson, --fix-manifests-dir or --cleanup-annotations")
+	}
+
+	if args.CSVOutput != "" && len(args.Clusters) > 0 {
+		return errors.Errorf("cannot use --csv-output with --cluster")
+	}
+
+	if args.PlanOut != "" && args.Apply != "" {
+		return errors.Errorf("cannot use --plan-out with --apply")
+	}
+
+	if args.PlanOut != "" && (len(args.Clusters) > 0 || args.FixOverlap) {
+		return errors.Errorf("cannot use --plan-out with --cluster or --fix-overlap")
+	}
+
+	if args.Apply != "" && (args.Validate || args.DrainAssist || args.Inspect != "" || len(args.Clusters) > 0 || args.FixManifestsDir != "" || args.CleanupAnnotations) {
+		return errors.Errorf("cannot use --apply with --validate, --drain-assist, --inspect, --cluster, --fix-manifests-dir or --cleanup-annotations")
+	}
+
+	if args.DrainAssist != (args.Node != "") {
+		return errors.Errorf("--drain-assist and --node must be used together")
+	}
+
+	if args.DrainAssist && (args.Validate || len(args.Clusters) > 0 || args.NDJSON || args.FixManifestsDir != "" || args.CleanupAnnotations) {
+		return errors.Errorf("cannot use --drain-assist with --validate, --cluster, --ndjson, --fix-manifests-dir or --cleanup-annotations")
+	}
+
+	if args.Inspect != "" {
+		if namespace, name, ok := strings.Cut(args.Inspect, "/"); !ok || namespace == "" || name == "" {
+			return errors.Errorf("--inspect value '%v' must be in the form namespace/name", args.Inspect)
+		}
+		if args.Validate || args.DrainAssist || len(args.Clusters) > 0 || args.NDJSON || args.FixManifestsDir != "" || args.CleanupAnnotations {
+			return errors.Errorf("cannot use --inspect with --validate, --drain-assist, --cluster, --ndjson, --fix-manifests-dir or --cleanup-annotations")
+		}
+	}
+
+	if args.MaxReapableRatio < 0 || args.MaxReapableRatio > 1 {
+		return errors.Errorf("--max-reapable-ratio value must be between 0 and 1")
+	}
+	ctx.MaxReapableRatio = args.MaxReapableRatio
+	ctx.SkipFirstRunReap = args.SkipFirstRunReap
+
+	if args.CheckDisruptionController && (args.StaleStatusRatio <= 0 || args.StaleStatusRatio > 1) {
+		return errors.Errorf("--stale-status-ratio value must be greater than 0 and at most 1")
+	}
+	ctx.CheckDisruptionController = args.CheckDisruptionController
+	ctx.StaleStatusRatio = args.StaleStatusRatio
+
+	if args.ReapCooldown < 0 {
+		return errors.Errorf("--reap-cooldown value cannot be negative")
+	}
+	ctx.ReapCooldown = args.ReapCooldown
+
+	if args.RecreateWindow < 0 {
+		return errors.Errorf("--recreate-window value cannot be negative")
+	}
+	ctx.RecreateWindow = args.RecreateWindow
+
+	if args.UpdateDebounce < 0 {
+		return errors.Errorf("--update-debounce value cannot be negative")
+	}
+	ctx.UpdateDebounce = args.UpdateDebounce
+
+	if args.MaxReapsPerRun < 0 {
+		return errors.Errorf("--max-reaps-per-run value cannot be negative")
+	}
+	ctx.MaxReapsPerRun = args.MaxReapsPerRun
+
+	if args.MaxWritesPerRun < 0 {
+		return errors.Errorf("--max-writes-per-run value cannot be negative")
+	}
+	if args.DeferReapsOnWriteCap && args.MaxWritesPerRun == 0 {
+		return errors.New("cannot use --defer-reaps-on-write-cap without --max-writes-per-run")
+	}
+	ctx.MaxWritesPerRun = args.MaxWritesPerRun
+	ctx.DeferReapsOnWriteCap = args.DeferReapsOnWriteCap
+
+	if args.MaxNamespaces < 0 {
+		return errors.Errorf("--max-namespaces value cannot be negative")
+	}
+	ctx.MaxNamespaces = args.MaxNamespaces
+
+	ctx.DeletionOrder = DeletionOrderDiscovery
+	if args.DeletionOrder != "" {
+		if !common.StringSliceContains(DeletionOrders[:], args.DeletionOrder) {
+			return errors.Errorf("--deletion-order value '%v' is not one of %v", args.DeletionOrder, strings.Join(DeletionOrders[:], ","))
+		}
+		ctx.DeletionOrder = args.DeletionOrder
+	}
+	ctx.NamespaceFairness = args.NamespaceFairness
+
+	if args.MaxAgeToConsider < 0 {
+		return errors.Errorf("--max-age-to-consider value cannot be negative")
+	}
+	ctx.MaxAgeToConsider = args.MaxAgeToConsider
+
+	if args.BlockingRuns < 0 {
+		return errors.Errorf("--blocking-runs value cannot be negative")
+	}
+	ctx.BlockingRuns = args.BlockingRuns
+	thresholds, err := parseReportOnlyThresholds(args.ReportOnlyThreshold)
+	if err != nil {
+		return err
+	}
+	ctx.ReportOnlyThresholds = thresholds
+
+	if args.PDBTimeout < 0 {
+		return errors.Errorf("--pdb-timeout value cannot be negative")
+	}
+	ctx.PodDisruptionBudgetTimeout = args.PDBTimeout
+
+	if args.PodCountRetries < 0 || args.PodCountRetryDelay < 0 {
+		return errors.Errorf("--pod-count-retries and --pod-count-retry-delay values cannot be negative")
+	}
+	ctx.PodCountRetries = args.PodCountRetries
+	ctx.PodCountRetryDelay = args.PodCountRetryDelay
+
+	if args.ThrottleRetries < 0 || args.ThrottleBackoff < 0 || args.ThrottleMaxWait < 0 {
+		return errors.Errorf("--throttle-retries, --throttle-backoff and --throttle-max-wait values cannot be negative")
+	}
+	ctx.ThrottleRetries = args.ThrottleRetries
+	ctx.ThrottleBackoff = args.ThrottleBackoff
+	ctx.ThrottleMaxWait = args.ThrottleMaxWait
+
+	if args.ProgressEveryNamespaces < 0 || args.ProgressInterval < 0 {
+		return errors.Errorf("--progress-every-namespaces and --progress-interval values cannot be negative")
+	}
+	ctx.ProgressEveryNamespaces = args.ProgressEveryNamespaces
+	ctx.ProgressInterval = args.ProgressInterval
+
+	if args.ReapWindow != "" {
+		timezone := args.ReapWindowTimezone
+		if timezone == "" {
+			timezone = "UTC"
+		}
+		window, err := parseReapWindow(args.ReapWindow, timezone)
+		if err != nil {
+			return err
+		}
+		ctx.ReapWindow = window
+	}
+
+	if args.StateConfigMap != "" {
+		parts := strings.Split(args.StateConfigMap, "/")
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return errors.Errorf("--state-configmap value '%v' must be in the form namespace/name", args.StateConfigMap)
+		}
+		ctx.StateConfigMapNamespace = parts[0]
+		ctx.StateConfigMapName = parts[1]
+	}
+
 	log.Infof("Dry Run = %t", ctx.DryRun)
+	log.Infof("Annotate reapable PDBs in Dry Run = %t", ctx.DryRunAnnotate)
+	log.Infof("Capture events in the run result instead of publishing them in Dry Run = %t", ctx.DumpEvents)
+	log.Infof("Emit events = %t", ctx.EmitEvents)
+	log.Infof("Emit metrics = %t", ctx.EmitMetrics)
+	log.Infof("Reset metrics of PDBs which are no longer reapable = %t", ctx.ResetStaleMetrics)
+	if ctx.SummaryEventObject != nil {
+		log.Infof("Run summary event object = %v/%v/%v", ctx.SummaryEventObject.Kind, ctx.SummaryEventObject.Namespace, ctx.SummaryEventObject.Name)
+	}
+	if ctx.NamespaceFindingsEvents {
+		log.Infof("Findings are published per namespace, per-PDB detection events = %t", !ctx.NamespaceFindingsOnly)
+	}
+	if ctx.FixManifestsDir != "" {
+		log.Infof("Fixed manifests mode, fixed manifests of misconfigured PDBs are written to %v and no PDBs will be reaped", ctx.FixManifestsDir)
+	}
+	if ctx.Lint {
+		log.Info("Validate mode, a findings report of misconfigured, blocking and overlapping PDBs is written and no PDBs will be reaped")
+	}
+	if ctx.DrainAssistNode != "" {
+		log.Infof("Drain assist mode, PDBs blocking the drain of node %v are reported and no PDBs will be reaped", ctx.DrainAssistNode)
+	}
+	if ctx.InspectPDB != "" {
+		log.Infof("Inspect mode, the decision trace of PDB %v is reported and no PDBs will be reaped", ctx.InspectPDB)
+	}
+	if ctx.PlanOut != "" {
+		log.Infof("Plan mode, the deletions of the run are written to %v and no PDBs will be reaped", ctx.PlanOut)
+	}
+	if ctx.ApplyPlan != "" {
+		log.Infof("Apply mode, the deletions planned in %v are executed", ctx.ApplyPlan)
+	}
+	if ctx.CleanupAnnotations {
+		log.Info("Cleanup mode, managed annotations will be removed from all PDBs and no PDBs will be reaped")
+	}
 	log.Infof("Reap Misconfigured PDBs = %t", ctx.ReapMisconfigured)
+	log.Infof("Misconfigured PDBs must match at least one pod = %t", ctx.ReapOnlyIfPodsMatch)
 	log.Infof("Reap PDBs blocked by CrashLoopBackOff = %v", ctx.ReapCrashLoop)
-	log.Infof("All pods must be in CrashLoopBackOff = %t", ctx.AllCrashLoop)
+	log.Infof("Crashlooping container waiting reasons = %v, terminated containers with exit code >= %v = %t", ctx.CrashLoopReasons, ctx.CrashLoopMinExitCode, ctx.CrashLoopIncludeTerminated)
+	log.Infof("Exclude DaemonSet pods from crashloop and not-ready detection = %t", ctx.ExcludeDaemonSetPods)
+	log.Infof("Minimum fraction of pods in CrashLoopBackOff = %v (0 is any pod)", ctx.crashLoopPodFraction())
 	log.Infof("RestartCount Threshold = %v", ctx.CrashLoopRestartCount)
+	if ctx.ProbePodLogs {
+		log.Infof("Probe last %v log lines (up to %v bytes) of crashlooping containers", ctx.PodLogsLines, ctx.PodLogsMaxBytes)
+	}
 	log.Infof("Reap Multiple PDBs targeting same deployment = %t", ctx.ReapMultiple)
+	log.Infof("Reap PDBs sharing an identical selector = %t", ctx.ReapDuplicateSelector)
+	log.Infof("Reap PDBs with maxUnavailable resolving to 0 regardless of pod state = %t", ctx.ReapZeroMaxUnavailable)
+	log.Infof("Reap blocking PDBs matching pods of multiple controllers = %t", ctx.ReapMixedControllers)
+	log.Infof("Reap blocking PDBs whose pods are all on a single node = %t", ctx.ReapSingleNode)
+	log.Infof("Reap PDBs whose selector was likely left stale by a deployment label change = %t", ctx.ReapStaleSelector)
+	log.Infof("Reap blocking PDBs whose health score exceeds %v = %t", ctx.HealthScoreThreshold, ctx.ReapHealthScore)
+	if ctx.MultipleOverlapRatio > 0 {
+		log.Infof("Minimum ratio of shared pods for multiple PDBs = %v", ctx.MultipleOverlapRatio)
+	}
+	if ctx.FixOverlap {
+		log.Infof("Patch redundant overlapping PDBs to maxUnavailable %v instead of deleting them", FixOverlapMaxUnavailable)
+	}
+	log.Infof("Reap blocking PDBs with pods on cordoned/draining nodes = %t", ctx.ReapDrainBlocking)
+	log.Infof("Only reap PDBs with pods on cordoned/draining nodes = %t", ctx.DrainBlockingOnly)
+	if ctx.NodeDrainIntegration {
+		log.Infof("Planned maintenance indicated by node label '%v' / configmap '%v/%v'", ctx.MaintenanceNodeLabel, ctx.MaintenanceConfigMapNamespace, ctx.MaintenanceConfigMapName)
+	}
 	log.Infof("Reap PDBs with pods in not-ready state = %t", ctx.ReapNotReady)
 	log.Infof("Minimum seconds to wait when considering pods in not-ready state = %v", ctx.ReapNotReadyThreshold)
-	log.Infof("All pods must be in not-ready state = %t", ctx.AllNotReady)
+	log.Infof("Minimum fraction of pods in not-ready state = %v (0 is any pod)", ctx.notReadyPodFraction())
+	log.Infof("Add readiness probe initial delay to not-ready threshold = %t", ctx.ReadinessProbeGrace)
+	log.Infof("Count Pending pods in not-ready state = %t", ctx.NotReadyIncludePending)
+	log.Infof("Budgets resolved against %v pod count", ctx.ExpectedPodsSource)
+	log.Infof("Crashlooping pods are not counted as not-ready = %t", ctx.CrashLoopPrecedence)
+	log.Infof("Evaluate PDBs expecting 0 pods whose selector matches live pods = %t", ctx.EvaluateZeroExpectedPods)
+	if len(ctx.ReapReasonPriority) > 0 {
+		log.Infof("Reap reason priority = %+v", ctx.ReapReasonPriority)
+	}
+	if len(ctx.NotReadyGateTypes) > 0 {
+		log.Infof("Readiness gate conditions considered for not-ready state = %+v", ctx.NotReadyGateTypes)
+	}
+	log.Infof("Maximum ratio of reapable to scanned PDBs = %v", ctx.MaxReapableRatio)
+	log.Infof("Skip reaping in the first run = %t", ctx.SkipFirstRunReap)
+	if ctx.CheckDisruptionController {
+		log.Infof("Skip reaping when the status of at least %v of PDBs is stale", ctx.StaleStatusRatio)
+	}
+	log.Infof("Cooldown before reaping a recreated PDB = %v", ctx.ReapCooldown)
+	log.Infof("Window to detect recreated PDBs = %v", ctx.RecreateWindow)
+	log.Infof("Minimum time between evaluations of a PDB which keeps updating = %v", ctx.UpdateDebounce)
+	log.Infof("Maximum PDBs reaped per run = %v (0 is unlimited), deleted in %v order", ctx.MaxReapsPerRun, ctx.DeletionOrder)
+	log.Infof("Maximum events and annotation updates per run = %v (0 is unlimited), defer deletions once reached = %t", ctx.MaxWritesPerRun, ctx.DeferReapsOnWriteCap)
+	log.Infof("Delete PDBs round-robin across namespaces = %t", ctx.NamespaceFairness)
+	log.Infof("Maximum namespaces in scope = %v (0 is unlimited)", ctx.MaxNamespaces)
+	log.Infof("Maximum age of PDBs to consider = %v (0 disables)", ctx.MaxAgeToConsider)
+	log.Infof("Consecutive runs a PDB must allow 0 disruptions to be considered blocking = %v", ctx.BlockingRuns)
+	if len(ctx.ReportOnlyThresholds) > 0 {
+		log.Infof("Consecutive runs a PDB is only reported before it is reaped = %v", ctx.ReportOnlyThresholds)
+	}
+	log.Infof("Fail on insufficient RBAC permissions = %t", ctx.StrictRBAC)
+	if ctx.SelfTest {
+		log.Infof("Self-test in namespace %v, fail on self-test failure = %t", ctx.SelfTestNamespace, ctx.StrictSelfTest)
+	}
+	log.Infof("Timeout when evaluating a single PDB = %v", ctx.PodDisruptionBudgetTimeout)
+	log.Infof("Re-list pods fewer than expected = %v times every %v", ctx.PodCountRetries, ctx.PodCountRetryDelay)
+	log.Infof("Retry API server requests throttled without Retry-After = %v times, backoff %v, maximum wait %v", ctx.ThrottleRetries, ctx.ThrottleBackoff, ctx.ThrottleMaxWait)
+	log.Infof("Progress logged every %v namespaces / every %v (0 disables)", ctx.ProgressEveryNamespaces, ctx.ProgressInterval)
+	if ctx.ReapWindow != nil {
+		log.Infof("Reap window = %v", ctx.ReapWindow)
+	}
 
 	if args.PromPushgateway != "" {
 		log.Infof("Prometheus pushgateway %s", args.PromPushgateway)
 	}
 
+	if args.StatsdAddress != "" {
+		log.Infof("Statsd address %s, prefix '%s'", args.StatsdAddress, args.StatsdPrefix)
+	}
+
+	if ctx.StateConfigMapName != "" {
+		log.Infof("State ConfigMap = %v/%v", ctx.StateConfigMapNamespace, ctx.StateConfigMapName)
+	}
+
 	if len(ctx.ExcludedNamespaces) > 0 {
 		log.Infof("Excluded namespaces = %+v", ctx.ExcludedNamespaces)
 	}
 
+	if ctx.ExcludedNamespacesConfigMapName != "" {
+		log.Infof("Excluded namespaces are also read from key %v of configmap %v/%v", ctx.ExcludedNamespacesConfigMapKey, ctx.ExcludedNamespacesConfigMapNamespace, ctx.ExcludedNamespacesConfigMapName)
+	}
+	if ctx.PlatformExclusionsConfigMapName != "" {
+		log.Infof("Mandatory platform exclusions are read from key %v of configmap %v/%v", DefaultExcludedNamespacesConfigMapKey, ctx.PlatformExclusionsConfigMapNamespace, ctx.PlatformExclusionsConfigMapName)
+	}
+
+	if len(ctx.ExcludedPodDisruptionBudgets) > 0 {
+		log.Infof("Excluded PDBs = %+v", ctx.ExcludedPodDisruptionBudgets)
+	}
+
+	if len(ctx.QuietNamespaces) > 0 {
+		log.Infof("Events are not published in quiet namespaces = %+v", ctx.QuietNamespaces)
+	}
+
+	if len(ctx.ProtectedPriorityClasses) > 0 {
+		log.Infof("PDBs of pods with priority classes %+v are protected", ctx.ProtectedPriorityClasses)
+	}
+
+	if ctx.EnforceNamespaceLabel != "" {
+		log.Infof("Reaping is enforced in namespaces labeled '%v', other namespaces are report-only", ctx.EnforceNamespaceLabel)
+	}
+
+	if ctx.HonorPausedNamespaces {
+		log.Infof("Reaping is paused in namespaces annotated '%v=true', skip detection: %t", PausedAnnotationKey, ctx.PausedSkipDetection)
+	}
+
+	if ctx.RequiredPDBLabel != "" {
+		log.Infof("Only PDBs labeled '%v' are considered", ctx.RequiredPDBLabel)
+	}
+
+	if len(args.Clusters) > 0 {
+		if args.K8sConfigPath != "" || args.LocalMode {
+			return errors.Errorf("cannot use --cluster with --kubeconfig or --local-mode")
+		}
+		if err := ctx.loadClusterTargets(args.Clusters); err != nil {
+			return err
+		}
+		log.Infof("Clusters = %+v", clusterTargetNames(ctx.Clusters))
+		return nil
+	}
+
 	if args.K8sConfigPath != "" {
 		if ok := common.PathExists(args.K8sConfigPath); !ok {
 			return errors.Errorf("--kubeconfig path '%v' was not found", args.K8sConfigPath)
@@ -138,14 +1158,14 @@ func (ctx *ReaperContext) validate(args *Args) error {
 		}
 
 		var err error
-		ctx.KubernetesClient, err = common.OutOfClusterAuth(ctx.KubernetesConfigPath)
+		ctx.KubernetesClient, err = common.OutOfClusterAuth(ctx.KubernetesConfigPath, ctx.throttleConfig()...)
 		if err != nil {
 			return errors.Wrap(err, "cluster external auth failed")
 		}
 
 	} else {
 		var err error
-		ctx.KubernetesClient, err = common.InClusterAuth()
+		ctx.KubernetesClient, err = common.InClusterAuth(ctx.throttleConfig()...)
 		if err != nil {
 			return errors.Wrap(err, "in-cluster auth failed")
 		}
@@ -154,6 +1174,14 @@ func (ctx *ReaperContext) validate(args *Args) error {
 	return nil
 }
 
+func clusterTargetNames(targets []ClusterTarget) []string {
+	names := make([]string, 0)
+	for _, target := range targets {
+		names = append(names, target.Name)
+	}
+	return names
+}
+
 func pdbNamespacedName(pdb policyv1.PodDisruptionBudget) string {
 	var (
 		name      = pdb.GetName()
@@ -183,3 +1211,39 @@ func podSliceNamespacedNames(pods []corev1.Pod) []string {
 	}
 	return names
 }
+
+func uniqueStrings(sl []string) []string {
+	seen := make(map[string]bool)
+	unique := make([]string, 0)
+	for _, s := range sl {
+		if seen[s] {
+			continue
+		}
+		seen[s] = true
+		unique = append(unique, s)
+	}
+	return unique
+}
+
+func podSliceNodeNames(pods []corev1.Pod) []string {
+	names := make([]string, 0)
+	for _, pod := range pods {
+		names = append(names, pod.Spec.NodeName)
+	}
+	return uniqueStrings(names)
+}
+
+// singleNodeName returns the node all pods are scheduled on, when there are at least two pods and they all share the
+// same node, an unscheduled pod is not on any node
+func singleNodeName(pods []corev1.Pod) (string, bool) {
+	if len(pods) < 2 {
+		return "", false
+	}
+	nodeName := pods[0].Spec.NodeName
+	for _, pod := range pods {
+		if pod.Spec.NodeName == "" || pod.Spec.NodeName != nodeName {
+			return "", false
+		}
+	}
+	return nodeName, true
+}
@@ -16,12 +16,51 @@ limitations under the License.
 package pdbreaper
 
 import (
+	"bytes"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
 
+func TestReaperContext_applyReapModes(t *testing.T) {
+	tests := []struct {
+		name              string
+		modes             []string
+		wantMisconfigured bool
+		wantCrashLoop     bool
+		wantNotReady      bool
+		wantMultiple      bool
+		wantErr           bool
+		wantErrMsg        string
+	}{
+		{"Misconfigured-CrashLoop", []string{"misconfigured", "crashloop"}, true, true, false, false, false, ""},
+		{"NotReady-Multiple", []string{"not-ready", "multiple"}, false, false, true, true, false, ""},
+		{"All", []string{"misconfigured", "crashloop", "not-ready", "multiple"}, true, true, true, true, false, ""},
+		{"Unknown", []string{"misconfigured", "orphaned"}, false, false, false, false, true, "--reap-modes value 'orphaned' is not one of misconfigured,crashloop,not-ready,multiple,drain-blocking,duplicate-selector,zero-max-unavailable,health-score,mixed-controllers,single-node,stale-selector"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := _fakeReaperContext()
+			ctx.ReapMisconfigured = false
+			ctx.ReapCrashLoop = false
+			ctx.ReapNotReady = false
+			ctx.ReapMultiple = false
+			err := ctx.applyReapModes(tt.modes)
+			if tt.wantErr {
+				assert.EqualError(t, err, tt.wantErrMsg)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantMisconfigured, ctx.ReapMisconfigured)
+			assert.Equal(t, tt.wantCrashLoop, ctx.ReapCrashLoop)
+			assert.Equal(t, tt.wantNotReady, ctx.ReapNotReady)
+			assert.Equal(t, tt.wantMultiple, ctx.ReapMultiple)
+		})
+	}
+}
+
 func TestReaperContext_validate(t *testing.T) {
 	reaperArgsValid := Args{
 		LocalMode:             false,
@@ -38,6 +77,144 @@ func TestReaperContext_validate(t *testing.T) {
 	reaperArgsInvalidReapNotReadyThreshold := Args(reaperArgsValid)
 	reaperArgsInvalidReapNotReadyThreshold.ReapNotReadyThreshold = -99
 
+	reaperArgsInvalidDryRunAnnotate := Args(reaperArgsValid)
+	reaperArgsInvalidDryRunAnnotate.DryRun = false
+	reaperArgsInvalidDryRunAnnotate.DryRunAnnotate = true
+
+	reaperArgsInvalidMaxReapableRatio := Args(reaperArgsValid)
+	reaperArgsInvalidMaxReapableRatio.MaxReapableRatio = 1.5
+
+	reaperArgsInvalidStateConfigMap := Args(reaperArgsValid)
+	reaperArgsInvalidStateConfigMap.StateConfigMap = "pdb-reaper-state"
+
+	reaperArgsInvalidCluster := Args(reaperArgsValid)
+	reaperArgsInvalidCluster.Clusters = []string{"cluster-a"}
+
+	reaperArgsInvalidClusterWithKubeconfig := Args(reaperArgsValid)
+	reaperArgsInvalidClusterWithKubeconfig.Clusters = []string{"cluster-a=/tmp/kubeconfig"}
+	reaperArgsInvalidClusterWithKubeconfig.K8sConfigPath = "/tmp/kubeconfig"
+
+	reaperArgsInvalidExcludedPDBNames := Args(reaperArgsValid)
+	reaperArgsInvalidExcludedPDBNames.ExcludedPDBNames = []string{"namespace-1/pdb-1", "namespace-1/"}
+
+	reaperArgsInvalidReapWindow := Args(reaperArgsValid)
+	reaperArgsInvalidReapWindow.ReapWindow = "9am-5pm"
+
+	reaperArgsInvalidReapReasonPriority := Args(reaperArgsValid)
+	reaperArgsInvalidReapReasonPriority.ReapReasonPriority = []string{"multiple", "blocking"}
+
+	reaperArgsInvalidMaxAgeToConsider := Args(reaperArgsValid)
+	reaperArgsInvalidMaxAgeToConsider.MaxAgeToConsider = -time.Hour
+
+	reaperArgsInvalidBlockingRuns := Args(reaperArgsValid)
+	reaperArgsInvalidBlockingRuns.BlockingRuns = -1
+
+	reaperArgsInvalidStatsdWithPushgateway := Args(reaperArgsValid)
+	reaperArgsInvalidStatsdWithPushgateway.StatsdAddress = "localhost:8125"
+
+	reaperArgsInvalidStatsdAddress := Args(reaperArgsValid)
+	reaperArgsInvalidStatsdAddress.PromPushgateway = ""
+	reaperArgsInvalidStatsdAddress.StatsdAddress = "localhost"
+
+	reaperArgsInvalidMultipleOverlapRatio := Args(reaperArgsValid)
+	reaperArgsInvalidMultipleOverlapRatio.MultipleOverlapRatio = 1.5
+
+	reaperArgsInvalidPodCountRetries := Args(reaperArgsValid)
+	reaperArgsInvalidPodCountRetries.PodCountRetries = -1
+
+	reaperArgsInvalidValidateWithNDJSON := Args(reaperArgsValid)
+	reaperArgsInvalidValidateWithNDJSON.Validate = true
+	reaperArgsInvalidValidateWithNDJSON.NDJSON = true
+
+	reaperArgsInvalidNodeDrainIntegration := Args(reaperArgsValid)
+	reaperArgsInvalidNodeDrainIntegration.NodeDrainIntegration = true
+
+	reaperArgsInvalidDeletionOrder := Args(reaperArgsValid)
+	reaperArgsInvalidDeletionOrder.DeletionOrder = "newest-first"
+
+	reaperArgsInvalidStaleStatusRatio := Args(reaperArgsValid)
+	reaperArgsInvalidStaleStatusRatio.CheckDisruptionController = true
+
+	reaperArgsInvalidSummaryEventObject := Args(reaperArgsValid)
+	reaperArgsInvalidSummaryEventObject.SummaryEventObject = "kube-system/pdb-reaper"
+
+	reaperArgsInvalidPDBLabelRequired := Args(reaperArgsValid)
+	reaperArgsInvalidPDBLabelRequired.PDBLabelRequired = "pdb-reaper/managed=true=yes"
+
+	reaperArgsInvalidHTTPProxy := Args(reaperArgsValid)
+	reaperArgsInvalidHTTPProxy.HTTPProxy = "proxy.example.com:3128"
+
+	reaperArgsInvalidHTTPCABundle := Args(reaperArgsValid)
+	reaperArgsInvalidHTTPCABundle.HTTPCABundle = "/nonexistent/ca.pem"
+
+	reaperArgsInvalidDrainAssist := Args(reaperArgsValid)
+	reaperArgsInvalidDrainAssist.DrainAssist = true
+
+	reaperArgsInvalidCSVOutput := Args(reaperArgsValid)
+	reaperArgsInvalidCSVOutput.CSVOutput = "findings.csv"
+	reaperArgsInvalidCSVOutput.Clusters = []string{"cluster-a"}
+
+	reaperArgsInvalidExcludedNamespacesConfigMap := Args(reaperArgsValid)
+	reaperArgsInvalidExcludedNamespacesConfigMap.ExcludedNamespacesConfigMap = "pdb-reaper-exclusions"
+
+	reaperArgsInvalidEnforceNamespaceLabel := Args(reaperArgsValid)
+	reaperArgsInvalidEnforceNamespaceLabel.EnforceNamespaceLabel = "pdb-reaper=enforce=yes"
+
+	reaperArgsInvalidThrottleRetries := Args(reaperArgsValid)
+	reaperArgsInvalidThrottleRetries.ThrottleRetries = -1
+
+	reaperArgsInvalidCrashLoopPodFraction := Args(reaperArgsValid)
+	reaperArgsInvalidCrashLoopPodFraction.CrashLoopPodFraction = 1.5
+
+	reaperArgsInvalidStrictSelfTest := Args(reaperArgsValid)
+	reaperArgsInvalidStrictSelfTest.StrictSelfTest = true
+
+	reaperArgsInvalidMaxNamespaces := Args(reaperArgsValid)
+	reaperArgsInvalidMaxNamespaces.MaxNamespaces = -1
+
+	reaperArgsInvalidHealthScoreWeights := Args(reaperArgsValid)
+	reaperArgsInvalidHealthScoreWeights.HealthScoreWeights = []string{"overlap=high"}
+	reaperArgsInvalidBackupBucket := Args(reaperArgsValid)
+	reaperArgsInvalidBackupBucket.BackupSink = BackupSinkObjectStore
+	reaperArgsInvalidStrictVersionCheck := Args(reaperArgsValid)
+	reaperArgsInvalidStrictVersionCheck.StrictVersionCheck = true
+	reaperArgsInvalidReportOnlyThreshold := Args(reaperArgsValid)
+	reaperArgsInvalidReportOnlyThreshold.ReportOnlyThreshold = []string{"crashloop=-1"}
+	reaperArgsInvalidInspect := Args(reaperArgsValid)
+	reaperArgsInvalidInspect.Inspect = "pdb-1"
+	reaperArgsInvalidDumpEvents := Args(reaperArgsValid)
+	reaperArgsInvalidDumpEvents.DryRun = false
+	reaperArgsInvalidDumpEvents.DumpEvents = true
+	reaperArgsInvalidPausedSkipDetection := Args(reaperArgsValid)
+	reaperArgsInvalidPausedSkipDetection.PausedSkipDetection = true
+
+	reaperArgsInvalidMaxWritesPerRun := Args(reaperArgsValid)
+	reaperArgsInvalidMaxWritesPerRun.MaxWritesPerRun = -1
+
+	reaperArgsInvalidDeferReapsOnWriteCap := Args(reaperArgsValid)
+	reaperArgsInvalidDeferReapsOnWriteCap.DeferReapsOnWriteCap = true
+
+	reaperArgsInvalidExpectedPodsSource := Args(reaperArgsValid)
+	reaperArgsInvalidExpectedPodsSource.ExpectedPodsSource = "spec"
+
+	reaperArgsInvalidNamespaceFindingsOnly := Args(reaperArgsValid)
+	reaperArgsInvalidNamespaceFindingsOnly.NamespaceFindingsOnly = true
+
+	reaperArgsInvalidCrashLoopReasons := Args(reaperArgsValid)
+	reaperArgsInvalidCrashLoopReasons.CrashLoopReasons = []string{ReasonCrashLoopBackOff, ""}
+
+	reaperArgsInvalidCrashLoopMinExitCode := Args(reaperArgsValid)
+	reaperArgsInvalidCrashLoopMinExitCode.CrashLoopMinExitCode = -1
+
+	reaperArgsInvalidPlanApply := Args(reaperArgsValid)
+	reaperArgsInvalidPlanApply.PlanOut = "plan.json"
+	reaperArgsInvalidPlanApply.Apply = "plan.json"
+
+	reaperArgsInvalidPlatformExclusionsConfigMap := Args(reaperArgsValid)
+	reaperArgsInvalidPlatformExclusionsConfigMap.PlatformExclusionsConfigMap = "platform-system/"
+	reaperArgsInvalidErrorWebhook := Args(reaperArgsValid)
+	reaperArgsInvalidErrorWebhook.ErrorWebhook = "hooks.example.com/pdb-reaper"
+
 	reaperArgsInvalidInClusterAuth := Args(reaperArgsValid)
 	reaperArgsInvalidInClusterAuth.LocalMode = false
 
@@ -62,6 +239,52 @@ func TestReaperContext_validate(t *testing.T) {
 		// {"Valid-Args", *_fakeReaperContext(), &reaperArgsValid, false},
 		{"Invalid-CrashLoopRestartCount", *_fakeReaperContext(), &reaperArgsInvalidCrashLoopRestartCount, true, "--crashloop-restart-count value cannot be less than 1"},
 		{"Invalid-ReapNotReadyThreshold", *_fakeReaperContext(), &reaperArgsInvalidReapNotReadyThreshold, true, "--not-ready-threshold-seconds value cannot be less than 1"},
+		{"Invalid-DryRunAnnotate", *_fakeReaperContext(), &reaperArgsInvalidDryRunAnnotate, true, "cannot use --dry-run-annotate without --dry-run"},
+		{"Invalid-MaxReapableRatio", *_fakeReaperContext(), &reaperArgsInvalidMaxReapableRatio, true, "--max-reapable-ratio value must be between 0 and 1"},
+		{"Invalid-StateConfigMap", *_fakeReaperContext(), &reaperArgsInvalidStateConfigMap, true, "--state-configmap value 'pdb-reaper-state' must be in the form namespace/name"},
+		{"Invalid-ReapReasonPriority", *_fakeReaperContext(), &reaperArgsInvalidReapReasonPriority, true, "--reap-reason-priority value 'blocking' is not one of misconfigured,crashloop,not-ready,multiple"},
+		{"Invalid-Cluster", *_fakeReaperContext(), &reaperArgsInvalidCluster, true, "--cluster value 'cluster-a' must be in the form name=kubeconfig[:context]"},
+		{"Invalid-ClusterWithKubeconfig", *_fakeReaperContext(), &reaperArgsInvalidClusterWithKubeconfig, true, "cannot use --cluster with --kubeconfig or --local-mode"},
+		{"Invalid-ExcludedPDBNames", *_fakeReaperContext(), &reaperArgsInvalidExcludedPDBNames, true, "--exclude-pdb-names value 'namespace-1/' must be in the form namespace/name or name"},
+		{"Invalid-ReapWindow", *_fakeReaperContext(), &reaperArgsInvalidReapWindow, true, "--reap-window value '9am-5pm' must be in the form HH:MM-HH:MM"},
+		{"Invalid-MaxAgeToConsider", *_fakeReaperContext(), &reaperArgsInvalidMaxAgeToConsider, true, "--max-age-to-consider value cannot be negative"},
+		{"Invalid-BlockingRuns", *_fakeReaperContext(), &reaperArgsInvalidBlockingRuns, true, "--blocking-runs value cannot be negative"},
+		{"Invalid-StatsdWithPushgateway", *_fakeReaperContext(), &reaperArgsInvalidStatsdWithPushgateway, true, "cannot use --statsd-address with --prometheus-pushgateway"},
+		{"Invalid-StatsdAddress", *_fakeReaperContext(), &reaperArgsInvalidStatsdAddress, true, "--statsd-address value 'localhost' must be in the form host:port"},
+		{"Invalid-MultipleOverlapRatio", *_fakeReaperContext(), &reaperArgsInvalidMultipleOverlapRatio, true, "--multiple-overlap-ratio value must be between 0 and 1"},
+		{"Invalid-PodCountRetries", *_fakeReaperContext(), &reaperArgsInvalidPodCountRetries, true, "--pod-count-retries and --pod-count-retry-delay values cannot be negative"},
+		{"Invalid-ValidateWithNDJSON", *_fakeReaperContext(), &reaperArgsInvalidValidateWithNDJSON, true, "cannot use --validate with --cluster, --ndjson, --fix-manifests-dir or --cleanup-annotations"},
+		{"Invalid-NodeDrainIntegration", *_fakeReaperContext(), &reaperArgsInvalidNodeDrainIntegration, true, "--node-drain-integration requires --maintenance-node-label or --maintenance-configmap"},
+		{"Invalid-DeletionOrder", *_fakeReaperContext(), &reaperArgsInvalidDeletionOrder, true, "--deletion-order value 'newest-first' is not one of discovery,oldest-first,most-pods-first"},
+		{"Invalid-StaleStatusRatio", *_fakeReaperContext(), &reaperArgsInvalidStaleStatusRatio, true, "--stale-status-ratio value must be greater than 0 and at most 1"},
+		{"Invalid-SummaryEventObject", *_fakeReaperContext(), &reaperArgsInvalidSummaryEventObject, true, "--summary-event-object value 'kube-system/pdb-reaper' must be in the form kind/namespace/name"},
+		{"Invalid-PDBLabelRequired", *_fakeReaperContext(), &reaperArgsInvalidPDBLabelRequired, true, "--pdb-label-required value 'pdb-reaper/managed=true=yes' must be in the form key or key=value"},
+		{"Invalid-HTTPProxy", *_fakeReaperContext(), &reaperArgsInvalidHTTPProxy, true, "--http-proxy value 'proxy.example.com:3128' must be an http or https URL"},
+		{"Invalid-HTTPCABundle", *_fakeReaperContext(), &reaperArgsInvalidHTTPCABundle, true, "failed to read --http-ca-bundle /nonexistent/ca.pem"},
+		{"Invalid-DrainAssist", *_fakeReaperContext(), &reaperArgsInvalidDrainAssist, true, "--drain-assist and --node must be used together"},
+		{"Invalid-CSVOutput", *_fakeReaperContext(), &reaperArgsInvalidCSVOutput, true, "cannot use --csv-output with --cluster"},
+		{"Invalid-ExcludedNamespacesConfigMap", *_fakeReaperContext(), &reaperArgsInvalidExcludedNamespacesConfigMap, true, "--excluded-namespaces-configmap value 'pdb-reaper-exclusions' must be in the form namespace/name"},
+		{"Invalid-EnforceNamespaceLabel", *_fakeReaperContext(), &reaperArgsInvalidEnforceNamespaceLabel, true, "--enforce-namespace-label value 'pdb-reaper=enforce=yes' must be in the form key or key=value"},
+		{"Invalid-ThrottleRetries", *_fakeReaperContext(), &reaperArgsInvalidThrottleRetries, true, "--throttle-retries, --throttle-backoff and --throttle-max-wait values cannot be negative"},
+		{"Invalid-CrashLoopPodFraction", *_fakeReaperContext(), &reaperArgsInvalidCrashLoopPodFraction, true, "--crashloop-pod-fraction value must be between 0 and 1"},
+		{"Invalid-StrictSelfTest", *_fakeReaperContext(), &reaperArgsInvalidStrictSelfTest, true, "--strict-self-test requires --self-test"},
+		{"Invalid-MaxNamespaces", *_fakeReaperContext(), &reaperArgsInvalidMaxNamespaces, true, "--max-namespaces value cannot be negative"},
+		{"Invalid-HealthScoreWeights", *_fakeReaperContext(), &reaperArgsInvalidHealthScoreWeights, true, "--health-score-weights weight 'high' of overlap must be a non-negative number"},
+		{"Invalid-BackupBucket", *_fakeReaperContext(), &reaperArgsInvalidBackupBucket, true, "--backup-sink=object-store requires --backup-bucket"},
+		{"Invalid-StrictVersionCheck", *_fakeReaperContext(), &reaperArgsInvalidStrictVersionCheck, true, "--strict-version-check requires --min-kubernetes-version"},
+		{"Invalid-ReportOnlyThreshold", *_fakeReaperContext(), &reaperArgsInvalidReportOnlyThreshold, true, "--report-only-threshold value 'crashloop=-1' must be a non-negative number of runs"},
+		{"Invalid-Inspect", *_fakeReaperContext(), &reaperArgsInvalidInspect, true, "--inspect value 'pdb-1' must be in the form namespace/name"},
+		{"Invalid-DumpEvents", *_fakeReaperContext(), &reaperArgsInvalidDumpEvents, true, "cannot use --dump-events without --dry-run"},
+		{"Invalid-PausedSkipDetection", *_fakeReaperContext(), &reaperArgsInvalidPausedSkipDetection, true, "cannot use --paused-skip-detection without --honor-paused-namespaces"},
+		{"Invalid-MaxWritesPerRun", *_fakeReaperContext(), &reaperArgsInvalidMaxWritesPerRun, true, "--max-writes-per-run value cannot be negative"},
+		{"Invalid-DeferReapsOnWriteCap", *_fakeReaperContext(), &reaperArgsInvalidDeferReapsOnWriteCap, true, "cannot use --defer-reaps-on-write-cap without --max-writes-per-run"},
+		{"Invalid-ExpectedPodsSource", *_fakeReaperContext(), &reaperArgsInvalidExpectedPodsSource, true, "--expected-pods-source value 'spec' is not one of status,live"},
+		{"Invalid-NamespaceFindingsOnly", *_fakeReaperContext(), &reaperArgsInvalidNamespaceFindingsOnly, true, "cannot use --namespace-findings-only without --annotate-findings-to-namespace-events"},
+		{"Invalid-CrashLoopReasons", *_fakeReaperContext(), &reaperArgsInvalidCrashLoopReasons, true, "--crashloop-reasons cannot contain an empty reason"},
+		{"Invalid-CrashLoopMinExitCode", *_fakeReaperContext(), &reaperArgsInvalidCrashLoopMinExitCode, true, "--crashloop-min-exit-code value cannot be negative"},
+		{"Invalid-PlanApply", *_fakeReaperContext(), &reaperArgsInvalidPlanApply, true, "cannot use --plan-out with --apply"},
+		{"Invalid-PlatformExclusionsConfigMap", *_fakeReaperContext(), &reaperArgsInvalidPlatformExclusionsConfigMap, true, "--platform-exclusions-configmap value 'platform-system/' must be in the form namespace/name"},
+		{"Invalid-ErrorWebhook", *_fakeReaperContext(), &reaperArgsInvalidErrorWebhook, true, "--report-webhook-on-error value 'hooks.example.com/pdb-reaper' must be an http or https URL"},
 		{"Invalid-InClusterAuth", *_fakeReaperContext(), &reaperArgsInvalidInClusterAuth, true, "in-cluster auth failed: unable to load in-cluster configuration, KUBERNETES_SERVICE_HOST and KUBERNETES_SERVICE_PORT must be defined"},
 		{"Invalid-LocalMode", *_fakeReaperContext(), &reaperArgsInvalidLocalMode, true, "cannot use --local-mode without --kubeconfig"},
 		{"Invalid-K8sConfigPath", *_fakeReaperContext(), &reaperArgsInvalidK8sConfigPath, true, "--kubeconfig path '/tmp/invalid/path' was not found"},
@@ -99,3 +322,113 @@ func TestReaperContext_validate(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateRunOnce(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    Args
+		wantErr string
+	}{
+		{"Valid", Args{}, ""},
+		{"SkipFirstRunWithState", Args{SkipFirstRunReap: true, StateConfigMap: "kube-system/pdb-reaper-state"}, ""},
+		{"SkipFirstRunWithoutState", Args{SkipFirstRunReap: true}, "cannot use --skip-first-run-reap without --state-configmap"},
+		{"ReportOnlyWithState", Args{ReportOnlyThreshold: []string{"3"}, StateConfigMap: "kube-system/pdb-reaper-state"}, ""},
+		{"ReportOnlyWithoutState", Args{ReportOnlyThreshold: []string{"3"}}, "cannot use --report-only-threshold without --state-configmap"},
+		{"SingleBlockingRunWithoutState", Args{BlockingRuns: 1}, ""},
+		{"BlockingRunsWithState", Args{BlockingRuns: 3, StateConfigMap: "kube-system/pdb-reaper-state"}, ""},
+		{"BlockingRunsWithoutState", Args{BlockingRuns: 3}, "cannot use --blocking-runs greater than 1 without --state-configmap"},
+		{"MaxReapableRatioWithState", Args{MaxReapableRatio: 0.5, StateConfigMap: "kube-system/pdb-reaper-state"}, ""},
+		{"MaxReapableRatioWithoutState", Args{MaxReapableRatio: 0.5}, "cannot use --max-reapable-ratio without --state-configmap"},
+		{"ReapCooldownWithState", Args{ReapCooldown: time.Hour, StateConfigMap: "kube-system/pdb-reaper-state"}, ""},
+		{"ReapCooldownWithoutState", Args{ReapCooldown: time.Hour}, "cannot use --reap-cooldown without --state-configmap"},
+		{"RecreateWindowWithState", Args{RecreateWindow: time.Hour, StateConfigMap: "kube-system/pdb-reaper-state"}, ""},
+		{"RecreateWindowWithoutState", Args{RecreateWindow: time.Hour}, "cannot use --recreate-window without --state-configmap"},
+		{"UpdateDebounceWithState", Args{UpdateDebounce: time.Hour, StateConfigMap: "kube-system/pdb-reaper-state"}, ""},
+		{"UpdateDebounceWithoutState", Args{UpdateDebounce: time.Hour}, "cannot use --update-debounce without --state-configmap"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateRunOnce(&tt.args)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateRunOnceResetStaleMetrics(t *testing.T) {
+	output := &bytes.Buffer{}
+	out := log.Out
+	log.Out = output
+	defer func() { log.Out = out }()
+
+	args := Args{ResetStaleMetrics: true, EmitMetrics: true, PromPushgateway: "http://pushgateway:9091"}
+	assert.NoError(t, validateRunOnce(&args))
+	assert.Contains(t, output.String(), "--reset-stale-metrics has no effect without --state-configmap")
+
+	output.Reset()
+	args.StateConfigMap = "kube-system/pdb-reaper-state"
+	assert.NoError(t, validateRunOnce(&args))
+	assert.NotContains(t, output.String(), "--reset-stale-metrics")
+}
+
+func TestParseClusterTarget(t *testing.T) {
+	tests := []struct {
+		value       string
+		name        string
+		configPath  string
+		kubeContext string
+		wantErr     bool
+	}{
+		{"cluster-a=/tmp/kubeconfig", "cluster-a", "/tmp/kubeconfig", "", false},
+		{"cluster-a=/tmp/kubeconfig:arn:aws:eks:us-west-2:000000000000:cluster/a", "cluster-a", "/tmp/kubeconfig", "arn:aws:eks:us-west-2:000000000000:cluster/a", false},
+		{"cluster-a=", "", "", "", true},
+		{"=/tmp/kubeconfig", "", "", "", true},
+		{"cluster-a=:context", "", "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			name, configPath, kubeContext, err := parseClusterTarget(tt.value)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.name, name)
+			assert.Equal(t, tt.configPath, configPath)
+			assert.Equal(t, tt.kubeContext, kubeContext)
+		})
+	}
+}
+
+func TestReapWindow_Contains(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("timezone data not available: %v", err)
+	}
+	tests := []struct {
+		name     string
+		window   string
+		timezone string
+		at       time.Time
+		want     bool
+	}{
+		{"Inside", "09:00-17:00", "UTC", time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC), true},
+		{"AtEnd", "09:00-17:00", "UTC", time.Date(2024, 1, 1, 17, 0, 0, 0, time.UTC), false},
+		{"Before", "09:00-17:00", "UTC", time.Date(2024, 1, 1, 8, 59, 0, 0, time.UTC), false},
+		{"OvernightLate", "22:00-02:00", "UTC", time.Date(2024, 1, 1, 23, 0, 0, 0, time.UTC), true},
+		{"OvernightEarly", "22:00-02:00", "UTC", time.Date(2024, 1, 1, 1, 0, 0, 0, time.UTC), true},
+		{"OvernightOutside", "22:00-02:00", "UTC", time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC), false},
+		{"Timezone", "09:00-17:00", "America/New_York", time.Date(2024, 1, 1, 15, 0, 0, 0, time.UTC), true},
+		{"TimezoneOutside", "09:00-17:00", "America/New_York", time.Date(2024, 1, 1, 10, 0, 0, 0, newYork).Add(8 * time.Hour), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			window, err := parseReapWindow(tt.window, tt.timezone)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, window.Contains(tt.at))
+		})
+	}
+}
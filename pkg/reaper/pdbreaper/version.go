@@ -0,0 +1,78 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pdbreaper
+
+import (
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/version"
+)
+
+const (
+	// FeatureConfirmWithEviction needs the policy/v1 Eviction API
+	FeatureConfirmWithEviction = "confirm-with-eviction-after-delete"
+)
+
+// VersionGatedFeature is an option which needs a minimum server version
+type VersionGatedFeature struct {
+	Name       string
+	MinVersion string
+	enabled    func(ctx *ReaperContext) bool
+}
+
+// VersionGatedFeatures are the options which are disabled on servers older than their minimum version
+var VersionGatedFeatures = []VersionGatedFeature{
+	{Name: FeatureConfirmWithEviction, MinVersion: "1.22", enabled: func(ctx *ReaperContext) bool { return ctx.ConfirmWithEviction }},
+}
+
+// checkServerVersion fails the run when the server is older than --min-kubernetes-version, and disables the enabled
+// version gated features the server is too old for, or fails the run with --strict-version-check
+func (ctx *ReaperContext) checkServerVersion() error {
+	if ctx.MinKubernetesVersion == "" {
+		return nil
+	}
+
+	info, err := ctx.KubernetesClient.Discovery().ServerVersion()
+	if err != nil {
+		return errors.Wrap(err, "failed to get server version")
+	}
+	serverVersion, err := version.ParseGeneric(info.GitVersion)
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse server version '%v'", info.GitVersion)
+	}
+	log.Infof("server version is %v", info.GitVersion)
+
+	minVersion := version.MustParseGeneric(ctx.MinKubernetesVersion)
+	if serverVersion.LessThan(minVersion) {
+		return errors.Errorf("server version %v is older than --min-kubernetes-version %v", info.GitVersion, ctx.MinKubernetesVersion)
+	}
+
+	for _, feature := range VersionGatedFeatures {
+		if !feature.enabled(ctx) || !serverVersion.LessThan(version.MustParseGeneric(feature.MinVersion)) {
+			continue
+		}
+		if ctx.StrictVersionCheck {
+			return errors.Errorf("--%v needs server version %v or newer, the server version is %v", feature.Name, feature.MinVersion, info.GitVersion)
+		}
+		log.Warnf("disabling --%v since it needs server version %v or newer, the server version is %v", feature.Name, feature.MinVersion, info.GitVersion)
+		ctx.unsupportedFeatures[feature.Name] = true
+	}
+	return nil
+}
+
+// isFeatureSupported returns false when a feature was disabled for the server version of the current run
+func (ctx *ReaperContext) isFeatureSupported(name string) bool {
+	return !ctx.unsupportedFeatures[name]
+}
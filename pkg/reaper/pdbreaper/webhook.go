@@ -0,0 +1,124 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pdbreaper
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ErrorWebhookTimeout is the maximum time to wait for the error webhook to respond
+const ErrorWebhookTimeout = 10 * time.Second
+
+// ErrorReport is the payload posted to --report-webhook-on-error when a run fails
+type ErrorReport struct {
+	Error     string                        `json:"error"`
+	Code      ErrorCode                     `json:"code,omitempty"`
+	Chain     []string                      `json:"chain"`
+	Cluster   string                        `json:"cluster,omitempty"`
+	Scanned   int                           `json:"scanned"`
+	Reapable  int                           `json:"reapable"`
+	Reaped    int                           `json:"reaped"`
+	Clusters  map[string]ClusterErrorReport `json:"clusters,omitempty"`
+	Timestamp time.Time                     `json:"timestamp"`
+}
+
+// ClusterErrorReport holds the partial counts of a single cluster when multiple clusters are processed
+type ClusterErrorReport struct {
+	Error    string    `json:"error,omitempty"`
+	Code     ErrorCode `json:"code,omitempty"`
+	Scanned  int       `json:"scanned"`
+	Reapable int       `json:"reapable"`
+	Reaped   int       `json:"reaped"`
+}
+
+// newErrorReport summarizes a failed run with the counts reached before the failure
+func (ctx *ReaperContext) newErrorReport(err error) ErrorReport {
+	report := ErrorReport{
+		Error:     err.Error(),
+		Code:      errorCode(err),
+		Chain:     errorChain(err),
+		Cluster:   ctx.ClusterName,
+		Scanned:   ctx.ScannedPodDisruptionBudgetsCount,
+		Reapable:  ctx.ReapablePodDisruptionBudgetsCount,
+		Reaped:    ctx.ReapedPodDisruptionBudgetCount,
+		Timestamp: ctx.now().UTC(),
+	}
+
+	if len(ctx.ClusterResults) > 0 {
+		report.Cluster = ""
+		report.Scanned, report.Reapable, report.Reaped = 0, 0, 0
+		report.Clusters = make(map[string]ClusterErrorReport)
+		for name, result := range ctx.ClusterResults {
+			clusterReport := ClusterErrorReport{
+				Scanned:  result.ScannedPodDisruptionBudgetsCount,
+				Reapable: result.ReapablePodDisruptionBudgetsCount,
+				Reaped:   result.ReapedPodDisruptionBudgetCount,
+			}
+			if result.Err != nil {
+				clusterReport.Error = result.Err.Error()
+				clusterReport.Code = errorCode(result.Err)
+			}
+			report.Clusters[name] = clusterReport
+			report.Scanned += clusterReport.Scanned
+			report.Reapable += clusterReport.Reapable
+			report.Reaped += clusterReport.Reaped
+		}
+	}
+	return report
+}
+
+// reportErrorWebhook posts a summary of a failed run to the error webhook
+func (ctx *ReaperContext) reportErrorWebhook(err error) error {
+	data, err := json.Marshal(ctx.newErrorReport(err))
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal error report")
+	}
+
+	client := ctx.httpClient(ErrorWebhookTimeout)
+	resp, err := client.Post(ctx.ErrorWebhookURL, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return errors.Wrap(err, "failed to post error report")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return errors.Errorf("error webhook responded with status %v", resp.Status)
+	}
+	return nil
+}
+
+// errorChain returns the message added by each wrapping layer of an error, outermost first
+func errorChain(err error) []string {
+	chain := make([]string, 0)
+	for err != nil {
+		cause := errors.Unwrap(err)
+		if cause == nil {
+			chain = append(chain, err.Error())
+			break
+		}
+		// layers which only add a stack trace do not change the message
+		if message := err.Error(); message != cause.Error() {
+			chain = append(chain, strings.TrimSuffix(message, ": "+cause.Error()))
+		}
+		err = cause
+	}
+	return chain
+}
@@ -0,0 +1,79 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pdbreaper
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const reapWindowTimeLayout = "15:04"
+
+// ReapWindow is a daily time-of-day window in which PDBs are allowed to be deleted
+type ReapWindow struct {
+	Start    time.Duration
+	End      time.Duration
+	Location *time.Location
+}
+
+// parseReapWindow parses a window in the form HH:MM-HH:MM, a window whose end is before its start spans midnight
+func parseReapWindow(value, timezone string) (*ReapWindow, error) {
+	parts := strings.Split(value, "-")
+	if len(parts) != 2 {
+		return nil, errors.Errorf("--reap-window value '%v' must be in the form HH:MM-HH:MM", value)
+	}
+
+	start, err := time.Parse(reapWindowTimeLayout, strings.TrimSpace(parts[0]))
+	if err != nil {
+		return nil, errors.Errorf("--reap-window value '%v' must be in the form HH:MM-HH:MM", value)
+	}
+	end, err := time.Parse(reapWindowTimeLayout, strings.TrimSpace(parts[1]))
+	if err != nil {
+		return nil, errors.Errorf("--reap-window value '%v' must be in the form HH:MM-HH:MM", value)
+	}
+	if start.Equal(end) {
+		return nil, errors.Errorf("--reap-window value '%v' must not start and end at the same time", value)
+	}
+
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, errors.Wrapf(err, "--reap-window-timezone value '%v' is not a valid timezone", timezone)
+	}
+
+	return &ReapWindow{
+		Start:    time.Duration(start.Hour())*time.Hour + time.Duration(start.Minute())*time.Minute,
+		End:      time.Duration(end.Hour())*time.Hour + time.Duration(end.Minute())*time.Minute,
+		Location: location,
+	}, nil
+}
+
+// Contains returns true if the given time is within the window
+func (w *ReapWindow) Contains(t time.Time) bool {
+	local := t.In(w.Location)
+	sinceMidnight := time.Duration(local.Hour())*time.Hour + time.Duration(local.Minute())*time.Minute + time.Duration(local.Second())*time.Second
+
+	if w.Start < w.End {
+		return sinceMidnight >= w.Start && sinceMidnight < w.End
+	}
+	return sinceMidnight >= w.Start || sinceMidnight < w.End
+}
+
+func (w *ReapWindow) String() string {
+	return fmt.Sprintf("%02d:%02d-%02d:%02d %v", int(w.Start.Hours()), int(w.Start.Minutes())%60, int(w.End.Hours()), int(w.End.Minutes())%60, w.Location)
+}
@@ -0,0 +1,138 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pdbreaper
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/keikoproj/governor/pkg/reaper/common"
+	"github.com/pkg/errors"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	LastReapedPDBAnnotationKey = "pdb-reaper/last-reaped-pdb"
+	LastReapedAtAnnotationKey  = "pdb-reaper/last-reaped-at"
+)
+
+// WorkloadAnnotationPermissions are the additional permissions needed when --annotate-workloads is set
+var WorkloadAnnotationPermissions = []authorizationv1.ResourceAttributes{
+	{Verb: "get", Group: "apps", Resource: "replicasets"},
+	{Verb: "patch", Group: "apps", Resource: "deployments"},
+	{Verb: "patch", Group: "apps", Resource: "statefulsets"},
+}
+
+// workloadRef is a Deployment or StatefulSet owning pods
+type workloadRef struct {
+	Kind string
+	Name string
+}
+
+// annotateReapedWorkloads annotates the Deployments and StatefulSets owning the pods of a reaped PDB with a reference
+// to it, so that their owners notice. This is best-effort, failures are logged.
+func (ctx *ReaperContext) annotateReapedWorkloads(pdb policyv1.PodDisruptionBudget) {
+	if !ctx.AnnotateWorkloads {
+		return
+	}
+
+	labelSelector, err := common.GetSelectorString(pdb.Spec.Selector)
+	if err != nil {
+		log.Warnf("failed to get label selector of reaped pdb %v: %v", pdbNamespacedName(pdb), err)
+		return
+	}
+	pods, err := ctx.listPodsWithSelector(ctx.runContext(), pdb.GetNamespace(), labelSelector)
+	if err != nil {
+		log.Warnf("failed to list pods of reaped pdb %v: %v", pdbNamespacedName(pdb), err)
+		return
+	}
+
+	annotated := make(map[workloadRef]bool)
+	for _, pod := range pods {
+		workload, ok := ctx.podWorkload(pod)
+		if !ok || annotated[workload] {
+			continue
+		}
+		annotated[workload] = true
+		if err := ctx.patchWorkloadAnnotations(pdb, workload); err != nil {
+			log.Warnf(err.Error())
+			continue
+		}
+		log.Infof("annotated %v %v/%v with reaped pdb %v", workload.Kind, pdb.GetNamespace(), workload.Name, pdbNamespacedName(pdb))
+	}
+}
+
+// podWorkload returns the Deployment or StatefulSet controlling a pod, a Deployment is resolved through its ReplicaSet
+func (ctx *ReaperContext) podWorkload(pod corev1.Pod) (workloadRef, bool) {
+	owner := metav1.GetControllerOf(&pod)
+	if owner == nil {
+		return workloadRef{}, false
+	}
+
+	switch owner.Kind {
+	case "StatefulSet":
+		return workloadRef{Kind: owner.Kind, Name: owner.Name}, true
+	case "ReplicaSet":
+		rs, err := ctx.KubernetesClient.AppsV1().ReplicaSets(pod.GetNamespace()).Get(context.Background(), owner.Name, metav1.GetOptions{})
+		if err != nil {
+			log.Warnf("failed to get replicaset %v/%v of pod %v: %v", pod.GetNamespace(), owner.Name, pod.GetName(), err)
+			return workloadRef{}, false
+		}
+		if rsOwner := metav1.GetControllerOf(rs); rsOwner != nil && rsOwner.Kind == "Deployment" {
+			return workloadRef{Kind: rsOwner.Kind, Name: rsOwner.Name}, true
+		}
+	}
+	return workloadRef{}, false
+}
+
+func (ctx *ReaperContext) patchWorkloadAnnotations(pdb policyv1.PodDisruptionBudget, workload workloadRef) error {
+	if !ctx.allowWrite(fmt.Sprintf("annotation update of %v %v/%v", workload.Kind, pdb.GetNamespace(), workload.Name)) {
+		return nil
+	}
+
+	patch := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{
+				LastReapedPDBAnnotationKey: pdb.GetName(),
+				LastReapedAtAnnotationKey:  ctx.now().UTC().Format(time.RFC3339),
+			},
+		},
+	}
+	data, err := json.Marshal(patch)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal workload annotations patch")
+	}
+
+	namespace := pdb.GetNamespace()
+	switch workload.Kind {
+	case "Deployment":
+		_, err = ctx.KubernetesClient.AppsV1().Deployments(namespace).Patch(context.Background(), workload.Name, types.MergePatchType, data, metav1.PatchOptions{})
+	case "StatefulSet":
+		_, err = ctx.KubernetesClient.AppsV1().StatefulSets(namespace).Patch(context.Background(), workload.Name, types.MergePatchType, data, metav1.PatchOptions{})
+	default:
+		return errors.Errorf("unsupported workload kind %v", workload.Kind)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to annotate %v %v/%v with reaped pdb %v", workload.Kind, namespace, workload.Name, pdbNamespacedName(pdb))
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pdbreaper
+
+const PdbReaperSuppressedWritesMetricName = "governor_pdb_reaper_suppressed_writes"
+
+// allowWrite returns true if an event creation or annotation update fits in --max-writes-per-run and counts it,
+// writes beyond the cap are counted as suppressed
+func (ctx *ReaperContext) allowWrite(write string) bool {
+	if ctx.MaxWritesPerRun == 0 {
+		return true
+	}
+	if ctx.writes >= ctx.MaxWritesPerRun {
+		if ctx.SuppressedWritesCount == 0 {
+			log.Warnf("reached --max-writes-per-run %v, further events and annotation updates are skipped in this run", ctx.MaxWritesPerRun)
+		}
+		log.Infof("skipping %v, --max-writes-per-run %v reached", write, ctx.MaxWritesPerRun)
+		ctx.SuppressedWritesCount++
+		return false
+	}
+	ctx.writes++
+	return true
+}
+
+// isWriteCapReached returns true once --max-writes-per-run writes were made in the current run
+func (ctx *ReaperContext) isWriteCapReached() bool {
+	return ctx.MaxWritesPerRun > 0 && ctx.writes >= ctx.MaxWritesPerRun
+}